@@ -13,6 +13,7 @@ type Config struct {
 	HTTPServer  HTTPServer  `yaml:"http_server" env-required:"true"`
 	Auth        Auth        `yaml:"auth" env-required:"true"`
 	QR          QR          `yaml:"qr" env-required:"true"`
+	Reservation Reservation `yaml:"reservation"`
 }
 
 type StoragePath struct {
@@ -45,6 +46,10 @@ type QR struct {
 	PathToFile  string `yaml:"path_to_file"`
 }
 
+type Reservation struct {
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"1m"`
+}
+
 func MustLoad() *Config {
 	configPath := os.Getenv("CONFIG_WARE_FLOW")
 	if configPath == "" {
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type CustomerHandler interface {
+	CreateCustomer(echo.Context) error
+	UpdateCustomer(echo.Context) error
+	GetAllCustomers(echo.Context) error
+	GetCustomer(echo.Context) error
+}
+
+type ICustomerHandler struct {
+	logger          slog.Logger
+	customerUsecase usecase.CustomerUsecase
+}
+
+func NewICustomerHandler(logger slog.Logger, customerUsecase usecase.CustomerUsecase) *ICustomerHandler {
+	return &ICustomerHandler{
+		logger:          logger,
+		customerUsecase: customerUsecase,
+	}
+}
+
+// CreateCustomer godoc
+// @Summary Создание покупателя
+// @Description Добавляет покупателя в справочник владельца. Название уникально в пределах владельца
+// @Tags customer
+// @Accept			json
+// @Produce		json
+// @Param request body delivery.CustomerModelRequest true "Карточка покупателя"
+// @Success 200 {object} delivery.CustomerModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /customer [post]
+func (ch *ICustomerHandler) CreateCustomer(c echo.Context) error {
+	reqBody := delivery.CustomerModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ch.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	customer, err := ch.customerUsecase.CreateCustomer(&reqBody, userId)
+	if err != nil {
+		ch.logger.Error(fmt.Sprintf("Can't create customer: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, customer)
+}
+
+// UpdateCustomer godoc
+// @Summary Изменение покупателя
+// @Description Обновляет карточку покупателя
+// @Tags customer
+// @Accept			json
+// @Produce		json
+// @Param customer_id	path		string	true	"customer id"
+// @Param request body delivery.CustomerModelRequest true "Карточка покупателя"
+// @Success 200 {object} delivery.CustomerModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /customer/{customer_id} [put]
+func (ch *ICustomerHandler) UpdateCustomer(c echo.Context) error {
+	reqBody := delivery.CustomerModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ch.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	customerId, err := strconv.ParseUint(c.Param("customer_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	customer, err := ch.customerUsecase.UpdateCustomer(&reqBody, userId, customerId)
+	if err != nil {
+		ch.logger.Error(fmt.Sprintf("Can't update customer: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, customer)
+}
+
+// GetAllCustomers godoc
+// @Summary Получение покупателей
+// @Description Возвращает справочник покупателей владельца
+// @Tags customer
+// @Accept			json
+// @Produce		json
+// @Success 200 {object} map[string]string "[]delivery.CustomerModelResponse"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /customer [get]
+func (ch *ICustomerHandler) GetAllCustomers(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	customers, err := ch.customerUsecase.GetAllCustomers(userId)
+	if err != nil {
+		ch.logger.Error(fmt.Sprintf("Can't get customers: %v", err))
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, customers)
+}
+
+// GetCustomer godoc
+// @Summary Получение покупателя
+// @Description Возвращает карточку покупателя
+// @Tags customer
+// @Accept			json
+// @Produce		json
+// @Param customer_id	path		string	true	"customer id"
+// @Success 200 {object} delivery.CustomerModelResponse
+// @Failure 400 {object} map[string]string "error: customer not found"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /customer/{customer_id} [get]
+func (ch *ICustomerHandler) GetCustomer(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	customerId, err := strconv.ParseUint(c.Param("customer_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	customer, err := ch.customerUsecase.GetCustomer(userId, customerId)
+	if err != nil {
+		ch.logger.Error(fmt.Sprintf("Can't get customer: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, customer)
+}
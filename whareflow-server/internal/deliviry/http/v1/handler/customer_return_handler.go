@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type CustomerReturnHandler interface {
+	CreateReturn(echo.Context) error
+	GetAllReturns(echo.Context) error
+	GetReturn(echo.Context) error
+	PostReturn(echo.Context) error
+}
+
+type ICustomerReturnHandler struct {
+	logger                slog.Logger
+	customerReturnUsecase usecase.CustomerReturnUsecase
+}
+
+func NewICustomerReturnHandler(logger slog.Logger, customerReturnUsecase usecase.CustomerReturnUsecase) *ICustomerReturnHandler {
+	return &ICustomerReturnHandler{
+		logger:                logger,
+		customerReturnUsecase: customerReturnUsecase,
+	}
+}
+
+// CreateReturn godoc
+// @Summary Создание возврата
+// @Description Создает черновик возврата покупателя по отгруженной отгрузке с решением по каждой строке
+// @Tags return
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.ReturnModelRequest true "Данные возврата"
+// @Success 200 {object} delivery.ReturnModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/return [post]
+func (rh *ICustomerReturnHandler) CreateReturn(c echo.Context) error {
+	reqBody := delivery.ReturnModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		rh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	customerReturn, err := rh.customerReturnUsecase.CreateReturn(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't create return: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, customerReturn)
+}
+
+// GetAllReturns godoc
+// @Summary Получение возвратов склада
+// @Description Возвращает возвраты покупателей по складу
+// @Tags return
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.ReturnModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/return [get]
+func (rh *ICustomerReturnHandler) GetAllReturns(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	returns, err := rh.customerReturnUsecase.GetAllReturns(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"returns": returns,
+	})
+}
+
+// GetReturn godoc
+// @Summary Получение возврата
+// @Description Возвращает документ возврата со строками
+// @Tags return
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param return_id	path		string	true	"return id"
+// @Success 200 {object} delivery.ReturnModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/return/{return_id} [get]
+func (rh *ICustomerReturnHandler) GetReturn(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, returnId, err := parseDocumentParams(c, "return_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	customerReturn, err := rh.customerReturnUsecase.GetReturn(userId, warehouseId, returnId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, customerReturn)
+}
+
+// PostReturn godoc
+// @Summary Проведение возврата
+// @Description Возвращает товар на остаток исходной позиции (resell) или в карантинную зону под блокировку (repair, scrap)
+// @Tags return
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param return_id	path		string	true	"return id"
+// @Param request body delivery.PostReturnModelRequest false "Серийные номера по строкам"
+// @Success 200 {object} map[string]string "message: return success posted"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/return/{return_id}/post [post]
+func (rh *ICustomerReturnHandler) PostReturn(c echo.Context) error {
+	reqBody := delivery.PostReturnModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		rh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, returnId, err := parseDocumentParams(c, "return_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := rh.customerReturnUsecase.PostReturn(&reqBody, userId, warehouseId, returnId, actorId); err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't post return: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "return success posted")
+}
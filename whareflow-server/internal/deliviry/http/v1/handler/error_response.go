@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"errors"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// customErrorResponse отдает клиенту текст бизнес-ошибки, остальные ошибки скрываются за 500
+func customErrorResponse(c echo.Context, err error) error {
+	var customErr *custom_errors.CustomError
+	if errors.As(err, &customErr) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": customErr.Message,
+		})
+	}
+
+	return c.JSON(http.StatusInternalServerError, "")
+}
@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type InventoryCountHandler interface {
+	CreateInventoryCount(echo.Context) error
+	GetAllInventoryCounts(echo.Context) error
+	GetInventoryCount(echo.Context) error
+	SubmitCount(echo.Context) error
+	ApproveInventoryCount(echo.Context) error
+	CancelInventoryCount(echo.Context) error
+	GetVarianceReport(echo.Context) error
+}
+
+type IInventoryCountHandler struct {
+	logger                slog.Logger
+	inventoryCountUsecase usecase.InventoryCountUsecase
+}
+
+func NewIInventoryCountHandler(logger slog.Logger, inventoryCountUsecase usecase.InventoryCountUsecase) *IInventoryCountHandler {
+	return &IInventoryCountHandler{
+		logger:                logger,
+		inventoryCountUsecase: inventoryCountUsecase,
+	}
+}
+
+// CreateInventoryCount godoc
+// @Summary Открытие пересчета
+// @Description Открывает сессию пересчета по складу или по одной зоне и фиксирует учетные остатки
+// @Tags inventory
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.InventoryCountModelRequest true "Зона (необязательно) и комментарий"
+// @Success 200 {object} delivery.InventoryCountModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/inventory [post]
+func (ih *IInventoryCountHandler) CreateInventoryCount(c echo.Context) error {
+	reqBody := delivery.InventoryCountModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ih.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	count, err := ih.inventoryCountUsecase.CreateInventoryCount(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		ih.logger.Error(fmt.Sprintf("Can't create inventory count: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, count)
+}
+
+// GetAllInventoryCounts godoc
+// @Summary Получение списка пересчетов
+// @Description Возвращает все сессии пересчета склада
+// @Tags inventory
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.InventoryCountModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/inventory [get]
+func (ih *IInventoryCountHandler) GetAllInventoryCounts(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	counts, err := ih.inventoryCountUsecase.GetAllInventoryCounts(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"inventory_counts": counts,
+	})
+}
+
+// GetInventoryCount godoc
+// @Summary Получение пересчета
+// @Description Возвращает сессию пересчета со строками
+// @Tags inventory
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param count_id	path		string	true	"inventory count id"
+// @Success 200 {object} delivery.InventoryCountModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/inventory/{count_id} [get]
+func (ih *IInventoryCountHandler) GetInventoryCount(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, countId, err := parseDocumentParams(c, "count_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	count, err := ih.inventoryCountUsecase.GetInventoryCount(userId, warehouseId, countId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, count)
+}
+
+// SubmitCount godoc
+// @Summary Отправка фактического количества
+// @Description Записывает посчитанное количество товара, определенного по QR-коду или uuid
+// @Tags inventory
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param count_id	path		string	true	"inventory count id"
+// @Param request body delivery.SubmitCountModelRequest true "Содержимое QR-кода и количество"
+// @Success 200 {object} map[string]string "message: count success submitted"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/inventory/{count_id}/scan [post]
+func (ih *IInventoryCountHandler) SubmitCount(c echo.Context) error {
+	reqBody := delivery.SubmitCountModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ih.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, countId, err := parseDocumentParams(c, "count_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := ih.inventoryCountUsecase.SubmitCount(&reqBody, userId, warehouseId, countId, actorId); err != nil {
+		ih.logger.Error(fmt.Sprintf("Can't submit count: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "count success submitted")
+}
+
+// ApproveInventoryCount godoc
+// @Summary Утверждение пересчета
+// @Description Закрывает пересчет, корректирует остатки посчитанных товаров и возвращает отчет о расхождениях
+// @Tags inventory
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param count_id	path		string	true	"inventory count id"
+// @Success 200 {object} delivery.VarianceReportResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/inventory/{count_id}/approve [post]
+func (ih *IInventoryCountHandler) ApproveInventoryCount(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, countId, err := parseDocumentParams(c, "count_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	report, err := ih.inventoryCountUsecase.ApproveInventoryCount(userId, warehouseId, countId, actorId)
+	if err != nil {
+		ih.logger.Error(fmt.Sprintf("Can't approve inventory count: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// CancelInventoryCount godoc
+// @Summary Отмена пересчета
+// @Description Закрывает пересчет без изменения остатков
+// @Tags inventory
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param count_id	path		string	true	"inventory count id"
+// @Success 200 {object} map[string]string "message: inventory count success cancelled"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/inventory/{count_id}/cancel [post]
+func (ih *IInventoryCountHandler) CancelInventoryCount(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, countId, err := parseDocumentParams(c, "count_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := ih.inventoryCountUsecase.CancelInventoryCount(userId, warehouseId, countId); err != nil {
+		ih.logger.Error(fmt.Sprintf("Can't cancel inventory count: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "inventory count success cancelled")
+}
+
+// GetVarianceReport godoc
+// @Summary Отчет о расхождениях пересчета
+// @Description Сравнивает фактическое количество с учетным по посчитанным товарам
+// @Tags inventory
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param count_id	path		string	true	"inventory count id"
+// @Success 200 {object} delivery.VarianceReportResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/inventory/{count_id}/variance [get]
+func (ih *IInventoryCountHandler) GetVarianceReport(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, countId, err := parseDocumentParams(c, "count_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	report, err := ih.inventoryCountUsecase.GetVarianceReport(userId, warehouseId, countId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type KitHandler interface {
+	GetKit(echo.Context) error
+	UpdateKit(echo.Context) error
+	AssembleKit(echo.Context) error
+	DisassembleKit(echo.Context) error
+	GetAllKitOperations(echo.Context) error
+	GetKitOperation(echo.Context) error
+}
+
+type IKitHandler struct {
+	logger     slog.Logger
+	kitUsecase usecase.KitUsecase
+}
+
+func NewIKitHandler(logger slog.Logger, kitUsecase usecase.KitUsecase) *IKitHandler {
+	return &IKitHandler{
+		logger:     logger,
+		kitUsecase: kitUsecase,
+	}
+}
+
+// GetKit godoc
+// @Summary Получение спецификации набора
+// @Description Возвращает компоненты набора и их количество на один набор в базовых единицах
+// @Tags kit
+// @Accept			json
+// @Produce		json
+// @Param sku_id	path		string	true	"sku id набора"
+// @Success 200 {object} delivery.KitModelResponse
+// @Failure 400 {object} map[string]string "error: sku not found"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /sku/{sku_id}/kit [get]
+func (kh *IKitHandler) GetKit(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	skuId, err := strconv.ParseUint(c.Param("sku_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	kit, err := kh.kitUsecase.GetKit(userId, skuId)
+	if err != nil {
+		kh.logger.Error(fmt.Sprintf("Can't get kit: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, kit)
+}
+
+// UpdateKit godoc
+// @Summary Изменение спецификации набора
+// @Description Заменяет список компонентов набора целиком, пустой список удаляет спецификацию. Серийные позиции в наборах не участвуют
+// @Tags kit
+// @Accept			json
+// @Produce		json
+// @Param sku_id	path		string	true	"sku id набора"
+// @Param request body delivery.KitModelRequest true "Компоненты набора"
+// @Success 200 {object} delivery.KitModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /sku/{sku_id}/kit [put]
+func (kh *IKitHandler) UpdateKit(c echo.Context) error {
+	reqBody := delivery.KitModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		kh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	skuId, err := strconv.ParseUint(c.Param("sku_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	kit, err := kh.kitUsecase.UpdateKit(&reqBody, userId, skuId)
+	if err != nil {
+		kh.logger.Error(fmt.Sprintf("Can't update kit: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, kit)
+}
+
+// AssembleKit godoc
+// @Summary Сборка наборов
+// @Description Списывает компоненты со склада по FEFO и приходует наборы в зону одной транзакцией
+// @Tags kit
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.AssembleKitModelRequest true "Данные сборки"
+// @Success 200 {object} delivery.KitOperationModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/kit/assemble [post]
+func (kh *IKitHandler) AssembleKit(c echo.Context) error {
+	reqBody := delivery.AssembleKitModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		kh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	operation, err := kh.kitUsecase.AssembleKit(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		kh.logger.Error(fmt.Sprintf("Can't assemble kit: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, operation)
+}
+
+// DisassembleKit godoc
+// @Summary Разборка наборов
+// @Description Списывает наборы со строки товара и приходует компоненты по текущей спецификации одной транзакцией
+// @Tags kit
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.DisassembleKitModelRequest true "Данные разборки"
+// @Success 200 {object} delivery.KitOperationModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/kit/disassemble [post]
+func (kh *IKitHandler) DisassembleKit(c echo.Context) error {
+	reqBody := delivery.DisassembleKitModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		kh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	operation, err := kh.kitUsecase.DisassembleKit(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		kh.logger.Error(fmt.Sprintf("Can't disassemble kit: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, operation)
+}
+
+// GetAllKitOperations godoc
+// @Summary Получение операций с наборами
+// @Description Возвращает сборки и разборки наборов на складе от новых к старым
+// @Tags kit
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.KitOperationModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/kit [get]
+func (kh *IKitHandler) GetAllKitOperations(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	operations, err := kh.kitUsecase.GetAllKitOperations(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"operations": operations,
+	})
+}
+
+// GetKitOperation godoc
+// @Summary Получение операции с наборами
+// @Description Возвращает сборку или разборку со списком движений по строкам товара
+// @Tags kit
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param operation_id	path		string	true	"operation id"
+// @Success 200 {object} delivery.KitOperationModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/kit/{operation_id} [get]
+func (kh *IKitHandler) GetKitOperation(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, operationId, err := parseDocumentParams(c, "operation_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	operation, err := kh.kitUsecase.GetKitOperation(userId, warehouseId, operationId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, operation)
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+)
+
+type LocationHandler interface {
+	CreateLocation(echo.Context) error
+	UpdateLocation(echo.Context) error
+	GetLocationTree(echo.Context) error
+	DeleteLocation(echo.Context) error
+	GetLocationQr(echo.Context) error
+}
+
+type ILocationHandler struct {
+	logger          slog.Logger
+	locationUsecase usecase.LocationUsecase
+}
+
+func NewILocationHandler(logger slog.Logger, locationUsecase usecase.LocationUsecase) *ILocationHandler {
+	return &ILocationHandler{
+		logger:          logger,
+		locationUsecase: locationUsecase,
+	}
+}
+
+// CreateLocation godoc
+// @Summary Создание адреса хранения
+// @Description Создает ряд, стеллаж, полку или ячейку в зоне. Вложенный узел должен быть глубже родителя, у ячейки появляется QR-код
+// @Tags location
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param zone_id	path		string	true	"zone id"
+// @Param request body delivery.LocationModelRequest true "Данные узла"
+// @Success 200 {object} delivery.LocationModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/zone/{zone_id}/location [post]
+func (lh *ILocationHandler) CreateLocation(c echo.Context) error {
+	reqBody := delivery.LocationModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		lh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, zoneId, err := parseDocumentParams(c, "zone_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	location, err := lh.locationUsecase.CreateLocation(&reqBody, userId, warehouseId, zoneId)
+	if err != nil {
+		lh.logger.Error(fmt.Sprintf("Can't create location: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, location)
+}
+
+// UpdateLocation godoc
+// @Summary Изменение адреса хранения
+// @Description Меняет код и вместимость узла. Вместимость не может быть меньше уже размещенного остатка
+// @Tags location
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param zone_id	path		string	true	"zone id"
+// @Param location_id	path		string	true	"location id"
+// @Param request body delivery.LocationModelRequest true "Данные узла"
+// @Success 200 {object} map[string]string "message: location updated"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/zone/{zone_id}/location/{location_id} [put]
+func (lh *ILocationHandler) UpdateLocation(c echo.Context) error {
+	reqBody := delivery.LocationModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		lh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, zoneId, locationId, err := parseLocationParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := lh.locationUsecase.UpdateLocation(&reqBody, userId, warehouseId, zoneId, locationId); err != nil {
+		lh.logger.Error(fmt.Sprintf("Can't update location: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "location updated",
+	})
+}
+
+// GetLocationTree godoc
+// @Summary Получение адресов хранения зоны
+// @Description Возвращает дерево адресов зоны с заполненностью каждого узла
+// @Tags location
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param zone_id	path		string	true	"zone id"
+// @Success 200 {object} map[string]string "[]delivery.LocationModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/zone/{zone_id}/location [get]
+func (lh *ILocationHandler) GetLocationTree(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, zoneId, err := parseDocumentParams(c, "zone_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	locations, err := lh.locationUsecase.GetLocationTree(userId, warehouseId, zoneId)
+	if err != nil {
+		lh.logger.Error(fmt.Sprintf("Can't get locations: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, locations)
+}
+
+// DeleteLocation godoc
+// @Summary Удаление адреса хранения
+// @Description Удаляет узел вместе с вложенными узлами, если в них нет остатков
+// @Tags location
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param zone_id	path		string	true	"zone id"
+// @Param location_id	path		string	true	"location id"
+// @Success 200 {object} map[string]string "message: location deleted"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/zone/{zone_id}/location/{location_id} [delete]
+func (lh *ILocationHandler) DeleteLocation(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, zoneId, locationId, err := parseLocationParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := lh.locationUsecase.DeleteLocation(userId, warehouseId, zoneId, locationId); err != nil {
+		lh.logger.Error(fmt.Sprintf("Can't delete location: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "location deleted",
+	})
+}
+
+// GetLocationQr godoc
+// @Summary QR-код ячейки
+// @Description Возвращает QR-код ячейки в base64 для печати этикетки
+// @Tags location
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param zone_id	path		string	true	"zone id"
+// @Param location_id	path		string	true	"location id"
+// @Success 200 {object} delivery.LocationQrResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/zone/{zone_id}/location/{location_id}/qr [get]
+func (lh *ILocationHandler) GetLocationQr(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, zoneId, locationId, err := parseLocationParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	qrRes, err := lh.locationUsecase.GetLocationQr(userId, warehouseId, zoneId, locationId)
+	if err != nil {
+		lh.logger.Error(fmt.Sprintf("Can't get location qr: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, qrRes)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"strconv"
+)
+
+// parseDocumentParams разбирает id склада и id складского документа из пути запроса
+func parseDocumentParams(c echo.Context, documentParam string) (int, uint64, error) {
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	documentId, err := strconv.ParseUint(c.Param(documentParam), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return warehouseId, documentId, nil
+}
+
+// parseLocationParams разбирает id склада, зоны и узла адреса хранения
+func parseLocationParams(c echo.Context) (int, uint64, uint64, error) {
+	warehouseId, zoneId, err := parseDocumentParams(c, "zone_id")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	locationId, err := strconv.ParseUint(c.Param("location_id"), 10, 64)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return warehouseId, zoneId, locationId, nil
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ParcelHandler interface {
+	CreateParcel(echo.Context) error
+	DeleteParcel(echo.Context) error
+	GetParcels(echo.Context) error
+	GetParcelLabel(echo.Context) error
+}
+
+type IParcelHandler struct {
+	logger        slog.Logger
+	parcelUsecase usecase.ParcelUsecase
+}
+
+func NewIParcelHandler(logger slog.Logger, parcelUsecase usecase.ParcelUsecase) *IParcelHandler {
+	return &IParcelHandler{
+		logger:        logger,
+		parcelUsecase: parcelUsecase,
+	}
+}
+
+// CreateParcel godoc
+// @Summary Добавление грузового места
+// @Description Укладывает часть собранного товара упакованной отгрузки в грузовое место с весом и габаритами. Без weight_kg вес считается по карточкам SKU
+// @Tags parcel
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param shipment_id	path		string	true	"shipment id"
+// @Param request body delivery.ParcelModelRequest true "Содержимое, вес и габариты места"
+// @Success 200 {object} delivery.ParcelModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment/{shipment_id}/parcel [post]
+func (ph *IParcelHandler) CreateParcel(c echo.Context) error {
+	reqBody := delivery.ParcelModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ph.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, shipmentId, err := parseDocumentParams(c, "shipment_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	parcel, err := ph.parcelUsecase.CreateParcel(&reqBody, userId, warehouseId, shipmentId, actorId)
+	if err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't create parcel: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, parcel)
+}
+
+// DeleteParcel godoc
+// @Summary Удаление грузового места
+// @Description Удаляет место из упакованной отгрузки, номера следующих мест уменьшаются на единицу
+// @Tags parcel
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param shipment_id	path		string	true	"shipment id"
+// @Param parcel_id	path		string	true	"parcel id"
+// @Success 200 {object} map[string]string "message: parcel success deleted"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment/{shipment_id}/parcel/{parcel_id} [delete]
+func (ph *IParcelHandler) DeleteParcel(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, shipmentId, err := parseDocumentParams(c, "shipment_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	parcelId, err := strconv.ParseUint(c.Param("parcel_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := ph.parcelUsecase.DeleteParcel(userId, warehouseId, shipmentId, parcelId); err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't delete parcel: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "parcel success deleted")
+}
+
+// GetParcels godoc
+// @Summary Получение грузовых мест отгрузки
+// @Description Возвращает места отгрузки по порядку номеров с содержимым в единицах строк отгрузки
+// @Tags parcel
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param shipment_id	path		string	true	"shipment id"
+// @Success 200 {object} map[string]string "[]delivery.ParcelModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment/{shipment_id}/parcel [get]
+func (ph *IParcelHandler) GetParcels(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, shipmentId, err := parseDocumentParams(c, "shipment_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	parcels, err := ph.parcelUsecase.GetParcels(userId, warehouseId, shipmentId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"parcels": parcels,
+	})
+}
+
+// GetParcelLabel godoc
+// @Summary Транспортная этикетка грузового места
+// @Description Возвращает этикетку 4x6 дюйма в ZPL для термопринтера: отправитель, получатель, номер места, вес, габариты и штрихкод заказа
+// @Tags parcel
+// @Produce		plain
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param shipment_id	path		string	true	"shipment id"
+// @Param parcel_id	path		string	true	"parcel id"
+// @Success 200 {string} string "ZPL"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment/{shipment_id}/parcel/{parcel_id}/label [get]
+func (ph *IParcelHandler) GetParcelLabel(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, shipmentId, err := parseDocumentParams(c, "shipment_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	parcelId, err := strconv.ParseUint(c.Param("parcel_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	data, err := ph.parcelUsecase.GetParcelLabel(userId, warehouseId, shipmentId, parcelId)
+	if err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't render parcel label: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=parcel-%d.zpl", parcelId))
+	return c.Blob(http.StatusOK, "application/zpl", data)
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type PickListHandler interface {
+	GetWalkPath(echo.Context) error
+	UpdateWalkPath(echo.Context) error
+	CreatePickList(echo.Context) error
+	GetAllPickLists(echo.Context) error
+	GetPickList(echo.Context) error
+}
+
+type IPickListHandler struct {
+	logger          slog.Logger
+	pickListUsecase usecase.PickListUsecase
+}
+
+func NewIPickListHandler(logger slog.Logger, pickListUsecase usecase.PickListUsecase) *IPickListHandler {
+	return &IPickListHandler{
+		logger:          logger,
+		pickListUsecase: pickListUsecase,
+	}
+}
+
+// GetWalkPath godoc
+// @Summary Получение маршрута обхода склада
+// @Description Возвращает шаги маршрута, по которому упорядочиваются остановки листов сборки
+// @Tags pick
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.WalkPathStepModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/pick/walk_path [get]
+func (ph *IPickListHandler) GetWalkPath(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	steps, err := ph.pickListUsecase.GetWalkPath(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"steps": steps,
+	})
+}
+
+// UpdateWalkPath godoc
+// @Summary Изменение маршрута обхода склада
+// @Description Заменяет маршрут целиком. Шаги перечисляются в порядке обхода, шаг без location_id покрывает всю зону
+// @Tags pick
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.WalkPathModelRequest true "Шаги маршрута"
+// @Success 200 {object} map[string]string "[]delivery.WalkPathStepModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/pick/walk_path [put]
+func (ph *IPickListHandler) UpdateWalkPath(c echo.Context) error {
+	reqBody := delivery.WalkPathModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ph.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	steps, err := ph.pickListUsecase.UpdateWalkPath(&reqBody, userId, warehouseId)
+	if err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't update walk path: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"steps": steps,
+	})
+}
+
+// CreatePickList godoc
+// @Summary Формирование листа сборки
+// @Description Собирает в лист невыполненные задания на сборку и раскладывает их по маршруту обхода. Задания, которые нельзя собрать из незаблокированного остатка, остаются в очереди
+// @Tags pick
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.PickListModelRequest true "Заказы и предел числа заданий"
+// @Success 200 {object} delivery.PickListModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/pick [post]
+func (ph *IPickListHandler) CreatePickList(c echo.Context) error {
+	reqBody := delivery.PickListModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ph.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	list, err := ph.pickListUsecase.CreatePickList(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't create pick list: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetAllPickLists godoc
+// @Summary Получение листов сборки
+// @Description Возвращает листы сборки склада без остановок. status ограничивает выборку open или done
+// @Tags pick
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param status	query		string	false	"pick list status"
+// @Success 200 {object} map[string]string "[]delivery.PickListModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/pick [get]
+func (ph *IPickListHandler) GetAllPickLists(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	lists, err := ph.pickListUsecase.GetAllPickLists(userId, warehouseId, c.QueryParam("status"))
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"lists": lists,
+	})
+}
+
+// GetPickList godoc
+// @Summary Получение листа сборки
+// @Description Возвращает остановки листа по маршруту обхода склада с заданиями каждой остановки
+// @Tags pick
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param list_id	path		string	true	"pick list id"
+// @Success 200 {object} delivery.PickListModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/pick/{list_id} [get]
+func (ph *IPickListHandler) GetPickList(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, listId, err := parseDocumentParams(c, "list_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	list, err := ph.pickListUsecase.GetPickList(userId, warehouseId, listId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
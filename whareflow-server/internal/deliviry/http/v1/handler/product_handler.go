@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
 	"github.com/Miroslovelife/whareflow/internal/usecase"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 	"net/http"
 	"strconv"
 )
@@ -15,6 +17,10 @@ type ProductHandler interface {
 	GetAllProductsFromZone(echo.Context) error
 	GetAllProductsFromWarehouse(echo.Context) error
 	UpdateProduct(echo.Context) error
+	GetProductMovements(echo.Context) error
+	MoveProduct(echo.Context) error
+	GetFefoSuggestion(echo.Context) error
+	GetExpiringProducts(echo.Context) error
 	//DeleteProduct(echo.Context) error
 }
 
@@ -52,16 +58,15 @@ func (ph *IProductHandler) CreateProduct(c echo.Context) error {
 	}
 
 	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
 
 	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, "")
 	}
 
-
-
-	if err := ph.productUsecase.CreateProduct(reqBody, userId, warehouseId, reqBody.ZoneId); err != nil {
-		return c.JSON(http.StatusInternalServerError, "")
+	if err := ph.productUsecase.CreateProduct(reqBody, userId, warehouseId, reqBody.ZoneId, actorId); err != nil {
+		return customErrorResponse(c, err)
 	}
 
 	return c.JSON(http.StatusOK, "product success created")
@@ -76,6 +81,7 @@ func (ph *IProductHandler) CreateProduct(c echo.Context) error {
 // @Param warehouse_id	path		string	true	"warehouse id"
 // @Param zone_id	path		string	true	"zone id"
 // @Param product_id	path		string	true	"product id"
+// @Param unit	query		string	false	"единица, в которой вернуть quantity"
 // @Success 200 {object} map[string]string "delivery.ProductModelResponse"
 // @Failure 400 {object} map[string]string "error: invalid request body"
 // @Failure 500 {object} map[string]string "error: internal server error"
@@ -86,9 +92,9 @@ func (ph *IProductHandler) GetProduct(c echo.Context) error {
 	productId := c.Param("product_id")
 
 	fmt.Println(productId, userId)
-	product, err := ph.productUsecase.FindProduct(userId, productId)
+	product, err := ph.productUsecase.FindProduct(userId, productId, c.QueryParam("unit"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "")
+		return customErrorResponse(c, err)
 	}
 
 	return c.JSON(http.StatusOK, product)
@@ -102,6 +108,7 @@ func (ph *IProductHandler) GetProduct(c echo.Context) error {
 // @Produce		json
 // @Param warehouse_id	path		string	true	"warehouse id"
 // @Param zone_id	path		string	true	"zone id"
+// @Param unit	query		string	false	"единица, в которой вернуть quantity"
 // @Success 200 {object} map[string]string "[]delivery.ProductModelResponse"
 // @Failure 400 {object} map[string]string "error: invalid request body"
 // @Failure 500 {object} map[string]string "error: internal server error"
@@ -116,9 +123,9 @@ func (ph *IProductHandler) GetAllProductsFromZone(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, "")
 	}
 
-	products, err := ph.productUsecase.FindAllProductFromZone(userId, zoneId)
+	products, err := ph.productUsecase.FindAllProductFromZone(userId, zoneId, c.QueryParam("unit"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "")
+		return customErrorResponse(c, err)
 	}
 
 	return c.JSON(http.StatusOK, products)
@@ -131,6 +138,7 @@ func (ph *IProductHandler) GetAllProductsFromZone(c echo.Context) error {
 // @Accept			json
 // @Produce		json
 // @Param warehouse_id	path		string	true	"warehouse id"
+// @Param unit	query		string	false	"единица, в которой вернуть quantity"
 // @Success 200 {object} map[string]string "[]delivery.ProductModelResponse"
 // @Failure 400 {object} map[string]string "error: invalid request body"
 // @Failure 500 {object} map[string]string "error: internal server error"
@@ -145,9 +153,9 @@ func (ph *IProductHandler) GetAllProductsFromWarehouse(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, "")
 	}
 
-	products, err := ph.productUsecase.FindAllProductFromWarehouse(userId, warehouseId)
+	products, err := ph.productUsecase.FindAllProductFromWarehouse(userId, warehouseId, c.QueryParam("unit"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "")
+		return customErrorResponse(c, err)
 	}
 
 	return c.JSON(http.StatusOK, products)
@@ -169,6 +177,7 @@ func (ph *IProductHandler) GetAllProductsFromWarehouse(c echo.Context) error {
 // @Router /warehouse/{warehouse_id}/product/{product_id}  [put]
 func (ph *IProductHandler) UpdateProduct(c echo.Context) error {
 	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
 	productId := c.Param("product_id")
 	reqBody := &delivery.ProductModelRequest{}
 
@@ -183,10 +192,163 @@ func (ph *IProductHandler) UpdateProduct(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, "")
 	}
 
-	err = ph.productUsecase.UpdateProduct(reqBody, warehouseId, productId, userId)
+	err = ph.productUsecase.UpdateProduct(reqBody, warehouseId, productId, userId, actorId)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "")
+		return customErrorResponse(c, err)
 	}
 
 	return c.JSON(http.StatusOK, "product success updated")
 }
+
+// GetProductMovements godoc
+// @Summary Получение истории движений товара
+// @Description Возвращает журнал движений товара и сверку остатка с журналом
+// @Tags product
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param product_id	path		string	true	"product id"
+// @Success 200 {object} delivery.StockMovementListResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/product/{product_id}/movements [get]
+func (ph *IProductHandler) GetProductMovements(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	productId := c.Param("product_id")
+
+	movements, err := ph.productUsecase.FindProductMovements(userId, productId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "product not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, movements)
+}
+
+// MoveProduct godoc
+// @Summary Перемещение товара между зонами
+// @Description Переносит весь остаток или его часть в другую зону склада с проверкой вместимости. При частичном переносе создается новая строка товара
+// @Tags product
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param product_id	path		string	true	"product id"
+// @Param request body delivery.MoveProductModelRequest true "Целевая зона и количество (0 - весь остаток)"
+// @Success 200 {object} delivery.ProductModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/product/{product_id}/move [post]
+func (ph *IProductHandler) MoveProduct(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+	productId := c.Param("product_id")
+	reqBody := delivery.MoveProductModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	product, err := ph.productUsecase.MoveProduct(&reqBody, warehouseId, productId, userId, actorId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+// GetFefoSuggestion godoc
+// @Summary Подбор партий по FEFO
+// @Description Предлагает партии товара под выдачу: первыми идут партии с ближайшим сроком годности, партии без срока - в конце
+// @Tags product
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param sku_id	query		int	true	"позиция каталога"
+// @Param quantity	query		number	true	"требуемое количество"
+// @Param unit	query		string	false	"единица количества, по умолчанию базовая"
+// @Success 200 {object} delivery.FefoSuggestionResponse
+// @Failure 400 {object} map[string]string "error: invalid request params"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/product/fefo [get]
+func (ph *IProductHandler) GetFefoSuggestion(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	skuId, err := strconv.ParseUint(c.QueryParam("sku_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request params",
+		})
+	}
+
+	quantity, err := strconv.ParseFloat(c.QueryParam("quantity"), 64)
+	if err != nil || quantity <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request params",
+		})
+	}
+
+	suggestion, err := ph.productUsecase.SuggestFefo(userId, warehouseId, skuId, quantity, c.QueryParam("unit"))
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, suggestion)
+}
+
+// GetExpiringProducts godoc
+// @Summary Отчет по истекающим срокам годности
+// @Description Возвращает партии со сроком годности, истекающим в ближайшие days дней (по умолчанию 30), включая уже просроченные
+// @Tags product
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param days	query		int	false	"горизонт в днях"
+// @Success 200 {object} delivery.ExpiringReportResponse
+// @Failure 400 {object} map[string]string "error: invalid request params"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/expiring [get]
+func (ph *IProductHandler) GetExpiringProducts(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	days := 30
+	if c.QueryParam("days") != "" {
+		days, err = strconv.Atoi(c.QueryParam("days"))
+		if err != nil || days < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid request params",
+			})
+		}
+	}
+
+	report, err := ph.productUsecase.FindExpiringProducts(userId, warehouseId, days)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
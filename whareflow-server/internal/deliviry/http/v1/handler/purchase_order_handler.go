@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type PurchaseOrderHandler interface {
+	CreatePurchaseOrder(echo.Context) error
+	UpdatePurchaseOrder(echo.Context) error
+	ClosePurchaseOrder(echo.Context) error
+	GetAllPurchaseOrders(echo.Context) error
+	GetOpenPurchaseOrders(echo.Context) error
+	GetPurchaseOrder(echo.Context) error
+	CreatePurchaseOrderReceipt(echo.Context) error
+}
+
+type IPurchaseOrderHandler struct {
+	logger               slog.Logger
+	purchaseOrderUsecase usecase.PurchaseOrderUsecase
+}
+
+func NewIPurchaseOrderHandler(logger slog.Logger, purchaseOrderUsecase usecase.PurchaseOrderUsecase) *IPurchaseOrderHandler {
+	return &IPurchaseOrderHandler{
+		logger:               logger,
+		purchaseOrderUsecase: purchaseOrderUsecase,
+	}
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Создание заказа поставщику
+// @Description Создает открытый заказ поставщику с ожидаемыми строками и датами поставки
+// @Tags purchase_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.PurchaseOrderModelRequest true "Данные заказа"
+// @Success 200 {object} delivery.PurchaseOrderModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/purchase_order [post]
+func (ph *IPurchaseOrderHandler) CreatePurchaseOrder(c echo.Context) error {
+	reqBody := delivery.PurchaseOrderModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ph.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	order, err := ph.purchaseOrderUsecase.CreatePurchaseOrder(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't create purchase order: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary Изменение заказа поставщику
+// @Description Заменяет поставщика, даты и строки заказа. Доступно, пока по заказу не заведено ни одного поступления
+// @Tags purchase_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param order_id	path		string	true	"order id"
+// @Param request body delivery.PurchaseOrderModelRequest true "Данные заказа"
+// @Success 200 {object} map[string]string "message: purchase order success updated"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/purchase_order/{order_id} [put]
+func (ph *IPurchaseOrderHandler) UpdatePurchaseOrder(c echo.Context) error {
+	reqBody := delivery.PurchaseOrderModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ph.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, orderId, err := parseDocumentParams(c, "order_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := ph.purchaseOrderUsecase.UpdatePurchaseOrder(&reqBody, userId, warehouseId, orderId); err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't update purchase order: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "purchase order success updated")
+}
+
+// ClosePurchaseOrder godoc
+// @Summary Закрытие заказа поставщику
+// @Description Закрывает заказ вручную, непоставленный остаток строк становится недопоставкой
+// @Tags purchase_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param order_id	path		string	true	"order id"
+// @Success 200 {object} map[string]string "message: purchase order success closed"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/purchase_order/{order_id}/close [post]
+func (ph *IPurchaseOrderHandler) ClosePurchaseOrder(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, orderId, err := parseDocumentParams(c, "order_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := ph.purchaseOrderUsecase.ClosePurchaseOrder(userId, warehouseId, orderId); err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't close purchase order: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "purchase order success closed")
+}
+
+// GetAllPurchaseOrders godoc
+// @Summary Получение заказов поставщикам
+// @Description Возвращает все заказы поставщикам по складу
+// @Tags purchase_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.PurchaseOrderModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/purchase_order [get]
+func (ph *IPurchaseOrderHandler) GetAllPurchaseOrders(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	orders, err := ph.purchaseOrderUsecase.GetAllPurchaseOrders(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"orders": orders,
+	})
+}
+
+// GetOpenPurchaseOrders godoc
+// @Summary Отчет по открытым заказам поставщикам
+// @Description Возвращает заказы склада, по которым еще ожидается поставка, по ближайшей дате поставки. Просроченные отмечены overdue
+// @Tags purchase_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.PurchaseOrderModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/purchase_order/open [get]
+func (ph *IPurchaseOrderHandler) GetOpenPurchaseOrders(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	orders, err := ph.purchaseOrderUsecase.GetOpenPurchaseOrders(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"orders": orders,
+	})
+}
+
+// GetPurchaseOrder godoc
+// @Summary Получение заказа поставщику
+// @Description Возвращает заказ с заказанным, принятым, ожидаемым и перепоставленным количеством по строкам
+// @Tags purchase_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param order_id	path		string	true	"order id"
+// @Success 200 {object} delivery.PurchaseOrderModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/purchase_order/{order_id} [get]
+func (ph *IPurchaseOrderHandler) GetPurchaseOrder(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, orderId, err := parseDocumentParams(c, "order_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	order, err := ph.purchaseOrderUsecase.GetPurchaseOrder(userId, warehouseId, orderId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// CreatePurchaseOrderReceipt godoc
+// @Summary Приемка по заказу поставщику
+// @Description Создает черновик поступления на непоставленный остаток заказа. Дальше поступление принимается и проводится как обычно
+// @Tags purchase_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param order_id	path		string	true	"order id"
+// @Param request body delivery.PurchaseOrderReceiptModelRequest true "Зона приемки"
+// @Success 200 {object} delivery.ReceiptModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/purchase_order/{order_id}/receipt [post]
+func (ph *IPurchaseOrderHandler) CreatePurchaseOrderReceipt(c echo.Context) error {
+	reqBody := delivery.PurchaseOrderReceiptModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ph.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, orderId, err := parseDocumentParams(c, "order_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	receipt, err := ph.purchaseOrderUsecase.CreatePurchaseOrderReceipt(&reqBody, userId, warehouseId, orderId, actorId)
+	if err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't create purchase order receipt: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, receipt)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type PutawayHandler interface {
+	GetPutawaySuggestions(echo.Context) error
+	ConfirmPutaway(echo.Context) error
+	GetPutaways(echo.Context) error
+}
+
+type IPutawayHandler struct {
+	logger         slog.Logger
+	putawayUsecase usecase.PutawayUsecase
+}
+
+func NewIPutawayHandler(logger slog.Logger, putawayUsecase usecase.PutawayUsecase) *IPutawayHandler {
+	return &IPutawayHandler{
+		logger:         logger,
+		putawayUsecase: putawayUsecase,
+	}
+}
+
+// GetPutawaySuggestions godoc
+// @Summary Подбор мест размещения
+// @Description Для каждой неразмещенной строки принятого поступления предлагает до 5 зон или ячеек. Места ранжируются по свободной вместимости, совместимости хранения, остатку того же товара и расстоянию по маршруту обхода от места приемки
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param receipt_id	path		string	true	"receipt id"
+// @Success 200 {object} map[string]string "[]delivery.PutawayLineModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt/{receipt_id}/putaway [get]
+func (ph *IPutawayHandler) GetPutawaySuggestions(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, receiptId, err := parseDocumentParams(c, "receipt_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	lines, err := ph.putawayUsecase.GetPutawaySuggestions(userId, warehouseId, receiptId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"lines": lines,
+	})
+}
+
+// ConfirmPutaway godoc
+// @Summary Размещение строки поступления
+// @Description Переносит принятое по строке проведенного поступления количество в выбранную зону или ячейку. Если место отличается от первого предложенного, нужна причина, размещение записывается как отступление от подбора
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param receipt_id	path		string	true	"receipt id"
+// @Param line_id	path		string	true	"receipt line id"
+// @Param request body delivery.PutawayModelRequest true "Место размещения"
+// @Success 200 {object} delivery.PutawayModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt/{receipt_id}/putaway/{line_id} [post]
+func (ph *IPutawayHandler) ConfirmPutaway(c echo.Context) error {
+	reqBody := delivery.PutawayModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		ph.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, receiptId, err := parseDocumentParams(c, "receipt_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	lineId, err := strconv.ParseUint(c.Param("line_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	putaway, err := ph.putawayUsecase.ConfirmPutaway(&reqBody, userId, warehouseId, receiptId, lineId, actorId)
+	if err != nil {
+		ph.logger.Error(fmt.Sprintf("Can't put away receipt line: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, putaway)
+}
+
+// GetPutaways godoc
+// @Summary Размещения по поступлению
+// @Description Возвращает размещенные строки поступления: куда положен товар, что предлагал подбор и причину отступления от него
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param receipt_id	path		string	true	"receipt id"
+// @Success 200 {object} map[string]string "[]delivery.PutawayModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt/{receipt_id}/putaway/history [get]
+func (ph *IPutawayHandler) GetPutaways(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, receiptId, err := parseDocumentParams(c, "receipt_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	putaways, err := ph.putawayUsecase.GetPutaways(userId, warehouseId, receiptId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"putaways": putaways,
+	})
+}
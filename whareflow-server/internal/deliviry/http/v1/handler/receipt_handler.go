@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ReceiptHandler interface {
+	CreateReceipt(echo.Context) error
+	GetAllReceipts(echo.Context) error
+	GetReceipt(echo.Context) error
+	UpdateReceipt(echo.Context) error
+	ReceiveReceipt(echo.Context) error
+	PostReceipt(echo.Context) error
+}
+
+type IReceiptHandler struct {
+	logger         slog.Logger
+	receiptUsecase usecase.ReceiptUsecase
+}
+
+func NewIReceiptHandler(logger slog.Logger, receiptUsecase usecase.ReceiptUsecase) *IReceiptHandler {
+	return &IReceiptHandler{
+		logger:         logger,
+		receiptUsecase: receiptUsecase,
+	}
+}
+
+// CreateReceipt godoc
+// @Summary Создание поступления
+// @Description Создает черновик документа поступления товаров на склад
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.ReceiptModelRequest true "Данные поступления"
+// @Success 200 {object} delivery.ReceiptModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt [post]
+func (rh *IReceiptHandler) CreateReceipt(c echo.Context) error {
+	reqBody := delivery.ReceiptModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		rh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	receipt, err := rh.receiptUsecase.CreateReceipt(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't create receipt: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, receipt)
+}
+
+// GetAllReceipts godoc
+// @Summary Получение списка поступлений
+// @Description Возвращает все документы поступления склада
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.ReceiptModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt [get]
+func (rh *IReceiptHandler) GetAllReceipts(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	receipts, err := rh.receiptUsecase.GetAllReceipts(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"receipts": receipts,
+	})
+}
+
+// GetReceipt godoc
+// @Summary Получение поступления
+// @Description Возвращает документ поступления со строками
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param receipt_id	path		string	true	"receipt id"
+// @Success 200 {object} delivery.ReceiptModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt/{receipt_id} [get]
+func (rh *IReceiptHandler) GetReceipt(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, receiptId, err := parseDocumentParams(c, "receipt_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	receipt, err := rh.receiptUsecase.GetReceipt(userId, warehouseId, receiptId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, receipt)
+}
+
+// UpdateReceipt godoc
+// @Summary Обновление поступления
+// @Description Заменяет строки черновика поступления
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param receipt_id	path		string	true	"receipt id"
+// @Param request body delivery.ReceiptModelRequest true "Данные поступления"
+// @Success 200 {object} map[string]string "message: receipt success updated"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt/{receipt_id} [put]
+func (rh *IReceiptHandler) UpdateReceipt(c echo.Context) error {
+	reqBody := delivery.ReceiptModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		rh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, receiptId, err := parseDocumentParams(c, "receipt_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := rh.receiptUsecase.UpdateReceipt(&reqBody, userId, warehouseId, receiptId); err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't update receipt: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "receipt success updated")
+}
+
+// ReceiveReceipt godoc
+// @Summary Приемка поступления
+// @Description Фиксирует фактически принятое количество и переводит поступление в статус received
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param receipt_id	path		string	true	"receipt id"
+// @Param request body delivery.ReceiveReceiptModelRequest false "Фактическое количество по строкам"
+// @Success 200 {object} map[string]string "message: receipt success received"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt/{receipt_id}/receive [post]
+func (rh *IReceiptHandler) ReceiveReceipt(c echo.Context) error {
+	reqBody := delivery.ReceiveReceiptModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		rh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, receiptId, err := parseDocumentParams(c, "receipt_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := rh.receiptUsecase.ReceiveReceipt(&reqBody, userId, warehouseId, receiptId); err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't receive receipt: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "receipt success received")
+}
+
+// PostReceipt godoc
+// @Summary Проведение поступления
+// @Description Увеличивает остатки в зонах по принятому количеству и создает QR-коды новым товарам
+// @Tags receipt
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param receipt_id	path		string	true	"receipt id"
+// @Success 200 {object} map[string]string "message: receipt success posted"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/receipt/{receipt_id}/post [post]
+func (rh *IReceiptHandler) PostReceipt(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, receiptId, err := parseDocumentParams(c, "receipt_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := rh.receiptUsecase.PostReceipt(userId, warehouseId, receiptId, actorId); err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't post receipt: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "receipt success posted")
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ReorderRuleHandler interface {
+	SetReorderRule(echo.Context) error
+	GetAllReorderRules(echo.Context) error
+	DeleteReorderRule(echo.Context) error
+	GetLowStockReport(echo.Context) error
+}
+
+type IReorderRuleHandler struct {
+	logger             slog.Logger
+	reorderRuleUsecase usecase.ReorderRuleUsecase
+}
+
+func NewIReorderRuleHandler(logger slog.Logger, reorderRuleUsecase usecase.ReorderRuleUsecase) *IReorderRuleHandler {
+	return &IReorderRuleHandler{
+		logger:             logger,
+		reorderRuleUsecase: reorderRuleUsecase,
+	}
+}
+
+// SetReorderRule godoc
+// @Summary Установка уровней запаса
+// @Description Задает минимальный (точка перезаказа) и максимальный уровни позиции каталога на складе. Повторный вызов для той же позиции меняет уровни
+// @Tags reorder
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.ReorderRuleModelRequest true "Уровни запаса"
+// @Success 200 {object} delivery.ReorderRuleModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/reorder-rule [put]
+func (rh *IReorderRuleHandler) SetReorderRule(c echo.Context) error {
+	reqBody := delivery.ReorderRuleModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		rh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	rule, err := rh.reorderRuleUsecase.SetReorderRule(&reqBody, userId, warehouseId)
+	if err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't set reorder rule: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// GetAllReorderRules godoc
+// @Summary Получение уровней запаса склада
+// @Description Возвращает правила перезаказа склада
+// @Tags reorder
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.ReorderRuleModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/reorder-rule [get]
+func (rh *IReorderRuleHandler) GetAllReorderRules(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	rules, err := rh.reorderRuleUsecase.GetAllReorderRules(userId, warehouseId)
+	if err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't get reorder rules: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+// DeleteReorderRule godoc
+// @Summary Удаление уровней запаса
+// @Description Удаляет правило перезаказа, оповещения по позиции прекращаются
+// @Tags reorder
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param rule_id	path		string	true	"rule id"
+// @Success 200 {object} map[string]string "message: reorder rule deleted"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/reorder-rule/{rule_id} [delete]
+func (rh *IReorderRuleHandler) DeleteReorderRule(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, ruleId, err := parseDocumentParams(c, "rule_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := rh.reorderRuleUsecase.DeleteReorderRule(userId, warehouseId, ruleId); err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't delete reorder rule: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "reorder rule deleted",
+	})
+}
+
+// GetLowStockReport godoc
+// @Summary Отчет по низким остаткам
+// @Description Возвращает позиции склада, остаток которых ниже точки перезаказа, и количество для дозаказа до максимального уровня
+// @Tags reorder
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} delivery.LowStockReportResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/low-stock [get]
+func (rh *IReorderRuleHandler) GetLowStockReport(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	report, err := rh.reorderRuleUsecase.GetLowStockReport(userId, warehouseId)
+	if err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't get low stock report: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
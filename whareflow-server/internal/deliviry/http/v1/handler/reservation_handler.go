@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ReservationHandler interface {
+	CreateReservation(echo.Context) error
+	GetAllReservations(echo.Context) error
+	ReleaseReservation(echo.Context) error
+}
+
+type IReservationHandler struct {
+	logger             slog.Logger
+	reservationUsecase usecase.ReservationUsecase
+}
+
+func NewIReservationHandler(logger slog.Logger, reservationUsecase usecase.ReservationUsecase) *IReservationHandler {
+	return &IReservationHandler{
+		logger:             logger,
+		reservationUsecase: reservationUsecase,
+	}
+}
+
+// CreateReservation godoc
+// @Summary Резервирование товара
+// @Description Резервирует часть доступного остатка товара за заказом или другим документом до истечения срока
+// @Tags reservation
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.ReservationModelRequest true "Данные резерва"
+// @Success 200 {object} delivery.ReservationModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/reservation [post]
+func (rh *IReservationHandler) CreateReservation(c echo.Context) error {
+	reqBody := delivery.ReservationModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		rh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	reservation, err := rh.reservationUsecase.CreateReservation(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't create reservation: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reservation)
+}
+
+// GetAllReservations godoc
+// @Summary Получение резервов склада
+// @Description Возвращает действующие резервы склада, при передаче product_id - только по этому товару
+// @Tags reservation
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param product_id	query		string	false	"product id"
+// @Success 200 {object} map[string]string "[]delivery.ReservationModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/reservation [get]
+func (rh *IReservationHandler) GetAllReservations(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	reservations, err := rh.reservationUsecase.GetAllReservations(userId, warehouseId, c.QueryParam("product_id"))
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"reservations": reservations,
+	})
+}
+
+// ReleaseReservation godoc
+// @Summary Снятие резерва
+// @Description Досрочно снимает действующий резерв
+// @Tags reservation
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param reservation_id	path		string	true	"reservation id"
+// @Success 200 {object} map[string]string "message: reservation success released"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/reservation/{reservation_id} [delete]
+func (rh *IReservationHandler) ReleaseReservation(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, reservationId, err := parseDocumentParams(c, "reservation_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := rh.reservationUsecase.ReleaseReservation(userId, warehouseId, reservationId); err != nil {
+		rh.logger.Error(fmt.Sprintf("Can't release reservation: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "reservation success released")
+}
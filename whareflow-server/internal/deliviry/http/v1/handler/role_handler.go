@@ -140,6 +140,6 @@ func (rh *IRoleHandler) GetAllUserPermissionOnWh(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-	    "permissions": permissions,
+		"permissions": permissions,
 	})
 }
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type SalesOrderHandler interface {
+	CreateSalesOrder(echo.Context) error
+	ConfirmSalesOrder(echo.Context) error
+	CancelSalesOrder(echo.Context) error
+	GetAllSalesOrders(echo.Context) error
+	GetSalesOrder(echo.Context) error
+	GetSalesOrderPickTasks(echo.Context) error
+	GetPickTasks(echo.Context) error
+	CompletePickTask(echo.Context) error
+}
+
+type ISalesOrderHandler struct {
+	logger            slog.Logger
+	salesOrderUsecase usecase.SalesOrderUsecase
+}
+
+func NewISalesOrderHandler(logger slog.Logger, salesOrderUsecase usecase.SalesOrderUsecase) *ISalesOrderHandler {
+	return &ISalesOrderHandler{
+		logger:            logger,
+		salesOrderUsecase: salesOrderUsecase,
+	}
+}
+
+// CreateSalesOrder godoc
+// @Summary Создание заказа покупателя
+// @Description Создает черновик заказа и резервирует товар под каждую строку. Без свободного остатка заказ не создается
+// @Tags sales_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.SalesOrderModelRequest true "Данные заказа"
+// @Success 200 {object} delivery.SalesOrderModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/sales_order [post]
+func (sh *ISalesOrderHandler) CreateSalesOrder(c echo.Context) error {
+	reqBody := delivery.SalesOrderModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	order, err := sh.salesOrderUsecase.CreateSalesOrder(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't create sales order: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// ConfirmSalesOrder godoc
+// @Summary Подтверждение заказа покупателя
+// @Description Заводит по заказу отгрузку и задания на сборку, работники склада с правом product_manage получают оповещение
+// @Tags sales_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param order_id	path		string	true	"order id"
+// @Success 200 {object} map[string]string "message: sales order success confirmed"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/sales_order/{order_id}/confirm [post]
+func (sh *ISalesOrderHandler) ConfirmSalesOrder(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, orderId, err := parseDocumentParams(c, "order_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := sh.salesOrderUsecase.ConfirmSalesOrder(userId, warehouseId, orderId, actorId); err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't confirm sales order: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "sales order success confirmed")
+}
+
+// CancelSalesOrder godoc
+// @Summary Отмена заказа покупателя
+// @Description Отменяет заказ до упаковки: снимает резервы, отменяет задания на сборку и удаляет отгрузку заказа
+// @Tags sales_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param order_id	path		string	true	"order id"
+// @Success 200 {object} map[string]string "message: sales order success cancelled"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/sales_order/{order_id}/cancel [post]
+func (sh *ISalesOrderHandler) CancelSalesOrder(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, orderId, err := parseDocumentParams(c, "order_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := sh.salesOrderUsecase.CancelSalesOrder(userId, warehouseId, orderId); err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't cancel sales order: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "sales order success cancelled")
+}
+
+// GetAllSalesOrders godoc
+// @Summary Получение заказов покупателей
+// @Description Возвращает все заказы покупателей по складу
+// @Tags sales_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.SalesOrderModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/sales_order [get]
+func (sh *ISalesOrderHandler) GetAllSalesOrders(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	orders, err := sh.salesOrderUsecase.GetAllSalesOrders(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"orders": orders,
+	})
+}
+
+// GetSalesOrder godoc
+// @Summary Получение заказа покупателя
+// @Description Возвращает заказ с его строками и резервами
+// @Tags sales_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param order_id	path		string	true	"order id"
+// @Success 200 {object} delivery.SalesOrderModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/sales_order/{order_id} [get]
+func (sh *ISalesOrderHandler) GetSalesOrder(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, orderId, err := parseDocumentParams(c, "order_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	order, err := sh.salesOrderUsecase.GetSalesOrder(userId, warehouseId, orderId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// GetSalesOrderPickTasks godoc
+// @Summary Задания на сборку заказа
+// @Description Возвращает все задания на сборку заказа, включая выполненные и отмененные
+// @Tags sales_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param order_id	path		string	true	"order id"
+// @Success 200 {object} map[string]string "[]delivery.PickTaskModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/sales_order/{order_id}/pick_task [get]
+func (sh *ISalesOrderHandler) GetSalesOrderPickTasks(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, orderId, err := parseDocumentParams(c, "order_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	tasks, err := sh.salesOrderUsecase.GetPickTasks(userId, warehouseId, orderId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tasks": tasks,
+	})
+}
+
+// GetPickTasks godoc
+// @Summary Очередь заданий на сборку
+// @Description Возвращает невыполненные задания на сборку склада вне волн, сгруппированные по зонам и ячейкам
+// @Tags sales_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.PickTaskModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/pick_task [get]
+func (sh *ISalesOrderHandler) GetPickTasks(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	tasks, err := sh.salesOrderUsecase.GetPickTasks(userId, warehouseId, 0)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tasks": tasks,
+	})
+}
+
+// CompletePickTask godoc
+// @Summary Выполнение задания на сборку
+// @Description Фиксирует собранное количество в строке отгрузки заказа. Без picked_quantity задание считается собранным полностью
+// @Tags sales_order
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param task_id	path		string	true	"task id"
+// @Param request body delivery.CompletePickTaskModelRequest true "Собранное количество"
+// @Success 200 {object} map[string]string "message: pick task success completed"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/pick_task/{task_id}/complete [post]
+func (sh *ISalesOrderHandler) CompletePickTask(c echo.Context) error {
+	reqBody := delivery.CompletePickTaskModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, taskId, err := parseDocumentParams(c, "task_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := sh.salesOrderUsecase.CompletePickTask(&reqBody, userId, warehouseId, taskId, actorId); err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't complete pick task: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "pick task success completed")
+}
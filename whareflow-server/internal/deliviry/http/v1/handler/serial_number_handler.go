@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type SerialNumberHandler interface {
+	GetSerialHistory(echo.Context) error
+	GetProductSerials(echo.Context) error
+}
+
+type ISerialNumberHandler struct {
+	logger              slog.Logger
+	serialNumberUsecase usecase.SerialNumberUsecase
+}
+
+func NewISerialNumberHandler(logger slog.Logger, serialNumberUsecase usecase.SerialNumberUsecase) *ISerialNumberHandler {
+	return &ISerialNumberHandler{
+		logger:              logger,
+		serialNumberUsecase: serialNumberUsecase,
+	}
+}
+
+// GetSerialHistory godoc
+// @Summary История серийного номера
+// @Description Возвращает текущее положение серийного номера и все движения, в которых он участвовал
+// @Tags serial
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param serial	path		string	true	"serial number"
+// @Success 200 {object} delivery.SerialHistoryResponse
+// @Failure 400 {object} map[string]string "error: serial number not found"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/serial/{serial} [get]
+func (sh *ISerialNumberHandler) GetSerialHistory(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	history, err := sh.serialNumberUsecase.FindSerialHistory(userId, warehouseId, c.Param("serial"))
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't find serial history: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+// GetProductSerials godoc
+// @Summary Серийные номера на остатке товара
+// @Description Возвращает номера, которые сейчас лежат на строке товара
+// @Tags serial
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param product_id	path		string	true	"product id"
+// @Success 200 {object} map[string]string "[]delivery.SerialNumberResponse"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/product/{product_id}/serials [get]
+func (sh *ISerialNumberHandler) GetProductSerials(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	serials, err := sh.serialNumberUsecase.FindAllSerialFromProduct(userId, c.Param("product_id"))
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't find product serials: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, serials)
+}
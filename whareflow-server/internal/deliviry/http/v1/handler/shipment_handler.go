@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ShipmentHandler interface {
+	CreateShipment(echo.Context) error
+	GetAllShipments(echo.Context) error
+	GetShipment(echo.Context) error
+	UpdateShipment(echo.Context) error
+	PackShipment(echo.Context) error
+	ShipShipment(echo.Context) error
+}
+
+type IShipmentHandler struct {
+	logger          slog.Logger
+	shipmentUsecase usecase.ShipmentUsecase
+}
+
+func NewIShipmentHandler(logger slog.Logger, shipmentUsecase usecase.ShipmentUsecase) *IShipmentHandler {
+	return &IShipmentHandler{
+		logger:          logger,
+		shipmentUsecase: shipmentUsecase,
+	}
+}
+
+// CreateShipment godoc
+// @Summary Создание отгрузки
+// @Description Создает документ отгрузки в статусе picking
+// @Tags shipment
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.ShipmentModelRequest true "Данные отгрузки"
+// @Success 200 {object} delivery.ShipmentModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment [post]
+func (sh *IShipmentHandler) CreateShipment(c echo.Context) error {
+	reqBody := delivery.ShipmentModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	shipment, err := sh.shipmentUsecase.CreateShipment(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't create shipment: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shipment)
+}
+
+// GetAllShipments godoc
+// @Summary Получение списка отгрузок
+// @Description Возвращает все документы отгрузки склада
+// @Tags shipment
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.ShipmentModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment [get]
+func (sh *IShipmentHandler) GetAllShipments(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	shipments, err := sh.shipmentUsecase.GetAllShipments(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"shipments": shipments,
+	})
+}
+
+// GetShipment godoc
+// @Summary Получение отгрузки
+// @Description Возвращает документ отгрузки со строками
+// @Tags shipment
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param shipment_id	path		string	true	"shipment id"
+// @Success 200 {object} delivery.ShipmentModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment/{shipment_id} [get]
+func (sh *IShipmentHandler) GetShipment(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, shipmentId, err := parseDocumentParams(c, "shipment_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	shipment, err := sh.shipmentUsecase.GetShipment(userId, warehouseId, shipmentId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, shipment)
+}
+
+// UpdateShipment godoc
+// @Summary Обновление отгрузки
+// @Description Заменяет строки отгрузки, пока она не упакована
+// @Tags shipment
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param shipment_id	path		string	true	"shipment id"
+// @Param request body delivery.ShipmentModelRequest true "Данные отгрузки"
+// @Success 200 {object} map[string]string "message: shipment success updated"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment/{shipment_id} [put]
+func (sh *IShipmentHandler) UpdateShipment(c echo.Context) error {
+	reqBody := delivery.ShipmentModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, shipmentId, err := parseDocumentParams(c, "shipment_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := sh.shipmentUsecase.UpdateShipment(&reqBody, userId, warehouseId, shipmentId); err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't update shipment: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "shipment success updated")
+}
+
+// PackShipment godoc
+// @Summary Упаковка отгрузки
+// @Description Фиксирует собранное количество и переводит отгрузку в статус packed
+// @Tags shipment
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param shipment_id	path		string	true	"shipment id"
+// @Param request body delivery.PackShipmentModelRequest false "Собранное количество по строкам"
+// @Success 200 {object} map[string]string "message: shipment success packed"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment/{shipment_id}/pack [post]
+func (sh *IShipmentHandler) PackShipment(c echo.Context) error {
+	reqBody := delivery.PackShipmentModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, shipmentId, err := parseDocumentParams(c, "shipment_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := sh.shipmentUsecase.PackShipment(&reqBody, userId, warehouseId, shipmentId); err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't pack shipment: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "shipment success packed")
+}
+
+// ShipShipment godoc
+// @Summary Отгрузка со склада
+// @Description Списывает собранное количество с остатков и переводит отгрузку в статус shipped. Если заведены грузовые места, в них должно быть уложено все собранное
+// @Tags shipment
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param shipment_id	path		string	true	"shipment id"
+// @Success 200 {object} map[string]string "message: shipment success shipped"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/shipment/{shipment_id}/ship [post]
+func (sh *IShipmentHandler) ShipShipment(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, shipmentId, err := parseDocumentParams(c, "shipment_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := sh.shipmentUsecase.ShipShipment(userId, warehouseId, shipmentId, actorId); err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't ship shipment: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "shipment success shipped")
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type SkuHandler interface {
+	CreateSku(echo.Context) error
+	UpdateSku(echo.Context) error
+	GetAllSkus(echo.Context) error
+	GetSku(echo.Context) error
+}
+
+type ISkuHandler struct {
+	logger     slog.Logger
+	skuUsecase usecase.SkuUsecase
+}
+
+func NewISkuHandler(logger slog.Logger, skuUsecase usecase.SkuUsecase) *ISkuHandler {
+	return &ISkuHandler{
+		logger:     logger,
+		skuUsecase: skuUsecase,
+	}
+}
+
+// CreateSku godoc
+// @Summary Создание позиции каталога
+// @Description Создает позицию каталога владельца. Код и штрихкод уникальны в пределах владельца
+// @Tags sku
+// @Accept			json
+// @Produce		json
+// @Param request body delivery.SkuModelRequest true "Карточка позиции"
+// @Success 200 {object} delivery.SkuModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /sku [post]
+func (sh *ISkuHandler) CreateSku(c echo.Context) error {
+	reqBody := delivery.SkuModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	sku, err := sh.skuUsecase.CreateSku(&reqBody, userId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't create sku: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, sku)
+}
+
+// UpdateSku godoc
+// @Summary Изменение позиции каталога
+// @Description Обновляет карточку позиции. Серийный учет нельзя переключить, пока позиция есть на остатке
+// @Tags sku
+// @Accept			json
+// @Produce		json
+// @Param sku_id	path		string	true	"sku id"
+// @Param request body delivery.SkuModelRequest true "Карточка позиции"
+// @Success 200 {object} delivery.SkuModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /sku/{sku_id} [put]
+func (sh *ISkuHandler) UpdateSku(c echo.Context) error {
+	reqBody := delivery.SkuModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	skuId, err := strconv.ParseUint(c.Param("sku_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	sku, err := sh.skuUsecase.UpdateSku(&reqBody, userId, skuId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't update sku: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, sku)
+}
+
+// GetAllSkus godoc
+// @Summary Получение каталога
+// @Description Возвращает все позиции каталога владельца
+// @Tags sku
+// @Accept			json
+// @Produce		json
+// @Success 200 {object} map[string]string "[]delivery.SkuModelResponse"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /sku [get]
+func (sh *ISkuHandler) GetAllSkus(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	skus, err := sh.skuUsecase.GetAllSkus(userId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't get skus: %v", err))
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, skus)
+}
+
+// GetSku godoc
+// @Summary Получение позиции каталога
+// @Description Возвращает карточку позиции каталога
+// @Tags sku
+// @Accept			json
+// @Produce		json
+// @Param sku_id	path		string	true	"sku id"
+// @Success 200 {object} delivery.SkuModelResponse
+// @Failure 400 {object} map[string]string "error: sku not found"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /sku/{sku_id} [get]
+func (sh *ISkuHandler) GetSku(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	skuId, err := strconv.ParseUint(c.Param("sku_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	sku, err := sh.skuUsecase.GetSku(userId, skuId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't get sku: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, sku)
+}
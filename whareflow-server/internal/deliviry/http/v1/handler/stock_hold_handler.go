@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type StockHoldHandler interface {
+	CreateStockHold(echo.Context) error
+	ChangeStockHoldStatus(echo.Context) error
+	ReleaseStockHold(echo.Context) error
+	GetAllStockHolds(echo.Context) error
+	GetStockStatusHistory(echo.Context) error
+}
+
+type IStockHoldHandler struct {
+	logger           slog.Logger
+	stockHoldUsecase usecase.StockHoldUsecase
+}
+
+func NewIStockHoldHandler(logger slog.Logger, stockHoldUsecase usecase.StockHoldUsecase) *IStockHoldHandler {
+	return &IStockHoldHandler{
+		logger:           logger,
+		stockHoldUsecase: stockHoldUsecase,
+	}
+}
+
+// CreateStockHold godoc
+// @Summary Блокировка остатка
+// @Description Переводит часть доступного остатка товара в карантин, брак или блокировку без списания
+// @Tags hold
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.StockHoldModelRequest true "Данные блокировки"
+// @Success 200 {object} delivery.StockHoldModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/hold [post]
+func (hh *IStockHoldHandler) CreateStockHold(c echo.Context) error {
+	reqBody := delivery.StockHoldModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		hh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	hold, err := hh.stockHoldUsecase.CreateStockHold(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		hh.logger.Error(fmt.Sprintf("Can't create stock hold: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, hold)
+}
+
+// ChangeStockHoldStatus godoc
+// @Summary Смена статуса блокировки
+// @Description Переводит действующую блокировку в другой статус, например из карантина в брак
+// @Tags hold
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param hold_id	path		string	true	"hold id"
+// @Param request body delivery.StockHoldStatusModelRequest true "Новый статус"
+// @Success 200 {object} delivery.StockHoldModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/hold/{hold_id} [put]
+func (hh *IStockHoldHandler) ChangeStockHoldStatus(c echo.Context) error {
+	reqBody := delivery.StockHoldStatusModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		hh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, holdId, err := parseDocumentParams(c, "hold_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	hold, err := hh.stockHoldUsecase.ChangeStockHoldStatus(&reqBody, userId, warehouseId, holdId, actorId)
+	if err != nil {
+		hh.logger.Error(fmt.Sprintf("Can't change stock hold status: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, hold)
+}
+
+// ReleaseStockHold godoc
+// @Summary Снятие блокировки
+// @Description Возвращает заблокированный остаток в доступный целиком или частично. Сотруднику нужно право hold_release
+// @Tags hold
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param hold_id	path		string	true	"hold id"
+// @Param request body delivery.ReleaseStockHoldModelRequest true "Количество и причина снятия"
+// @Success 200 {object} delivery.StockHoldModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/hold/{hold_id}/release [post]
+func (hh *IStockHoldHandler) ReleaseStockHold(c echo.Context) error {
+	reqBody := delivery.ReleaseStockHoldModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		hh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, holdId, err := parseDocumentParams(c, "hold_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	hold, err := hh.stockHoldUsecase.ReleaseStockHold(&reqBody, userId, warehouseId, holdId, actorId)
+	if err != nil {
+		hh.logger.Error(fmt.Sprintf("Can't release stock hold: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, hold)
+}
+
+// GetAllStockHolds godoc
+// @Summary Получение блокировок склада
+// @Description Возвращает действующие блокировки остатка склада, при передаче product_id - только по этому товару
+// @Tags hold
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param product_id	query		string	false	"product id"
+// @Success 200 {object} map[string]string "[]delivery.StockHoldModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/hold [get]
+func (hh *IStockHoldHandler) GetAllStockHolds(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	holds, err := hh.stockHoldUsecase.GetAllStockHolds(userId, warehouseId, c.QueryParam("product_id"))
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"holds": holds,
+	})
+}
+
+// GetStockStatusHistory godoc
+// @Summary История статусов остатка
+// @Description Возвращает историю блокировок и их снятия по складу, при передаче product_id - только по этому товару
+// @Tags hold
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param product_id	query		string	false	"product id"
+// @Success 200 {object} map[string]string "[]delivery.StockStatusChangeModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/hold/history [get]
+func (hh *IStockHoldHandler) GetStockStatusHistory(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	changes, err := hh.stockHoldUsecase.GetStockStatusHistory(userId, warehouseId, c.QueryParam("product_id"))
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"history": changes,
+	})
+}
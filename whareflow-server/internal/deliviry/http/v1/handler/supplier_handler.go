@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type SupplierHandler interface {
+	CreateSupplier(echo.Context) error
+	UpdateSupplier(echo.Context) error
+	GetAllSuppliers(echo.Context) error
+	GetSupplier(echo.Context) error
+}
+
+type ISupplierHandler struct {
+	logger          slog.Logger
+	supplierUsecase usecase.SupplierUsecase
+}
+
+func NewISupplierHandler(logger slog.Logger, supplierUsecase usecase.SupplierUsecase) *ISupplierHandler {
+	return &ISupplierHandler{
+		logger:          logger,
+		supplierUsecase: supplierUsecase,
+	}
+}
+
+// CreateSupplier godoc
+// @Summary Создание поставщика
+// @Description Добавляет поставщика в справочник владельца. Название уникально в пределах владельца
+// @Tags supplier
+// @Accept			json
+// @Produce		json
+// @Param request body delivery.SupplierModelRequest true "Карточка поставщика"
+// @Success 200 {object} delivery.SupplierModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /supplier [post]
+func (sh *ISupplierHandler) CreateSupplier(c echo.Context) error {
+	reqBody := delivery.SupplierModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	supplier, err := sh.supplierUsecase.CreateSupplier(&reqBody, userId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't create supplier: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, supplier)
+}
+
+// UpdateSupplier godoc
+// @Summary Изменение поставщика
+// @Description Обновляет карточку поставщика
+// @Tags supplier
+// @Accept			json
+// @Produce		json
+// @Param supplier_id	path		string	true	"supplier id"
+// @Param request body delivery.SupplierModelRequest true "Карточка поставщика"
+// @Success 200 {object} delivery.SupplierModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /supplier/{supplier_id} [put]
+func (sh *ISupplierHandler) UpdateSupplier(c echo.Context) error {
+	reqBody := delivery.SupplierModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		sh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	supplierId, err := strconv.ParseUint(c.Param("supplier_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	supplier, err := sh.supplierUsecase.UpdateSupplier(&reqBody, userId, supplierId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't update supplier: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, supplier)
+}
+
+// GetAllSuppliers godoc
+// @Summary Получение поставщиков
+// @Description Возвращает справочник поставщиков владельца
+// @Tags supplier
+// @Accept			json
+// @Produce		json
+// @Success 200 {object} map[string]string "[]delivery.SupplierModelResponse"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /supplier [get]
+func (sh *ISupplierHandler) GetAllSuppliers(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	suppliers, err := sh.supplierUsecase.GetAllSuppliers(userId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't get suppliers: %v", err))
+		return c.JSON(http.StatusInternalServerError, "")
+	}
+
+	return c.JSON(http.StatusOK, suppliers)
+}
+
+// GetSupplier godoc
+// @Summary Получение поставщика
+// @Description Возвращает карточку поставщика
+// @Tags supplier
+// @Accept			json
+// @Produce		json
+// @Param supplier_id	path		string	true	"supplier id"
+// @Success 200 {object} delivery.SupplierModelResponse
+// @Failure 400 {object} map[string]string "error: supplier not found"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /supplier/{supplier_id} [get]
+func (sh *ISupplierHandler) GetSupplier(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	supplierId, err := strconv.ParseUint(c.Param("supplier_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "")
+	}
+
+	supplier, err := sh.supplierUsecase.GetSupplier(userId, supplierId)
+	if err != nil {
+		sh.logger.Error(fmt.Sprintf("Can't get supplier: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, supplier)
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type TransferHandler interface {
+	CreateTransfer(echo.Context) error
+	GetAllTransfers(echo.Context) error
+	GetTransfer(echo.Context) error
+	UpdateTransfer(echo.Context) error
+	ShipTransfer(echo.Context) error
+	ReceiveTransfer(echo.Context) error
+	GetTransferDiscrepancy(echo.Context) error
+}
+
+type ITransferHandler struct {
+	logger          slog.Logger
+	transferUsecase usecase.TransferUsecase
+}
+
+func NewITransferHandler(logger slog.Logger, transferUsecase usecase.TransferUsecase) *ITransferHandler {
+	return &ITransferHandler{
+		logger:          logger,
+		transferUsecase: transferUsecase,
+	}
+}
+
+// CreateTransfer godoc
+// @Summary Создание перемещения между складами
+// @Description Создает черновик перемещения со склада warehouse_id на другой склад того же владельца
+// @Tags transfer
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id (склад-отправитель)"
+// @Param request body delivery.TransferModelRequest true "Данные перемещения"
+// @Success 200 {object} delivery.TransferModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/transfer [post]
+func (th *ITransferHandler) CreateTransfer(c echo.Context) error {
+	reqBody := delivery.TransferModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		th.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	transfer, err := th.transferUsecase.CreateTransfer(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		th.logger.Error(fmt.Sprintf("Can't create transfer: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, transfer)
+}
+
+// GetAllTransfers godoc
+// @Summary Получение списка перемещений
+// @Description Возвращает входящие и исходящие перемещения склада
+// @Tags transfer
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.TransferModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/transfer [get]
+func (th *ITransferHandler) GetAllTransfers(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	transfers, err := th.transferUsecase.GetAllTransfers(userId, warehouseId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"transfers": transfers,
+	})
+}
+
+// GetTransfer godoc
+// @Summary Получение перемещения
+// @Description Возвращает перемещение со строками и количеством товара в пути
+// @Tags transfer
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param transfer_id	path		string	true	"transfer id"
+// @Success 200 {object} delivery.TransferModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/transfer/{transfer_id} [get]
+func (th *ITransferHandler) GetTransfer(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, transferId, err := parseDocumentParams(c, "transfer_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	transfer, err := th.transferUsecase.GetTransfer(userId, warehouseId, transferId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, transfer)
+}
+
+// UpdateTransfer godoc
+// @Summary Обновление перемещения
+// @Description Заменяет строки черновика перемещения. Доступно только складу-отправителю
+// @Tags transfer
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id (склад-отправитель)"
+// @Param transfer_id	path		string	true	"transfer id"
+// @Param request body delivery.TransferModelRequest true "Данные перемещения"
+// @Success 200 {object} map[string]string "message: transfer success updated"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/transfer/{transfer_id} [put]
+func (th *ITransferHandler) UpdateTransfer(c echo.Context) error {
+	reqBody := delivery.TransferModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		th.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, transferId, err := parseDocumentParams(c, "transfer_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := th.transferUsecase.UpdateTransfer(&reqBody, userId, warehouseId, transferId); err != nil {
+		th.logger.Error(fmt.Sprintf("Can't update transfer: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "transfer success updated")
+}
+
+// ShipTransfer godoc
+// @Summary Отправка перемещения
+// @Description Списывает товар со склада-отправителя и переводит перемещение в статус in_transit
+// @Tags transfer
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id (склад-отправитель)"
+// @Param transfer_id	path		string	true	"transfer id"
+// @Success 200 {object} map[string]string "message: transfer success shipped"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/transfer/{transfer_id}/ship [post]
+func (th *ITransferHandler) ShipTransfer(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, transferId, err := parseDocumentParams(c, "transfer_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := th.transferUsecase.ShipTransfer(userId, warehouseId, transferId, actorId); err != nil {
+		th.logger.Error(fmt.Sprintf("Can't ship transfer: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "transfer success shipped")
+}
+
+// ReceiveTransfer godoc
+// @Summary Приемка перемещения
+// @Description Принимает товар в зоны склада-получателя и переводит перемещение в статус received
+// @Tags transfer
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id (склад-получатель)"
+// @Param transfer_id	path		string	true	"transfer id"
+// @Param request body delivery.ReceiveTransferModelRequest true "Зона по умолчанию и фактическое количество по строкам"
+// @Success 200 {object} map[string]string "message: transfer success received"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/transfer/{transfer_id}/receive [post]
+func (th *ITransferHandler) ReceiveTransfer(c echo.Context) error {
+	reqBody := delivery.ReceiveTransferModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		th.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, transferId, err := parseDocumentParams(c, "transfer_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := th.transferUsecase.ReceiveTransfer(&reqBody, userId, warehouseId, transferId, actorId); err != nil {
+		th.logger.Error(fmt.Sprintf("Can't receive transfer: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "transfer success received")
+}
+
+// GetTransferDiscrepancy godoc
+// @Summary Отчет о расхождениях перемещения
+// @Description Сравнивает отгруженное и принятое количество по строкам принятого перемещения
+// @Tags transfer
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param transfer_id	path		string	true	"transfer id"
+// @Success 200 {object} delivery.TransferDiscrepancyResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/transfer/{transfer_id}/discrepancy [get]
+func (th *ITransferHandler) GetTransferDiscrepancy(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, transferId, err := parseDocumentParams(c, "transfer_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	report, err := th.transferUsecase.GetTransferDiscrepancy(userId, warehouseId, transferId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
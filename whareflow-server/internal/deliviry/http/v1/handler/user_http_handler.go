@@ -76,7 +76,7 @@ func (h *IUserHttpHandler) Register(c echo.Context) error {
 			return c.JSON(http.StatusOK, map[string]string{"error": err.Error()})
 		}
 
-		h.logger.Error("Unexpected error during registration: %v", err)
+		h.logger.Error(fmt.Sprintf("Unexpected error during registration: %v", err))
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "internal server error",
 		})
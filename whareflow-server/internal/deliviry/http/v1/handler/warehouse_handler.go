@@ -175,7 +175,7 @@ func (wh *IWareHouseHandler) UpdateWarehouse(c echo.Context) error {
 		}
 		if errors.Is(err, custom_errors.ErrWareHouseNotFound) {
 			return c.JSON(http.StatusBadRequest, map[string]string{
-				"error": fmt.Sprintf("Warehouse not found with name: %s", warehouseId),
+				"error": fmt.Sprintf("Warehouse not found with name: %d", warehouseId),
 			})
 		}
 		return c.JSON(http.StatusInternalServerError, "")
@@ -206,11 +206,11 @@ func (wh *IWareHouseHandler) DeleteWarehouse(c echo.Context) error {
 	}
 
 	if err := wh.whUsecase.DeleteWarehouse(uint(warehouseId), userId); err != nil {
-		return c.JSON(http.StatusInternalServerError, "")
+		return customErrorResponse(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"warehouses": fmt.Sprintf("warehouse success delete with name: %s", warehouseId),
+		"warehouses": fmt.Sprintf("warehouse success delete with name: %d", warehouseId),
 	})
 
 }
@@ -266,6 +266,6 @@ func (wh *IWareHouseHandler) GetWhsEmployer(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-    		"warehouses": warehouses,
-    	})
+		"warehouses": warehouses,
+	})
 }
@@ -0,0 +1,443 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type WarehouseTaskHandler interface {
+	CreateWarehouseTask(echo.Context) error
+	AssignWarehouseTask(echo.Context) error
+	CancelWarehouseTask(echo.Context) error
+	ClaimWarehouseTask(echo.Context) error
+	CompleteWarehouseTask(echo.Context) error
+	AbandonWarehouseTask(echo.Context) error
+	GetAllWarehouseTasks(echo.Context) error
+	GetWorkerQueue(echo.Context) error
+	GetWarehouseTask(echo.Context) error
+	GetTaskStats(echo.Context) error
+}
+
+type IWarehouseTaskHandler struct {
+	logger               slog.Logger
+	warehouseTaskUsecase usecase.WarehouseTaskUsecase
+}
+
+func NewIWarehouseTaskHandler(logger slog.Logger, warehouseTaskUsecase usecase.WarehouseTaskUsecase) *IWarehouseTaskHandler {
+	return &IWarehouseTaskHandler{
+		logger:               logger,
+		warehouseTaskUsecase: warehouseTaskUsecase,
+	}
+}
+
+// CreateWarehouseTask godoc
+// @Summary Создание задания работнику склада
+// @Description Создает задание receive, putaway, pick, count или move. Задание с assignee_id получает только этот работник, он узнает о нем из уведомления, без assignee_id задание попадает в общую очередь склада
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.WarehouseTaskModelRequest true "Задание"
+// @Success 200 {object} delivery.WarehouseTaskModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task [post]
+func (th *IWarehouseTaskHandler) CreateWarehouseTask(c echo.Context) error {
+	reqBody := delivery.WarehouseTaskModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		th.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	task, err := th.warehouseTaskUsecase.CreateWarehouseTask(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		th.logger.Error(fmt.Sprintf("Can't create warehouse task: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, task)
+}
+
+// AssignWarehouseTask godoc
+// @Summary Назначение задания работнику
+// @Description Назначает открытое задание работнику с правом task_work на склад или, при assignee_id = null, возвращает его в общую очередь. Задание, взятое в работу, переназначить нельзя
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param task_id	path		string	true	"task id"
+// @Param request body delivery.AssignWarehouseTaskModelRequest true "Исполнитель"
+// @Success 200 {object} map[string]string "message: task success assigned"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task/{task_id}/assign [put]
+func (th *IWarehouseTaskHandler) AssignWarehouseTask(c echo.Context) error {
+	reqBody := delivery.AssignWarehouseTaskModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		th.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, taskId, err := parseDocumentParams(c, "task_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := th.warehouseTaskUsecase.AssignWarehouseTask(&reqBody, userId, warehouseId, taskId); err != nil {
+		th.logger.Error(fmt.Sprintf("Can't assign warehouse task: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "task success assigned")
+}
+
+// CancelWarehouseTask godoc
+// @Summary Отмена задания
+// @Description Отменяет открытое или взятое в работу задание
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param task_id	path		string	true	"task id"
+// @Success 200 {object} map[string]string "message: task success cancelled"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task/{task_id}/cancel [post]
+func (th *IWarehouseTaskHandler) CancelWarehouseTask(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, taskId, err := parseDocumentParams(c, "task_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := th.warehouseTaskUsecase.CancelWarehouseTask(userId, warehouseId, taskId); err != nil {
+		th.logger.Error(fmt.Sprintf("Can't cancel warehouse task: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "task success cancelled")
+}
+
+// ClaimWarehouseTask godoc
+// @Summary Взятие задания в работу
+// @Description Закрепляет открытое задание за автором запроса. Задание, назначенное другому работнику, взять нельзя
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param task_id	path		string	true	"task id"
+// @Success 200 {object} map[string]string "message: task success claimed"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task/{task_id}/claim [post]
+func (th *IWarehouseTaskHandler) ClaimWarehouseTask(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, taskId, err := parseDocumentParams(c, "task_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := th.warehouseTaskUsecase.ClaimWarehouseTask(userId, warehouseId, taskId, actorId); err != nil {
+		th.logger.Error(fmt.Sprintf("Can't claim warehouse task: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "task success claimed")
+}
+
+// CompleteWarehouseTask godoc
+// @Summary Выполнение задания
+// @Description Отмечает выполненным задание, которое автор запроса взял в работу
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param task_id	path		string	true	"task id"
+// @Success 200 {object} map[string]string "message: task success completed"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task/{task_id}/complete [post]
+func (th *IWarehouseTaskHandler) CompleteWarehouseTask(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, taskId, err := parseDocumentParams(c, "task_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := th.warehouseTaskUsecase.CompleteWarehouseTask(userId, warehouseId, taskId, actorId); err != nil {
+		th.logger.Error(fmt.Sprintf("Can't complete warehouse task: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "task success completed")
+}
+
+// AbandonWarehouseTask godoc
+// @Summary Отказ от задания
+// @Description Возвращает взятое в работу задание в очередь с записью причины в журнал
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param task_id	path		string	true	"task id"
+// @Param request body delivery.AbandonWarehouseTaskModelRequest true "Причина отказа"
+// @Success 200 {object} map[string]string "message: task success abandoned"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task/{task_id}/abandon [post]
+func (th *IWarehouseTaskHandler) AbandonWarehouseTask(c echo.Context) error {
+	reqBody := delivery.AbandonWarehouseTaskModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		th.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, taskId, err := parseDocumentParams(c, "task_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := th.warehouseTaskUsecase.AbandonWarehouseTask(&reqBody, userId, warehouseId, taskId, actorId); err != nil {
+		th.logger.Error(fmt.Sprintf("Can't abandon warehouse task: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "task success abandoned")
+}
+
+// GetAllWarehouseTasks godoc
+// @Summary Получение заданий склада
+// @Description Возвращает задания склада. status, type и assignee_id ограничивают выборку
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param status	query		string	false	"task status"
+// @Param type	query		string	false	"task type"
+// @Param assignee_id	query		string	false	"assignee id"
+// @Success 200 {object} map[string]string "[]delivery.WarehouseTaskModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task [get]
+func (th *IWarehouseTaskHandler) GetAllWarehouseTasks(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	tasks, err := th.warehouseTaskUsecase.GetAllWarehouseTasks(userId, warehouseId, c.QueryParam("status"), c.QueryParam("type"), c.QueryParam("assignee_id"))
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tasks": tasks,
+	})
+}
+
+// GetWorkerQueue godoc
+// @Summary Очередь заданий работника
+// @Description Возвращает задания автора запроса в работе, а за ними открытые задания, назначенные ему или лежащие в общей очереди, по приоритету и сроку
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Success 200 {object} map[string]string "[]delivery.WarehouseTaskModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task/queue [get]
+func (th *IWarehouseTaskHandler) GetWorkerQueue(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	tasks, err := th.warehouseTaskUsecase.GetWorkerQueue(userId, warehouseId, actorId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tasks": tasks,
+	})
+}
+
+// GetWarehouseTask godoc
+// @Summary Получение задания
+// @Description Возвращает задание склада
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param task_id	path		string	true	"task id"
+// @Success 200 {object} delivery.WarehouseTaskModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task/{task_id} [get]
+func (th *IWarehouseTaskHandler) GetWarehouseTask(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, taskId, err := parseDocumentParams(c, "task_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	task, err := th.warehouseTaskUsecase.GetWarehouseTask(userId, warehouseId, taskId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, task)
+}
+
+// GetWorkerTask godoc
+// @Summary Получение задания работником
+// @Description Возвращает задание склада, если оно лежит в общей очереди или назначено автору запроса
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param action	path		string	true	"action"
+// @Param task_id	path		string	true	"task id"
+// @Success 200 {object} delivery.WarehouseTaskModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /employer/warehouse/{warehouse_id}/task/{action}/{task_id} [get]
+func (th *IWarehouseTaskHandler) GetWorkerTask(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, taskId, err := parseDocumentParams(c, "task_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	task, err := th.warehouseTaskUsecase.GetWorkerTask(userId, warehouseId, taskId, actorId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, task)
+}
+
+// GetTaskStats godoc
+// @Summary Выработка работников
+// @Description Возвращает по каждому работнику число взятых, выполненных и брошенных заданий и время выполнения по типам заданий. from и to - даты в формате 2006-01-02, to включается в период. По умолчанию - последние 7 дней
+// @Tags task
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param from	query		string	false	"period start date"
+// @Param to	query		string	false	"period end date"
+// @Success 200 {object} delivery.TaskStatsModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/task/stats [get]
+func (th *IWarehouseTaskHandler) GetTaskStats(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	var from, to *time.Time
+	if value := c.QueryParam("from"); value != "" {
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid request body",
+			})
+		}
+		from = &date
+	}
+	if value := c.QueryParam("to"); value != "" {
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid request body",
+			})
+		}
+		// Конец периода - начало следующего дня, чтобы день to вошел целиком
+		date = date.AddDate(0, 0, 1)
+		to = &date
+	}
+
+	stats, err := th.warehouseTaskUsecase.GetTaskStats(userId, warehouseId, from, to)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type WaveHandler interface {
+	CreateWave(echo.Context) error
+	PickWave(echo.Context) error
+	SortWave(echo.Context) error
+	CompleteWave(echo.Context) error
+	GetAllWaves(echo.Context) error
+	GetWave(echo.Context) error
+}
+
+type IWaveHandler struct {
+	logger      slog.Logger
+	waveUsecase usecase.WaveUsecase
+}
+
+func NewIWaveHandler(logger slog.Logger, waveUsecase usecase.WaveUsecase) *IWaveHandler {
+	return &IWaveHandler{
+		logger:      logger,
+		waveUsecase: waveUsecase,
+	}
+}
+
+// CreateWave godoc
+// @Summary Планирование волны сборки
+// @Description Объединяет подтвержденные заказы в один обход склада: задания сворачиваются в строки по товару, заказы получают ячейки стены сортировки. Заказ, задания которого уже в листе сборки или волне, в волну не попадает
+// @Tags wave
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param request body delivery.WaveModelRequest true "Заказы волны"
+// @Success 200 {object} delivery.WaveModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/wave [post]
+func (wh *IWaveHandler) CreateWave(c echo.Context) error {
+	reqBody := delivery.WaveModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		wh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	wave, err := wh.waveUsecase.CreateWave(&reqBody, userId, warehouseId, actorId)
+	if err != nil {
+		wh.logger.Error(fmt.Sprintf("Can't create wave: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, wave)
+}
+
+// PickWave godoc
+// @Summary Завершение обхода волны
+// @Description Фиксирует собранное по строкам волны количество и переводит волну к раскладке. Строки без записи в запросе считаются собранными полностью
+// @Tags wave
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param wave_id	path		string	true	"wave id"
+// @Param request body delivery.WavePickModelRequest true "Строки с недобором"
+// @Success 200 {object} map[string]string "message: wave success picked"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/wave/{wave_id}/pick [post]
+func (wh *IWaveHandler) PickWave(c echo.Context) error {
+	reqBody := delivery.WavePickModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		wh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, waveId, err := parseDocumentParams(c, "wave_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := wh.waveUsecase.PickWave(&reqBody, userId, warehouseId, waveId); err != nil {
+		wh.logger.Error(fmt.Sprintf("Can't pick wave: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "wave success picked")
+}
+
+// SortWave godoc
+// @Summary Раскладка товара на стене сортировки
+// @Description Распределяет отсканированный товар по ячейкам заказов волны в порядке ячеек и возвращает, сколько положить в каждую. Задание заказа, набравшее свое количество, выполняется
+// @Tags wave
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param wave_id	path		string	true	"wave id"
+// @Param request body delivery.WaveSortModelRequest true "Отсканированный товар"
+// @Success 200 {object} map[string]string "[]delivery.WaveSortPutModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/wave/{wave_id}/sort [post]
+func (wh *IWaveHandler) SortWave(c echo.Context) error {
+	reqBody := delivery.WaveSortModelRequest{}
+
+	if err := c.Bind(&reqBody); err != nil {
+		wh.logger.Error(fmt.Sprintf("Incorrect request body: %v", err))
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, waveId, err := parseDocumentParams(c, "wave_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	puts, err := wh.waveUsecase.SortWave(&reqBody, userId, warehouseId, waveId, actorId)
+	if err != nil {
+		wh.logger.Error(fmt.Sprintf("Can't sort wave item: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"puts": puts,
+	})
+}
+
+// CompleteWave godoc
+// @Summary Завершение раскладки волны
+// @Description Закрывает волну. Задания, не набравшие своего количества, выполняются с недобором
+// @Tags wave
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param wave_id	path		string	true	"wave id"
+// @Success 200 {object} map[string]string "message: wave success completed"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/wave/{wave_id}/complete [post]
+func (wh *IWaveHandler) CompleteWave(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+	actorId := c.Get("x-actor-id").(string)
+
+	warehouseId, waveId, err := parseDocumentParams(c, "wave_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	if err := wh.waveUsecase.CompleteWave(userId, warehouseId, waveId, actorId); err != nil {
+		wh.logger.Error(fmt.Sprintf("Can't complete wave: %v", err))
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, "wave success completed")
+}
+
+// GetAllWaves godoc
+// @Summary Получение волн сборки
+// @Description Возвращает волны склада с ячейками заказов, без строк. status ограничивает выборку picking, sorting или done
+// @Tags wave
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param status	query		string	false	"wave status"
+// @Success 200 {object} map[string]string "[]delivery.WaveModelResponse"
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/wave [get]
+func (wh *IWaveHandler) GetAllWaves(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, err := strconv.Atoi(c.Param("warehouse_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	waves, err := wh.waveUsecase.GetAllWaves(userId, warehouseId, c.QueryParam("status"))
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"waves": waves,
+	})
+}
+
+// GetWave godoc
+// @Summary Получение волны сборки
+// @Description Возвращает строки волны по маршруту обхода склада и ячейки стены сортировки заказов
+// @Tags wave
+// @Accept			json
+// @Produce		json
+// @Param warehouse_id	path		string	true	"warehouse id"
+// @Param wave_id	path		string	true	"wave id"
+// @Success 200 {object} delivery.WaveModelResponse
+// @Failure 400 {object} map[string]string "error: invalid request body"
+// @Failure 500 {object} map[string]string "error: internal server error"
+// @Security		ApiKeyAuth
+// @Router /warehouse/{warehouse_id}/wave/{wave_id} [get]
+func (wh *IWaveHandler) GetWave(c echo.Context) error {
+	userId := c.Get("x-user-id").(string)
+
+	warehouseId, waveId, err := parseDocumentParams(c, "wave_id")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	wave, err := wh.waveUsecase.GetWave(userId, warehouseId, waveId)
+	if err != nil {
+		return customErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, wave)
+}
@@ -233,7 +233,7 @@ func (zh *IZoneHandler) DeleteZone(c echo.Context) error {
 
 	err = zh.zoneUsecase.DeleteZone(userId, warehouseId, zoneId)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "")
+		return customErrorResponse(c, err)
 	}
 
 	return c.JSON(http.StatusOK, "zone success deleted")
@@ -47,6 +47,8 @@ func (am *AuthHttpMiddleware) Auth(next echo.HandlerFunc) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid auth token")
 		}
 		c.Set("x-user-id", userId)
+		// x-user-id может быть заменён на владельца склада, x-actor-id всегда указывает на автора запроса
+		c.Set("x-actor-id", userId)
 		return next(c)
 	}
 }
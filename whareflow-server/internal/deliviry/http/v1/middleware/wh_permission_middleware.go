@@ -6,7 +6,6 @@ import (
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"strconv"
-	"fmt"
 )
 
 type WhPermissionMiddleware interface {
@@ -38,8 +37,6 @@ func (wp *IWhPermissionMiddleware) HasPermissionOnWarehouse(next echo.HandlerFun
 		// Извлекаем группу из контекста
 		group := c.Get("group").(string)
 
-
-        fmt.Println(action)
 		// Проверяем, что action соответствует нужному действию для этой группы
 		if !wp.isValidActionForGroup(group, action) {
 			return c.JSON(http.StatusForbidden, map[string]string{"error": "Invalid action for this group"})
@@ -67,13 +64,13 @@ func (wp *IWhPermissionMiddleware) HasPermissionOnWarehouse(next echo.HandlerFun
 func (wp *IWhPermissionMiddleware) isValidActionForGroup(group, action string) bool {
 	switch group {
 	case "self_perm":
-        		if action != "get_my_permissions" {
-        			return false
-        		}
+		if action != "get_my_permissions" {
+			return false
+		}
 	case "warehouse":
-    		if action != "warehouse_manage" {
-    			return false
-    		}
+		if action != "warehouse_manage" {
+			return false
+		}
 	case "zone":
 		if action != "zone_manage" {
 			return false
@@ -86,6 +83,70 @@ func (wp *IWhPermissionMiddleware) isValidActionForGroup(group, action string) b
 		if action != "role_manage" {
 			return false
 		}
+	case "receipt":
+		if action != "receipt_manage" {
+			return false
+		}
+	case "shipment":
+		if action != "shipment_manage" {
+			return false
+		}
+	case "transfer":
+		if action != "transfer_manage" {
+			return false
+		}
+	case "reservation":
+		if action != "reservation_manage" {
+			return false
+		}
+	case "inventory":
+		if action != "inventory_count" {
+			return false
+		}
+	case "hold":
+		if action != "hold_manage" {
+			return false
+		}
+	case "hold_release":
+		if action != "hold_release" {
+			return false
+		}
+	case "return":
+		if action != "return_manage" {
+			return false
+		}
+	case "kit":
+		if action != "kit_manage" {
+			return false
+		}
+	case "purchase_order":
+		if action != "purchase_manage" {
+			return false
+		}
+	case "sales_order":
+		if action != "sales_manage" {
+			return false
+		}
+	case "pick":
+		if action != "product_manage" {
+			return false
+		}
+	case "wave":
+		if action != "product_manage" {
+			return false
+		}
+	case "task":
+		if action != "task_work" {
+			return false
+		}
+	case "task_manage":
+		if action != "task_manage" {
+			return false
+		}
+	case "pick_task":
+		if action != "product_manage" {
+			return false
+		}
 	default:
 		return false
 	}
@@ -0,0 +1,55 @@
+package delivery
+
+import "time"
+
+// ReturnLineModelRequest: Quantity задается в единице строки отгрузки ShipmentLineId. Condition - resell, repair или scrap.
+// Для repair и scrap ZoneId должна быть карантинной зоной склада, для resell пустая зона - зона исходного товара
+type ReturnLineModelRequest struct {
+	ShipmentLineId uint64  `json:"shipment_line_id"`
+	Quantity       float64 `json:"quantity"`
+	Condition      string  `json:"condition"`
+	ZoneId         *uint64 `json:"zone_id"`
+}
+
+type ReturnModelRequest struct {
+	ShipmentId uint64                   `json:"shipment_id"`
+	Comment    string                   `json:"comment"`
+	Lines      []ReturnLineModelRequest `json:"lines"`
+}
+
+// PostReturnLineModelRequest: Serials - возвращаемые серийные номера строки, для несерийного товара не передаются
+type PostReturnLineModelRequest struct {
+	LineId  uint64   `json:"line_id"`
+	Serials []string `json:"serials"`
+}
+
+type PostReturnModelRequest struct {
+	Lines []PostReturnLineModelRequest `json:"lines"`
+}
+
+// ReturnLineModelResponse: Quantity указано в единице Unit строки отгрузки. ResultProductUuid - строка товара,
+// на которую вернулся остаток, HoldId - блокировка для repair и scrap
+type ReturnLineModelResponse struct {
+	Id                uint64  `json:"id"`
+	ShipmentLineId    uint64  `json:"shipment_line_id"`
+	ProductUuid       string  `json:"product_uuid"`
+	SkuId             uint64  `json:"sku_id"`
+	Unit              string  `json:"unit"`
+	Quantity          float64 `json:"quantity"`
+	Condition         string  `json:"condition"`
+	ZoneId            *uint64 `json:"zone_id"`
+	ResultProductUuid *string `json:"result_product_uuid"`
+	HoldId            *uint64 `json:"hold_id"`
+}
+
+type ReturnModelResponse struct {
+	Id          uint64                    `json:"id"`
+	WarehouseId uint64                    `json:"warehouse_id"`
+	ShipmentId  uint64                    `json:"shipment_id"`
+	Status      string                    `json:"status"`
+	Comment     string                    `json:"comment"`
+	CreatedBy   string                    `json:"created_by"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	PostedAt    *time.Time                `json:"posted_at"`
+	Lines       []ReturnLineModelResponse `json:"lines"`
+}
@@ -0,0 +1,62 @@
+package delivery
+
+import "time"
+
+type InventoryCountModelRequest struct {
+	ZoneId  *uint64 `json:"zone_id"`
+	Comment string  `json:"comment"`
+}
+
+// SubmitCountModelRequest определяет товар по содержимому QR-кода, а если его нет - по ProductUuid.
+// CountedQuantity в единице Unit, пустая единица - базовая
+type SubmitCountModelRequest struct {
+	Qr              string  `json:"qr"`
+	ProductUuid     string  `json:"product_uuid"`
+	CountedQuantity float64 `json:"counted_quantity"`
+	Unit            string  `json:"unit"`
+}
+
+// InventoryCountLineModelResponse: количества в базовой единице Unit
+type InventoryCountLineModelResponse struct {
+	Id               uint64     `json:"id"`
+	ProductUuid      string     `json:"product_uuid"`
+	Unit             string     `json:"unit"`
+	ExpectedQuantity float64    `json:"expected_quantity"`
+	CountedQuantity  *float64   `json:"counted_quantity"`
+	CountedBy        *string    `json:"counted_by"`
+	CountedAt        *time.Time `json:"counted_at"`
+}
+
+type InventoryCountModelResponse struct {
+	Id          uint64                            `json:"id"`
+	WarehouseId uint64                            `json:"warehouse_id"`
+	ZoneId      *uint64                           `json:"zone_id"`
+	Status      string                            `json:"status"`
+	Comment     string                            `json:"comment"`
+	CreatedBy   string                            `json:"created_by"`
+	ApprovedBy  *string                           `json:"approved_by"`
+	CreatedAt   time.Time                         `json:"created_at"`
+	ClosedAt    *time.Time                        `json:"closed_at"`
+	Lines       []InventoryCountLineModelResponse `json:"lines"`
+}
+
+// VarianceLineResponse: количества в базовой единице Unit
+type VarianceLineResponse struct {
+	ProductUuid     string  `json:"product_uuid"`
+	Unit            string  `json:"unit"`
+	BookQuantity    float64 `json:"book_quantity"`
+	CountedQuantity float64 `json:"counted_quantity"`
+	Variance        float64 `json:"variance"`
+}
+
+// VarianceReportResponse сравнивает факт с учетным остатком на момент сканирования - эти расхождения проводятся
+// при утверждении. Итоги - суммы строк в их базовых единицах
+type VarianceReportResponse struct {
+	InventoryCountId uint64                 `json:"inventory_count_id"`
+	Status           string                 `json:"status"`
+	CountedLines     int                    `json:"counted_lines"`
+	UncountedLines   int                    `json:"uncounted_lines"`
+	TotalShortage    float64                `json:"total_shortage"`
+	TotalSurplus     float64                `json:"total_surplus"`
+	Lines            []VarianceLineResponse `json:"lines"`
+}
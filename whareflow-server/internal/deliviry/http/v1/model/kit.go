@@ -0,0 +1,72 @@
+package delivery
+
+import "time"
+
+// KitComponentModelRequest: Quantity компонента SkuId на один набор в единице Unit, пустая единица - базовая
+type KitComponentModelRequest struct {
+	SkuId    uint64  `json:"sku_id"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+type KitModelRequest struct {
+	Components []KitComponentModelRequest `json:"components"`
+}
+
+// KitComponentModelResponse: Quantity указано в базовой единице компонента Unit
+type KitComponentModelResponse struct {
+	SkuId    uint64  `json:"sku_id"`
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit"`
+	Quantity float64 `json:"quantity"`
+}
+
+type KitModelResponse struct {
+	KitSkuId   uint64                      `json:"kit_sku_id"`
+	Components []KitComponentModelResponse `json:"components"`
+}
+
+// AssembleKitModelRequest: Quantity - число собираемых наборов в единице Unit набора, пустая единица - базовая.
+// ZoneId - зона, куда приходуются наборы
+type AssembleKitModelRequest struct {
+	KitSkuId uint64  `json:"kit_sku_id"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	ZoneId   uint64  `json:"zone_id"`
+	Comment  string  `json:"comment"`
+}
+
+// DisassembleKitModelRequest: ProductUuid - строка товара с наборами, Quantity - в единице Unit набора, пустая единица - базовая.
+// Пустая ZoneId - компоненты приходуются в зону этой строки
+type DisassembleKitModelRequest struct {
+	ProductUuid string  `json:"product_uuid"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit"`
+	ZoneId      *uint64 `json:"zone_id"`
+	Comment     string  `json:"comment"`
+}
+
+// KitOperationLineModelResponse: Quantity в базовой единице Unit, отрицательное - списано, положительное - оприходовано
+type KitOperationLineModelResponse struct {
+	ProductUuid string  `json:"product_uuid"`
+	SkuId       uint64  `json:"sku_id"`
+	Unit        string  `json:"unit"`
+	Quantity    float64 `json:"quantity"`
+	MovementId  uint64  `json:"movement_id"`
+}
+
+// KitOperationModelResponse: Quantity - число наборов в базовой единице набора Unit
+type KitOperationModelResponse struct {
+	Id          uint64                          `json:"id"`
+	WarehouseId uint64                          `json:"warehouse_id"`
+	KitSkuId    uint64                          `json:"kit_sku_id"`
+	Kind        string                          `json:"kind"`
+	Unit        string                          `json:"unit"`
+	Quantity    float64                         `json:"quantity"`
+	ZoneId      *uint64                         `json:"zone_id"`
+	Comment     string                          `json:"comment"`
+	CreatedBy   string                          `json:"created_by"`
+	CreatedAt   time.Time                       `json:"created_at"`
+	Lines       []KitOperationLineModelResponse `json:"lines"`
+}
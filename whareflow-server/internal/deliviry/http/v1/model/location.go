@@ -0,0 +1,31 @@
+package delivery
+
+import "time"
+
+// LocationModelRequest: ParentId и Kind задаются только при создании узла. Capacity nil - без ограничения
+type LocationModelRequest struct {
+	ParentId *uint64 `json:"parent_id"`
+	Kind     string  `json:"kind"`
+	Code     string  `json:"code"`
+	Capacity *uint64 `json:"capacity"`
+}
+
+// LocationModelResponse: Occupied - остаток во всем поддереве узла
+type LocationModelResponse struct {
+	Id        uint64                  `json:"id"`
+	ZoneId    uint64                  `json:"zone_id"`
+	ParentId  *uint64                 `json:"parent_id"`
+	Kind      string                  `json:"kind"`
+	Code      string                  `json:"code"`
+	Capacity  *uint64                 `json:"capacity"`
+	Occupied  uint64                  `json:"occupied"`
+	QrPath    string                  `json:"qr_path"`
+	CreatedAt time.Time               `json:"created_at"`
+	Children  []LocationModelResponse `json:"children"`
+}
+
+type LocationQrResponse struct {
+	LocationId uint64 `json:"location_id"`
+	Code       string `json:"code"`
+	QrImage    string `json:"qr_image"`
+}
@@ -0,0 +1,38 @@
+package delivery
+
+import "time"
+
+// ParcelLineModelRequest: Quantity задается в единице строки отгрузки
+type ParcelLineModelRequest struct {
+	LineId   uint64  `json:"line_id"`
+	Quantity float64 `json:"quantity"`
+}
+
+// ParcelModelRequest: без WeightKg вес места считается по весу единицы товара из карточки SKU
+type ParcelModelRequest struct {
+	WeightKg *float64                 `json:"weight_kg"`
+	LengthCm float64                  `json:"length_cm"`
+	WidthCm  float64                  `json:"width_cm"`
+	HeightCm float64                  `json:"height_cm"`
+	Lines    []ParcelLineModelRequest `json:"lines"`
+}
+
+type ParcelLineModelResponse struct {
+	LineId      uint64  `json:"line_id"`
+	ProductUuid string  `json:"product_uuid"`
+	Unit        string  `json:"unit"`
+	Quantity    float64 `json:"quantity"`
+}
+
+type ParcelModelResponse struct {
+	Id         uint64                    `json:"id"`
+	ShipmentId uint64                    `json:"shipment_id"`
+	Number     int                       `json:"number"`
+	WeightKg   float64                   `json:"weight_kg"`
+	LengthCm   float64                   `json:"length_cm"`
+	WidthCm    float64                   `json:"width_cm"`
+	HeightCm   float64                   `json:"height_cm"`
+	CreatedBy  string                    `json:"created_by"`
+	CreatedAt  time.Time                 `json:"created_at"`
+	Lines      []ParcelLineModelResponse `json:"lines"`
+}
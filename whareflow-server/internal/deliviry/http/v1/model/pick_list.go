@@ -0,0 +1,50 @@
+package delivery
+
+import "time"
+
+// WalkPathStepModel - шаг маршрута обхода. Без LocationId шаг покрывает всю зону
+type WalkPathStepModel struct {
+	ZoneId     uint64  `json:"zone_id"`
+	LocationId *uint64 `json:"location_id"`
+}
+
+// WalkPathModelRequest: шаги перечисляются в порядке обхода склада
+type WalkPathModelRequest struct {
+	Steps []WalkPathStepModel `json:"steps"`
+}
+
+type WalkPathStepModelResponse struct {
+	Sequence   int     `json:"sequence"`
+	ZoneId     uint64  `json:"zone_id"`
+	LocationId *uint64 `json:"location_id"`
+}
+
+// PickListModelRequest: OrderIds ограничивает лист заказами, MaxLines - числом заданий, 0 - без ограничения
+type PickListModelRequest struct {
+	OrderIds []uint64 `json:"order_ids"`
+	MaxLines int      `json:"max_lines"`
+}
+
+// PickStopModelResponse - остановка сборщика: зона или ячейка и задания, которые в ней собираются.
+// LocationPath - коды узлов адреса от корня зоны
+type PickStopModelResponse struct {
+	Sequence     int                     `json:"sequence"`
+	ZoneId       uint64                  `json:"zone_id"`
+	ZoneName     string                  `json:"zone_name"`
+	LocationId   *uint64                 `json:"location_id"`
+	LocationPath string                  `json:"location_path"`
+	Tasks        []PickTaskModelResponse `json:"tasks"`
+}
+
+// PickListModelResponse: Stops упорядочены по маршруту обхода склада, в списке листов они не заполняются
+type PickListModelResponse struct {
+	Id          uint64                  `json:"id"`
+	WarehouseId uint64                  `json:"warehouse_id"`
+	Status      string                  `json:"status"`
+	TaskCount   int                     `json:"task_count"`
+	OpenTasks   int                     `json:"open_tasks"`
+	CreatedBy   string                  `json:"created_by"`
+	CreatedAt   time.Time               `json:"created_at"`
+	DoneAt      *time.Time              `json:"done_at"`
+	Stops       []PickStopModelResponse `json:"stops,omitempty"`
+}
@@ -1,17 +1,93 @@
 package delivery
 
+import "time"
+
+// ProductModelRequest описывает остаток позиции каталога SkuId в зоне. Название и описание задаются в каталоге.
+// Count задается в единице Unit, пустая единица - базовая
 type ProductModelRequest struct {
-	Title       string `json:"title"`
-	Count       uint64 `json:"count"`
-	Description string `json:"description"`
-	ZoneId uint64 `json:"zone_id"`
+	SkuId          uint64     `json:"sku_id"`
+	Count          float64    `json:"count"`
+	Unit           string     `json:"unit"`
+	ZoneId         uint64     `json:"zone_id"`
+	LocationId     *uint64    `json:"location_id"`
+	LotNumber      string     `json:"lot_number"`
+	ProductionDate *time.Time `json:"production_date"`
+	ExpiryDate     *time.Time `json:"expiry_date"`
+	Serials        []string   `json:"serials"`
 }
 
+// ProductModelResponse: Title, Description, Unit и SerialTracked берутся из позиции каталога.
+// Count, Reserved, Held и Available - в базовой единице Unit, Quantity и AvailableQuantity - в единице QuantityUnit.
+// Held - остаток под блокировками (карантин, брак, блокировка), в Available он не входит
 type ProductModelResponse struct {
-	Uuid        string `json:"uuid"`
-	Title       string `json:"title"`
-	Count       uint64 `json:"count"`
-	QrImage     string `json:"qr_path"`
-	Description string `json:"description"`
-	ZoneId      uint64 `json:"zone_id"`
+	Uuid              string     `json:"uuid"`
+	SkuId             uint64     `json:"sku_id"`
+	SkuCode           string     `json:"sku_code"`
+	Title             string     `json:"title"`
+	Unit              string     `json:"unit"`
+	Count             float64    `json:"count"`
+	Reserved          float64    `json:"reserved"`
+	Held              float64    `json:"held"`
+	Available         float64    `json:"available"`
+	QuantityUnit      string     `json:"quantity_unit"`
+	Quantity          float64    `json:"quantity"`
+	AvailableQuantity float64    `json:"available_quantity"`
+	QrImage           string     `json:"qr_path"`
+	Description       string     `json:"description"`
+	ZoneId            uint64     `json:"zone_id"`
+	LocationId        *uint64    `json:"location_id"`
+	LotNumber         string     `json:"lot_number"`
+	ProductionDate    *time.Time `json:"production_date"`
+	ExpiryDate        *time.Time `json:"expiry_date"`
+	SerialTracked     bool       `json:"serial_tracked"`
+}
+
+// MoveProductModelRequest: LocationId - целевая ячейка в зоне ZoneId, без нее товар кладется в зону без ячейки.
+// Для переноса между ячейками одной зоны ZoneId - текущая зона товара. Serials обязательны при частичном переносе серийного товара.
+// Count задается в единице Unit, пустая единица - базовая
+type MoveProductModelRequest struct {
+	ZoneId     uint64   `json:"zone_id"`
+	LocationId *uint64  `json:"location_id"`
+	Count      float64  `json:"count"`
+	Unit       string   `json:"unit"`
+	Serials    []string `json:"serials"`
+}
+
+// FefoLineResponse - партия, из которой предлагается взять Take единиц
+type FefoLineResponse struct {
+	ProductUuid string     `json:"product_uuid"`
+	ZoneId      uint64     `json:"zone_id"`
+	LotNumber   string     `json:"lot_number"`
+	ExpiryDate  *time.Time `json:"expiry_date"`
+	Available   float64    `json:"available"`
+	Take        float64    `json:"take"`
+}
+
+// FefoSuggestionResponse: все количества, в том числе в строках, - в единице Unit
+type FefoSuggestionResponse struct {
+	SkuId     uint64             `json:"sku_id"`
+	Unit      string             `json:"unit"`
+	Requested float64            `json:"requested"`
+	Suggested float64            `json:"suggested"`
+	Shortage  float64            `json:"shortage"`
+	Lines     []FefoLineResponse `json:"lines"`
+}
+
+type ExpiringProductResponse struct {
+	Uuid       string    `json:"uuid"`
+	SkuId      uint64    `json:"sku_id"`
+	SkuCode    string    `json:"sku_code"`
+	Title      string    `json:"title"`
+	ZoneId     uint64    `json:"zone_id"`
+	LotNumber  string    `json:"lot_number"`
+	ExpiryDate time.Time `json:"expiry_date"`
+	Unit       string    `json:"unit"`
+	Count      float64   `json:"count"`
+	DaysLeft   int       `json:"days_left"`
+	Expired    bool      `json:"expired"`
+}
+
+type ExpiringReportResponse struct {
+	Days     int                       `json:"days"`
+	Products []ExpiringProductResponse `json:"products"`
 }
@@ -0,0 +1,56 @@
+package delivery
+
+import "time"
+
+// PurchaseOrderLineModelRequest: Quantity задается в единице Unit позиции, пустая единица - базовая.
+// Пустая ExpectedDate - строка ожидается к дате заказа
+type PurchaseOrderLineModelRequest struct {
+	SkuId        uint64     `json:"sku_id"`
+	Quantity     float64    `json:"quantity"`
+	Unit         string     `json:"unit"`
+	ExpectedDate *time.Time `json:"expected_date"`
+}
+
+type PurchaseOrderModelRequest struct {
+	SupplierId   uint64                          `json:"supplier_id"`
+	ExpectedDate *time.Time                      `json:"expected_date"`
+	Comment      string                          `json:"comment"`
+	Lines        []PurchaseOrderLineModelRequest `json:"lines"`
+}
+
+// PurchaseOrderReceiptModelRequest: ZoneId - зона, в которую принимается весь непоставленный остаток заказа
+type PurchaseOrderReceiptModelRequest struct {
+	ZoneId  uint64 `json:"zone_id"`
+	Comment string `json:"comment"`
+}
+
+// PurchaseOrderLineModelResponse: количества указаны в единице Unit строки. OutstandingQuantity - еще ожидается,
+// OverQuantity - принято сверх заказанного, UnderQuantity - не поставлено по закрытому заказу
+type PurchaseOrderLineModelResponse struct {
+	Id                  uint64     `json:"id"`
+	SkuId               uint64     `json:"sku_id"`
+	Title               string     `json:"title"`
+	Unit                string     `json:"unit"`
+	Quantity            float64    `json:"quantity"`
+	ReceivedQuantity    float64    `json:"received_quantity"`
+	OutstandingQuantity float64    `json:"outstanding_quantity"`
+	OverQuantity        float64    `json:"over_quantity"`
+	UnderQuantity       float64    `json:"under_quantity"`
+	ExpectedDate        *time.Time `json:"expected_date"`
+}
+
+// PurchaseOrderModelResponse: Overdue - по заказу еще ожидается товар, дата поставки которого уже прошла
+type PurchaseOrderModelResponse struct {
+	Id           uint64                           `json:"id"`
+	WarehouseId  uint64                           `json:"warehouse_id"`
+	SupplierId   uint64                           `json:"supplier_id"`
+	SupplierName string                           `json:"supplier_name"`
+	Status       string                           `json:"status"`
+	ExpectedDate *time.Time                       `json:"expected_date"`
+	Overdue      bool                             `json:"overdue"`
+	Comment      string                           `json:"comment"`
+	CreatedBy    string                           `json:"created_by"`
+	CreatedAt    time.Time                        `json:"created_at"`
+	ClosedAt     *time.Time                       `json:"closed_at"`
+	Lines        []PurchaseOrderLineModelResponse `json:"lines"`
+}
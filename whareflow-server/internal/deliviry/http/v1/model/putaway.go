@@ -0,0 +1,61 @@
+package delivery
+
+import "time"
+
+// PutawayModelRequest: LocationId - ячейка зоны, nil - зона без ячейки. Reason обязателен, если место
+// отличается от первого предложенного
+type PutawayModelRequest struct {
+	ZoneId     uint64  `json:"zone_id"`
+	LocationId *uint64 `json:"location_id"`
+	Reason     string  `json:"reason"`
+}
+
+// PutawayCandidateModelResponse - предложенное место размещения. Score - итоговая оценка от 0 до 1, из которой
+// складываются оценки свободного места, совместимости хранения, консолидации с тем же товаром и расстояния.
+// SameSkuQuantity - остаток той же позиции в ячейке, а для места без ячейки - в зоне, в единице строки.
+// Distance - число шагов маршрута обхода от места приемки, nil - место вне маршрута
+type PutawayCandidateModelResponse struct {
+	ZoneId             uint64  `json:"zone_id"`
+	ZoneName           string  `json:"zone_name"`
+	LocationId         *uint64 `json:"location_id"`
+	LocationPath       string  `json:"location_path"`
+	Score              float64 `json:"score"`
+	CapacityScore      float64 `json:"capacity_score"`
+	CompatibilityScore float64 `json:"compatibility_score"`
+	ConsolidationScore float64 `json:"consolidation_score"`
+	DistanceScore      float64 `json:"distance_score"`
+	SameSkuQuantity    float64 `json:"same_sku_quantity"`
+	Distance           *int    `json:"distance"`
+}
+
+// PutawayLineModelResponse - строка поступления, ожидающая размещения. ZoneId и LocationId - где товар лежит сейчас,
+// Candidates упорядочены по убыванию оценки
+type PutawayLineModelResponse struct {
+	LineId      uint64                          `json:"line_id"`
+	ProductUuid *string                         `json:"product_uuid"`
+	SkuId       uint64                          `json:"sku_id"`
+	SkuCode     string                          `json:"sku_code"`
+	Title       string                          `json:"title"`
+	Quantity    float64                         `json:"quantity"`
+	Unit        string                          `json:"unit"`
+	ZoneId      uint64                          `json:"zone_id"`
+	LocationId  *uint64                         `json:"location_id"`
+	Candidates  []PutawayCandidateModelResponse `json:"candidates"`
+}
+
+type PutawayModelResponse struct {
+	Id                  uint64    `json:"id"`
+	ReceiptId           uint64    `json:"receipt_id"`
+	LineId              uint64    `json:"line_id"`
+	ProductUuid         *string   `json:"product_uuid"`
+	Quantity            float64   `json:"quantity"`
+	Unit                string    `json:"unit"`
+	ZoneId              uint64    `json:"zone_id"`
+	LocationId          *uint64   `json:"location_id"`
+	SuggestedZoneId     *uint64   `json:"suggested_zone_id"`
+	SuggestedLocationId *uint64   `json:"suggested_location_id"`
+	Override            bool      `json:"override"`
+	Reason              string    `json:"reason"`
+	CreatedBy           string    `json:"created_by"`
+	CreatedAt           time.Time `json:"created_at"`
+}
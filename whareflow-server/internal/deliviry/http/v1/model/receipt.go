@@ -0,0 +1,68 @@
+package delivery
+
+import "time"
+
+// ReceiptLineModelRequest без product_uuid создает новую строку остатка позиции sku_id в зоне zone_id.
+// Если для существующего товара указана другая партия, при проведении создается новая строка товара.
+// Quantity задается в единице Unit позиции, пустая единица - базовая.
+// В поступлении по заказу поставщику каждая строка указывает purchase_order_line_id
+type ReceiptLineModelRequest struct {
+	ProductUuid         string     `json:"product_uuid"`
+	SkuId               uint64     `json:"sku_id"`
+	ZoneId              uint64     `json:"zone_id"`
+	Quantity            float64    `json:"quantity"`
+	Unit                string     `json:"unit"`
+	LotNumber           string     `json:"lot_number"`
+	ProductionDate      *time.Time `json:"production_date"`
+	ExpiryDate          *time.Time `json:"expiry_date"`
+	PurchaseOrderLineId *uint64    `json:"purchase_order_line_id"`
+}
+
+// ReceiptModelRequest: PurchaseOrderId задается только при создании, при изменении черновика не меняется
+type ReceiptModelRequest struct {
+	PurchaseOrderId *uint64                   `json:"purchase_order_id"`
+	Comment         string                    `json:"comment"`
+	Lines           []ReceiptLineModelRequest `json:"lines"`
+}
+
+// ReceivedLineModelRequest: ReceivedQuantity задается в единице строки.
+// Для серийного товара Serials должны совпадать с принятым количеством
+type ReceivedLineModelRequest struct {
+	LineId           uint64   `json:"line_id"`
+	ReceivedQuantity float64  `json:"received_quantity"`
+	Serials          []string `json:"serials"`
+}
+
+type ReceiveReceiptModelRequest struct {
+	Lines []ReceivedLineModelRequest `json:"lines"`
+}
+
+// ReceiptLineModelResponse: количества указаны в единице Unit, в которой строка была заведена
+type ReceiptLineModelResponse struct {
+	Id                  uint64     `json:"id"`
+	ProductUuid         string     `json:"product_uuid"`
+	SkuId               uint64     `json:"sku_id"`
+	Title               string     `json:"title"`
+	Description         string     `json:"description"`
+	ZoneId              uint64     `json:"zone_id"`
+	Unit                string     `json:"unit"`
+	Quantity            float64    `json:"quantity"`
+	ReceivedQuantity    float64    `json:"received_quantity"`
+	LotNumber           string     `json:"lot_number"`
+	ProductionDate      *time.Time `json:"production_date"`
+	ExpiryDate          *time.Time `json:"expiry_date"`
+	PurchaseOrderLineId *uint64    `json:"purchase_order_line_id"`
+}
+
+type ReceiptModelResponse struct {
+	Id              uint64                     `json:"id"`
+	WarehouseId     uint64                     `json:"warehouse_id"`
+	PurchaseOrderId *uint64                    `json:"purchase_order_id"`
+	Status          string                     `json:"status"`
+	Comment         string                     `json:"comment"`
+	CreatedBy       string                     `json:"created_by"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	ReceivedAt      *time.Time                 `json:"received_at"`
+	PostedAt        *time.Time                 `json:"posted_at"`
+	Lines           []ReceiptLineModelResponse `json:"lines"`
+}
@@ -0,0 +1,41 @@
+package delivery
+
+import "time"
+
+// ReorderRuleModelRequest: уровни задаются в единице Unit, пустая единица - базовая
+type ReorderRuleModelRequest struct {
+	SkuId    uint64  `json:"sku_id"`
+	MinLevel float64 `json:"min_level"`
+	MaxLevel float64 `json:"max_level"`
+	Unit     string  `json:"unit"`
+}
+
+// ReorderRuleModelResponse: уровни в базовой единице Unit
+type ReorderRuleModelResponse struct {
+	Id          uint64     `json:"id"`
+	WarehouseId uint64     `json:"warehouse_id"`
+	SkuId       uint64     `json:"sku_id"`
+	Unit        string     `json:"unit"`
+	MinLevel    float64    `json:"min_level"`
+	MaxLevel    float64    `json:"max_level"`
+	AlertedAt   *time.Time `json:"alerted_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// LowStockItemResponse: ReorderQuantity - сколько нужно дозаказать, чтобы остаток дошел до max_level.
+// Количества в базовой единице Unit
+type LowStockItemResponse struct {
+	SkuId           uint64  `json:"sku_id"`
+	SkuCode         string  `json:"sku_code"`
+	Title           string  `json:"title"`
+	Unit            string  `json:"unit"`
+	OnHand          float64 `json:"on_hand"`
+	MinLevel        float64 `json:"min_level"`
+	MaxLevel        float64 `json:"max_level"`
+	ReorderQuantity float64 `json:"reorder_quantity"`
+}
+
+type LowStockReportResponse struct {
+	WarehouseId uint64                 `json:"warehouse_id"`
+	Items       []LowStockItemResponse `json:"items"`
+}
@@ -0,0 +1,28 @@
+package delivery
+
+import "time"
+
+// ReservationModelRequest задает срок резерва либо датой ExpiresAt, либо временем жизни TtlMinutes.
+// Quantity в единице Unit, пустая единица - базовая
+type ReservationModelRequest struct {
+	ProductUuid string     `json:"product_uuid"`
+	Quantity    float64    `json:"quantity"`
+	Unit        string     `json:"unit"`
+	OwnerRef    string     `json:"owner_ref"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	TtlMinutes  uint64     `json:"ttl_minutes"`
+}
+
+// ReservationModelResponse: Quantity в базовой единице Unit. ExpiresAt пустой у бессрочного резерва подтвержденного заказа
+type ReservationModelResponse struct {
+	Id          uint64     `json:"id"`
+	ProductUuid string     `json:"product_uuid"`
+	Unit        string     `json:"unit"`
+	Quantity    float64    `json:"quantity"`
+	OwnerRef    string     `json:"owner_ref"`
+	Status      string     `json:"status"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ReleasedAt  *time.Time `json:"released_at"`
+}
@@ -0,0 +1,66 @@
+package delivery
+
+import "time"
+
+// SalesOrderLineModelRequest: Quantity задается в единице Unit позиции товара, пустая единица - базовая
+type SalesOrderLineModelRequest struct {
+	ProductUuid string  `json:"product_uuid"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit"`
+}
+
+// SalesOrderModelRequest: ReservedUntil - до какого момента строки держат резерв, по умолчанию 30 дней
+type SalesOrderModelRequest struct {
+	CustomerId    uint64                       `json:"customer_id"`
+	ReservedUntil *time.Time                   `json:"reserved_until"`
+	Comment       string                       `json:"comment"`
+	Lines         []SalesOrderLineModelRequest `json:"lines"`
+}
+
+type SalesOrderLineModelResponse struct {
+	Id            uint64  `json:"id"`
+	ProductUuid   string  `json:"product_uuid"`
+	Unit          string  `json:"unit"`
+	Quantity      float64 `json:"quantity"`
+	ReservationId *uint64 `json:"reservation_id"`
+}
+
+type SalesOrderModelResponse struct {
+	Id            uint64                        `json:"id"`
+	WarehouseId   uint64                        `json:"warehouse_id"`
+	CustomerId    uint64                        `json:"customer_id"`
+	CustomerName  string                        `json:"customer_name"`
+	Status        string                        `json:"status"`
+	ShipmentId    *uint64                       `json:"shipment_id"`
+	ReservedUntil time.Time                     `json:"reserved_until"`
+	Comment       string                        `json:"comment"`
+	CreatedBy     string                        `json:"created_by"`
+	CreatedAt     time.Time                     `json:"created_at"`
+	ConfirmedAt   *time.Time                    `json:"confirmed_at"`
+	ShippedAt     *time.Time                    `json:"shipped_at"`
+	CancelledAt   *time.Time                    `json:"cancelled_at"`
+	Lines         []SalesOrderLineModelResponse `json:"lines"`
+}
+
+// CompletePickTaskModelRequest: PickedQuantity задается в единице задания, без него задание собрано полностью
+type CompletePickTaskModelRequest struct {
+	PickedQuantity *float64 `json:"picked_quantity"`
+}
+
+// PickTaskModelResponse: количества указаны в единице Unit строки заказа
+type PickTaskModelResponse struct {
+	Id             uint64     `json:"id"`
+	OrderId        uint64     `json:"order_id"`
+	WaveId         *uint64    `json:"wave_id"`
+	ShipmentLineId *uint64    `json:"shipment_line_id"`
+	ProductUuid    string     `json:"product_uuid"`
+	ZoneId         uint64     `json:"zone_id"`
+	LocationId     *uint64    `json:"location_id"`
+	Unit           string     `json:"unit"`
+	Quantity       float64    `json:"quantity"`
+	PickedQuantity float64    `json:"picked_quantity"`
+	Status         string     `json:"status"`
+	PickedBy       *string    `json:"picked_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DoneAt         *time.Time `json:"done_at"`
+}
@@ -0,0 +1,31 @@
+package delivery
+
+import "time"
+
+type SerialNumberResponse struct {
+	Id          uint64    `json:"id"`
+	Serial      string    `json:"serial"`
+	Status      string    `json:"status"`
+	ProductUuid *string   `json:"product_uuid"`
+	QrImage     string    `json:"qr_path"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SerialMovementResponse - движение, в котором участвовал номер. ProductUuid - строка остатка, по которой прошло движение
+type SerialMovementResponse struct {
+	ProductUuid  string    `json:"product_uuid"`
+	Reason       string    `json:"reason"`
+	ActorUuid    string    `json:"actor_uuid"`
+	SourceZoneId *uint64   `json:"source_zone_id"`
+	TargetZoneId *uint64   `json:"target_zone_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type SerialHistoryResponse struct {
+	Serial      string                   `json:"serial"`
+	Status      string                   `json:"status"`
+	ProductUuid *string                  `json:"product_uuid"`
+	ZoneId      *uint64                  `json:"zone_id"`
+	QrImage     string                   `json:"qr_path"`
+	Movements   []SerialMovementResponse `json:"movements"`
+}
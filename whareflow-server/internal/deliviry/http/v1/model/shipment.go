@@ -0,0 +1,47 @@
+package delivery
+
+import "time"
+
+// ShipmentLineModelRequest: Quantity задается в единице Unit позиции товара, пустая единица - базовая
+type ShipmentLineModelRequest struct {
+	ProductUuid string  `json:"product_uuid"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit"`
+}
+
+type ShipmentModelRequest struct {
+	Comment string                     `json:"comment"`
+	Lines   []ShipmentLineModelRequest `json:"lines"`
+}
+
+// PickedLineModelRequest: PickedQuantity задается в единице строки. Для серийного товара Serials - номера, собранные по строке
+type PickedLineModelRequest struct {
+	LineId         uint64   `json:"line_id"`
+	PickedQuantity float64  `json:"picked_quantity"`
+	Serials        []string `json:"serials"`
+}
+
+type PackShipmentModelRequest struct {
+	Lines []PickedLineModelRequest `json:"lines"`
+}
+
+// ShipmentLineModelResponse: количества указаны в единице Unit, в которой строка была заведена
+type ShipmentLineModelResponse struct {
+	Id             uint64  `json:"id"`
+	ProductUuid    string  `json:"product_uuid"`
+	Unit           string  `json:"unit"`
+	Quantity       float64 `json:"quantity"`
+	PickedQuantity float64 `json:"picked_quantity"`
+}
+
+type ShipmentModelResponse struct {
+	Id          uint64                      `json:"id"`
+	WarehouseId uint64                      `json:"warehouse_id"`
+	Status      string                      `json:"status"`
+	Comment     string                      `json:"comment"`
+	CreatedBy   string                      `json:"created_by"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	PackedAt    *time.Time                  `json:"packed_at"`
+	ShippedAt   *time.Time                  `json:"shipped_at"`
+	Lines       []ShipmentLineModelResponse `json:"lines"`
+}
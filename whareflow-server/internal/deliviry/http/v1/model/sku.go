@@ -0,0 +1,52 @@
+package delivery
+
+import "time"
+
+// SkuUnitModel - дополнительная единица позиции: Factor базовых единиц в одной единице Code
+type SkuUnitModel struct {
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Factor float64 `json:"factor"`
+}
+
+// SkuModelRequest: Unit - базовая единица, в которой хранится остаток, Decimals - число знаков после запятой для нее.
+// Пустой TemperatureClass означает ambient
+type SkuModelRequest struct {
+	Code             string            `json:"code"`
+	Name             string            `json:"name"`
+	Description      string            `json:"description"`
+	Unit             string            `json:"unit"`
+	Barcode          string            `json:"barcode"`
+	Attributes       map[string]string `json:"attributes"`
+	SerialTracked    bool              `json:"serial_tracked"`
+	Decimals         uint8             `json:"decimals"`
+	Units            []SkuUnitModel    `json:"units"`
+	LengthCm         float64           `json:"length_cm"`
+	WidthCm          float64           `json:"width_cm"`
+	HeightCm         float64           `json:"height_cm"`
+	WeightKg         float64           `json:"weight_kg"`
+	UnitsPerPallet   uint64            `json:"units_per_pallet"`
+	TemperatureClass string            `json:"temperature_class"`
+	HazardClass      string            `json:"hazard_class"`
+}
+
+type SkuModelResponse struct {
+	Id               uint64            `json:"id"`
+	Code             string            `json:"code"`
+	Name             string            `json:"name"`
+	Description      string            `json:"description"`
+	Unit             string            `json:"unit"`
+	Barcode          *string           `json:"barcode"`
+	Attributes       map[string]string `json:"attributes"`
+	SerialTracked    bool              `json:"serial_tracked"`
+	Decimals         uint8             `json:"decimals"`
+	Units            []SkuUnitModel    `json:"units"`
+	LengthCm         float64           `json:"length_cm"`
+	WidthCm          float64           `json:"width_cm"`
+	HeightCm         float64           `json:"height_cm"`
+	WeightKg         float64           `json:"weight_kg"`
+	UnitsPerPallet   uint64            `json:"units_per_pallet"`
+	TemperatureClass string            `json:"temperature_class"`
+	HazardClass      string            `json:"hazard_class"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
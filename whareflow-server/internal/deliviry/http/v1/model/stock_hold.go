@@ -0,0 +1,52 @@
+package delivery
+
+import "time"
+
+// StockHoldModelRequest: Status - quarantine, damaged или blocked, Quantity - в единице Unit, пустая единица - базовая
+type StockHoldModelRequest struct {
+	ProductUuid string  `json:"product_uuid"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit"`
+	Status      string  `json:"status"`
+	Reason      string  `json:"reason"`
+}
+
+type StockHoldStatusModelRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// ReleaseStockHoldModelRequest: нулевой Quantity снимает блокировку целиком. Quantity в единице Unit, пустая единица - базовая
+type ReleaseStockHoldModelRequest struct {
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Reason   string  `json:"reason"`
+}
+
+// StockHoldModelResponse: Quantity в базовой единице Unit
+type StockHoldModelResponse struct {
+	Id          uint64     `json:"id"`
+	ProductUuid string     `json:"product_uuid"`
+	Unit        string     `json:"unit"`
+	Quantity    float64    `json:"quantity"`
+	Status      string     `json:"status"`
+	Reason      string     `json:"reason"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ReleasedBy  *string    `json:"released_by"`
+	ReleasedAt  *time.Time `json:"released_at"`
+}
+
+// StockStatusChangeModelResponse: Quantity в базовой единице Unit
+type StockStatusChangeModelResponse struct {
+	Id          uint64    `json:"id"`
+	HoldId      uint64    `json:"hold_id"`
+	ProductUuid string    `json:"product_uuid"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	Unit        string    `json:"unit"`
+	Quantity    float64   `json:"quantity"`
+	Reason      string    `json:"reason"`
+	ActorUuid   string    `json:"actor_uuid"`
+	CreatedAt   time.Time `json:"created_at"`
+}
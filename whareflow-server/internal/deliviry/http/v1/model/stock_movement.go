@@ -0,0 +1,23 @@
+package delivery
+
+import "time"
+
+type StockMovementResponse struct {
+	Id           uint64    `json:"id"`
+	Quantity     float64   `json:"quantity"`
+	Reason       string    `json:"reason"`
+	ActorUuid    string    `json:"actor_uuid"`
+	SourceZoneId *uint64   `json:"source_zone_id"`
+	TargetZoneId *uint64   `json:"target_zone_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StockMovementListResponse: остаток, баланс журнала и количества движений - в базовой единице Unit
+type StockMovementListResponse struct {
+	ProductUuid   string                  `json:"product_uuid"`
+	Unit          string                  `json:"unit"`
+	Count         float64                 `json:"count"`
+	LedgerBalance float64                 `json:"ledger_balance"`
+	Reconciled    bool                    `json:"reconciled"`
+	Movements     []StockMovementResponse `json:"movements"`
+}
@@ -0,0 +1,23 @@
+package delivery
+
+import "time"
+
+type SupplierModelRequest struct {
+	Name        string `json:"name"`
+	TaxId       string `json:"tax_id"`
+	ContactName string `json:"contact_name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	Address     string `json:"address"`
+}
+
+type SupplierModelResponse struct {
+	Id          uint64    `json:"id"`
+	Name        string    `json:"name"`
+	TaxId       string    `json:"tax_id"`
+	ContactName string    `json:"contact_name"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	Address     string    `json:"address"`
+	CreatedAt   time.Time `json:"created_at"`
+}
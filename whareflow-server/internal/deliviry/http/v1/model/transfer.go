@@ -0,0 +1,84 @@
+package delivery
+
+import "time"
+
+// TransferLineModelRequest: Quantity в единице Unit, пустая единица - базовая
+type TransferLineModelRequest struct {
+	ProductUuid string  `json:"product_uuid"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit"`
+}
+
+type TransferModelRequest struct {
+	TargetWarehouseId uint64                     `json:"target_warehouse_id"`
+	Comment           string                     `json:"comment"`
+	Lines             []TransferLineModelRequest `json:"lines"`
+}
+
+// ReceivedTransferLineModelRequest: ReceivedQuantity в единице Unit, пустая единица - базовая
+type ReceivedTransferLineModelRequest struct {
+	LineId           uint64  `json:"line_id"`
+	ReceivedQuantity float64 `json:"received_quantity"`
+	Unit             string  `json:"unit"`
+	ZoneId           uint64  `json:"zone_id"`
+}
+
+type ReceiveTransferModelRequest struct {
+	ZoneId uint64                             `json:"zone_id"`
+	Lines  []ReceivedTransferLineModelRequest `json:"lines"`
+}
+
+// TransferLineModelResponse: Quantity и ReceivedQuantity в базовой единице Unit
+type TransferLineModelResponse struct {
+	Id                uint64     `json:"id"`
+	ProductUuid       *string    `json:"product_uuid"`
+	SkuId             *uint64    `json:"sku_id"`
+	Title             string     `json:"title"`
+	Description       string     `json:"description"`
+	Unit              string     `json:"unit"`
+	Quantity          float64    `json:"quantity"`
+	ReceivedQuantity  float64    `json:"received_quantity"`
+	TargetZoneId      *uint64    `json:"target_zone_id"`
+	TargetProductUuid *string    `json:"target_product_uuid"`
+	LotNumber         string     `json:"lot_number"`
+	ProductionDate    *time.Time `json:"production_date"`
+	ExpiryDate        *time.Time `json:"expiry_date"`
+}
+
+// TransferModelResponse: InTransit - сумма строк в пути в их базовых единицах
+type TransferModelResponse struct {
+	Id                uint64                      `json:"id"`
+	SourceWarehouseId uint64                      `json:"source_warehouse_id"`
+	TargetWarehouseId uint64                      `json:"target_warehouse_id"`
+	Status            string                      `json:"status"`
+	Comment           string                      `json:"comment"`
+	CreatedBy         string                      `json:"created_by"`
+	ShippedBy         *string                     `json:"shipped_by"`
+	ReceivedBy        *string                     `json:"received_by"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	ShippedAt         *time.Time                  `json:"shipped_at"`
+	ReceivedAt        *time.Time                  `json:"received_at"`
+	InTransit         float64                     `json:"in_transit"`
+	Lines             []TransferLineModelResponse `json:"lines"`
+}
+
+// TransferDiscrepancyLineResponse: количества в базовой единице Unit
+type TransferDiscrepancyLineResponse struct {
+	LineId      uint64  `json:"line_id"`
+	ProductUuid *string `json:"product_uuid"`
+	Title       string  `json:"title"`
+	Unit        string  `json:"unit"`
+	Shipped     float64 `json:"shipped"`
+	Received    float64 `json:"received"`
+	Difference  float64 `json:"difference"`
+}
+
+// TransferDiscrepancyResponse: итоги - суммы строк в их базовых единицах
+type TransferDiscrepancyResponse struct {
+	TransferId     uint64                            `json:"transfer_id"`
+	Status         string                            `json:"status"`
+	TotalShipped   float64                           `json:"total_shipped"`
+	TotalReceived  float64                           `json:"total_received"`
+	HasDiscrepancy bool                              `json:"has_discrepancy"`
+	Lines          []TransferDiscrepancyLineResponse `json:"lines"`
+}
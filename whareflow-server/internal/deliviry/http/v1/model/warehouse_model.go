@@ -1,7 +1,7 @@
 package delivery
 
 type WarehouseModelRequest struct {
-	Name    string `json:"name`
+	Name    string `json:"name"`
 	Address string `json:"address"`
 }
 
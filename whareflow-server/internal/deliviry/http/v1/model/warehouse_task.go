@@ -0,0 +1,80 @@
+package delivery
+
+import "time"
+
+// WarehouseTaskModelRequest: Type - receive, putaway, pick, count или move. Без AssigneeId задание попадает в общую очередь склада.
+// Для receive и putaway обязателен ReceiptId - поступление склада, для pick - PickTaskId, задание на сборку заказа
+type WarehouseTaskModelRequest struct {
+	Type        string     `json:"type"`
+	Priority    int        `json:"priority"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Reference   string     `json:"reference"`
+	ProductUuid *string    `json:"product_uuid"`
+	ZoneId      *uint64    `json:"zone_id"`
+	LocationId  *uint64    `json:"location_id"`
+	ReceiptId   *uint64    `json:"receipt_id"`
+	PickTaskId  *uint64    `json:"pick_task_id"`
+	AssigneeId  *string    `json:"assignee_id"`
+	DueAt       *time.Time `json:"due_at"`
+}
+
+// AssignWarehouseTaskModelRequest: AssigneeId = null возвращает задание в общую очередь
+type AssignWarehouseTaskModelRequest struct {
+	AssigneeId *string `json:"assignee_id"`
+}
+
+type AbandonWarehouseTaskModelRequest struct {
+	Reason string `json:"reason"`
+}
+
+type WarehouseTaskModelResponse struct {
+	Id          uint64     `json:"id"`
+	WarehouseId uint64     `json:"warehouse_id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Priority    int        `json:"priority"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Reference   string     `json:"reference"`
+	ProductUuid *string    `json:"product_uuid"`
+	ZoneId      *uint64    `json:"zone_id"`
+	LocationId  *uint64    `json:"location_id"`
+	ReceiptId   *uint64    `json:"receipt_id"`
+	PickTaskId  *uint64    `json:"pick_task_id"`
+	AssigneeId  *string    `json:"assignee_id"`
+	ClaimedBy   *string    `json:"claimed_by"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DueAt       *time.Time `json:"due_at"`
+	ClaimedAt   *time.Time `json:"claimed_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+}
+
+// TaskTypeStatModelResponse: AvgMinutes - среднее время выполнения задания этого типа от взятия в работу
+type TaskTypeStatModelResponse struct {
+	Type       string  `json:"type"`
+	Completed  int64   `json:"completed"`
+	AvgMinutes float64 `json:"avg_minutes"`
+}
+
+// WorkerStatModelResponse - выработка работника за период. WorkMinutes - время в работе по выполненным заданиям
+type WorkerStatModelResponse struct {
+	UserId      string                      `json:"user_id"`
+	Username    string                      `json:"username"`
+	FirstName   string                      `json:"first_name"`
+	LastName    string                      `json:"last_name"`
+	Claimed     int64                       `json:"claimed"`
+	Completed   int64                       `json:"completed"`
+	Abandoned   int64                       `json:"abandoned"`
+	WorkMinutes float64                     `json:"work_minutes"`
+	AvgMinutes  float64                     `json:"avg_minutes"`
+	ByType      []TaskTypeStatModelResponse `json:"by_type"`
+}
+
+type TaskStatsModelResponse struct {
+	From    time.Time                 `json:"from"`
+	To      time.Time                 `json:"to"`
+	Workers []WorkerStatModelResponse `json:"workers"`
+}
@@ -0,0 +1,70 @@
+package delivery
+
+import "time"
+
+// WaveModelRequest: OrderIds - заказы волны, без них в волну попадают самые ранние подходящие заказы, не больше MaxOrders
+// (0 - без ограничения). MaxOrders удобно приравнять к числу ячеек стены сортировки
+type WaveModelRequest struct {
+	OrderIds  []uint64 `json:"order_ids"`
+	MaxOrders int      `json:"max_orders"`
+}
+
+type WavePickLineModel struct {
+	LineId         uint64  `json:"line_id"`
+	PickedQuantity float64 `json:"picked_quantity"`
+}
+
+// WavePickModelRequest перечисляет только строки с недобором, остальные строки волны считаются собранными полностью
+type WavePickModelRequest struct {
+	Lines []WavePickLineModel `json:"lines"`
+}
+
+// WaveSortModelRequest - отсканированный на стене сортировки товар. Quantity указывается в единице строки волны,
+// без него раскладывается одна единица
+type WaveSortModelRequest struct {
+	ProductUuid string   `json:"product_uuid"`
+	Quantity    *float64 `json:"quantity"`
+}
+
+type WaveOrderModelResponse struct {
+	OrderId uint64 `json:"order_id"`
+	Slot    int    `json:"slot"`
+}
+
+// WaveLineModelResponse: Sequence - порядок строки на маршруте обхода склада
+type WaveLineModelResponse struct {
+	Id             uint64  `json:"id"`
+	Sequence       int     `json:"sequence"`
+	ProductUuid    string  `json:"product_uuid"`
+	ZoneId         uint64  `json:"zone_id"`
+	ZoneName       string  `json:"zone_name"`
+	LocationId     *uint64 `json:"location_id"`
+	LocationPath   string  `json:"location_path"`
+	Unit           string  `json:"unit"`
+	Quantity       float64 `json:"quantity"`
+	PickedQuantity float64 `json:"picked_quantity"`
+	SortedQuantity float64 `json:"sorted_quantity"`
+}
+
+// WaveModelResponse: Lines упорядочены по маршруту обхода склада, в списке волн они не заполняются
+type WaveModelResponse struct {
+	Id          uint64                   `json:"id"`
+	WarehouseId uint64                   `json:"warehouse_id"`
+	Status      string                   `json:"status"`
+	CreatedBy   string                   `json:"created_by"`
+	CreatedAt   time.Time                `json:"created_at"`
+	PickedAt    *time.Time               `json:"picked_at"`
+	DoneAt      *time.Time               `json:"done_at"`
+	Orders      []WaveOrderModelResponse `json:"orders"`
+	Lines       []WaveLineModelResponse  `json:"lines,omitempty"`
+}
+
+// WaveSortPutModelResponse - сколько отсканированного товара положить в ячейку Slot, в единице задания заказа.
+// Slot = 0 - товар отмененных заказов, его возвращают на место хранения строки волны
+type WaveSortPutModelResponse struct {
+	Slot     int     `json:"slot"`
+	OrderId  uint64  `json:"order_id"`
+	TaskId   uint64  `json:"task_id"`
+	Unit     string  `json:"unit"`
+	Quantity float64 `json:"quantity"`
+}
@@ -1,12 +1,31 @@
 package delivery
 
+// ZoneModelRequest: MaxVolume в м³, MaxWeight в кг, пустое значение снимает предел.
+// Пустые ZoneType и TemperatureClass означают general и ambient
 type ZoneModelRequest struct {
-	Name     string `json:"name"`
-	Capacity int    `json:"capacity"`
+	Name             string   `json:"name"`
+	Capacity         int      `json:"capacity"`
+	ZoneType         string   `json:"zone_type"`
+	TemperatureClass string   `json:"temperature_class"`
+	MaxVolume        *float64 `json:"max_volume"`
+	MaxWeight        *float64 `json:"max_weight"`
+	MaxPallets       *uint64  `json:"max_pallets"`
 }
 
+// ZoneModelResponse: проценты загрузки равны nil, если соответствующий предел не задан
 type ZoneModelResponse struct {
-	Id       int    `json:"id"`
-	Name     string `json:"name"`
-	Capacity int    `json:"capacity"`
+	Id                int      `json:"id"`
+	Name              string   `json:"name"`
+	Capacity          int      `json:"capacity"`
+	ZoneType          string   `json:"zone_type"`
+	TemperatureClass  string   `json:"temperature_class"`
+	MaxVolume         *float64 `json:"max_volume"`
+	MaxWeight         *float64 `json:"max_weight"`
+	MaxPallets        *uint64  `json:"max_pallets"`
+	UsedVolume        float64  `json:"used_volume"`
+	UsedWeight        float64  `json:"used_weight"`
+	UsedPallets       uint64   `json:"used_pallets"`
+	VolumeUtilization *float64 `json:"volume_utilization"`
+	WeightUtilization *float64 `json:"weight_utilization"`
+	PalletUtilization *float64 `json:"pallet_utilization"`
 }
@@ -12,11 +12,32 @@ import (
 )
 
 type ProviderHandler struct {
-	UserHandler      *handler.IUserHttpHandler
-	WareHouseHandler *handler.IWareHouseHandler
-	ZoneHandler      *handler.IZoneHandler
-	ProductHandler   *handler.IProductHandler
-	RoleHandler      *handler.IRoleHandler
+	UserHandler           *handler.IUserHttpHandler
+	WareHouseHandler      *handler.IWareHouseHandler
+	ZoneHandler           *handler.IZoneHandler
+	ProductHandler        *handler.IProductHandler
+	RoleHandler           *handler.IRoleHandler
+	ReceiptHandler        *handler.IReceiptHandler
+	ShipmentHandler       *handler.IShipmentHandler
+	TransferHandler       *handler.ITransferHandler
+	ReservationHandler    *handler.IReservationHandler
+	InventoryCountHandler *handler.IInventoryCountHandler
+	SerialNumberHandler   *handler.ISerialNumberHandler
+	SkuHandler            *handler.ISkuHandler
+	ReorderRuleHandler    *handler.IReorderRuleHandler
+	LocationHandler       *handler.ILocationHandler
+	StockHoldHandler      *handler.IStockHoldHandler
+	CustomerReturnHandler *handler.ICustomerReturnHandler
+	KitHandler            *handler.IKitHandler
+	SupplierHandler       *handler.ISupplierHandler
+	PurchaseOrderHandler  *handler.IPurchaseOrderHandler
+	CustomerHandler       *handler.ICustomerHandler
+	SalesOrderHandler     *handler.ISalesOrderHandler
+	PickListHandler       *handler.IPickListHandler
+	WaveHandler           *handler.IWaveHandler
+	ParcelHandler         *handler.IParcelHandler
+	WarehouseTaskHandler  *handler.IWarehouseTaskHandler
+	PutawayHandler        *handler.IPutawayHandler
 }
 
 // Providers for repositories
@@ -41,6 +62,90 @@ func ProvideRoleHandler(permUsecase usecase.PermissionUsecase) *handler.IRoleHan
 	return handler.NewIRolehandler(permUsecase)
 }
 
+func ProvideReceiptHandler(logger slog.Logger, receiptUsecase usecase.ReceiptUsecase) *handler.IReceiptHandler {
+	return handler.NewIReceiptHandler(logger, receiptUsecase)
+}
+
+func ProvideShipmentHandler(logger slog.Logger, shipmentUsecase usecase.ShipmentUsecase) *handler.IShipmentHandler {
+	return handler.NewIShipmentHandler(logger, shipmentUsecase)
+}
+
+func ProvideTransferHandler(logger slog.Logger, transferUsecase usecase.TransferUsecase) *handler.ITransferHandler {
+	return handler.NewITransferHandler(logger, transferUsecase)
+}
+
+func ProvideReservationHandler(logger slog.Logger, reservationUsecase usecase.ReservationUsecase) *handler.IReservationHandler {
+	return handler.NewIReservationHandler(logger, reservationUsecase)
+}
+
+func ProvideInventoryCountHandler(logger slog.Logger, inventoryCountUsecase usecase.InventoryCountUsecase) *handler.IInventoryCountHandler {
+	return handler.NewIInventoryCountHandler(logger, inventoryCountUsecase)
+}
+
+func ProvideSerialNumberHandler(logger slog.Logger, serialNumberUsecase usecase.SerialNumberUsecase) *handler.ISerialNumberHandler {
+	return handler.NewISerialNumberHandler(logger, serialNumberUsecase)
+}
+
+func ProvideSkuHandler(logger slog.Logger, skuUsecase usecase.SkuUsecase) *handler.ISkuHandler {
+	return handler.NewISkuHandler(logger, skuUsecase)
+}
+
+func ProvideReorderRuleHandler(logger slog.Logger, reorderRuleUsecase usecase.ReorderRuleUsecase) *handler.IReorderRuleHandler {
+	return handler.NewIReorderRuleHandler(logger, reorderRuleUsecase)
+}
+
+func ProvideLocationHandler(logger slog.Logger, locationUsecase usecase.LocationUsecase) *handler.ILocationHandler {
+	return handler.NewILocationHandler(logger, locationUsecase)
+}
+
+func ProvideStockHoldHandler(logger slog.Logger, stockHoldUsecase usecase.StockHoldUsecase) *handler.IStockHoldHandler {
+	return handler.NewIStockHoldHandler(logger, stockHoldUsecase)
+}
+
+func ProvideCustomerReturnHandler(logger slog.Logger, customerReturnUsecase usecase.CustomerReturnUsecase) *handler.ICustomerReturnHandler {
+	return handler.NewICustomerReturnHandler(logger, customerReturnUsecase)
+}
+
+func ProvideKitHandler(logger slog.Logger, kitUsecase usecase.KitUsecase) *handler.IKitHandler {
+	return handler.NewIKitHandler(logger, kitUsecase)
+}
+
+func ProvideSupplierHandler(logger slog.Logger, supplierUsecase usecase.SupplierUsecase) *handler.ISupplierHandler {
+	return handler.NewISupplierHandler(logger, supplierUsecase)
+}
+
+func ProvidePurchaseOrderHandler(logger slog.Logger, purchaseOrderUsecase usecase.PurchaseOrderUsecase) *handler.IPurchaseOrderHandler {
+	return handler.NewIPurchaseOrderHandler(logger, purchaseOrderUsecase)
+}
+
+func ProvideCustomerHandler(logger slog.Logger, customerUsecase usecase.CustomerUsecase) *handler.ICustomerHandler {
+	return handler.NewICustomerHandler(logger, customerUsecase)
+}
+
+func ProvideSalesOrderHandler(logger slog.Logger, salesOrderUsecase usecase.SalesOrderUsecase) *handler.ISalesOrderHandler {
+	return handler.NewISalesOrderHandler(logger, salesOrderUsecase)
+}
+
+func ProvidePickListHandler(logger slog.Logger, pickListUsecase usecase.PickListUsecase) *handler.IPickListHandler {
+	return handler.NewIPickListHandler(logger, pickListUsecase)
+}
+
+func ProvideWaveHandler(logger slog.Logger, waveUsecase usecase.WaveUsecase) *handler.IWaveHandler {
+	return handler.NewIWaveHandler(logger, waveUsecase)
+}
+
+func ProvideParcelHandler(logger slog.Logger, parcelUsecase usecase.ParcelUsecase) *handler.IParcelHandler {
+	return handler.NewIParcelHandler(logger, parcelUsecase)
+}
+
+func ProvideWarehouseTaskHandler(logger slog.Logger, warehouseTaskUsecase usecase.WarehouseTaskUsecase) *handler.IWarehouseTaskHandler {
+	return handler.NewIWarehouseTaskHandler(logger, warehouseTaskUsecase)
+}
+
+func ProvidePutawayHandler(logger slog.Logger, putawayUsecase usecase.PutawayUsecase) *handler.IPutawayHandler {
+	return handler.NewIPutawayHandler(logger, putawayUsecase)
+}
+
 // RepositoryProviderSet for repo layer
 var HandlerProviderSet = wire.NewSet(
 	ProvideUserHandler,
@@ -48,10 +153,31 @@ var HandlerProviderSet = wire.NewSet(
 	ProvideZoneHandler,
 	ProvideProductHandler,
 	ProvideRoleHandler,
-	wire.Struct(new(ProviderHandler), "UserHandler", "WareHouseHandler", "ZoneHandler", "ProductHandler", "RoleHandler"),
+	ProvideReceiptHandler,
+	ProvideShipmentHandler,
+	ProvideTransferHandler,
+	ProvideReservationHandler,
+	ProvideInventoryCountHandler,
+	ProvideSerialNumberHandler,
+	ProvideSkuHandler,
+	ProvideReorderRuleHandler,
+	ProvideLocationHandler,
+	ProvideStockHoldHandler,
+	ProvideCustomerReturnHandler,
+	ProvideKitHandler,
+	ProvideSupplierHandler,
+	ProvidePurchaseOrderHandler,
+	ProvideCustomerHandler,
+	ProvideSalesOrderHandler,
+	ProvidePickListHandler,
+	ProvideWaveHandler,
+	ProvideParcelHandler,
+	ProvideWarehouseTaskHandler,
+	ProvidePutawayHandler,
+	wire.Struct(new(ProviderHandler), "UserHandler", "WareHouseHandler", "ZoneHandler", "ProductHandler", "RoleHandler", "ReceiptHandler", "ShipmentHandler", "TransferHandler", "ReservationHandler", "InventoryCountHandler", "SerialNumberHandler", "SkuHandler", "ReorderRuleHandler", "LocationHandler", "StockHoldHandler", "CustomerReturnHandler", "KitHandler", "SupplierHandler", "PurchaseOrderHandler", "CustomerHandler", "SalesOrderHandler", "PickListHandler", "WaveHandler", "ParcelHandler", "WarehouseTaskHandler", "PutawayHandler"),
 )
 
-func InitializeHandlerProviderSet(logger slog.Logger, userUsecase usecase.UserUsecase, whUsecase usecase.WarehouseUsecase, zoneUsecase usecase.ZoneUsecase, productUsecase usecase.ProductUsecase, cfg config.Config, permUsecase usecase.PermissionUsecase) ProviderHandler {
+func InitializeHandlerProviderSet(logger slog.Logger, userUsecase usecase.UserUsecase, whUsecase usecase.WarehouseUsecase, zoneUsecase usecase.ZoneUsecase, productUsecase usecase.ProductUsecase, cfg config.Config, permUsecase usecase.PermissionUsecase, receiptUsecase usecase.ReceiptUsecase, shipmentUsecase usecase.ShipmentUsecase, transferUsecase usecase.TransferUsecase, reservationUsecase usecase.ReservationUsecase, inventoryCountUsecase usecase.InventoryCountUsecase, serialNumberUsecase usecase.SerialNumberUsecase, skuUsecase usecase.SkuUsecase, reorderRuleUsecase usecase.ReorderRuleUsecase, locationUsecase usecase.LocationUsecase, stockHoldUsecase usecase.StockHoldUsecase, customerReturnUsecase usecase.CustomerReturnUsecase, kitUsecase usecase.KitUsecase, supplierUsecase usecase.SupplierUsecase, purchaseOrderUsecase usecase.PurchaseOrderUsecase, customerUsecase usecase.CustomerUsecase, salesOrderUsecase usecase.SalesOrderUsecase, pickListUsecase usecase.PickListUsecase, waveUsecase usecase.WaveUsecase, parcelUsecase usecase.ParcelUsecase, warehouseTaskUsecase usecase.WarehouseTaskUsecase, putawayUsecase usecase.PutawayUsecase) ProviderHandler {
 	wire.Build(HandlerProviderSet)
 	return ProviderHandler{}
 }
@@ -11,11 +11,33 @@ import (
 )
 
 type ProviderRepository struct {
-	UserRepo       *repositories.UserPostgresRepository
-	ProductRepo    *repositories.ProductPostgresRepository
-	WareHouseRepo  *repositories.WareHousePostgresRepository
-	ZoneRepo       *repositories.ZonePostgresRepository
-	PermissionRepo *repositories.PermissionPostgresRepository
+	UserRepo           *repositories.UserPostgresRepository
+	ProductRepo        *repositories.ProductPostgresRepository
+	WareHouseRepo      *repositories.WareHousePostgresRepository
+	ZoneRepo           *repositories.ZonePostgresRepository
+	PermissionRepo     *repositories.PermissionPostgresRepository
+	StockMovementRepo  *repositories.StockMovementPostgresRepository
+	ReceiptRepo        *repositories.ReceiptPostgresRepository
+	ShipmentRepo       *repositories.ShipmentPostgresRepository
+	TransferRepo       *repositories.TransferPostgresRepository
+	ReservationRepo    *repositories.ReservationPostgresRepository
+	InventoryCountRepo *repositories.InventoryCountPostgresRepository
+	SerialNumberRepo   *repositories.SerialNumberPostgresRepository
+	SkuRepo            *repositories.SkuPostgresRepository
+	ReorderRuleRepo    *repositories.ReorderRulePostgresRepository
+	LocationRepo       *repositories.LocationPostgresRepository
+	StockHoldRepo      *repositories.StockHoldPostgresRepository
+	CustomerReturnRepo *repositories.CustomerReturnPostgresRepository
+	KitRepo            *repositories.KitPostgresRepository
+	SupplierRepo       *repositories.SupplierPostgresRepository
+	PurchaseOrderRepo  *repositories.PurchaseOrderPostgresRepository
+	CustomerRepo       *repositories.CustomerPostgresRepository
+	SalesOrderRepo     *repositories.SalesOrderPostgresRepository
+	PickListRepo       *repositories.PickListPostgresRepository
+	WaveRepo           *repositories.WavePostgresRepository
+	ParcelRepo         *repositories.ParcelPostgresRepository
+	WarehouseTaskRepo  *repositories.WarehouseTaskPostgresRepository
+	PutawayRepo        *repositories.PutawayPostgresRepository
 }
 
 // Providers for repositories
@@ -40,6 +62,94 @@ func ProvidePermissionRepository(db database.Database, logger slog.Logger) *repo
 	return repositories.NewPermissionPostgresRepository(db, logger)
 }
 
+func ProvideStockMovementRepository(db database.Database, logger slog.Logger) *repositories.StockMovementPostgresRepository {
+	return repositories.NewStockMovementPostgresRepository(db, logger)
+}
+
+func ProvideReceiptRepository(db database.Database, logger slog.Logger) *repositories.ReceiptPostgresRepository {
+	return repositories.NewReceiptPostgresRepository(db, logger)
+}
+
+func ProvideShipmentRepository(db database.Database, logger slog.Logger) *repositories.ShipmentPostgresRepository {
+	return repositories.NewShipmentPostgresRepository(db, logger)
+}
+
+func ProvideTransferRepository(db database.Database, logger slog.Logger) *repositories.TransferPostgresRepository {
+	return repositories.NewTransferPostgresRepository(db, logger)
+}
+
+func ProvideReservationRepository(db database.Database, logger slog.Logger) *repositories.ReservationPostgresRepository {
+	return repositories.NewReservationPostgresRepository(db, logger)
+}
+
+func ProvideInventoryCountRepository(db database.Database, logger slog.Logger) *repositories.InventoryCountPostgresRepository {
+	return repositories.NewInventoryCountPostgresRepository(db, logger)
+}
+
+func ProvideSerialNumberRepository(db database.Database, logger slog.Logger) *repositories.SerialNumberPostgresRepository {
+	return repositories.NewSerialNumberPostgresRepository(db, logger)
+}
+
+func ProvideSkuRepository(db database.Database, logger slog.Logger) *repositories.SkuPostgresRepository {
+	return repositories.NewSkuPostgresRepository(db, logger)
+}
+
+func ProvideReorderRuleRepository(db database.Database, logger slog.Logger) *repositories.ReorderRulePostgresRepository {
+	return repositories.NewReorderRulePostgresRepository(db, logger)
+}
+
+func ProvideLocationRepository(db database.Database, logger slog.Logger) *repositories.LocationPostgresRepository {
+	return repositories.NewLocationPostgresRepository(db, logger)
+}
+
+func ProvideStockHoldRepository(db database.Database, logger slog.Logger) *repositories.StockHoldPostgresRepository {
+	return repositories.NewStockHoldPostgresRepository(db, logger)
+}
+
+func ProvideCustomerReturnRepository(db database.Database, logger slog.Logger) *repositories.CustomerReturnPostgresRepository {
+	return repositories.NewCustomerReturnPostgresRepository(db, logger)
+}
+
+func ProvideKitRepository(db database.Database, logger slog.Logger) *repositories.KitPostgresRepository {
+	return repositories.NewKitPostgresRepository(db, logger)
+}
+
+func ProvideSupplierRepository(db database.Database, logger slog.Logger) *repositories.SupplierPostgresRepository {
+	return repositories.NewSupplierPostgresRepository(db, logger)
+}
+
+func ProvidePurchaseOrderRepository(db database.Database, logger slog.Logger) *repositories.PurchaseOrderPostgresRepository {
+	return repositories.NewPurchaseOrderPostgresRepository(db, logger)
+}
+
+func ProvideCustomerRepository(db database.Database, logger slog.Logger) *repositories.CustomerPostgresRepository {
+	return repositories.NewCustomerPostgresRepository(db, logger)
+}
+
+func ProvideSalesOrderRepository(db database.Database, logger slog.Logger) *repositories.SalesOrderPostgresRepository {
+	return repositories.NewSalesOrderPostgresRepository(db, logger)
+}
+
+func ProvidePickListRepository(db database.Database, logger slog.Logger) *repositories.PickListPostgresRepository {
+	return repositories.NewPickListPostgresRepository(db, logger)
+}
+
+func ProvideWaveRepository(db database.Database, logger slog.Logger) *repositories.WavePostgresRepository {
+	return repositories.NewWavePostgresRepository(db, logger)
+}
+
+func ProvideParcelRepository(db database.Database, logger slog.Logger) *repositories.ParcelPostgresRepository {
+	return repositories.NewParcelPostgresRepository(db, logger)
+}
+
+func ProvideWarehouseTaskRepository(db database.Database, logger slog.Logger) *repositories.WarehouseTaskPostgresRepository {
+	return repositories.NewWarehouseTaskPostgresRepository(db, logger)
+}
+
+func ProvidePutawayRepository(db database.Database, logger slog.Logger) *repositories.PutawayPostgresRepository {
+	return repositories.NewPutawayPostgresRepository(db, logger)
+}
+
 // RepositoryProviderSet for repo layer
 var RepositoryProviderSet = wire.NewSet(
 	ProvideUserRepository,
@@ -47,7 +157,29 @@ var RepositoryProviderSet = wire.NewSet(
 	ProvideWareHouseRepository,
 	ProvideZoneRepository,
 	ProvidePermissionRepository,
-	wire.Struct(new(ProviderRepository), "UserRepo", "ProductRepo", "WareHouseRepo", "ZoneRepo", "PermissionRepo"),
+	ProvideStockMovementRepository,
+	ProvideReceiptRepository,
+	ProvideShipmentRepository,
+	ProvideTransferRepository,
+	ProvideReservationRepository,
+	ProvideInventoryCountRepository,
+	ProvideSerialNumberRepository,
+	ProvideSkuRepository,
+	ProvideReorderRuleRepository,
+	ProvideLocationRepository,
+	ProvideStockHoldRepository,
+	ProvideCustomerReturnRepository,
+	ProvideKitRepository,
+	ProvideSupplierRepository,
+	ProvidePurchaseOrderRepository,
+	ProvideCustomerRepository,
+	ProvideSalesOrderRepository,
+	ProvidePickListRepository,
+	ProvideWaveRepository,
+	ProvideParcelRepository,
+	ProvideWarehouseTaskRepository,
+	ProvidePutawayRepository,
+	wire.Struct(new(ProviderRepository), "UserRepo", "ProductRepo", "WareHouseRepo", "ZoneRepo", "PermissionRepo", "StockMovementRepo", "ReceiptRepo", "ShipmentRepo", "TransferRepo", "ReservationRepo", "InventoryCountRepo", "SerialNumberRepo", "SkuRepo", "ReorderRuleRepo", "LocationRepo", "StockHoldRepo", "CustomerReturnRepo", "KitRepo", "SupplierRepo", "PurchaseOrderRepo", "CustomerRepo", "SalesOrderRepo", "PickListRepo", "WaveRepo", "ParcelRepo", "WarehouseTaskRepo", "PutawayRepo"),
 )
 
 func InitializeRepoProviderSet(db database.Database, logger slog.Logger) ProviderRepository {
@@ -5,6 +5,8 @@ package wire
 
 import (
 	"github.com/Miroslovelife/whareflow/internal/services"
+	"github.com/Miroslovelife/whareflow/pkg/label"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
 	"github.com/Miroslovelife/whareflow/pkg/qr"
 	"github.com/google/wire"
 	"log/slog"
@@ -14,6 +16,8 @@ type ProviderService struct {
 	TokenManager *services.TokenM
 	Hasher       *services.SHA1Hasher
 	QR           *qr.Generator
+	Notifier     *notifier.LogNotifier
+	Label        *label.Renderer
 }
 
 func ProvideTokenManagerService() *services.TokenM {
@@ -28,11 +32,21 @@ func ProvideQRService(logger slog.Logger) *qr.Generator {
 	return qr.NewGenerator(logger)
 }
 
+func ProvideLabelService(logger slog.Logger) *label.Renderer {
+	return label.NewRenderer(logger)
+}
+
+func ProvideNotifierService(logger slog.Logger) *notifier.LogNotifier {
+	return notifier.NewLogNotifier(logger)
+}
+
 var ServiceProviderSet = wire.NewSet(
 	ProvideTokenManagerService,
 	ProvideHasherService,
 	ProvideQRService,
-	wire.Struct(new(ProviderService), "TokenManager", "Hasher", "QR"),
+	ProvideNotifierService,
+	ProvideLabelService,
+	wire.Struct(new(ProviderService), "TokenManager", "Hasher", "QR", "Notifier", "Label"),
 )
 
 func InitializeServiceProviderSet(salt string, logger slog.Logger) ProviderService {
@@ -8,17 +8,41 @@ import (
 	"github.com/Miroslovelife/whareflow/internal/repositories"
 	"github.com/Miroslovelife/whareflow/internal/services"
 	"github.com/Miroslovelife/whareflow/internal/usecase"
+	"github.com/Miroslovelife/whareflow/pkg/label"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
 	"github.com/Miroslovelife/whareflow/pkg/qr"
 	"github.com/google/wire"
+	"log/slog"
 )
 
 type ProviderUsecase struct {
-	UserUsecase       *usecase.IUserUsecase
-	WareHouseUsecase  *usecase.IWarehouseUsecase
-	ZoneUsecase       *usecase.IZoneUsecase
-	ProductUsecase    *usecase.IProductUsecase
-	PermissionUsecase *usecase.IPermissionUsecase
-	AuthUsecase       *usecase.IAuthUsecase
+	UserUsecase           *usecase.IUserUsecase
+	WareHouseUsecase      *usecase.IWarehouseUsecase
+	ZoneUsecase           *usecase.IZoneUsecase
+	ProductUsecase        *usecase.IProductUsecase
+	PermissionUsecase     *usecase.IPermissionUsecase
+	AuthUsecase           *usecase.IAuthUsecase
+	ReceiptUsecase        *usecase.IReceiptUsecase
+	ShipmentUsecase       *usecase.IShipmentUsecase
+	TransferUsecase       *usecase.ITransferUsecase
+	ReservationUsecase    *usecase.IReservationUsecase
+	InventoryCountUsecase *usecase.IInventoryCountUsecase
+	SerialNumberUsecase   *usecase.ISerialNumberUsecase
+	SkuUsecase            *usecase.ISkuUsecase
+	ReorderRuleUsecase    *usecase.IReorderRuleUsecase
+	LocationUsecase       *usecase.ILocationUsecase
+	StockHoldUsecase      *usecase.IStockHoldUsecase
+	CustomerReturnUsecase *usecase.ICustomerReturnUsecase
+	KitUsecase            *usecase.IKitUsecase
+	SupplierUsecase       *usecase.ISupplierUsecase
+	PurchaseOrderUsecase  *usecase.IPurchaseOrderUsecase
+	CustomerUsecase       *usecase.ICustomerUsecase
+	SalesOrderUsecase     *usecase.ISalesOrderUsecase
+	PickListUsecase       *usecase.IPickListUsecase
+	WaveUsecase           *usecase.IWaveUsecase
+	ParcelUsecase         *usecase.IParcelUsecase
+	WarehouseTaskUsecase  *usecase.IWarehouseTaskUsecase
+	PutawayUsecase        *usecase.IPutawayUsecase
 }
 
 func ProvideUserUsecase(repoUser repositories.UserRepository, passwordHasher services.PasswordHasher, tokenManager services.TokenManager) *usecase.IUserUsecase {
@@ -33,8 +57,8 @@ func ProvideZoneUsecase(repoZone repositories.ZoneRepository) *usecase.IZoneUsec
 	return usecase.NewIZoneUsecase(repoZone)
 }
 
-func ProvideProductUsecase(repoProduct repositories.ProductRepository, qr qr.GeneratorQR, cfg config.Config) *usecase.IProductUsecase {
-	return usecase.NewIProductUsecase(repoProduct, qr, cfg)
+func ProvideProductUsecase(repoProduct repositories.ProductRepository, repoSku repositories.SkuRepository, repoStockMovement repositories.StockMovementRepository, repoReservation repositories.ReservationRepository, repoStockHold repositories.StockHoldRepository, repoSerialNumber repositories.SerialNumberRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.IProductUsecase {
+	return usecase.NewIProductUsecase(repoProduct, repoSku, repoStockMovement, repoReservation, repoStockHold, repoSerialNumber, repoReorderRule, alertNotifier, qr, cfg, logger)
 }
 
 func ProvidePermissionUsecase(repoUser repositories.UserRepository, repoPermission repositories.PermissionRepository, repoWarehouse repositories.WareHouseRepository) *usecase.IPermissionUsecase {
@@ -45,6 +69,90 @@ func ProvideAuthUsecase(repoUser repositories.UserRepository, tokenManager servi
 	return usecase.NewIAuthUsecase(repoUser, tokenManager)
 }
 
+func ProvideReceiptUsecase(repoReceipt repositories.ReceiptRepository, repoProduct repositories.ProductRepository, repoSerialNumber repositories.SerialNumberRepository, repoSku repositories.SkuRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.IReceiptUsecase {
+	return usecase.NewIReceiptUsecase(repoReceipt, repoProduct, repoSerialNumber, repoSku, repoReorderRule, alertNotifier, qr, cfg, logger)
+}
+
+func ProvideShipmentUsecase(repoShipment repositories.ShipmentRepository, repoProduct repositories.ProductRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.IShipmentUsecase {
+	return usecase.NewIShipmentUsecase(repoShipment, repoProduct, repoReorderRule, alertNotifier, logger)
+}
+
+func ProvideTransferUsecase(repoTransfer repositories.TransferRepository, repoProduct repositories.ProductRepository, repoSku repositories.SkuRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.ITransferUsecase {
+	return usecase.NewITransferUsecase(repoTransfer, repoProduct, repoSku, repoReorderRule, alertNotifier, qr, cfg, logger)
+}
+
+func ProvideReservationUsecase(repoReservation repositories.ReservationRepository, repoSku repositories.SkuRepository) *usecase.IReservationUsecase {
+	return usecase.NewIReservationUsecase(repoReservation, repoSku)
+}
+
+func ProvideInventoryCountUsecase(repoInventoryCount repositories.InventoryCountRepository, repoSku repositories.SkuRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.IInventoryCountUsecase {
+	return usecase.NewIInventoryCountUsecase(repoInventoryCount, repoSku, repoReorderRule, alertNotifier, logger)
+}
+
+func ProvideSerialNumberUsecase(repoSerialNumber repositories.SerialNumberRepository, repoProduct repositories.ProductRepository) *usecase.ISerialNumberUsecase {
+	return usecase.NewISerialNumberUsecase(repoSerialNumber, repoProduct)
+}
+
+func ProvideSkuUsecase(repoSku repositories.SkuRepository) *usecase.ISkuUsecase {
+	return usecase.NewISkuUsecase(repoSku)
+}
+
+func ProvideReorderRuleUsecase(repoReorderRule repositories.ReorderRuleRepository, repoSku repositories.SkuRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.IReorderRuleUsecase {
+	return usecase.NewIReorderRuleUsecase(repoReorderRule, repoSku, alertNotifier, logger)
+}
+
+func ProvideLocationUsecase(repoLocation repositories.LocationRepository, qr qr.GeneratorQR, cfg config.Config) *usecase.ILocationUsecase {
+	return usecase.NewILocationUsecase(repoLocation, qr, cfg)
+}
+
+func ProvideStockHoldUsecase(repoStockHold repositories.StockHoldRepository, repoSku repositories.SkuRepository) *usecase.IStockHoldUsecase {
+	return usecase.NewIStockHoldUsecase(repoStockHold, repoSku)
+}
+
+func ProvideCustomerReturnUsecase(repoCustomerReturn repositories.CustomerReturnRepository, repoShipment repositories.ShipmentRepository, repoProduct repositories.ProductRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.ICustomerReturnUsecase {
+	return usecase.NewICustomerReturnUsecase(repoCustomerReturn, repoShipment, repoProduct, repoReorderRule, alertNotifier, qr, cfg, logger)
+}
+
+func ProvideKitUsecase(repoKit repositories.KitRepository, repoSku repositories.SkuRepository, repoProduct repositories.ProductRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.IKitUsecase {
+	return usecase.NewIKitUsecase(repoKit, repoSku, repoProduct, repoReorderRule, alertNotifier, qr, cfg, logger)
+}
+
+func ProvideSupplierUsecase(repoSupplier repositories.SupplierRepository) *usecase.ISupplierUsecase {
+	return usecase.NewISupplierUsecase(repoSupplier)
+}
+
+func ProvidePurchaseOrderUsecase(repoPurchaseOrder repositories.PurchaseOrderRepository, repoReceipt repositories.ReceiptRepository, repoSku repositories.SkuRepository) *usecase.IPurchaseOrderUsecase {
+	return usecase.NewIPurchaseOrderUsecase(repoPurchaseOrder, repoReceipt, repoSku)
+}
+
+func ProvideCustomerUsecase(repoCustomer repositories.CustomerRepository) *usecase.ICustomerUsecase {
+	return usecase.NewICustomerUsecase(repoCustomer)
+}
+
+func ProvideSalesOrderUsecase(repoSalesOrder repositories.SalesOrderRepository, repoProduct repositories.ProductRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.ISalesOrderUsecase {
+	return usecase.NewISalesOrderUsecase(repoSalesOrder, repoProduct, alertNotifier, logger)
+}
+
+func ProvidePickListUsecase(repoPickList repositories.PickListRepository) *usecase.IPickListUsecase {
+	return usecase.NewIPickListUsecase(repoPickList)
+}
+
+func ProvideWaveUsecase(repoWave repositories.WaveRepository, repoPickList repositories.PickListRepository) *usecase.IWaveUsecase {
+	return usecase.NewIWaveUsecase(repoWave, repoPickList)
+}
+
+func ProvideParcelUsecase(repoParcel repositories.ParcelRepository, repoShipment repositories.ShipmentRepository, repoWarehouse repositories.WareHouseRepository, labelRenderer label.RendererLabel) *usecase.IParcelUsecase {
+	return usecase.NewIParcelUsecase(repoParcel, repoShipment, repoWarehouse, labelRenderer)
+}
+
+func ProvideWarehouseTaskUsecase(repoWarehouseTask repositories.WarehouseTaskRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.IWarehouseTaskUsecase {
+	return usecase.NewIWarehouseTaskUsecase(repoWarehouseTask, alertNotifier, logger)
+}
+
+func ProvidePutawayUsecase(repoPutaway repositories.PutawayRepository, repoPickList repositories.PickListRepository, repoZone repositories.ZoneRepository) *usecase.IPutawayUsecase {
+	return usecase.NewIPutawayUsecase(repoPutaway, repoPickList, repoZone)
+}
+
 var UsecaseProviderSet = wire.NewSet(
 	ProvideUserUsecase,
 	ProvideWarehouseUsecase,
@@ -52,7 +160,28 @@ var UsecaseProviderSet = wire.NewSet(
 	ProvideProductUsecase,
 	ProvidePermissionUsecase,
 	ProvideAuthUsecase,
-	wire.Struct(new(ProviderUsecase), "UserUsecase", "WareHouseUsecase", "ZoneUsecase", "ProductUsecase", "PermissionUsecase", "AuthUsecase"),
+	ProvideReceiptUsecase,
+	ProvideShipmentUsecase,
+	ProvideTransferUsecase,
+	ProvideReservationUsecase,
+	ProvideInventoryCountUsecase,
+	ProvideSerialNumberUsecase,
+	ProvideSkuUsecase,
+	ProvideReorderRuleUsecase,
+	ProvideLocationUsecase,
+	ProvideStockHoldUsecase,
+	ProvideCustomerReturnUsecase,
+	ProvideKitUsecase,
+	ProvideSupplierUsecase,
+	ProvidePurchaseOrderUsecase,
+	ProvideCustomerUsecase,
+	ProvideSalesOrderUsecase,
+	ProvidePickListUsecase,
+	ProvideWaveUsecase,
+	ProvideParcelUsecase,
+	ProvideWarehouseTaskUsecase,
+	ProvidePutawayUsecase,
+	wire.Struct(new(ProviderUsecase), "UserUsecase", "WareHouseUsecase", "ZoneUsecase", "ProductUsecase", "PermissionUsecase", "AuthUsecase", "ReceiptUsecase", "ShipmentUsecase", "TransferUsecase", "ReservationUsecase", "InventoryCountUsecase", "SerialNumberUsecase", "SkuUsecase", "ReorderRuleUsecase", "LocationUsecase", "StockHoldUsecase", "CustomerReturnUsecase", "KitUsecase", "SupplierUsecase", "PurchaseOrderUsecase", "CustomerUsecase", "SalesOrderUsecase", "PickListUsecase", "WaveUsecase", "ParcelUsecase", "WarehouseTaskUsecase", "PutawayUsecase"),
 )
 
 func InitializeUsecaseProviderSet(repoUser repositories.UserRepository,
@@ -61,9 +190,34 @@ func InitializeUsecaseProviderSet(repoUser repositories.UserRepository,
 	repoWarehouse repositories.WareHouseRepository,
 	repoZone repositories.ZoneRepository,
 	repoProduct repositories.ProductRepository,
+	repoStockMovement repositories.StockMovementRepository,
 	qr qr.GeneratorQR,
 	cfg config.Config,
 	repoPermission repositories.PermissionRepository,
+	repoReceipt repositories.ReceiptRepository,
+	repoShipment repositories.ShipmentRepository,
+	repoTransfer repositories.TransferRepository,
+	repoReservation repositories.ReservationRepository,
+	repoInventoryCount repositories.InventoryCountRepository,
+	repoSerialNumber repositories.SerialNumberRepository,
+	repoSku repositories.SkuRepository,
+	repoReorderRule repositories.ReorderRuleRepository,
+	alertNotifier notifier.Notifier,
+	repoLocation repositories.LocationRepository,
+	repoStockHold repositories.StockHoldRepository,
+	repoCustomerReturn repositories.CustomerReturnRepository,
+	repoKit repositories.KitRepository,
+	repoSupplier repositories.SupplierRepository,
+	repoPurchaseOrder repositories.PurchaseOrderRepository,
+	repoCustomer repositories.CustomerRepository,
+	repoSalesOrder repositories.SalesOrderRepository,
+	repoPickList repositories.PickListRepository,
+	repoWave repositories.WaveRepository,
+	repoParcel repositories.ParcelRepository,
+	labelRenderer label.RendererLabel,
+	repoWarehouseTask repositories.WarehouseTaskRepository,
+	repoPutaway repositories.PutawayRepository,
+	logger slog.Logger,
 ) ProviderUsecase {
 	wire.Build(UsecaseProviderSet)
 	return ProviderUsecase{}
@@ -14,6 +14,8 @@ import (
 	"github.com/Miroslovelife/whareflow/internal/services"
 	"github.com/Miroslovelife/whareflow/internal/usecase"
 	"github.com/Miroslovelife/whareflow/pkg/database"
+	"github.com/Miroslovelife/whareflow/pkg/label"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
 	"github.com/Miroslovelife/whareflow/pkg/qr"
 	"github.com/google/wire"
 	"log/slog"
@@ -21,18 +23,60 @@ import (
 
 // Injectors from handler_provider.go:
 
-func InitializeHandlerProviderSet(logger slog.Logger, userUsecase usecase.UserUsecase, whUsecase usecase.WarehouseUsecase, zoneUsecase usecase.ZoneUsecase, productUsecase usecase.ProductUsecase, cfg config.Config, permUsecase usecase.PermissionUsecase) ProviderHandler {
+func InitializeHandlerProviderSet(logger slog.Logger, userUsecase usecase.UserUsecase, whUsecase usecase.WarehouseUsecase, zoneUsecase usecase.ZoneUsecase, productUsecase usecase.ProductUsecase, cfg config.Config, permUsecase usecase.PermissionUsecase, receiptUsecase usecase.ReceiptUsecase, shipmentUsecase usecase.ShipmentUsecase, transferUsecase usecase.TransferUsecase, reservationUsecase usecase.ReservationUsecase, inventoryCountUsecase usecase.InventoryCountUsecase, serialNumberUsecase usecase.SerialNumberUsecase, skuUsecase usecase.SkuUsecase, reorderRuleUsecase usecase.ReorderRuleUsecase, locationUsecase usecase.LocationUsecase, stockHoldUsecase usecase.StockHoldUsecase, customerReturnUsecase usecase.CustomerReturnUsecase, kitUsecase usecase.KitUsecase, supplierUsecase usecase.SupplierUsecase, purchaseOrderUsecase usecase.PurchaseOrderUsecase, customerUsecase usecase.CustomerUsecase, salesOrderUsecase usecase.SalesOrderUsecase, pickListUsecase usecase.PickListUsecase, waveUsecase usecase.WaveUsecase, parcelUsecase usecase.ParcelUsecase, warehouseTaskUsecase usecase.WarehouseTaskUsecase, putawayUsecase usecase.PutawayUsecase) ProviderHandler {
 	iUserHttpHandler := ProvideUserHandler(logger, userUsecase, cfg)
 	iWareHouseHandler := ProvideWareHouseHandler(logger, whUsecase, cfg)
 	iZoneHandler := ProvideZoneHandler(logger, zoneUsecase, cfg)
 	iProductHandler := ProvideProductHandler(productUsecase, cfg)
 	iRoleHandler := ProvideRoleHandler(permUsecase)
+	iReceiptHandler := ProvideReceiptHandler(logger, receiptUsecase)
+	iShipmentHandler := ProvideShipmentHandler(logger, shipmentUsecase)
+	iTransferHandler := ProvideTransferHandler(logger, transferUsecase)
+	iReservationHandler := ProvideReservationHandler(logger, reservationUsecase)
+	iInventoryCountHandler := ProvideInventoryCountHandler(logger, inventoryCountUsecase)
+	iSerialNumberHandler := ProvideSerialNumberHandler(logger, serialNumberUsecase)
+	iSkuHandler := ProvideSkuHandler(logger, skuUsecase)
+	iReorderRuleHandler := ProvideReorderRuleHandler(logger, reorderRuleUsecase)
+	iLocationHandler := ProvideLocationHandler(logger, locationUsecase)
+	iStockHoldHandler := ProvideStockHoldHandler(logger, stockHoldUsecase)
+	iCustomerReturnHandler := ProvideCustomerReturnHandler(logger, customerReturnUsecase)
+	iKitHandler := ProvideKitHandler(logger, kitUsecase)
+	iSupplierHandler := ProvideSupplierHandler(logger, supplierUsecase)
+	iPurchaseOrderHandler := ProvidePurchaseOrderHandler(logger, purchaseOrderUsecase)
+	iCustomerHandler := ProvideCustomerHandler(logger, customerUsecase)
+	iSalesOrderHandler := ProvideSalesOrderHandler(logger, salesOrderUsecase)
+	iPickListHandler := ProvidePickListHandler(logger, pickListUsecase)
+	iWaveHandler := ProvideWaveHandler(logger, waveUsecase)
+	iParcelHandler := ProvideParcelHandler(logger, parcelUsecase)
+	iWarehouseTaskHandler := ProvideWarehouseTaskHandler(logger, warehouseTaskUsecase)
+	iPutawayHandler := ProvidePutawayHandler(logger, putawayUsecase)
 	providerHandler := ProviderHandler{
-		UserHandler:      iUserHttpHandler,
-		WareHouseHandler: iWareHouseHandler,
-		ZoneHandler:      iZoneHandler,
-		ProductHandler:   iProductHandler,
-		RoleHandler:      iRoleHandler,
+		UserHandler:           iUserHttpHandler,
+		WareHouseHandler:      iWareHouseHandler,
+		ZoneHandler:           iZoneHandler,
+		ProductHandler:        iProductHandler,
+		RoleHandler:           iRoleHandler,
+		ReceiptHandler:        iReceiptHandler,
+		ShipmentHandler:       iShipmentHandler,
+		TransferHandler:       iTransferHandler,
+		ReservationHandler:    iReservationHandler,
+		InventoryCountHandler: iInventoryCountHandler,
+		SerialNumberHandler:   iSerialNumberHandler,
+		SkuHandler:            iSkuHandler,
+		ReorderRuleHandler:    iReorderRuleHandler,
+		LocationHandler:       iLocationHandler,
+		StockHoldHandler:      iStockHoldHandler,
+		CustomerReturnHandler: iCustomerReturnHandler,
+		KitHandler:            iKitHandler,
+		SupplierHandler:       iSupplierHandler,
+		PurchaseOrderHandler:  iPurchaseOrderHandler,
+		CustomerHandler:       iCustomerHandler,
+		SalesOrderHandler:     iSalesOrderHandler,
+		PickListHandler:       iPickListHandler,
+		WaveHandler:           iWaveHandler,
+		ParcelHandler:         iParcelHandler,
+		WarehouseTaskHandler:  iWarehouseTaskHandler,
+		PutawayHandler:        iPutawayHandler,
 	}
 	return providerHandler
 }
@@ -59,12 +103,56 @@ func InitializeRepoProviderSet(db database.Database, logger slog.Logger) Provide
 	wareHousePostgresRepository := ProvideWareHouseRepository(db, logger)
 	zonePostgresRepository := ProvideZoneRepository(db, logger)
 	permissionPostgresRepository := ProvidePermissionRepository(db, logger)
+	stockMovementPostgresRepository := ProvideStockMovementRepository(db, logger)
+	receiptPostgresRepository := ProvideReceiptRepository(db, logger)
+	shipmentPostgresRepository := ProvideShipmentRepository(db, logger)
+	transferPostgresRepository := ProvideTransferRepository(db, logger)
+	reservationPostgresRepository := ProvideReservationRepository(db, logger)
+	inventoryCountPostgresRepository := ProvideInventoryCountRepository(db, logger)
+	serialNumberPostgresRepository := ProvideSerialNumberRepository(db, logger)
+	skuPostgresRepository := ProvideSkuRepository(db, logger)
+	reorderRulePostgresRepository := ProvideReorderRuleRepository(db, logger)
+	locationPostgresRepository := ProvideLocationRepository(db, logger)
+	stockHoldPostgresRepository := ProvideStockHoldRepository(db, logger)
+	customerReturnPostgresRepository := ProvideCustomerReturnRepository(db, logger)
+	kitPostgresRepository := ProvideKitRepository(db, logger)
+	supplierPostgresRepository := ProvideSupplierRepository(db, logger)
+	purchaseOrderPostgresRepository := ProvidePurchaseOrderRepository(db, logger)
+	customerPostgresRepository := ProvideCustomerRepository(db, logger)
+	salesOrderPostgresRepository := ProvideSalesOrderRepository(db, logger)
+	pickListPostgresRepository := ProvidePickListRepository(db, logger)
+	wavePostgresRepository := ProvideWaveRepository(db, logger)
+	parcelPostgresRepository := ProvideParcelRepository(db, logger)
+	warehouseTaskPostgresRepository := ProvideWarehouseTaskRepository(db, logger)
+	putawayPostgresRepository := ProvidePutawayRepository(db, logger)
 	providerRepository := ProviderRepository{
-		UserRepo:       userPostgresRepository,
-		ProductRepo:    productPostgresRepository,
-		WareHouseRepo:  wareHousePostgresRepository,
-		ZoneRepo:       zonePostgresRepository,
-		PermissionRepo: permissionPostgresRepository,
+		UserRepo:           userPostgresRepository,
+		ProductRepo:        productPostgresRepository,
+		WareHouseRepo:      wareHousePostgresRepository,
+		ZoneRepo:           zonePostgresRepository,
+		PermissionRepo:     permissionPostgresRepository,
+		StockMovementRepo:  stockMovementPostgresRepository,
+		ReceiptRepo:        receiptPostgresRepository,
+		ShipmentRepo:       shipmentPostgresRepository,
+		TransferRepo:       transferPostgresRepository,
+		ReservationRepo:    reservationPostgresRepository,
+		InventoryCountRepo: inventoryCountPostgresRepository,
+		SerialNumberRepo:   serialNumberPostgresRepository,
+		SkuRepo:            skuPostgresRepository,
+		ReorderRuleRepo:    reorderRulePostgresRepository,
+		LocationRepo:       locationPostgresRepository,
+		StockHoldRepo:      stockHoldPostgresRepository,
+		CustomerReturnRepo: customerReturnPostgresRepository,
+		KitRepo:            kitPostgresRepository,
+		SupplierRepo:       supplierPostgresRepository,
+		PurchaseOrderRepo:  purchaseOrderPostgresRepository,
+		CustomerRepo:       customerPostgresRepository,
+		SalesOrderRepo:     salesOrderPostgresRepository,
+		PickListRepo:       pickListPostgresRepository,
+		WaveRepo:           wavePostgresRepository,
+		ParcelRepo:         parcelPostgresRepository,
+		WarehouseTaskRepo:  warehouseTaskPostgresRepository,
+		PutawayRepo:        putawayPostgresRepository,
 	}
 	return providerRepository
 }
@@ -75,30 +163,76 @@ func InitializeServiceProviderSet(salt string, logger slog.Logger) ProviderServi
 	tokenM := ProvideTokenManagerService()
 	sha1Hasher := ProvideHasherService(salt)
 	generator := ProvideQRService(logger)
+	logNotifier := ProvideNotifierService(logger)
+	renderer := ProvideLabelService(logger)
 	providerService := ProviderService{
 		TokenManager: tokenM,
 		Hasher:       sha1Hasher,
 		QR:           generator,
+		Notifier:     logNotifier,
+		Label:        renderer,
 	}
 	return providerService
 }
 
 // Injectors from usecase_provider.go:
 
-func InitializeUsecaseProviderSet(repoUser repositories.UserRepository, passwordHasher services.PasswordHasher, tokenManager services.TokenManager, repoWarehouse repositories.WareHouseRepository, repoZone repositories.ZoneRepository, repoProduct repositories.ProductRepository, qr2 qr.GeneratorQR, cfg config.Config, repoPermission repositories.PermissionRepository) ProviderUsecase {
+func InitializeUsecaseProviderSet(repoUser repositories.UserRepository, passwordHasher services.PasswordHasher, tokenManager services.TokenManager, repoWarehouse repositories.WareHouseRepository, repoZone repositories.ZoneRepository, repoProduct repositories.ProductRepository, repoStockMovement repositories.StockMovementRepository, qr2 qr.GeneratorQR, cfg config.Config, repoPermission repositories.PermissionRepository, repoReceipt repositories.ReceiptRepository, repoShipment repositories.ShipmentRepository, repoTransfer repositories.TransferRepository, repoReservation repositories.ReservationRepository, repoInventoryCount repositories.InventoryCountRepository, repoSerialNumber repositories.SerialNumberRepository, repoSku repositories.SkuRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, repoLocation repositories.LocationRepository, repoStockHold repositories.StockHoldRepository, repoCustomerReturn repositories.CustomerReturnRepository, repoKit repositories.KitRepository, repoSupplier repositories.SupplierRepository, repoPurchaseOrder repositories.PurchaseOrderRepository, repoCustomer repositories.CustomerRepository, repoSalesOrder repositories.SalesOrderRepository, repoPickList repositories.PickListRepository, repoWave repositories.WaveRepository, repoParcel repositories.ParcelRepository, labelRenderer label.RendererLabel, repoWarehouseTask repositories.WarehouseTaskRepository, repoPutaway repositories.PutawayRepository, logger slog.Logger) ProviderUsecase {
 	iUserUsecase := ProvideUserUsecase(repoUser, passwordHasher, tokenManager)
 	iWarehouseUsecase := ProvideWarehouseUsecase(repoWarehouse)
 	iZoneUsecase := ProvideZoneUsecase(repoZone)
-	iProductUsecase := ProvideProductUsecase(repoProduct, qr2, cfg)
+	iProductUsecase := ProvideProductUsecase(repoProduct, repoSku, repoStockMovement, repoReservation, repoStockHold, repoSerialNumber, repoReorderRule, alertNotifier, qr2, cfg, logger)
 	iPermissionUsecase := ProvidePermissionUsecase(repoUser, repoPermission, repoWarehouse)
 	iAuthUsecase := ProvideAuthUsecase(repoUser, tokenManager)
+	iReceiptUsecase := ProvideReceiptUsecase(repoReceipt, repoProduct, repoSerialNumber, repoSku, repoReorderRule, alertNotifier, qr2, cfg, logger)
+	iShipmentUsecase := ProvideShipmentUsecase(repoShipment, repoProduct, repoReorderRule, alertNotifier, logger)
+	iTransferUsecase := ProvideTransferUsecase(repoTransfer, repoProduct, repoSku, repoReorderRule, alertNotifier, qr2, cfg, logger)
+	iReservationUsecase := ProvideReservationUsecase(repoReservation, repoSku)
+	iInventoryCountUsecase := ProvideInventoryCountUsecase(repoInventoryCount, repoSku, repoReorderRule, alertNotifier, logger)
+	iSerialNumberUsecase := ProvideSerialNumberUsecase(repoSerialNumber, repoProduct)
+	iSkuUsecase := ProvideSkuUsecase(repoSku)
+	iReorderRuleUsecase := ProvideReorderRuleUsecase(repoReorderRule, repoSku, alertNotifier, logger)
+	iLocationUsecase := ProvideLocationUsecase(repoLocation, qr2, cfg)
+	iStockHoldUsecase := ProvideStockHoldUsecase(repoStockHold, repoSku)
+	iCustomerReturnUsecase := ProvideCustomerReturnUsecase(repoCustomerReturn, repoShipment, repoProduct, repoReorderRule, alertNotifier, qr2, cfg, logger)
+	iKitUsecase := ProvideKitUsecase(repoKit, repoSku, repoProduct, repoReorderRule, alertNotifier, qr2, cfg, logger)
+	iSupplierUsecase := ProvideSupplierUsecase(repoSupplier)
+	iPurchaseOrderUsecase := ProvidePurchaseOrderUsecase(repoPurchaseOrder, repoReceipt, repoSku)
+	iCustomerUsecase := ProvideCustomerUsecase(repoCustomer)
+	iSalesOrderUsecase := ProvideSalesOrderUsecase(repoSalesOrder, repoProduct, alertNotifier, logger)
+	iPickListUsecase := ProvidePickListUsecase(repoPickList)
+	iWaveUsecase := ProvideWaveUsecase(repoWave, repoPickList)
+	iParcelUsecase := ProvideParcelUsecase(repoParcel, repoShipment, repoWarehouse, labelRenderer)
+	iWarehouseTaskUsecase := ProvideWarehouseTaskUsecase(repoWarehouseTask, alertNotifier, logger)
+	iPutawayUsecase := ProvidePutawayUsecase(repoPutaway, repoPickList, repoZone)
 	providerUsecase := ProviderUsecase{
-		UserUsecase:       iUserUsecase,
-		WareHouseUsecase:  iWarehouseUsecase,
-		ZoneUsecase:       iZoneUsecase,
-		ProductUsecase:    iProductUsecase,
-		PermissionUsecase: iPermissionUsecase,
-		AuthUsecase:       iAuthUsecase,
+		UserUsecase:           iUserUsecase,
+		WareHouseUsecase:      iWarehouseUsecase,
+		ZoneUsecase:           iZoneUsecase,
+		ProductUsecase:        iProductUsecase,
+		PermissionUsecase:     iPermissionUsecase,
+		AuthUsecase:           iAuthUsecase,
+		ReceiptUsecase:        iReceiptUsecase,
+		ShipmentUsecase:       iShipmentUsecase,
+		TransferUsecase:       iTransferUsecase,
+		ReservationUsecase:    iReservationUsecase,
+		InventoryCountUsecase: iInventoryCountUsecase,
+		SerialNumberUsecase:   iSerialNumberUsecase,
+		SkuUsecase:            iSkuUsecase,
+		ReorderRuleUsecase:    iReorderRuleUsecase,
+		LocationUsecase:       iLocationUsecase,
+		StockHoldUsecase:      iStockHoldUsecase,
+		CustomerReturnUsecase: iCustomerReturnUsecase,
+		KitUsecase:            iKitUsecase,
+		SupplierUsecase:       iSupplierUsecase,
+		PurchaseOrderUsecase:  iPurchaseOrderUsecase,
+		CustomerUsecase:       iCustomerUsecase,
+		SalesOrderUsecase:     iSalesOrderUsecase,
+		PickListUsecase:       iPickListUsecase,
+		WaveUsecase:           iWaveUsecase,
+		ParcelUsecase:         iParcelUsecase,
+		WarehouseTaskUsecase:  iWarehouseTaskUsecase,
+		PutawayUsecase:        iPutawayUsecase,
 	}
 	return providerUsecase
 }
@@ -106,11 +240,32 @@ func InitializeUsecaseProviderSet(repoUser repositories.UserRepository, password
 // handler_provider.go:
 
 type ProviderHandler struct {
-	UserHandler      *handler.IUserHttpHandler
-	WareHouseHandler *handler.IWareHouseHandler
-	ZoneHandler      *handler.IZoneHandler
-	ProductHandler   *handler.IProductHandler
-	RoleHandler      *handler.IRoleHandler
+	UserHandler           *handler.IUserHttpHandler
+	WareHouseHandler      *handler.IWareHouseHandler
+	ZoneHandler           *handler.IZoneHandler
+	ProductHandler        *handler.IProductHandler
+	RoleHandler           *handler.IRoleHandler
+	ReceiptHandler        *handler.IReceiptHandler
+	ShipmentHandler       *handler.IShipmentHandler
+	TransferHandler       *handler.ITransferHandler
+	ReservationHandler    *handler.IReservationHandler
+	InventoryCountHandler *handler.IInventoryCountHandler
+	SerialNumberHandler   *handler.ISerialNumberHandler
+	SkuHandler            *handler.ISkuHandler
+	ReorderRuleHandler    *handler.IReorderRuleHandler
+	LocationHandler       *handler.ILocationHandler
+	StockHoldHandler      *handler.IStockHoldHandler
+	CustomerReturnHandler *handler.ICustomerReturnHandler
+	KitHandler            *handler.IKitHandler
+	SupplierHandler       *handler.ISupplierHandler
+	PurchaseOrderHandler  *handler.IPurchaseOrderHandler
+	CustomerHandler       *handler.ICustomerHandler
+	SalesOrderHandler     *handler.ISalesOrderHandler
+	PickListHandler       *handler.IPickListHandler
+	WaveHandler           *handler.IWaveHandler
+	ParcelHandler         *handler.IParcelHandler
+	WarehouseTaskHandler  *handler.IWarehouseTaskHandler
+	PutawayHandler        *handler.IPutawayHandler
 }
 
 func ProvideUserHandler(logger slog.Logger, userUsecase usecase.UserUsecase, cfg config.Config) *handler.IUserHttpHandler {
@@ -133,13 +288,118 @@ func ProvideRoleHandler(permUsecase usecase.PermissionUsecase) *handler.IRoleHan
 	return handler.NewIRolehandler(permUsecase)
 }
 
+func ProvideReceiptHandler(logger slog.Logger, receiptUsecase usecase.ReceiptUsecase) *handler.IReceiptHandler {
+	return handler.NewIReceiptHandler(logger, receiptUsecase)
+}
+
+func ProvideShipmentHandler(logger slog.Logger, shipmentUsecase usecase.ShipmentUsecase) *handler.IShipmentHandler {
+	return handler.NewIShipmentHandler(logger, shipmentUsecase)
+}
+
+func ProvideTransferHandler(logger slog.Logger, transferUsecase usecase.TransferUsecase) *handler.ITransferHandler {
+	return handler.NewITransferHandler(logger, transferUsecase)
+}
+
+func ProvideReservationHandler(logger slog.Logger, reservationUsecase usecase.ReservationUsecase) *handler.IReservationHandler {
+	return handler.NewIReservationHandler(logger, reservationUsecase)
+}
+
+func ProvideInventoryCountHandler(logger slog.Logger, inventoryCountUsecase usecase.InventoryCountUsecase) *handler.IInventoryCountHandler {
+	return handler.NewIInventoryCountHandler(logger, inventoryCountUsecase)
+}
+
+func ProvideSerialNumberHandler(logger slog.Logger, serialNumberUsecase usecase.SerialNumberUsecase) *handler.ISerialNumberHandler {
+	return handler.NewISerialNumberHandler(logger, serialNumberUsecase)
+}
+
+func ProvideSkuHandler(logger slog.Logger, skuUsecase usecase.SkuUsecase) *handler.ISkuHandler {
+	return handler.NewISkuHandler(logger, skuUsecase)
+}
+
+func ProvideReorderRuleHandler(logger slog.Logger, reorderRuleUsecase usecase.ReorderRuleUsecase) *handler.IReorderRuleHandler {
+	return handler.NewIReorderRuleHandler(logger, reorderRuleUsecase)
+}
+
+func ProvideLocationHandler(logger slog.Logger, locationUsecase usecase.LocationUsecase) *handler.ILocationHandler {
+	return handler.NewILocationHandler(logger, locationUsecase)
+}
+
+func ProvideStockHoldHandler(logger slog.Logger, stockHoldUsecase usecase.StockHoldUsecase) *handler.IStockHoldHandler {
+	return handler.NewIStockHoldHandler(logger, stockHoldUsecase)
+}
+
+func ProvideCustomerReturnHandler(logger slog.Logger, customerReturnUsecase usecase.CustomerReturnUsecase) *handler.ICustomerReturnHandler {
+	return handler.NewICustomerReturnHandler(logger, customerReturnUsecase)
+}
+
+func ProvideKitHandler(logger slog.Logger, kitUsecase usecase.KitUsecase) *handler.IKitHandler {
+	return handler.NewIKitHandler(logger, kitUsecase)
+}
+
+func ProvideSupplierHandler(logger slog.Logger, supplierUsecase usecase.SupplierUsecase) *handler.ISupplierHandler {
+	return handler.NewISupplierHandler(logger, supplierUsecase)
+}
+
+func ProvidePurchaseOrderHandler(logger slog.Logger, purchaseOrderUsecase usecase.PurchaseOrderUsecase) *handler.IPurchaseOrderHandler {
+	return handler.NewIPurchaseOrderHandler(logger, purchaseOrderUsecase)
+}
+
+func ProvideCustomerHandler(logger slog.Logger, customerUsecase usecase.CustomerUsecase) *handler.ICustomerHandler {
+	return handler.NewICustomerHandler(logger, customerUsecase)
+}
+
+func ProvideSalesOrderHandler(logger slog.Logger, salesOrderUsecase usecase.SalesOrderUsecase) *handler.ISalesOrderHandler {
+	return handler.NewISalesOrderHandler(logger, salesOrderUsecase)
+}
+
+func ProvidePickListHandler(logger slog.Logger, pickListUsecase usecase.PickListUsecase) *handler.IPickListHandler {
+	return handler.NewIPickListHandler(logger, pickListUsecase)
+}
+
+func ProvideWaveHandler(logger slog.Logger, waveUsecase usecase.WaveUsecase) *handler.IWaveHandler {
+	return handler.NewIWaveHandler(logger, waveUsecase)
+}
+
+func ProvideParcelHandler(logger slog.Logger, parcelUsecase usecase.ParcelUsecase) *handler.IParcelHandler {
+	return handler.NewIParcelHandler(logger, parcelUsecase)
+}
+
+func ProvideWarehouseTaskHandler(logger slog.Logger, warehouseTaskUsecase usecase.WarehouseTaskUsecase) *handler.IWarehouseTaskHandler {
+	return handler.NewIWarehouseTaskHandler(logger, warehouseTaskUsecase)
+}
+
+func ProvidePutawayHandler(logger slog.Logger, putawayUsecase usecase.PutawayUsecase) *handler.IPutawayHandler {
+	return handler.NewIPutawayHandler(logger, putawayUsecase)
+}
+
 // RepositoryProviderSet for repo layer
 var HandlerProviderSet = wire.NewSet(
 	ProvideUserHandler,
 	ProvideWareHouseHandler,
 	ProvideZoneHandler,
 	ProvideProductHandler,
-	ProvideRoleHandler, wire.Struct(new(ProviderHandler), "UserHandler", "WareHouseHandler", "ZoneHandler", "ProductHandler", "RoleHandler"),
+	ProvideRoleHandler,
+	ProvideReceiptHandler,
+	ProvideShipmentHandler,
+	ProvideTransferHandler,
+	ProvideReservationHandler,
+	ProvideInventoryCountHandler,
+	ProvideSerialNumberHandler,
+	ProvideSkuHandler,
+	ProvideReorderRuleHandler,
+	ProvideLocationHandler,
+	ProvideStockHoldHandler,
+	ProvideCustomerReturnHandler,
+	ProvideKitHandler,
+	ProvideSupplierHandler,
+	ProvidePurchaseOrderHandler,
+	ProvideCustomerHandler,
+	ProvideSalesOrderHandler,
+	ProvidePickListHandler,
+	ProvideWaveHandler,
+	ProvideParcelHandler,
+	ProvideWarehouseTaskHandler,
+	ProvidePutawayHandler, wire.Struct(new(ProviderHandler), "UserHandler", "WareHouseHandler", "ZoneHandler", "ProductHandler", "RoleHandler", "ReceiptHandler", "ShipmentHandler", "TransferHandler", "ReservationHandler", "InventoryCountHandler", "SerialNumberHandler", "SkuHandler", "ReorderRuleHandler", "LocationHandler", "StockHoldHandler", "CustomerReturnHandler", "KitHandler", "SupplierHandler", "PurchaseOrderHandler", "CustomerHandler", "SalesOrderHandler", "PickListHandler", "WaveHandler", "ParcelHandler", "WarehouseTaskHandler", "PutawayHandler"),
 )
 
 // middleware_provider.go:
@@ -171,11 +431,33 @@ var MiddlewareProviderSet = wire.NewSet(
 // repository_provider.go:
 
 type ProviderRepository struct {
-	UserRepo       *repositories.UserPostgresRepository
-	ProductRepo    *repositories.ProductPostgresRepository
-	WareHouseRepo  *repositories.WareHousePostgresRepository
-	ZoneRepo       *repositories.ZonePostgresRepository
-	PermissionRepo *repositories.PermissionPostgresRepository
+	UserRepo           *repositories.UserPostgresRepository
+	ProductRepo        *repositories.ProductPostgresRepository
+	WareHouseRepo      *repositories.WareHousePostgresRepository
+	ZoneRepo           *repositories.ZonePostgresRepository
+	PermissionRepo     *repositories.PermissionPostgresRepository
+	StockMovementRepo  *repositories.StockMovementPostgresRepository
+	ReceiptRepo        *repositories.ReceiptPostgresRepository
+	ShipmentRepo       *repositories.ShipmentPostgresRepository
+	TransferRepo       *repositories.TransferPostgresRepository
+	ReservationRepo    *repositories.ReservationPostgresRepository
+	InventoryCountRepo *repositories.InventoryCountPostgresRepository
+	SerialNumberRepo   *repositories.SerialNumberPostgresRepository
+	SkuRepo            *repositories.SkuPostgresRepository
+	ReorderRuleRepo    *repositories.ReorderRulePostgresRepository
+	LocationRepo       *repositories.LocationPostgresRepository
+	StockHoldRepo      *repositories.StockHoldPostgresRepository
+	CustomerReturnRepo *repositories.CustomerReturnPostgresRepository
+	KitRepo            *repositories.KitPostgresRepository
+	SupplierRepo       *repositories.SupplierPostgresRepository
+	PurchaseOrderRepo  *repositories.PurchaseOrderPostgresRepository
+	CustomerRepo       *repositories.CustomerPostgresRepository
+	SalesOrderRepo     *repositories.SalesOrderPostgresRepository
+	PickListRepo       *repositories.PickListPostgresRepository
+	WaveRepo           *repositories.WavePostgresRepository
+	ParcelRepo         *repositories.ParcelPostgresRepository
+	WarehouseTaskRepo  *repositories.WarehouseTaskPostgresRepository
+	PutawayRepo        *repositories.PutawayPostgresRepository
 }
 
 func ProvideUserRepository(db database.Database, logger slog.Logger) *repositories.UserPostgresRepository {
@@ -198,13 +480,123 @@ func ProvidePermissionRepository(db database.Database, logger slog.Logger) *repo
 	return repositories.NewPermissionPostgresRepository(db, logger)
 }
 
+func ProvideStockMovementRepository(db database.Database, logger slog.Logger) *repositories.StockMovementPostgresRepository {
+	return repositories.NewStockMovementPostgresRepository(db, logger)
+}
+
+func ProvideReceiptRepository(db database.Database, logger slog.Logger) *repositories.ReceiptPostgresRepository {
+	return repositories.NewReceiptPostgresRepository(db, logger)
+}
+
+func ProvideShipmentRepository(db database.Database, logger slog.Logger) *repositories.ShipmentPostgresRepository {
+	return repositories.NewShipmentPostgresRepository(db, logger)
+}
+
+func ProvideTransferRepository(db database.Database, logger slog.Logger) *repositories.TransferPostgresRepository {
+	return repositories.NewTransferPostgresRepository(db, logger)
+}
+
+func ProvideReservationRepository(db database.Database, logger slog.Logger) *repositories.ReservationPostgresRepository {
+	return repositories.NewReservationPostgresRepository(db, logger)
+}
+
+func ProvideInventoryCountRepository(db database.Database, logger slog.Logger) *repositories.InventoryCountPostgresRepository {
+	return repositories.NewInventoryCountPostgresRepository(db, logger)
+}
+
+func ProvideSerialNumberRepository(db database.Database, logger slog.Logger) *repositories.SerialNumberPostgresRepository {
+	return repositories.NewSerialNumberPostgresRepository(db, logger)
+}
+
+func ProvideSkuRepository(db database.Database, logger slog.Logger) *repositories.SkuPostgresRepository {
+	return repositories.NewSkuPostgresRepository(db, logger)
+}
+
+func ProvideReorderRuleRepository(db database.Database, logger slog.Logger) *repositories.ReorderRulePostgresRepository {
+	return repositories.NewReorderRulePostgresRepository(db, logger)
+}
+
+func ProvideLocationRepository(db database.Database, logger slog.Logger) *repositories.LocationPostgresRepository {
+	return repositories.NewLocationPostgresRepository(db, logger)
+}
+
+func ProvideStockHoldRepository(db database.Database, logger slog.Logger) *repositories.StockHoldPostgresRepository {
+	return repositories.NewStockHoldPostgresRepository(db, logger)
+}
+
+func ProvideCustomerReturnRepository(db database.Database, logger slog.Logger) *repositories.CustomerReturnPostgresRepository {
+	return repositories.NewCustomerReturnPostgresRepository(db, logger)
+}
+
+func ProvideKitRepository(db database.Database, logger slog.Logger) *repositories.KitPostgresRepository {
+	return repositories.NewKitPostgresRepository(db, logger)
+}
+
+func ProvideSupplierRepository(db database.Database, logger slog.Logger) *repositories.SupplierPostgresRepository {
+	return repositories.NewSupplierPostgresRepository(db, logger)
+}
+
+func ProvidePurchaseOrderRepository(db database.Database, logger slog.Logger) *repositories.PurchaseOrderPostgresRepository {
+	return repositories.NewPurchaseOrderPostgresRepository(db, logger)
+}
+
+func ProvideCustomerRepository(db database.Database, logger slog.Logger) *repositories.CustomerPostgresRepository {
+	return repositories.NewCustomerPostgresRepository(db, logger)
+}
+
+func ProvideSalesOrderRepository(db database.Database, logger slog.Logger) *repositories.SalesOrderPostgresRepository {
+	return repositories.NewSalesOrderPostgresRepository(db, logger)
+}
+
+func ProvidePickListRepository(db database.Database, logger slog.Logger) *repositories.PickListPostgresRepository {
+	return repositories.NewPickListPostgresRepository(db, logger)
+}
+
+func ProvideWaveRepository(db database.Database, logger slog.Logger) *repositories.WavePostgresRepository {
+	return repositories.NewWavePostgresRepository(db, logger)
+}
+
+func ProvideParcelRepository(db database.Database, logger slog.Logger) *repositories.ParcelPostgresRepository {
+	return repositories.NewParcelPostgresRepository(db, logger)
+}
+
+func ProvideWarehouseTaskRepository(db database.Database, logger slog.Logger) *repositories.WarehouseTaskPostgresRepository {
+	return repositories.NewWarehouseTaskPostgresRepository(db, logger)
+}
+
+func ProvidePutawayRepository(db database.Database, logger slog.Logger) *repositories.PutawayPostgresRepository {
+	return repositories.NewPutawayPostgresRepository(db, logger)
+}
+
 // RepositoryProviderSet for repo layer
 var RepositoryProviderSet = wire.NewSet(
 	ProvideUserRepository,
 	ProvideProductRepository,
 	ProvideWareHouseRepository,
 	ProvideZoneRepository,
-	ProvidePermissionRepository, wire.Struct(new(ProviderRepository), "UserRepo", "ProductRepo", "WareHouseRepo", "ZoneRepo", "PermissionRepo"),
+	ProvidePermissionRepository,
+	ProvideStockMovementRepository,
+	ProvideReceiptRepository,
+	ProvideShipmentRepository,
+	ProvideTransferRepository,
+	ProvideReservationRepository,
+	ProvideInventoryCountRepository,
+	ProvideSerialNumberRepository,
+	ProvideSkuRepository,
+	ProvideReorderRuleRepository,
+	ProvideLocationRepository,
+	ProvideStockHoldRepository,
+	ProvideCustomerReturnRepository,
+	ProvideKitRepository,
+	ProvideSupplierRepository,
+	ProvidePurchaseOrderRepository,
+	ProvideCustomerRepository,
+	ProvideSalesOrderRepository,
+	ProvidePickListRepository,
+	ProvideWaveRepository,
+	ProvideParcelRepository,
+	ProvideWarehouseTaskRepository,
+	ProvidePutawayRepository, wire.Struct(new(ProviderRepository), "UserRepo", "ProductRepo", "WareHouseRepo", "ZoneRepo", "PermissionRepo", "StockMovementRepo", "ReceiptRepo", "ShipmentRepo", "TransferRepo", "ReservationRepo", "InventoryCountRepo", "SerialNumberRepo", "SkuRepo", "ReorderRuleRepo", "LocationRepo", "StockHoldRepo", "CustomerReturnRepo", "KitRepo", "SupplierRepo", "PurchaseOrderRepo", "CustomerRepo", "SalesOrderRepo", "PickListRepo", "WaveRepo", "ParcelRepo", "WarehouseTaskRepo", "PutawayRepo"),
 )
 
 // service_provider.go:
@@ -213,6 +605,8 @@ type ProviderService struct {
 	TokenManager *services.TokenM
 	Hasher       *services.SHA1Hasher
 	QR           *qr.Generator
+	Notifier     *notifier.LogNotifier
+	Label        *label.Renderer
 }
 
 func ProvideTokenManagerService() *services.TokenM {
@@ -227,21 +621,52 @@ func ProvideQRService(logger slog.Logger) *qr.Generator {
 	return qr.NewGenerator(logger)
 }
 
+func ProvideLabelService(logger slog.Logger) *label.Renderer {
+	return label.NewRenderer(logger)
+}
+
+func ProvideNotifierService(logger slog.Logger) *notifier.LogNotifier {
+	return notifier.NewLogNotifier(logger)
+}
+
 var ServiceProviderSet = wire.NewSet(
 	ProvideTokenManagerService,
 	ProvideHasherService,
-	ProvideQRService, wire.Struct(new(ProviderService), "TokenManager", "Hasher", "QR"),
+	ProvideQRService,
+	ProvideNotifierService,
+	ProvideLabelService, wire.Struct(new(ProviderService), "TokenManager", "Hasher", "QR", "Notifier", "Label"),
 )
 
 // usecase_provider.go:
 
 type ProviderUsecase struct {
-	UserUsecase       *usecase.IUserUsecase
-	WareHouseUsecase  *usecase.IWarehouseUsecase
-	ZoneUsecase       *usecase.IZoneUsecase
-	ProductUsecase    *usecase.IProductUsecase
-	PermissionUsecase *usecase.IPermissionUsecase
-	AuthUsecase       *usecase.IAuthUsecase
+	UserUsecase           *usecase.IUserUsecase
+	WareHouseUsecase      *usecase.IWarehouseUsecase
+	ZoneUsecase           *usecase.IZoneUsecase
+	ProductUsecase        *usecase.IProductUsecase
+	PermissionUsecase     *usecase.IPermissionUsecase
+	AuthUsecase           *usecase.IAuthUsecase
+	ReceiptUsecase        *usecase.IReceiptUsecase
+	ShipmentUsecase       *usecase.IShipmentUsecase
+	TransferUsecase       *usecase.ITransferUsecase
+	ReservationUsecase    *usecase.IReservationUsecase
+	InventoryCountUsecase *usecase.IInventoryCountUsecase
+	SerialNumberUsecase   *usecase.ISerialNumberUsecase
+	SkuUsecase            *usecase.ISkuUsecase
+	ReorderRuleUsecase    *usecase.IReorderRuleUsecase
+	LocationUsecase       *usecase.ILocationUsecase
+	StockHoldUsecase      *usecase.IStockHoldUsecase
+	CustomerReturnUsecase *usecase.ICustomerReturnUsecase
+	KitUsecase            *usecase.IKitUsecase
+	SupplierUsecase       *usecase.ISupplierUsecase
+	PurchaseOrderUsecase  *usecase.IPurchaseOrderUsecase
+	CustomerUsecase       *usecase.ICustomerUsecase
+	SalesOrderUsecase     *usecase.ISalesOrderUsecase
+	PickListUsecase       *usecase.IPickListUsecase
+	WaveUsecase           *usecase.IWaveUsecase
+	ParcelUsecase         *usecase.IParcelUsecase
+	WarehouseTaskUsecase  *usecase.IWarehouseTaskUsecase
+	PutawayUsecase        *usecase.IPutawayUsecase
 }
 
 func ProvideUserUsecase(repoUser repositories.UserRepository, passwordHasher services.PasswordHasher, tokenManager services.TokenManager) *usecase.IUserUsecase {
@@ -256,8 +681,8 @@ func ProvideZoneUsecase(repoZone repositories.ZoneRepository) *usecase.IZoneUsec
 	return usecase.NewIZoneUsecase(repoZone)
 }
 
-func ProvideProductUsecase(repoProduct repositories.ProductRepository, qr2 qr.GeneratorQR, cfg config.Config) *usecase.IProductUsecase {
-	return usecase.NewIProductUsecase(repoProduct, qr2, cfg)
+func ProvideProductUsecase(repoProduct repositories.ProductRepository, repoSku repositories.SkuRepository, repoStockMovement repositories.StockMovementRepository, repoReservation repositories.ReservationRepository, repoStockHold repositories.StockHoldRepository, repoSerialNumber repositories.SerialNumberRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr2 qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.IProductUsecase {
+	return usecase.NewIProductUsecase(repoProduct, repoSku, repoStockMovement, repoReservation, repoStockHold, repoSerialNumber, repoReorderRule, alertNotifier, qr2, cfg, logger)
 }
 
 func ProvidePermissionUsecase(repoUser repositories.UserRepository, repoPermission repositories.PermissionRepository, repoWarehouse repositories.WareHouseRepository) *usecase.IPermissionUsecase {
@@ -268,11 +693,116 @@ func ProvideAuthUsecase(repoUser repositories.UserRepository, tokenManager servi
 	return usecase.NewIAuthUsecase(repoUser, tokenManager)
 }
 
+func ProvideReceiptUsecase(repoReceipt repositories.ReceiptRepository, repoProduct repositories.ProductRepository, repoSerialNumber repositories.SerialNumberRepository, repoSku repositories.SkuRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr2 qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.IReceiptUsecase {
+	return usecase.NewIReceiptUsecase(repoReceipt, repoProduct, repoSerialNumber, repoSku, repoReorderRule, alertNotifier, qr2, cfg, logger)
+}
+
+func ProvideShipmentUsecase(repoShipment repositories.ShipmentRepository, repoProduct repositories.ProductRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.IShipmentUsecase {
+	return usecase.NewIShipmentUsecase(repoShipment, repoProduct, repoReorderRule, alertNotifier, logger)
+}
+
+func ProvideTransferUsecase(repoTransfer repositories.TransferRepository, repoProduct repositories.ProductRepository, repoSku repositories.SkuRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr2 qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.ITransferUsecase {
+	return usecase.NewITransferUsecase(repoTransfer, repoProduct, repoSku, repoReorderRule, alertNotifier, qr2, cfg, logger)
+}
+
+func ProvideReservationUsecase(repoReservation repositories.ReservationRepository, repoSku repositories.SkuRepository) *usecase.IReservationUsecase {
+	return usecase.NewIReservationUsecase(repoReservation, repoSku)
+}
+
+func ProvideInventoryCountUsecase(repoInventoryCount repositories.InventoryCountRepository, repoSku repositories.SkuRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.IInventoryCountUsecase {
+	return usecase.NewIInventoryCountUsecase(repoInventoryCount, repoSku, repoReorderRule, alertNotifier, logger)
+}
+
+func ProvideSerialNumberUsecase(repoSerialNumber repositories.SerialNumberRepository, repoProduct repositories.ProductRepository) *usecase.ISerialNumberUsecase {
+	return usecase.NewISerialNumberUsecase(repoSerialNumber, repoProduct)
+}
+
+func ProvideSkuUsecase(repoSku repositories.SkuRepository) *usecase.ISkuUsecase {
+	return usecase.NewISkuUsecase(repoSku)
+}
+
+func ProvideReorderRuleUsecase(repoReorderRule repositories.ReorderRuleRepository, repoSku repositories.SkuRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.IReorderRuleUsecase {
+	return usecase.NewIReorderRuleUsecase(repoReorderRule, repoSku, alertNotifier, logger)
+}
+
+func ProvideLocationUsecase(repoLocation repositories.LocationRepository, qr2 qr.GeneratorQR, cfg config.Config) *usecase.ILocationUsecase {
+	return usecase.NewILocationUsecase(repoLocation, qr2, cfg)
+}
+
+func ProvideStockHoldUsecase(repoStockHold repositories.StockHoldRepository, repoSku repositories.SkuRepository) *usecase.IStockHoldUsecase {
+	return usecase.NewIStockHoldUsecase(repoStockHold, repoSku)
+}
+
+func ProvideCustomerReturnUsecase(repoCustomerReturn repositories.CustomerReturnRepository, repoShipment repositories.ShipmentRepository, repoProduct repositories.ProductRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr2 qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.ICustomerReturnUsecase {
+	return usecase.NewICustomerReturnUsecase(repoCustomerReturn, repoShipment, repoProduct, repoReorderRule, alertNotifier, qr2, cfg, logger)
+}
+
+func ProvideKitUsecase(repoKit repositories.KitRepository, repoSku repositories.SkuRepository, repoProduct repositories.ProductRepository, repoReorderRule repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, qr2 qr.GeneratorQR, cfg config.Config, logger slog.Logger) *usecase.IKitUsecase {
+	return usecase.NewIKitUsecase(repoKit, repoSku, repoProduct, repoReorderRule, alertNotifier, qr2, cfg, logger)
+}
+
+func ProvideSupplierUsecase(repoSupplier repositories.SupplierRepository) *usecase.ISupplierUsecase {
+	return usecase.NewISupplierUsecase(repoSupplier)
+}
+
+func ProvidePurchaseOrderUsecase(repoPurchaseOrder repositories.PurchaseOrderRepository, repoReceipt repositories.ReceiptRepository, repoSku repositories.SkuRepository) *usecase.IPurchaseOrderUsecase {
+	return usecase.NewIPurchaseOrderUsecase(repoPurchaseOrder, repoReceipt, repoSku)
+}
+
+func ProvideCustomerUsecase(repoCustomer repositories.CustomerRepository) *usecase.ICustomerUsecase {
+	return usecase.NewICustomerUsecase(repoCustomer)
+}
+
+func ProvideSalesOrderUsecase(repoSalesOrder repositories.SalesOrderRepository, repoProduct repositories.ProductRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.ISalesOrderUsecase {
+	return usecase.NewISalesOrderUsecase(repoSalesOrder, repoProduct, alertNotifier, logger)
+}
+
+func ProvidePickListUsecase(repoPickList repositories.PickListRepository) *usecase.IPickListUsecase {
+	return usecase.NewIPickListUsecase(repoPickList)
+}
+
+func ProvideWaveUsecase(repoWave repositories.WaveRepository, repoPickList repositories.PickListRepository) *usecase.IWaveUsecase {
+	return usecase.NewIWaveUsecase(repoWave, repoPickList)
+}
+
+func ProvideParcelUsecase(repoParcel repositories.ParcelRepository, repoShipment repositories.ShipmentRepository, repoWarehouse repositories.WareHouseRepository, labelRenderer label.RendererLabel) *usecase.IParcelUsecase {
+	return usecase.NewIParcelUsecase(repoParcel, repoShipment, repoWarehouse, labelRenderer)
+}
+
+func ProvideWarehouseTaskUsecase(repoWarehouseTask repositories.WarehouseTaskRepository, alertNotifier notifier.Notifier, logger slog.Logger) *usecase.IWarehouseTaskUsecase {
+	return usecase.NewIWarehouseTaskUsecase(repoWarehouseTask, alertNotifier, logger)
+}
+
+func ProvidePutawayUsecase(repoPutaway repositories.PutawayRepository, repoPickList repositories.PickListRepository, repoZone repositories.ZoneRepository) *usecase.IPutawayUsecase {
+	return usecase.NewIPutawayUsecase(repoPutaway, repoPickList, repoZone)
+}
+
 var UsecaseProviderSet = wire.NewSet(
 	ProvideUserUsecase,
 	ProvideWarehouseUsecase,
 	ProvideZoneUsecase,
 	ProvideProductUsecase,
 	ProvidePermissionUsecase,
-	ProvideAuthUsecase, wire.Struct(new(ProviderUsecase), "UserUsecase", "WareHouseUsecase", "ZoneUsecase", "ProductUsecase", "PermissionUsecase", "AuthUsecase"),
+	ProvideAuthUsecase,
+	ProvideReceiptUsecase,
+	ProvideShipmentUsecase,
+	ProvideTransferUsecase,
+	ProvideReservationUsecase,
+	ProvideInventoryCountUsecase,
+	ProvideSerialNumberUsecase,
+	ProvideSkuUsecase,
+	ProvideReorderRuleUsecase,
+	ProvideLocationUsecase,
+	ProvideStockHoldUsecase,
+	ProvideCustomerReturnUsecase,
+	ProvideKitUsecase,
+	ProvideSupplierUsecase,
+	ProvidePurchaseOrderUsecase,
+	ProvideCustomerUsecase,
+	ProvideSalesOrderUsecase,
+	ProvidePickListUsecase,
+	ProvideWaveUsecase,
+	ProvideParcelUsecase,
+	ProvideWarehouseTaskUsecase,
+	ProvidePutawayUsecase, wire.Struct(new(ProviderUsecase), "UserUsecase", "WareHouseUsecase", "ZoneUsecase", "ProductUsecase", "PermissionUsecase", "AuthUsecase", "ReceiptUsecase", "ShipmentUsecase", "TransferUsecase", "ReservationUsecase", "InventoryCountUsecase", "SerialNumberUsecase", "SkuUsecase", "ReorderRuleUsecase", "LocationUsecase", "StockHoldUsecase", "CustomerReturnUsecase", "KitUsecase", "SupplierUsecase", "PurchaseOrderUsecase", "CustomerUsecase", "SalesOrderUsecase", "PickListUsecase", "WaveUsecase", "ParcelUsecase", "WarehouseTaskUsecase", "PutawayUsecase"),
 )
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Customer - покупатель из справочника владельца складов, на него оформляются заказы SalesOrder
+type Customer struct {
+	Id          uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	UuidUser    string    `gorm:"column:uuid_user"`
+	Name        string    `gorm:"column:name"`
+	ContactName string    `gorm:"column:contact_name"`
+	Email       string    `gorm:"column:email"`
+	Phone       string    `gorm:"column:phone"`
+	Address     string    `gorm:"column:address"`
+	CreatedAt   time.Time `gorm:"column:created_at;default:now()"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+const (
+	ReturnStatusDraft  = "draft"
+	ReturnStatusPosted = "posted"
+)
+
+const (
+	ReturnConditionResell = "resell"
+	ReturnConditionRepair = "repair"
+	ReturnConditionScrap  = "scrap"
+)
+
+// CustomerReturn - возврат покупателя по отгрузке ShipmentId. Остатки меняются только при проведении
+type CustomerReturn struct {
+	Id          uint64               `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64               `gorm:"column:ware_house_id"`
+	ShipmentId  uint64               `gorm:"column:shipment_id"`
+	Status      string               `gorm:"column:status;default:draft"`
+	Comment     string               `gorm:"column:comment"`
+	CreatedBy   string               `gorm:"column:created_by"`
+	CreatedAt   time.Time            `gorm:"column:created_at;default:now()"`
+	PostedAt    *time.Time           `gorm:"column:posted_at"`
+	Lines       []CustomerReturnLine `gorm:"foreignKey:ReturnId"`
+}
+
+// CustomerReturnLine возвращает Quantity (в долях базовой единицы) по строке отгрузки ShipmentLineId.
+// Unit и UnitFactor берутся из строки отгрузки. ZoneId - зона размещения, для resell пустая зона означает
+// зону исходного товара. ResultProductUuid и HoldId заполняются при проведении
+type CustomerReturnLine struct {
+	Id                uint64  `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ReturnId          uint64  `gorm:"column:return_id"`
+	ShipmentLineId    uint64  `gorm:"column:shipment_line_id"`
+	ProductUuid       string  `gorm:"column:product_uuid"`
+	SkuId             uint64  `gorm:"column:sku_id"`
+	Quantity          uint64  `gorm:"column:quantity"`
+	Unit              string  `gorm:"column:unit"`
+	UnitFactor        float64 `gorm:"column:unit_factor"`
+	Condition         string  `gorm:"column:condition"`
+	ZoneId            *uint64 `gorm:"column:zone_id"`
+	ResultProductUuid *string `gorm:"column:result_product_uuid"`
+	HoldId            *uint64 `gorm:"column:hold_id"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+const (
+	InventoryCountStatusOpen      = "open"
+	InventoryCountStatusApproved  = "approved"
+	InventoryCountStatusCancelled = "cancelled"
+)
+
+// InventoryCount - сессия пересчета товара на складе целиком или в одной зоне (ZoneId)
+type InventoryCount struct {
+	Id          uint64               `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64               `gorm:"column:ware_house_id"`
+	ZoneId      *uint64              `gorm:"column:zone_id"`
+	Status      string               `gorm:"column:status;default:open"`
+	Comment     string               `gorm:"column:comment"`
+	CreatedBy   string               `gorm:"column:created_by"`
+	ApprovedBy  *string              `gorm:"column:approved_by"`
+	CreatedAt   time.Time            `gorm:"column:created_at;default:now()"`
+	ClosedAt    *time.Time           `gorm:"column:closed_at"`
+	Lines       []InventoryCountLine `gorm:"foreignKey:InventoryCountId"`
+}
+
+type InventoryCountLine struct {
+	Id               uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	InventoryCountId uint64     `gorm:"column:inventory_count_id"`
+	ProductUuid      string     `gorm:"column:product_uuid"`
+	ExpectedQuantity uint64     `gorm:"column:expected_quantity"`
+	CountedQuantity  *uint64    `gorm:"column:counted_quantity"`
+	CountedBy        *string    `gorm:"column:counted_by"`
+	CountedAt        *time.Time `gorm:"column:counted_at"`
+}
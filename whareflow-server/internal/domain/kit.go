@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+const (
+	KitOperationAssembly    = "assembly"
+	KitOperationDisassembly = "disassembly"
+)
+
+// KitComponent - строка спецификации набора KitSkuId: на одну базовую единицу набора уходит Quantity
+// долей базовой единицы позиции ComponentSkuId
+type KitComponent struct {
+	Id             uint64 `gorm:"primaryKey;autoIncrement:true;column:id"`
+	KitSkuId       uint64 `gorm:"column:kit_sku_id"`
+	ComponentSkuId uint64 `gorm:"column:component_sku_id"`
+	Quantity       uint64 `gorm:"column:quantity"`
+	ComponentSku   *Sku   `gorm:"foreignKey:ComponentSkuId"`
+}
+
+// KitOperation - сборка или разборка Quantity наборов на складе. ZoneId - зона, куда приходуется результат:
+// набор при сборке, компоненты при разборке. Lines - движения по строкам товара, записанные операцией
+type KitOperation struct {
+	Id          uint64             `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64             `gorm:"column:ware_house_id"`
+	KitSkuId    uint64             `gorm:"column:kit_sku_id"`
+	Kind        string             `gorm:"column:kind"`
+	Quantity    uint64             `gorm:"column:quantity"`
+	ZoneId      *uint64            `gorm:"column:zone_id"`
+	Comment     string             `gorm:"column:comment"`
+	CreatedBy   string             `gorm:"column:created_by"`
+	CreatedAt   time.Time          `gorm:"column:created_at;default:now()"`
+	Lines       []KitOperationLine `gorm:"foreignKey:OperationId"`
+	KitSku      *Sku               `gorm:"foreignKey:KitSkuId"`
+}
+
+// KitOperationLine: Quantity со знаком - отрицательное списано со строки товара, положительное оприходовано на нее
+type KitOperationLine struct {
+	Id          uint64 `gorm:"primaryKey;autoIncrement:true;column:id"`
+	OperationId uint64 `gorm:"column:operation_id"`
+	ProductUuid string `gorm:"column:product_uuid"`
+	SkuId       uint64 `gorm:"column:sku_id"`
+	Quantity    int64  `gorm:"column:quantity"`
+	MovementId  uint64 `gorm:"column:movement_id"`
+	Sku         *Sku   `gorm:"foreignKey:SkuId"`
+}
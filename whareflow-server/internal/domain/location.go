@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	LocationKindAisle = "aisle"
+	LocationKindRack  = "rack"
+	LocationKindShelf = "shelf"
+	LocationKindBin   = "bin"
+)
+
+// Location - узел дерева адресов хранения зоны. Товар размещается только в узлах вида LocationKindBin.
+// Capacity ограничивает суммарный остаток во всем поддереве узла, nil - без ограничения
+type Location struct {
+	Id        uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ZoneId    uint64    `gorm:"column:zone_id"`
+	ParentId  *uint64   `gorm:"column:parent_id"`
+	Kind      string    `gorm:"column:kind"`
+	Code      string    `gorm:"column:code"`
+	Capacity  *uint64   `gorm:"column:capacity"`
+	QrPath    string    `gorm:"column:qr"`
+	CreatedAt time.Time `gorm:"column:created_at;default:now()"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Parcel - грузовое место упакованной отгрузки ShipmentId. Number - номер места в отгрузке, места нумеруются подряд с 1
+type Parcel struct {
+	Id         uint64       `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ShipmentId uint64       `gorm:"column:shipment_id"`
+	Number     int          `gorm:"column:number"`
+	WeightKg   float64      `gorm:"column:weight_kg"`
+	LengthCm   float64      `gorm:"column:length_cm"`
+	WidthCm    float64      `gorm:"column:width_cm"`
+	HeightCm   float64      `gorm:"column:height_cm"`
+	CreatedBy  string       `gorm:"column:created_by"`
+	CreatedAt  time.Time    `gorm:"column:created_at;default:now()"`
+	Lines      []ParcelLine `gorm:"foreignKey:ParcelId"`
+}
+
+// ParcelLine - часть собранного количества строки отгрузки ShipmentLineId, уложенная в место, в долях базовой единицы
+type ParcelLine struct {
+	Id             uint64 `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ParcelId       uint64 `gorm:"column:parcel_id"`
+	ShipmentLineId uint64 `gorm:"column:shipment_line_id"`
+	Quantity       uint64 `gorm:"column:quantity"`
+}
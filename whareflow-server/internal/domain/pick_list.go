@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+const (
+	PickListStatusOpen = "open"
+	PickListStatusDone = "done"
+)
+
+// WalkPathStep - шаг маршрута обхода склада. Шаг с LocationId покрывает все поддерево узла адреса,
+// шаг без него - всю зону ZoneId. Меньший Sequence проходится раньше
+type WalkPathStep struct {
+	Id          uint64  `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64  `gorm:"column:ware_house_id"`
+	Sequence    int     `gorm:"column:sequence"`
+	ZoneId      uint64  `gorm:"column:zone_id"`
+	LocationId  *uint64 `gorm:"column:location_id"`
+}
+
+// PickList - лист сборки: набор заданий на сборку, которые сборщик проходит за один обход склада.
+// Лист закрывается, когда в нем не остается невыполненных заданий
+type PickList struct {
+	Id          uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64     `gorm:"column:ware_house_id"`
+	Status      string     `gorm:"column:status;default:open"`
+	CreatedBy   string     `gorm:"column:created_by"`
+	CreatedAt   time.Time  `gorm:"column:created_at;default:now()"`
+	DoneAt      *time.Time `gorm:"column:done_at"`
+	Tasks       []PickTask `gorm:"foreignKey:PickListId"`
+}
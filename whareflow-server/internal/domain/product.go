@@ -1,10 +1,19 @@
 package domain
 
+import "time"
+
+// Product - остаток позиции каталога (Sku) определенной партии в зоне. Партия задается LotNumber и датами,
+// у товара без партии они пустые. Если позиция серийная, каждая единица остатка - отдельный SerialNumber.
+// LocationId - ячейка зоны, в которой лежит товар, nil - товар лежит в зоне без уточнения адреса
 type Product struct {
-	Uuid        []byte `gorm:"table:products;column:uuid;primaryKey;default:gen_random_uuid()"`
-	Title       string `gorm:"column:title"`
-	Count       uint64 `gorm:"column:count"`
-	QrPath      string `gorm:"column:qr"`
-	Description string `gorm:"column:description"`
-	ZoneId      uint64 `gorm:"column:zone_id"`
+	Uuid           []byte     `gorm:"table:products;column:uuid;primaryKey;default:gen_random_uuid()"`
+	SkuId          uint64     `gorm:"column:sku_id"`
+	Count          uint64     `gorm:"column:count"`
+	QrPath         string     `gorm:"column:qr"`
+	ZoneId         uint64     `gorm:"column:zone_id"`
+	LocationId     *uint64    `gorm:"column:location_id"`
+	LotNumber      string     `gorm:"column:lot_number"`
+	ProductionDate *time.Time `gorm:"column:production_date"`
+	ExpiryDate     *time.Time `gorm:"column:expiry_date"`
+	Sku            *Sku       `gorm:"foreignKey:SkuId"`
 }
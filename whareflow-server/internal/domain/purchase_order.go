@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+const (
+	PurchaseOrderStatusOpen              = "open"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusClosed            = "closed"
+)
+
+// PurchaseOrder - заказ поставщику SupplierId на склад WarehouseId. Принимается обычными поступлениями
+// со ссылкой на заказ, каждое проведенное поступление увеличивает ReceivedQuantity строк заказа
+type PurchaseOrder struct {
+	Id           uint64              `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId  uint64              `gorm:"column:ware_house_id"`
+	SupplierId   uint64              `gorm:"column:supplier_id"`
+	Status       string              `gorm:"column:status;default:open"`
+	ExpectedDate *time.Time          `gorm:"column:expected_date"`
+	Comment      string              `gorm:"column:comment"`
+	CreatedBy    string              `gorm:"column:created_by"`
+	CreatedAt    time.Time           `gorm:"column:created_at;default:now()"`
+	ClosedAt     *time.Time          `gorm:"column:closed_at"`
+	Supplier     *Supplier           `gorm:"foreignKey:SupplierId"`
+	Lines        []PurchaseOrderLine `gorm:"foreignKey:OrderId"`
+}
+
+// PurchaseOrderLine: Quantity и ReceivedQuantity хранятся в долях базовой единицы позиции SkuId.
+// ExpectedDate строки, если задана, уточняет дату поставки заказа
+type PurchaseOrderLine struct {
+	Id               uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	OrderId          uint64     `gorm:"column:order_id"`
+	SkuId            uint64     `gorm:"column:sku_id"`
+	Title            string     `gorm:"column:title"`
+	Quantity         uint64     `gorm:"column:quantity"`
+	ReceivedQuantity uint64     `gorm:"column:received_quantity"`
+	Unit             string     `gorm:"column:unit"`
+	UnitFactor       float64    `gorm:"column:unit_factor"`
+	ExpectedDate     *time.Time `gorm:"column:expected_date"`
+}
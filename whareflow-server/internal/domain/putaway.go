@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Putaway - размещение принятого по строке поступления товара. SuggestedZoneId и SuggestedLocationId - лучшее место
+// по подбору на момент размещения, Override - товар положили не туда, куда предлагал подбор, Reason - почему
+type Putaway struct {
+	Id                  uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ReceiptId           uint64    `gorm:"column:receipt_id"`
+	ReceiptLineId       uint64    `gorm:"column:receipt_line_id"`
+	ProductUuid         *string   `gorm:"column:product_uuid"`
+	Quantity            uint64    `gorm:"column:quantity"`
+	ZoneId              uint64    `gorm:"column:zone_id"`
+	LocationId          *uint64   `gorm:"column:location_id"`
+	SuggestedZoneId     *uint64   `gorm:"column:suggested_zone_id"`
+	SuggestedLocationId *uint64   `gorm:"column:suggested_location_id"`
+	Override            bool      `gorm:"column:override"`
+	Reason              string    `gorm:"column:reason"`
+	CreatedBy           string    `gorm:"column:created_by"`
+	CreatedAt           time.Time `gorm:"column:created_at;default:now()"`
+}
+
+// PutawayLine - принятая строка поступления с позицией каталога и строкой товара. Product есть только у проведенного
+// поступления: до проведения товар еще не на остатке. Putaway - размещение строки, nil - строка еще не размещена
+type PutawayLine struct {
+	Line    ReceiptLine
+	Sku     Sku
+	Product *Product
+	Putaway *Putaway
+}
+
+// PutawayStock - остаток позиции в зоне или ячейке, по нему подбор оценивает свободное место и консолидацию
+type PutawayStock struct {
+	ZoneId     uint64
+	LocationId *uint64
+	SkuId      uint64
+	Count      uint64
+}
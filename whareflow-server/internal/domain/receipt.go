@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+const (
+	ReceiptStatusDraft    = "draft"
+	ReceiptStatusReceived = "received"
+	ReceiptStatusPosted   = "posted"
+)
+
+// Receipt с PurchaseOrderId принимает товар по заказу поставщику: каждая его строка ссылается на строку заказа
+type Receipt struct {
+	Id              uint64        `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId     uint64        `gorm:"column:ware_house_id"`
+	PurchaseOrderId *uint64       `gorm:"column:purchase_order_id"`
+	Status          string        `gorm:"column:status;default:draft"`
+	Comment         string        `gorm:"column:comment"`
+	CreatedBy       string        `gorm:"column:created_by"`
+	CreatedAt       time.Time     `gorm:"column:created_at;default:now()"`
+	ReceivedAt      *time.Time    `gorm:"column:received_at"`
+	PostedAt        *time.Time    `gorm:"column:posted_at"`
+	Lines           []ReceiptLine `gorm:"foreignKey:ReceiptId"`
+}
+
+// ReceiptLine без ProductUuid означает новую строку остатка позиции SkuId, которая будет создана при проведении поступления.
+// Количества хранятся в долях базовой единицы, Unit и UnitFactor - единица, в которой строка была заведена.
+// PurchaseOrderLineId - строка заказа поставщику, по которой принимается товар
+type ReceiptLine struct {
+	Id                  uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ReceiptId           uint64     `gorm:"column:receipt_id"`
+	ZoneId              uint64     `gorm:"column:zone_id"`
+	ProductUuid         *string    `gorm:"column:product_uuid"`
+	SkuId               uint64     `gorm:"column:sku_id"`
+	Title               string     `gorm:"column:title"`
+	Description         string     `gorm:"column:description"`
+	Quantity            uint64     `gorm:"column:quantity"`
+	ReceivedQuantity    uint64     `gorm:"column:received_quantity"`
+	Unit                string     `gorm:"column:unit"`
+	UnitFactor          float64    `gorm:"column:unit_factor"`
+	LotNumber           string     `gorm:"column:lot_number"`
+	ProductionDate      *time.Time `gorm:"column:production_date"`
+	ExpiryDate          *time.Time `gorm:"column:expiry_date"`
+	PurchaseOrderLineId *uint64    `gorm:"column:purchase_order_line_id"`
+}
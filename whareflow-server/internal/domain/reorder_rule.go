@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// ReorderRule задает минимальный (точка перезаказа) и максимальный уровни запаса позиции каталога на складе
+type ReorderRule struct {
+	Id          uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64     `gorm:"column:ware_house_id"`
+	SkuId       uint64     `gorm:"column:sku_id"`
+	MinLevel    uint64     `gorm:"column:min_level"`
+	MaxLevel    uint64     `gorm:"column:max_level"`
+	AlertedAt   *time.Time `gorm:"column:alerted_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;default:now()"`
+}
+
+// StockLevel - правило перезаказа вместе с текущим остатком позиции на складе. Unit и Decimals - базовая единица позиции
+type StockLevel struct {
+	RuleId      uint64     `gorm:"column:rule_id"`
+	WarehouseId uint64     `gorm:"column:ware_house_id"`
+	SkuId       uint64     `gorm:"column:sku_id"`
+	SkuCode     string     `gorm:"column:sku_code"`
+	SkuName     string     `gorm:"column:sku_name"`
+	Unit        string     `gorm:"column:unit"`
+	Decimals    uint8      `gorm:"column:decimals"`
+	MinLevel    uint64     `gorm:"column:min_level"`
+	MaxLevel    uint64     `gorm:"column:max_level"`
+	OnHand      uint64     `gorm:"column:on_hand"`
+	AlertedAt   *time.Time `gorm:"column:alerted_at"`
+}
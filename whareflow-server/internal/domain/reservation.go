@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+const (
+	ReservationStatusActive   = "active"
+	ReservationStatusReleased = "released"
+	ReservationStatusExpired  = "expired"
+)
+
+// Reservation обещает часть остатка товара заказу или другому документу (OwnerRef), не перемещая его.
+// Резерв действует, пока он активен и не истек ExpiresAt. Без ExpiresAt резерв бессрочный - так держатся резервы
+// подтвержденных заказов
+type Reservation struct {
+	Id          uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ProductUuid string     `gorm:"column:product_uuid"`
+	Quantity    uint64     `gorm:"column:quantity"`
+	OwnerRef    string     `gorm:"column:owner_ref"`
+	Status      string     `gorm:"column:status;default:active"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at"`
+	CreatedBy   string     `gorm:"column:created_by"`
+	CreatedAt   time.Time  `gorm:"column:created_at;default:now()"`
+	ReleasedAt  *time.Time `gorm:"column:released_at"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+const (
+	SalesOrderStatusDraft     = "draft"
+	SalesOrderStatusConfirmed = "confirmed"
+	SalesOrderStatusPicking   = "picking"
+	SalesOrderStatusPacked    = "packed"
+	SalesOrderStatusShipped   = "shipped"
+	SalesOrderStatusCancelled = "cancelled"
+	SalesOrderStatusExpired   = "expired"
+)
+
+const (
+	PickTaskStatusOpen      = "open"
+	PickTaskStatusDone      = "done"
+	PickTaskStatusCancelled = "cancelled"
+)
+
+// SalesOrder - заказ покупателя CustomerId со склада WarehouseId. Строки черновика резервируют товар до ReservedUntil,
+// черновик с истекшим резервом переходит в expired. При подтверждении резервы становятся бессрочными, по заказу
+// заводится отгрузка ShipmentId и задания на сборку, дальше статус заказа следует за сборкой и отгрузкой
+type SalesOrder struct {
+	Id            uint64           `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId   uint64           `gorm:"column:ware_house_id"`
+	CustomerId    uint64           `gorm:"column:customer_id"`
+	Status        string           `gorm:"column:status;default:draft"`
+	ShipmentId    *uint64          `gorm:"column:shipment_id"`
+	ReservedUntil time.Time        `gorm:"column:reserved_until"`
+	Comment       string           `gorm:"column:comment"`
+	CreatedBy     string           `gorm:"column:created_by"`
+	CreatedAt     time.Time        `gorm:"column:created_at;default:now()"`
+	ConfirmedAt   *time.Time       `gorm:"column:confirmed_at"`
+	ShippedAt     *time.Time       `gorm:"column:shipped_at"`
+	CancelledAt   *time.Time       `gorm:"column:cancelled_at"`
+	Customer      *Customer        `gorm:"foreignKey:CustomerId"`
+	Lines         []SalesOrderLine `gorm:"foreignKey:OrderId"`
+}
+
+// SalesOrderLine хранит количество в долях базовой единицы. ReservationId - резерв строки на товаре ProductUuid
+type SalesOrderLine struct {
+	Id            uint64  `gorm:"primaryKey;autoIncrement:true;column:id"`
+	OrderId       uint64  `gorm:"column:order_id"`
+	ProductUuid   string  `gorm:"column:product_uuid"`
+	Quantity      uint64  `gorm:"column:quantity"`
+	Unit          string  `gorm:"column:unit"`
+	UnitFactor    float64 `gorm:"column:unit_factor"`
+	ReservationId *uint64 `gorm:"column:reservation_id"`
+}
+
+// PickTask - задание собрать строку отгрузки заказа из зоны ZoneId (и ячейки LocationId, если товар адресный).
+// Задание не закреплено за работником, PickedBy - кто его выполнил. PickListId - лист сборки, в который попало задание,
+// WaveId - волна: задание волны закрывается раскладкой на стене сортировки, а не по одному
+type PickTask struct {
+	Id             uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId    uint64     `gorm:"column:ware_house_id"`
+	OrderId        uint64     `gorm:"column:order_id"`
+	PickListId     *uint64    `gorm:"column:pick_list_id"`
+	WaveId         *uint64    `gorm:"column:wave_id"`
+	ShipmentLineId *uint64    `gorm:"column:shipment_line_id"`
+	ProductUuid    string     `gorm:"column:product_uuid"`
+	ZoneId         uint64     `gorm:"column:zone_id"`
+	LocationId     *uint64    `gorm:"column:location_id"`
+	Quantity       uint64     `gorm:"column:quantity"`
+	PickedQuantity uint64     `gorm:"column:picked_quantity"`
+	Unit           string     `gorm:"column:unit"`
+	UnitFactor     float64    `gorm:"column:unit_factor"`
+	Status         string     `gorm:"column:status;default:open"`
+	PickedBy       *string    `gorm:"column:picked_by"`
+	CreatedAt      time.Time  `gorm:"column:created_at;default:now()"`
+	DoneAt         *time.Time `gorm:"column:done_at"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+const (
+	SerialStatusPending = "pending"
+	SerialStatusInStock = "in_stock"
+	SerialStatusIssued  = "issued"
+)
+
+// SerialNumber - единица серийного товара. ProductUuid указывает на строку остатка, в которой номер лежит сейчас
+// (или лежал перед выдачей). ShipmentLineId заполняется при сборке отгрузки. Номер уникален в пределах владельца UuidUser
+type SerialNumber struct {
+	Id             uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	Serial         string    `gorm:"column:serial"`
+	UuidUser       string    `gorm:"column:uuid_user"`
+	WarehouseId    uint64    `gorm:"column:ware_house_id"`
+	ProductUuid    *string   `gorm:"column:product_uuid"`
+	Status         string    `gorm:"column:status"`
+	ReceiptLineId  *uint64   `gorm:"column:receipt_line_id"`
+	ShipmentLineId *uint64   `gorm:"column:shipment_line_id"`
+	QrPath         string    `gorm:"column:qr"`
+	CreatedAt      time.Time `gorm:"column:created_at;default:now()"`
+}
+
+// SerialMovement связывает серийный номер с движением остатка, в котором он участвовал
+type SerialMovement struct {
+	SerialNumberId  uint64 `gorm:"primaryKey;column:serial_number_id"`
+	StockMovementId uint64 `gorm:"primaryKey;column:stock_movement_id"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+const (
+	ShipmentStatusPicking = "picking"
+	ShipmentStatusPacked  = "packed"
+	ShipmentStatusShipped = "shipped"
+)
+
+type Shipment struct {
+	Id          uint64         `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64         `gorm:"column:ware_house_id"`
+	Status      string         `gorm:"column:status;default:picking"`
+	Comment     string         `gorm:"column:comment"`
+	CreatedBy   string         `gorm:"column:created_by"`
+	CreatedAt   time.Time      `gorm:"column:created_at;default:now()"`
+	PackedAt    *time.Time     `gorm:"column:packed_at"`
+	ShippedAt   *time.Time     `gorm:"column:shipped_at"`
+	Lines       []ShipmentLine `gorm:"foreignKey:ShipmentId"`
+}
+
+// ShipmentLine хранит количества в долях базовой единицы, Unit и UnitFactor - единица, в которой строка была заведена.
+// SkuId - позиция каталога, отгруженная по строке
+type ShipmentLine struct {
+	Id             uint64  `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ShipmentId     uint64  `gorm:"column:shipment_id"`
+	ProductUuid    string  `gorm:"column:product_uuid"`
+	SkuId          uint64  `gorm:"column:sku_id"`
+	Quantity       uint64  `gorm:"column:quantity"`
+	PickedQuantity uint64  `gorm:"column:picked_quantity"`
+	Unit           string  `gorm:"column:unit"`
+	UnitFactor     float64 `gorm:"column:unit_factor"`
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Sku - позиция каталога владельца складов: что это за товар, без привязки к зоне и количеству.
+// Остатки по зонам хранятся в Product. Габариты и вес указываются для одной базовой единицы.
+// TemperatureClass и HazardClass - требования к зоне хранения, пустой HazardClass - не опасный груз
+type Sku struct {
+	Id               uint64        `gorm:"primaryKey;autoIncrement:true;column:id"`
+	UuidUser         string        `gorm:"column:uuid_user"`
+	Code             string        `gorm:"column:code"`
+	Name             string        `gorm:"column:name"`
+	Description      string        `gorm:"column:description"`
+	Unit             string        `gorm:"column:unit;default:pcs"`
+	Barcode          *string       `gorm:"column:barcode"`
+	Attributes       SkuAttributes `gorm:"column:attributes"`
+	SerialTracked    bool          `gorm:"column:serial_tracked"`
+	Decimals         uint8         `gorm:"column:decimals"`
+	LengthCm         float64       `gorm:"column:length_cm"`
+	WidthCm          float64       `gorm:"column:width_cm"`
+	HeightCm         float64       `gorm:"column:height_cm"`
+	WeightKg         float64       `gorm:"column:weight_kg"`
+	UnitsPerPallet   uint64        `gorm:"column:units_per_pallet"`
+	TemperatureClass string        `gorm:"column:temperature_class;default:ambient"`
+	HazardClass      string        `gorm:"column:hazard_class"`
+	CreatedAt        time.Time     `gorm:"column:created_at;default:now()"`
+	Units            []SkuUnit     `gorm:"foreignKey:SkuId"`
+}
+
+// SkuUnit - дополнительная единица измерения позиции (коробка, паллета). Factor - число базовых единиц в ней
+type SkuUnit struct {
+	Id     uint64  `gorm:"primaryKey;autoIncrement:true;column:id"`
+	SkuId  uint64  `gorm:"column:sku_id"`
+	Code   string  `gorm:"column:code"`
+	Name   string  `gorm:"column:name"`
+	Factor float64 `gorm:"column:factor"`
+}
+
+// SkuAttributes - произвольные свойства позиции (цвет, размер и т.п.), хранятся в jsonb
+type SkuAttributes map[string]string
+
+func (a SkuAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func (a *SkuAttributes) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*a = SkuAttributes{}
+		return nil
+	default:
+		return errors.New("unsupported type for sku attributes")
+	}
+
+	return json.Unmarshal(data, a)
+}
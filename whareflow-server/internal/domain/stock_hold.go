@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+const (
+	StockStatusAvailable  = "available"
+	StockStatusQuarantine = "quarantine"
+	StockStatusDamaged    = "damaged"
+	StockStatusBlocked    = "blocked"
+	StockStatusReleased   = "released"
+)
+
+// StockHold блокирует часть остатка товара в статусе Status (quarantine, damaged, blocked), не списывая его.
+// Снятая блокировка остается в статусе released для истории
+type StockHold struct {
+	Id          uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ProductUuid string     `gorm:"column:product_uuid"`
+	Quantity    uint64     `gorm:"column:quantity"`
+	Status      string     `gorm:"column:status"`
+	Reason      string     `gorm:"column:reason"`
+	CreatedBy   string     `gorm:"column:created_by"`
+	CreatedAt   time.Time  `gorm:"column:created_at;default:now()"`
+	ReleasedBy  *string    `gorm:"column:released_by"`
+	ReleasedAt  *time.Time `gorm:"column:released_at"`
+}
+
+// StockStatusChange - запись истории: Quantity единиц товара перешли из FromStatus в ToStatus.
+// Остаток без блокировки имеет статус StockStatusAvailable
+type StockStatusChange struct {
+	Id          uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	HoldId      uint64    `gorm:"column:hold_id"`
+	ProductUuid string    `gorm:"column:product_uuid"`
+	FromStatus  string    `gorm:"column:from_status"`
+	ToStatus    string    `gorm:"column:to_status"`
+	Quantity    uint64    `gorm:"column:quantity"`
+	Reason      string    `gorm:"column:reason"`
+	ActorUuid   string    `gorm:"column:actor_uuid"`
+	CreatedAt   time.Time `gorm:"column:created_at;default:now()"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+const (
+	MovementReasonInitial     = "initial"
+	MovementReasonAdjustment  = "adjustment"
+	MovementReasonReceipt     = "receipt"
+	MovementReasonShipment    = "shipment"
+	MovementReasonMove        = "move"
+	MovementReasonTransferOut = "transfer_out"
+	MovementReasonTransferIn  = "transfer_in"
+	MovementReasonInventory   = "inventory"
+	MovementReasonReturn      = "return"
+	MovementReasonAssembly    = "assembly"
+	MovementReasonDisassembly = "disassembly"
+)
+
+type StockMovement struct {
+	Id           uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	ProductUuid  string    `gorm:"column:product_uuid"`
+	Quantity     int64     `gorm:"column:quantity"`
+	Reason       string    `gorm:"column:reason"`
+	ActorUuid    string    `gorm:"column:actor_uuid"`
+	SourceZoneId *uint64   `gorm:"column:source_zone_id"`
+	TargetZoneId *uint64   `gorm:"column:target_zone_id"`
+	CreatedAt    time.Time `gorm:"column:created_at;default:now()"`
+}
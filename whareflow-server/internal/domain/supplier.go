@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Supplier - поставщик из справочника владельца складов, на него оформляются заказы PurchaseOrder
+type Supplier struct {
+	Id          uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	UuidUser    string    `gorm:"column:uuid_user"`
+	Name        string    `gorm:"column:name"`
+	TaxId       string    `gorm:"column:tax_id"`
+	ContactName string    `gorm:"column:contact_name"`
+	Email       string    `gorm:"column:email"`
+	Phone       string    `gorm:"column:phone"`
+	Address     string    `gorm:"column:address"`
+	CreatedAt   time.Time `gorm:"column:created_at;default:now()"`
+}
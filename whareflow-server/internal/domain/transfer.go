@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+const (
+	TransferStatusDraft     = "draft"
+	TransferStatusInTransit = "in_transit"
+	TransferStatusReceived  = "received"
+)
+
+type Transfer struct {
+	Id                uint64         `gorm:"primaryKey;autoIncrement:true;column:id"`
+	SourceWarehouseId uint64         `gorm:"column:source_ware_house_id"`
+	TargetWarehouseId uint64         `gorm:"column:target_ware_house_id"`
+	Status            string         `gorm:"column:status;default:draft"`
+	Comment           string         `gorm:"column:comment"`
+	CreatedBy         string         `gorm:"column:created_by"`
+	ShippedBy         *string        `gorm:"column:shipped_by"`
+	ReceivedBy        *string        `gorm:"column:received_by"`
+	CreatedAt         time.Time      `gorm:"column:created_at;default:now()"`
+	ShippedAt         *time.Time     `gorm:"column:shipped_at"`
+	ReceivedAt        *time.Time     `gorm:"column:received_at"`
+	Lines             []TransferLine `gorm:"foreignKey:TransferId"`
+}
+
+// TransferLine хранит позицию каталога, название и партию товара, чтобы принять его на складе-получателе,
+// даже если исходная строка товара к тому времени удалена
+type TransferLine struct {
+	Id                uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	TransferId        uint64     `gorm:"column:transfer_id"`
+	ProductUuid       *string    `gorm:"column:product_uuid"`
+	SkuId             *uint64    `gorm:"column:sku_id"`
+	Title             string     `gorm:"column:title"`
+	Description       string     `gorm:"column:description"`
+	Quantity          uint64     `gorm:"column:quantity"`
+	ReceivedQuantity  uint64     `gorm:"column:received_quantity"`
+	TargetZoneId      *uint64    `gorm:"column:target_zone_id"`
+	TargetProductUuid *string    `gorm:"column:target_product_uuid"`
+	LotNumber         string     `gorm:"column:lot_number"`
+	ProductionDate    *time.Time `gorm:"column:production_date"`
+	ExpiryDate        *time.Time `gorm:"column:expiry_date"`
+}
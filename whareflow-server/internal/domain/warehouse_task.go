@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+const (
+	WarehouseTaskTypeReceive = "receive"
+	WarehouseTaskTypePutaway = "putaway"
+	WarehouseTaskTypePick    = "pick"
+	WarehouseTaskTypeCount   = "count"
+	WarehouseTaskTypeMove    = "move"
+)
+
+const (
+	WarehouseTaskStatusOpen      = "open"
+	WarehouseTaskStatusClaimed   = "claimed"
+	WarehouseTaskStatusDone      = "done"
+	WarehouseTaskStatusCancelled = "cancelled"
+)
+
+const (
+	WarehouseTaskActionClaim    = "claim"
+	WarehouseTaskActionComplete = "complete"
+	WarehouseTaskActionAbandon  = "abandon"
+)
+
+// WarehouseTask - задание работнику склада. Без AssigneeId задание лежит в общей очереди склада и его может взять
+// любой работник с правом task_work. ClaimedBy - кто взял задание в работу, при отказе задание возвращается в очередь.
+// ReceiptId задан у приемки и размещения, PickTaskId - у сборки
+type WarehouseTask struct {
+	Id          uint64     `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64     `gorm:"column:ware_house_id"`
+	Type        string     `gorm:"column:type"`
+	Status      string     `gorm:"column:status;default:open"`
+	Priority    int        `gorm:"column:priority"`
+	Title       string     `gorm:"column:title"`
+	Description string     `gorm:"column:description"`
+	Reference   string     `gorm:"column:reference"`
+	ProductUuid *string    `gorm:"column:product_uuid"`
+	ZoneId      *uint64    `gorm:"column:zone_id"`
+	LocationId  *uint64    `gorm:"column:location_id"`
+	ReceiptId   *uint64    `gorm:"column:receipt_id"`
+	PickTaskId  *uint64    `gorm:"column:pick_task_id"`
+	AssigneeId  *string    `gorm:"column:assignee_id"`
+	ClaimedBy   *string    `gorm:"column:claimed_by"`
+	CreatedBy   string     `gorm:"column:created_by"`
+	CreatedAt   time.Time  `gorm:"column:created_at;default:now()"`
+	DueAt       *time.Time `gorm:"column:due_at"`
+	ClaimedAt   *time.Time `gorm:"column:claimed_at"`
+	CompletedAt *time.Time `gorm:"column:completed_at"`
+	CancelledAt *time.Time `gorm:"column:cancelled_at"`
+}
+
+// WarehouseTaskEvent - запись журнала заданий. DurationSeconds заполняется для выполнения и отказа
+type WarehouseTaskEvent struct {
+	Id              uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	TaskId          uint64    `gorm:"column:task_id"`
+	WarehouseId     uint64    `gorm:"column:ware_house_id"`
+	TaskType        string    `gorm:"column:task_type"`
+	Action          string    `gorm:"column:action"`
+	ActorId         string    `gorm:"column:actor_id"`
+	Reason          string    `gorm:"column:reason"`
+	DurationSeconds int64     `gorm:"column:duration_seconds"`
+	CreatedAt       time.Time `gorm:"column:created_at;default:now()"`
+}
+
+// WorkerTaskStat - свод журнала заданий по работнику, типу задания и действию за период
+type WorkerTaskStat struct {
+	ActorId         string
+	Username        string
+	FirstName       string
+	LastName        string
+	TaskType        string
+	Action          string
+	Count           int64
+	DurationSeconds int64
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+const (
+	WaveStatusPicking = "picking"
+	WaveStatusSorting = "sorting"
+	WaveStatusDone    = "done"
+)
+
+// Wave - волна сборки. Задания заказов волны собираются одним обходом по строкам Lines,
+// после чего собранный товар раскладывается по ячейкам стены сортировки из Orders
+type Wave struct {
+	Id          uint64      `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WarehouseId uint64      `gorm:"column:ware_house_id"`
+	Status      string      `gorm:"column:status;default:picking"`
+	CreatedBy   string      `gorm:"column:created_by"`
+	CreatedAt   time.Time   `gorm:"column:created_at;default:now()"`
+	PickedAt    *time.Time  `gorm:"column:picked_at"`
+	DoneAt      *time.Time  `gorm:"column:done_at"`
+	Orders      []WaveOrder `gorm:"foreignKey:WaveId"`
+	Lines       []WaveLine  `gorm:"foreignKey:WaveId"`
+}
+
+// WaveOrder закрепляет заказ волны за ячейкой Slot стены сортировки
+type WaveOrder struct {
+	Id      uint64 `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WaveId  uint64 `gorm:"column:wave_id"`
+	OrderId uint64 `gorm:"column:order_id"`
+	Slot    int    `gorm:"column:slot"`
+}
+
+// WaveLine - суммарная потребность заказов волны в товаре ProductUuid. Количества хранятся в долях базовой единицы,
+// Unit и UnitFactor - самая мелкая единица из заданий, в которой строка показывается сборщику
+type WaveLine struct {
+	Id             uint64  `gorm:"primaryKey;autoIncrement:true;column:id"`
+	WaveId         uint64  `gorm:"column:wave_id"`
+	ProductUuid    string  `gorm:"column:product_uuid"`
+	ZoneId         uint64  `gorm:"column:zone_id"`
+	LocationId     *uint64 `gorm:"column:location_id"`
+	Quantity       uint64  `gorm:"column:quantity"`
+	PickedQuantity uint64  `gorm:"column:picked_quantity"`
+	SortedQuantity uint64  `gorm:"column:sorted_quantity"`
+	Unit           string  `gorm:"column:unit"`
+	UnitFactor     float64 `gorm:"column:unit_factor"`
+}
+
+// WaveSortPut - часть отсканированного товара, которую нужно положить в ячейку Slot для задания TaskId заказа OrderId.
+// Slot = 0 - товар отмененных заказов, его возвращают на место хранения строки волны
+type WaveSortPut struct {
+	Slot       int
+	OrderId    uint64
+	TaskId     uint64
+	Quantity   uint64
+	Unit       string
+	UnitFactor float64
+}
@@ -1,8 +1,44 @@
 package domain
 
+const (
+	ZoneTypeGeneral    = "general"
+	ZoneTypeHazardous  = "hazardous"
+	ZoneTypeQuarantine = "quarantine"
+)
+
+const (
+	TemperatureClassAmbient = "ambient"
+	TemperatureClassChilled = "chilled"
+	TemperatureClassFrozen  = "frozen"
+)
+
+// Zone: MaxVolume (м³), MaxWeight (кг) и MaxPallets ограничивают загрузку зоны, nil - предел не задан.
+// ZoneType и TemperatureClass определяют, какие позиции можно размещать в зоне
 type Zone struct {
-	Id          int `gorm:"primaryKey;autoIncrement:true;column:id"`
-	Name        string
-	Capacity    int
-	WarehouseId int `gorm:"column:ware_house_id"`
+	Id               int `gorm:"primaryKey;autoIncrement:true;column:id"`
+	Name             string
+	Capacity         int
+	WarehouseId      int      `gorm:"column:ware_house_id"`
+	MaxVolume        *float64 `gorm:"column:max_volume"`
+	MaxWeight        *float64 `gorm:"column:max_weight"`
+	MaxPallets       *uint64  `gorm:"column:max_pallets"`
+	ZoneType         string   `gorm:"column:zone_type;default:general"`
+	TemperatureClass string   `gorm:"column:temperature_class;default:ambient"`
+}
+
+// CanStore проверяет правила хранения: температурный режим зоны совпадает с позицией,
+// а опасный груз попадает только в зону для опасных грузов или в карантин
+func (z *Zone) CanStore(sku *Sku) bool {
+	if z.TemperatureClass != sku.TemperatureClass {
+		return false
+	}
+
+	return sku.HazardClass == "" || z.ZoneType == ZoneTypeHazardous || z.ZoneType == ZoneTypeQuarantine
+}
+
+// ZoneLoad - текущая загрузка зоны по объему (м³), весу (кг) и паллетоместам
+type ZoneLoad struct {
+	Volume  float64
+	Weight  float64
+	Pallets uint64
 }
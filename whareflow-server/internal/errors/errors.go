@@ -40,11 +40,212 @@ var (
 // Zone errors
 
 var (
-	ErrZoneNotFound = &CustomError{Arg: 409, Message: "Zone not found with name"}
+	ErrZoneNotFound         = &CustomError{Arg: 409, Message: "Zone not found with name"}
+	ErrZoneCapacityExceeded = &CustomError{Arg: 409, Message: "Zone capacity exceeded"}
+	ErrZoneVolumeExceeded   = &CustomError{Arg: 409, Message: "Zone volume limit exceeded"}
+	ErrZoneWeightExceeded   = &CustomError{Arg: 409, Message: "Zone weight limit exceeded"}
+	ErrZonePalletsExceeded  = &CustomError{Arg: 409, Message: "Zone pallet positions limit exceeded"}
+	ErrInvalidZoneLimits    = &CustomError{Arg: 400, Message: "Zone limits must not be negative"}
+	ErrInvalidZoneType      = &CustomError{Arg: 400, Message: "Unknown zone type or temperature class"}
+	ErrIncompatibleStorage  = &CustomError{Arg: 409, Message: "Sku storage requirements are incompatible with zone"}
 )
 
 // Product errors
 
 var (
-	ErrProductNotFound = &CustomError{Arg: 409, Message: "Product not found with name"}
+	ErrProductNotFound    = &CustomError{Arg: 409, Message: "Product not found with name"}
+	ErrInvalidProductMove = &CustomError{Arg: 409, Message: "Product is already in target zone"}
+	ErrInvalidLotDates    = &CustomError{Arg: 409, Message: "Production date is after expiry date"}
+)
+
+// Stock errors
+
+var (
+	ErrInsufficientStock = &CustomError{Arg: 409, Message: "Not enough stock for product"}
+)
+
+// Document errors
+
+var (
+	ErrInvalidDocumentStatus = &CustomError{Arg: 409, Message: "Document status does not allow this operation"}
+	ErrInvalidDocumentLine   = &CustomError{Arg: 409, Message: "Document line is not valid"}
+)
+
+// Receipt errors
+
+var (
+	ErrReceiptNotFound = &CustomError{Arg: 409, Message: "Receipt not found"}
+)
+
+// Shipment errors
+
+var (
+	ErrShipmentNotFound = &CustomError{Arg: 409, Message: "Shipment not found"}
+)
+
+// Transfer errors
+
+var (
+	ErrTransferNotFound       = &CustomError{Arg: 409, Message: "Transfer not found"}
+	ErrInvalidTransferTarget  = &CustomError{Arg: 409, Message: "Transfer target warehouse is not valid"}
+	ErrTransferSideNotAllowed = &CustomError{Arg: 409, Message: "Operation is not allowed from this side of the transfer"}
+)
+
+// Reservation errors
+
+var (
+	ErrReservationNotFound        = &CustomError{Arg: 409, Message: "Reservation not found"}
+	ErrInvalidReservation         = &CustomError{Arg: 409, Message: "Reservation is not valid"}
+	ErrInsufficientAvailableStock = &CustomError{Arg: 409, Message: "Not enough available stock for reservation"}
+	ErrStockReserved              = &CustomError{Arg: 409, Message: "Stock is reserved and cannot be taken"}
+)
+
+// Inventory count errors
+
+var (
+	ErrInventoryCountNotFound = &CustomError{Arg: 409, Message: "Inventory count not found"}
+	ErrInvalidProductQR       = &CustomError{Arg: 409, Message: "Product QR code is not valid"}
+)
+
+// Serial number errors
+
+var (
+	ErrSerialNotFound       = &CustomError{Arg: 409, Message: "Serial number not found"}
+	ErrSerialAlreadyExists  = &CustomError{Arg: 409, Message: "Serial number is already registered"}
+	ErrSerialNotInStock     = &CustomError{Arg: 409, Message: "Serial number is not in stock for this product"}
+	ErrSerialCountMismatch  = &CustomError{Arg: 409, Message: "Serial numbers do not match the quantity"}
+	ErrSerialTrackedProduct = &CustomError{Arg: 409, Message: "Stock of a serial-tracked product changes only with serial numbers"}
+)
+
+// Sku errors
+
+var (
+	ErrSkuNotFound      = &CustomError{Arg: 409, Message: "Sku not found"}
+	ErrSkuAlreadyExists = &CustomError{Arg: 409, Message: "Sku with this code or barcode already exists"}
+	ErrInvalidSku       = &CustomError{Arg: 409, Message: "Sku is not valid"}
+	ErrSkuInStock       = &CustomError{Arg: 409, Message: "Sku serial tracking or decimals cannot be changed while it is in stock"}
+	ErrSkuInDocuments   = &CustomError{Arg: 409, Message: "Sku decimals cannot be changed while open documents or rules refer to it"}
+)
+
+// Unit errors
+
+var (
+	ErrUnitNotFound       = &CustomError{Arg: 409, Message: "Unit of measure not found for sku"}
+	ErrFractionalQuantity = &CustomError{Arg: 409, Message: "Quantity is finer than sku base unit precision"}
+)
+
+// Reorder rule errors
+
+var (
+	ErrReorderRuleNotFound = &CustomError{Arg: 409, Message: "Reorder rule not found"}
+	ErrInvalidReorderRule  = &CustomError{Arg: 409, Message: "Reorder rule is not valid"}
+)
+
+// Location errors
+
+var (
+	ErrLocationNotFound         = &CustomError{Arg: 409, Message: "Location not found in zone"}
+	ErrLocationAlreadyExists    = &CustomError{Arg: 409, Message: "Location with this code already exists"}
+	ErrInvalidLocation          = &CustomError{Arg: 409, Message: "Location is not valid"}
+	ErrLocationNotEmpty         = &CustomError{Arg: 409, Message: "Location still holds stock"}
+	ErrLocationCapacityExceeded = &CustomError{Arg: 409, Message: "Location capacity exceeded"}
+)
+
+// Stock hold errors
+
+var (
+	ErrStockHoldNotFound = &CustomError{Arg: 409, Message: "Stock hold not found"}
+	ErrInvalidStockHold  = &CustomError{Arg: 409, Message: "Stock hold is not valid"}
+	ErrStockOnHold       = &CustomError{Arg: 409, Message: "Stock is on hold and cannot be taken"}
+)
+
+// Return errors
+
+var (
+	ErrReturnNotFound         = &CustomError{Arg: 409, Message: "Return not found"}
+	ErrInvalidReturn          = &CustomError{Arg: 409, Message: "Return is not valid"}
+	ErrReturnQuantityExceeded = &CustomError{Arg: 409, Message: "Returned quantity exceeds shipped quantity"}
+)
+
+// Kit errors
+
+var (
+	ErrInvalidKit             = &CustomError{Arg: 409, Message: "Kit components are not valid"}
+	ErrKitHasNoComponents     = &CustomError{Arg: 409, Message: "Kit has no components"}
+	ErrKitOperationNotFound   = &CustomError{Arg: 409, Message: "Kit operation not found"}
+	ErrInsufficientComponents = &CustomError{Arg: 409, Message: "Not enough available component stock to assemble kit"}
+)
+
+// Supplier errors
+
+var (
+	ErrSupplierNotFound      = &CustomError{Arg: 409, Message: "Supplier not found"}
+	ErrSupplierAlreadyExists = &CustomError{Arg: 409, Message: "Supplier with this name already exists"}
+	ErrInvalidSupplier       = &CustomError{Arg: 409, Message: "Supplier is not valid"}
+)
+
+// Purchase order errors
+
+var (
+	ErrPurchaseOrderNotFound = &CustomError{Arg: 409, Message: "Purchase order not found"}
+	ErrInvalidPurchaseOrder  = &CustomError{Arg: 409, Message: "Purchase order is not valid"}
+)
+
+// Customer errors
+
+var (
+	ErrCustomerNotFound      = &CustomError{Arg: 409, Message: "Customer not found"}
+	ErrCustomerAlreadyExists = &CustomError{Arg: 409, Message: "Customer with this name already exists"}
+	ErrInvalidCustomer       = &CustomError{Arg: 409, Message: "Customer is not valid"}
+)
+
+// Sales order errors
+
+var (
+	ErrSalesOrderNotFound = &CustomError{Arg: 409, Message: "Sales order not found"}
+	ErrInvalidSalesOrder  = &CustomError{Arg: 409, Message: "Sales order is not valid"}
+	ErrPickTaskNotFound   = &CustomError{Arg: 409, Message: "Pick task not found"}
+)
+
+// Pick list errors
+
+var (
+	ErrPickListNotFound = &CustomError{Arg: 409, Message: "Pick list not found"}
+	ErrNothingToPick    = &CustomError{Arg: 409, Message: "No open pick tasks can be picked now"}
+	ErrInvalidWalkPath  = &CustomError{Arg: 409, Message: "Walk path is not valid"}
+)
+
+// Wave errors
+
+var (
+	ErrWaveNotFound  = &CustomError{Arg: 409, Message: "Wave not found"}
+	ErrInvalidWave   = &CustomError{Arg: 409, Message: "Wave is not valid"}
+	ErrNothingToSort = &CustomError{Arg: 409, Message: "Nothing left to sort for this product"}
+)
+
+// Parcel errors
+
+var (
+	ErrParcelNotFound    = &CustomError{Arg: 409, Message: "Parcel not found"}
+	ErrInvalidParcel     = &CustomError{Arg: 409, Message: "Parcel is not valid"}
+	ErrParcelsIncomplete = &CustomError{Arg: 409, Message: "Parcels do not cover packed quantity"}
+)
+
+// Warehouse task errors
+
+var (
+	ErrWarehouseTaskNotFound   = &CustomError{Arg: 409, Message: "Task not found"}
+	ErrInvalidWarehouseTask    = &CustomError{Arg: 409, Message: "Task is not valid"}
+	ErrWarehouseTaskAssigned   = &CustomError{Arg: 409, Message: "Task is assigned to another employee"}
+	ErrWarehouseTaskNotClaimed = &CustomError{Arg: 409, Message: "Task is not claimed by this employee"}
+	ErrTaskWorkerNotFound      = &CustomError{Arg: 409, Message: "Employee has no task permission on this warehouse"}
+	ErrInvalidStatsPeriod      = &CustomError{Arg: 409, Message: "Statistics period is not valid"}
+	ErrTaskDocumentPending     = &CustomError{Arg: 409, Message: "Task document is not processed yet"}
+)
+
+// Putaway errors
+
+var (
+	ErrPutawayDone           = &CustomError{Arg: 409, Message: "Receipt line is already put away"}
+	ErrPutawayReasonRequired = &CustomError{Arg: 409, Message: "Reason is required to put away outside the suggested place"}
 )
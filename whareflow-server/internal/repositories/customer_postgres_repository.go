@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"log/slog"
+)
+
+type CustomerRepository interface {
+	InsertCustomerData(in *domain.Customer) error
+	UpdateCustomerData(in *domain.Customer, userId string) error
+	FindAllCustomerData(userId string) (*[]domain.Customer, error)
+	FindCustomerData(userId string, customerId uint64) (*domain.Customer, error)
+}
+
+type CustomerPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewCustomerPostgresRepository(db database.Database, logger slog.Logger) *CustomerPostgresRepository {
+	return &CustomerPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (cr *CustomerPostgresRepository) InsertCustomerData(in *domain.Customer) error {
+	if err := checkCustomerUnique(cr.db.GetDb(), in); err != nil {
+		return err
+	}
+
+	return cr.db.GetDb().Create(in).Error
+}
+
+func (cr *CustomerPostgresRepository) UpdateCustomerData(in *domain.Customer, userId string) error {
+	customer, err := findCustomer(cr.db.GetDb(), userId, in.Id)
+	if err != nil {
+		return err
+	}
+
+	in.UuidUser = customer.UuidUser
+	if err := checkCustomerUnique(cr.db.GetDb(), in); err != nil {
+		return err
+	}
+
+	return cr.db.GetDb().Model(&domain.Customer{}).Where("id = ?", customer.Id).
+		Select("name", "contact_name", "email", "phone", "address").
+		Updates(in).Error
+}
+
+func (cr *CustomerPostgresRepository) FindAllCustomerData(userId string) (*[]domain.Customer, error) {
+	var customers []domain.Customer
+
+	if err := cr.db.GetDb().Where("uuid_user = ?", userId).Order("name").Find(&customers).Error; err != nil {
+		return nil, err
+	}
+
+	return &customers, nil
+}
+
+func (cr *CustomerPostgresRepository) FindCustomerData(userId string, customerId uint64) (*domain.Customer, error) {
+	return findCustomer(cr.db.GetDb(), userId, customerId)
+}
+
+func findCustomer(db *gorm.DB, userId string, customerId uint64) (*domain.Customer, error) {
+	var customer domain.Customer
+
+	if err := db.Where("id = ? AND uuid_user = ?", customerId, userId).First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// checkCustomerUnique не дает завести у владельца двух покупателей с одним названием
+func checkCustomerUnique(db *gorm.DB, in *domain.Customer) error {
+	var count int64
+	err := db.Model(&domain.Customer{}).
+		Where("uuid_user = ? AND name = ? AND id <> ?", in.UuidUser, in.Name, in.Id).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return custom_errors.ErrCustomerAlreadyExists
+	}
+
+	return nil
+}
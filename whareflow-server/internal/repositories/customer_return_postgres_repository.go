@@ -0,0 +1,427 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type CustomerReturnRepository interface {
+	InsertCustomerReturnData(in *domain.CustomerReturn, userId string) error
+	FindAllCustomerReturnData(userId string, warehouseId int) (*[]domain.CustomerReturn, error)
+	FindCustomerReturnData(userId string, warehouseId int, returnId uint64) (*domain.CustomerReturn, error)
+	PostCustomerReturnData(userId string, warehouseId int, returnId uint64, serials map[uint64][]string, actorId string) (*[]domain.Product, error)
+}
+
+type CustomerReturnPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewCustomerReturnPostgresRepository(db database.Database, logger slog.Logger) *CustomerReturnPostgresRepository {
+	return &CustomerReturnPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (cr *CustomerReturnPostgresRepository) InsertCustomerReturnData(in *domain.CustomerReturn, userId string) error {
+	tx := cr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := cr.checkReturnLines(tx, int(in.WarehouseId), in.ShipmentId, in.Lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (cr *CustomerReturnPostgresRepository) FindAllCustomerReturnData(userId string, warehouseId int) (*[]domain.CustomerReturn, error) {
+	var returns []domain.CustomerReturn
+
+	if err := checkWarehouseOwner(cr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := cr.db.GetDb().Preload("Lines", orderCustomerReturnLines).
+		Where("ware_house_id = ?", warehouseId).
+		Order("created_at DESC").
+		Find(&returns).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &returns, nil
+}
+
+func (cr *CustomerReturnPostgresRepository) FindCustomerReturnData(userId string, warehouseId int, returnId uint64) (*domain.CustomerReturn, error) {
+	var customerReturn domain.CustomerReturn
+
+	if err := checkWarehouseOwner(cr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := cr.db.GetDb().Preload("Lines", orderCustomerReturnLines).
+		Where("id = ? AND ware_house_id = ?", returnId, warehouseId).
+		First(&customerReturn).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrReturnNotFound
+		}
+		return nil, err
+	}
+
+	return &customerReturn, nil
+}
+
+// PostCustomerReturnData проводит возврат: товар с решением resell возвращается на остаток исходной позиции,
+// repair и scrap попадают в карантинную зону под блокировку quarantine и damaged соответственно.
+// Для серийного товара serials - возвращаемые номера по строке, они снова ставятся на остаток.
+// Возвращает строки товара, созданные проведением
+func (cr *CustomerReturnPostgresRepository) PostCustomerReturnData(userId string, warehouseId int, returnId uint64, serials map[uint64][]string, actorId string) (*[]domain.Product, error) {
+	tx := cr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	customerReturn, err := cr.lockCustomerReturn(tx, userId, warehouseId, returnId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if customerReturn.Status != domain.ReturnStatusDraft {
+		tx.Rollback()
+		return nil, custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.CustomerReturnLine
+	if err := tx.Where("return_id = ?", customerReturn.Id).Order("id").Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	for lineId := range serials {
+		found := false
+		for _, line := range lines {
+			if line.Id == lineId {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tx.Rollback()
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+	}
+
+	// С момента создания черновика по отгрузке могли провести другие возвраты, поэтому количества проверяются повторно
+	if err := cr.checkReturnLines(tx, warehouseId, customerReturn.ShipmentId, lines); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	createdProducts := []domain.Product{}
+	for _, line := range lines {
+		var original domain.Product
+		if err := tx.Where("uuid = ?", line.ProductUuid).First(&original).Error; err != nil {
+			tx.Rollback()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, custom_errors.ErrProductNotFound
+			}
+			return nil, err
+		}
+
+		zoneId := original.ZoneId
+		if line.ZoneId != nil {
+			zoneId = *line.ZoneId
+		}
+
+		target, created, err := returnTargetProduct(tx, &original, line.SkuId, zoneId, line.Condition)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if created {
+			target.Count = line.Quantity
+			createdProducts = append(createdProducts, *target)
+		}
+
+		targetUuid := string(target.Uuid)
+		movement := &domain.StockMovement{
+			ProductUuid:  targetUuid,
+			Quantity:     int64(line.Quantity),
+			Reason:       domain.MovementReasonReturn,
+			ActorUuid:    actorId,
+			TargetZoneId: &zoneId,
+		}
+		if err := applyStockMovement(tx, movement); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := restockReturnedSerials(tx, &line, serials[line.Id], targetUuid, movement.Id); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		updates := map[string]interface{}{
+			"result_product_uuid": targetUuid,
+		}
+
+		if line.Condition != domain.ReturnConditionResell {
+			status := domain.StockStatusQuarantine
+			if line.Condition == domain.ReturnConditionScrap {
+				status = domain.StockStatusDamaged
+			}
+
+			hold := &domain.StockHold{
+				ProductUuid: targetUuid,
+				Quantity:    line.Quantity,
+				Status:      status,
+				Reason:      fmt.Sprintf("return %d: %s", customerReturn.Id, line.Condition),
+				CreatedBy:   actorId,
+			}
+			if err := insertStockHold(tx, hold); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			updates["hold_id"] = hold.Id
+		}
+
+		if err := tx.Model(&domain.CustomerReturnLine{}).Where("id = ?", line.Id).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	err = tx.Model(customerReturn).Updates(map[string]interface{}{
+		"status":    domain.ReturnStatusPosted,
+		"posted_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &createdProducts, nil
+}
+
+// returnTargetProduct выбирает строку остатка позиции skuId из строки отгрузки, на которую вернется товар.
+// Годный товар в зону исходной строки возвращается на нее же, иначе используется строка той же партии
+// в зоне zoneId или создается новая
+func returnTargetProduct(tx *gorm.DB, original *domain.Product, skuId uint64, zoneId uint64, condition string) (*domain.Product, bool, error) {
+	if condition == domain.ReturnConditionResell && zoneId == original.ZoneId && original.SkuId == skuId {
+		return original, false, nil
+	}
+
+	var existing []domain.Product
+	err := tx.Where("sku_id = ? AND zone_id = ? AND lot_number = ? AND location_id IS NULL", skuId, zoneId, original.LotNumber).
+		Where("expiry_date IS NOT DISTINCT FROM ?", original.ExpiryDate).
+		Where("uuid <> ?", original.Uuid).
+		Limit(1).
+		Find(&existing).Error
+	if err != nil {
+		return nil, false, err
+	}
+	if len(existing) > 0 {
+		return &existing[0], false, nil
+	}
+
+	product := &domain.Product{
+		SkuId:          skuId,
+		ZoneId:         zoneId,
+		LotNumber:      original.LotNumber,
+		ProductionDate: original.ProductionDate,
+		ExpiryDate:     original.ExpiryDate,
+	}
+	if err := tx.Create(product).Error; err != nil {
+		return nil, false, err
+	}
+
+	return product, true, nil
+}
+
+// restockReturnedSerials ставит на остаток номера, выданные по строке отгрузки. Для несерийного товара номеров быть не должно
+func restockReturnedSerials(tx *gorm.DB, line *domain.CustomerReturnLine, serials []string, productUuid string, movementId uint64) error {
+	serialTracked, err := skuSerialTracked(tx, line.SkuId)
+	if err != nil {
+		return err
+	}
+
+	if !serialTracked {
+		if len(serials) > 0 {
+			return custom_errors.ErrSerialCountMismatch
+		}
+		return nil
+	}
+
+	if err := checkSerialList(serials, line.Quantity); err != nil {
+		return err
+	}
+
+	var locked []domain.SerialNumber
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("shipment_line_id = ? AND status = ? AND serial IN ?", line.ShipmentLineId, domain.SerialStatusIssued, serials).
+		Find(&locked).Error
+	if err != nil {
+		return err
+	}
+	if len(locked) != len(serials) {
+		return custom_errors.ErrSerialNotInStock
+	}
+
+	err = tx.Model(&domain.SerialNumber{}).
+		Where("id IN ?", serialIds(locked)).
+		Updates(map[string]interface{}{
+			"status":           domain.SerialStatusInStock,
+			"product_uuid":     productUuid,
+			"shipment_line_id": nil,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	return linkSerialMovement(tx, locked, movementId)
+}
+
+// checkReturnLines сверяет строки возврата с проведенной отгрузкой и дополняет их товаром, позицией и единицей строки
+// отгрузки. Вместе с уже проведенными возвратами по строке нельзя вернуть больше, чем было отгружено.
+// Для repair и scrap нужна зона склада типа quarantine
+func (cr *CustomerReturnPostgresRepository) checkReturnLines(tx *gorm.DB, warehouseId int, shipmentId uint64, lines []domain.CustomerReturnLine) error {
+	if len(lines) == 0 {
+		return custom_errors.ErrInvalidDocumentLine
+	}
+
+	var shipment domain.Shipment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").
+		Where("id = ? AND ware_house_id = ?", shipmentId, warehouseId).
+		First(&shipment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrShipmentNotFound
+		}
+		return err
+	}
+
+	if shipment.Status != domain.ShipmentStatusShipped {
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	shipmentLines := make(map[uint64]domain.ShipmentLine, len(shipment.Lines))
+	for _, shipmentLine := range shipment.Lines {
+		shipmentLines[shipmentLine.Id] = shipmentLine
+	}
+
+	requested := make(map[uint64]uint64, len(lines))
+	for i := range lines {
+		shipmentLine, ok := shipmentLines[lines[i].ShipmentLineId]
+		if !ok || lines[i].Quantity == 0 {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+
+		lines[i].ProductUuid = shipmentLine.ProductUuid
+		lines[i].SkuId = shipmentLine.SkuId
+		lines[i].Unit = shipmentLine.Unit
+		lines[i].UnitFactor = shipmentLine.UnitFactor
+		requested[shipmentLine.Id] += lines[i].Quantity
+
+		if err := checkReturnZone(tx, warehouseId, &lines[i]); err != nil {
+			return err
+		}
+	}
+
+	for shipmentLineId, quantity := range requested {
+		var returned uint64
+		err := tx.Model(&domain.CustomerReturnLine{}).
+			Joins("JOIN customer_returns ON customer_return_lines.return_id = customer_returns.id").
+			Where("customer_return_lines.shipment_line_id = ? AND customer_returns.status = ?", shipmentLineId, domain.ReturnStatusPosted).
+			Select("COALESCE(SUM(customer_return_lines.quantity), 0)").
+			Scan(&returned).Error
+		if err != nil {
+			return err
+		}
+
+		if returned+quantity > shipmentLines[shipmentLineId].PickedQuantity {
+			return custom_errors.ErrReturnQuantityExceeded
+		}
+	}
+
+	return nil
+}
+
+func checkReturnZone(tx *gorm.DB, warehouseId int, line *domain.CustomerReturnLine) error {
+	if line.ZoneId == nil {
+		if line.Condition != domain.ReturnConditionResell {
+			return custom_errors.ErrInvalidReturn
+		}
+		return nil
+	}
+
+	var zone domain.Zone
+	if err := tx.Where("id = ? AND ware_house_id = ?", *line.ZoneId, warehouseId).First(&zone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrZoneNotFound
+		}
+		return err
+	}
+
+	if line.Condition != domain.ReturnConditionResell && zone.ZoneType != domain.ZoneTypeQuarantine {
+		return custom_errors.ErrInvalidReturn
+	}
+
+	return nil
+}
+
+func orderCustomerReturnLines(db *gorm.DB) *gorm.DB {
+	return db.Order("customer_return_lines.id")
+}
+
+// lockCustomerReturn блокирует документ до конца транзакции, чтобы его нельзя было провести дважды
+func (cr *CustomerReturnPostgresRepository) lockCustomerReturn(tx *gorm.DB, userId string, warehouseId int, returnId uint64) (*domain.CustomerReturn, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var customerReturn domain.CustomerReturn
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", returnId, warehouseId).
+		First(&customerReturn).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrReturnNotFound
+		}
+		return nil, err
+	}
+
+	return &customerReturn, nil
+}
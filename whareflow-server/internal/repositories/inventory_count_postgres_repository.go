@@ -0,0 +1,319 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type InventoryCountRepository interface {
+	InsertInventoryCountData(in *domain.InventoryCount, userId string) error
+	FindAllInventoryCountData(userId string, warehouseId int) (*[]domain.InventoryCount, error)
+	FindInventoryCountData(userId string, warehouseId int, countId uint64) (*domain.InventoryCount, error)
+	SubmitInventoryCountData(userId string, warehouseId int, countId uint64, productId string, counted uint64, actorId string) error
+	ApproveInventoryCountData(userId string, warehouseId int, countId uint64, actorId string) error
+	CancelInventoryCountData(userId string, warehouseId int, countId uint64) error
+}
+
+type InventoryCountPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewInventoryCountPostgresRepository(db database.Database, logger slog.Logger) *InventoryCountPostgresRepository {
+	return &InventoryCountPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// InsertInventoryCountData открывает сессию и фиксирует учетный остаток всех товаров в ее границах
+func (ir *InventoryCountPostgresRepository) InsertInventoryCountData(in *domain.InventoryCount, userId string) error {
+	tx := ir.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if in.ZoneId != nil {
+		if err := checkZonesInWarehouse(tx, int(in.WarehouseId), []uint64{*in.ZoneId}); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	var products []domain.Product
+	if err := inventoryCountScope(tx, in).Find(&products).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	in.Lines = nil
+	for _, product := range products {
+		in.Lines = append(in.Lines, domain.InventoryCountLine{
+			ProductUuid:      string(product.Uuid),
+			ExpectedQuantity: product.Count,
+		})
+	}
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (ir *InventoryCountPostgresRepository) FindAllInventoryCountData(userId string, warehouseId int) (*[]domain.InventoryCount, error) {
+	var counts []domain.InventoryCount
+
+	if err := checkWarehouseOwner(ir.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := ir.db.GetDb().Preload("Lines", orderInventoryCountLines).
+		Where("ware_house_id = ?", warehouseId).
+		Order("created_at DESC").
+		Find(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &counts, nil
+}
+
+func (ir *InventoryCountPostgresRepository) FindInventoryCountData(userId string, warehouseId int, countId uint64) (*domain.InventoryCount, error) {
+	var count domain.InventoryCount
+
+	if err := checkWarehouseOwner(ir.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := ir.db.GetDb().Preload("Lines", orderInventoryCountLines).
+		Where("id = ? AND ware_house_id = ?", countId, warehouseId).
+		First(&count).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrInventoryCountNotFound
+		}
+		return nil, err
+	}
+
+	return &count, nil
+}
+
+// SubmitInventoryCountData записывает фактическое количество товара вместе с учетным остатком на момент сканирования.
+// Повторная отправка перезаписывает оба значения. Товар, которого не было при открытии сессии, добавляется новой строкой
+func (ir *InventoryCountPostgresRepository) SubmitInventoryCountData(userId string, warehouseId int, countId uint64, productId string, counted uint64, actorId string) error {
+	tx := ir.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	count, err := ir.lockInventoryCount(tx, userId, warehouseId, countId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count.Status != domain.InventoryCountStatusOpen {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var product domain.Product
+	if err := inventoryCountScope(tx, count).Where("products.uuid = ?", productId).First(&product).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrProductNotFound
+		}
+		return err
+	}
+
+	now := time.Now()
+	line := domain.InventoryCountLine{
+		InventoryCountId: count.Id,
+		ProductUuid:      productId,
+		ExpectedQuantity: product.Count,
+		CountedQuantity:  &counted,
+		CountedBy:        &actorId,
+		CountedAt:        &now,
+	}
+
+	err = tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "inventory_count_id"}, {Name: "product_uuid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expected_quantity", "counted_quantity", "counted_by", "counted_at"}),
+	}).Create(&line).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ApproveInventoryCountData закрывает сессию и проводит расхождения посчитанных товаров корректирующими движениями.
+// Непосчитанные строки остатки не меняют
+func (ir *InventoryCountPostgresRepository) ApproveInventoryCountData(userId string, warehouseId int, countId uint64, actorId string) error {
+	tx := ir.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	count, err := ir.lockInventoryCount(tx, userId, warehouseId, countId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count.Status != domain.InventoryCountStatusOpen {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.InventoryCountLine
+	if err := tx.Where("inventory_count_id = ? AND counted_quantity IS NOT NULL", count.Id).Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Тот же порядок блокировок, что и при отгрузке
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].ProductUuid < lines[j].ProductUuid
+	})
+
+	for _, line := range lines {
+		var product domain.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", line.ProductUuid).First(&product).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		delta := inventoryCountDelta(&line)
+		if delta != 0 {
+			serialTracked, err := productSerialTracked(tx, line.ProductUuid)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			if serialTracked {
+				tx.Rollback()
+				return custom_errors.ErrSerialTrackedProduct
+			}
+
+			movement := &domain.StockMovement{
+				ProductUuid: line.ProductUuid,
+				Quantity:    delta,
+				Reason:      domain.MovementReasonInventory,
+				ActorUuid:   actorId,
+			}
+			if err := applyStockMovement(tx, movement); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	now := time.Now()
+	err = tx.Model(count).Updates(map[string]interface{}{
+		"status":      domain.InventoryCountStatusApproved,
+		"approved_by": actorId,
+		"closed_at":   now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (ir *InventoryCountPostgresRepository) CancelInventoryCountData(userId string, warehouseId int, countId uint64) error {
+	tx := ir.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	count, err := ir.lockInventoryCount(tx, userId, warehouseId, countId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if count.Status != domain.InventoryCountStatusOpen {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	now := time.Now()
+	err = tx.Model(count).Updates(map[string]interface{}{
+		"status":    domain.InventoryCountStatusCancelled,
+		"closed_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// inventoryCountDelta - расхождение факта с учетным остатком на момент сканирования. Оно прибавляется к текущему остатку,
+// поэтому отгрузки, перемещения и приемки, прошедшие между сканированием и утверждением, сохраняются
+func inventoryCountDelta(line *domain.InventoryCountLine) int64 {
+	return int64(*line.CountedQuantity) - int64(line.ExpectedQuantity)
+}
+
+func orderInventoryCountLines(db *gorm.DB) *gorm.DB {
+	return db.Order("inventory_count_lines.id")
+}
+
+// inventoryCountScope выбирает товары, которые входят в границы сессии пересчета
+func inventoryCountScope(db *gorm.DB, count *domain.InventoryCount) *gorm.DB {
+	query := db.Model(&domain.Product{}).
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Where("zones.ware_house_id = ?", count.WarehouseId)
+	if count.ZoneId != nil {
+		query = query.Where("products.zone_id = ?", *count.ZoneId)
+	}
+
+	return query
+}
+
+// lockInventoryCount блокирует сессию до конца транзакции, чтобы ее нельзя было утвердить дважды
+func (ir *InventoryCountPostgresRepository) lockInventoryCount(tx *gorm.DB, userId string, warehouseId int, countId uint64) (*domain.InventoryCount, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var count domain.InventoryCount
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", countId, warehouseId).
+		First(&count).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrInventoryCountNotFound
+		}
+		return nil, err
+	}
+
+	return &count, nil
+}
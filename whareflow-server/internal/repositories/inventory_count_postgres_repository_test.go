@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"testing"
+)
+
+func TestInventoryCountDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  uint64
+		counted   uint64
+		current   uint64
+		wantDelta int64
+		wantCount uint64
+		wantErr   error
+	}{
+		{name: "nothing moved, shortage", expected: 10, counted: 8, current: 10, wantDelta: -2, wantCount: 8},
+		{name: "nothing moved, surplus", expected: 10, counted: 12, current: 10, wantDelta: 2, wantCount: 12},
+		{name: "count matches", expected: 10, counted: 10, current: 10, wantDelta: 0, wantCount: 10},
+		{name: "shipped after scan stays shipped", expected: 10, counted: 10, current: 7, wantDelta: 0, wantCount: 7},
+		{name: "shortage on top of shipment", expected: 10, counted: 9, current: 7, wantDelta: -1, wantCount: 6},
+		{name: "received after scan is kept", expected: 10, counted: 11, current: 15, wantDelta: 1, wantCount: 16},
+		{name: "shortage larger than what is left", expected: 10, counted: 2, current: 3, wantDelta: -8, wantErr: custom_errors.ErrInsufficientStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := &domain.InventoryCountLine{ExpectedQuantity: tt.expected, CountedQuantity: &tt.counted}
+
+			delta := inventoryCountDelta(line)
+			if delta != tt.wantDelta {
+				t.Fatalf("inventoryCountDelta() = %v, want %v", delta, tt.wantDelta)
+			}
+
+			movement := &domain.StockMovement{Quantity: delta, Reason: domain.MovementReasonInventory}
+			err := checkStockMovement(tt.current, movement, 0, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkStockMovement() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := uint64(int64(tt.current) + delta); got != tt.wantCount {
+				t.Errorf("count after approval = %v, want %v", got, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,521 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type KitRepository interface {
+	ReplaceKitComponentsData(userId string, kitSkuId uint64, components []domain.KitComponent) error
+	FindKitComponentsData(userId string, kitSkuId uint64) (*[]domain.KitComponent, error)
+	AssembleKitData(in *domain.KitOperation, userId string) (*[]domain.Product, error)
+	DisassembleKitData(in *domain.KitOperation, productId string, userId string) (*[]domain.Product, error)
+	FindAllKitOperationData(userId string, warehouseId int) (*[]domain.KitOperation, error)
+	FindKitOperationData(userId string, warehouseId int, operationId uint64) (*domain.KitOperation, error)
+}
+
+type KitPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewKitPostgresRepository(db database.Database, logger slog.Logger) *KitPostgresRepository {
+	return &KitPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ReplaceKitComponentsData заменяет спецификацию набора целиком, пустой список удаляет ее.
+// Набор учитывается целыми штуками, а серийные позиции не участвуют ни как набор, ни как компонент:
+// при сборке не из чего взять номера
+func (kr *KitPostgresRepository) ReplaceKitComponentsData(userId string, kitSkuId uint64, components []domain.KitComponent) error {
+	tx := kr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var kit domain.Sku
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND uuid_user = ?", kitSkuId, userId).
+		First(&kit).Error
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrSkuNotFound
+		}
+		return err
+	}
+
+	if len(components) > 0 && (kit.SerialTracked || kit.Decimals > 0) {
+		tx.Rollback()
+		return custom_errors.ErrInvalidKit
+	}
+
+	seen := make(map[uint64]struct{}, len(components))
+	for i := range components {
+		componentSkuId := components[i].ComponentSkuId
+		if _, ok := seen[componentSkuId]; ok || componentSkuId == kit.Id || components[i].Quantity == 0 {
+			tx.Rollback()
+			return custom_errors.ErrInvalidKit
+		}
+		seen[componentSkuId] = struct{}{}
+
+		component, err := findSku(tx, userId, componentSkuId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if component.SerialTracked {
+			tx.Rollback()
+			return custom_errors.ErrInvalidKit
+		}
+
+		components[i].Id = 0
+		components[i].KitSkuId = kit.Id
+		components[i].ComponentSku = nil
+	}
+
+	if err := tx.Where("kit_sku_id = ?", kit.Id).Delete(&domain.KitComponent{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(components) > 0 {
+		if err := tx.Create(&components).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (kr *KitPostgresRepository) FindKitComponentsData(userId string, kitSkuId uint64) (*[]domain.KitComponent, error) {
+	if _, err := findSku(kr.db.GetDb(), userId, kitSkuId); err != nil {
+		return nil, err
+	}
+
+	components, err := findKitComponents(kr.db.GetDb(), kitSkuId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &components, nil
+}
+
+// AssembleKitData собирает in.Quantity наборов в зону in.ZoneId. Компоненты списываются со строк товара склада
+// по FEFO: сначала партии с ближайшим сроком годности. Зарезервированный и заблокированный остаток не берется.
+// Возвращает строки товара, созданные операцией
+func (kr *KitPostgresRepository) AssembleKitData(in *domain.KitOperation, userId string) (*[]domain.Product, error) {
+	tx := kr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if _, err := findSku(tx, userId, in.KitSkuId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if in.ZoneId == nil {
+		tx.Rollback()
+		return nil, custom_errors.ErrZoneNotFound
+	}
+	if err := checkZonesInWarehouse(tx, int(in.WarehouseId), []uint64{*in.ZoneId}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	components, err := kitComponentsForOperation(tx, in.KitSkuId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Omit("Lines", "KitSku").Create(in).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	for _, component := range components {
+		need := component.Quantity * in.Quantity
+
+		var sources []domain.Product
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "products"}}).
+			Joins("JOIN zones ON products.zone_id = zones.id").
+			Where("products.sku_id = ? AND products.count > 0 AND zones.ware_house_id = ?", component.ComponentSkuId, in.WarehouseId).
+			Order("products.expiry_date ASC NULLS LAST, products.count ASC").
+			Find(&sources).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		for _, source := range sources {
+			if need == 0 {
+				break
+			}
+
+			sourceUuid := string(source.Uuid)
+			available, err := availableQuantity(tx, &source)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if available == 0 {
+				continue
+			}
+
+			take := min(available, need)
+			if err := kr.insertKitMovement(tx, in, sourceUuid, source.SkuId, -int64(take), domain.MovementReasonAssembly); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			need -= take
+		}
+
+		if need > 0 {
+			tx.Rollback()
+			return nil, custom_errors.ErrInsufficientComponents
+		}
+	}
+
+	kitProduct, created, err := kitTargetProduct(tx, in.KitSkuId, *in.ZoneId, kitLot{})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := kr.insertKitMovement(tx, in, string(kitProduct.Uuid), in.KitSkuId, int64(in.Quantity), domain.MovementReasonAssembly); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	createdProducts := []domain.Product{}
+	if created {
+		createdProducts = append(createdProducts, *kitProduct)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &createdProducts, nil
+}
+
+// DisassembleKitData разбирает in.Quantity наборов со строки товара productId по текущей спецификации набора.
+// Компоненты приходуются в зону in.ZoneId, пустая зона означает зону строки набора. Компоненты возвращаются в партии,
+// из которых собирались наборы этой строки, - с их номерами и сроками годности. Остаток сверх собранного на складе
+// приходуется без партии. Возвращает строки товара, созданные операцией
+func (kr *KitPostgresRepository) DisassembleKitData(in *domain.KitOperation, productId string, userId string) (*[]domain.Product, error) {
+	tx := kr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := checkProductsInWarehouse(tx, int(in.WarehouseId), []string{productId}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var kitProduct domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", productId).First(&kitProduct).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrProductNotFound
+		}
+		return nil, err
+	}
+	in.KitSkuId = kitProduct.SkuId
+
+	if in.ZoneId == nil {
+		in.ZoneId = &kitProduct.ZoneId
+	} else if err := checkZonesInWarehouse(tx, int(in.WarehouseId), []uint64{*in.ZoneId}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	components, err := kitComponentsForOperation(tx, in.KitSkuId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	available, err := availableQuantity(tx, &kitProduct)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if available < in.Quantity {
+		tx.Rollback()
+		return nil, custom_errors.ErrInsufficientAvailableStock
+	}
+
+	if err := tx.Omit("Lines", "KitSku").Create(in).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := kr.insertKitMovement(tx, in, productId, in.KitSkuId, -int64(in.Quantity), domain.MovementReasonDisassembly); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	createdProducts := []domain.Product{}
+	for _, component := range components {
+		lots, err := assembledKitLots(tx, productId, component.ComponentSkuId)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		need := component.Quantity * in.Quantity
+		for _, lot := range append(lots, kitLot{Quantity: need}) {
+			if need == 0 {
+				break
+			}
+
+			quantity := min(lot.Quantity, need)
+			if quantity == 0 {
+				continue
+			}
+
+			target, created, err := kitTargetProduct(tx, component.ComponentSkuId, *in.ZoneId, lot)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if created {
+				createdProducts = append(createdProducts, *target)
+			}
+
+			if err := kr.insertKitMovement(tx, in, string(target.Uuid), component.ComponentSkuId, int64(quantity), domain.MovementReasonDisassembly); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			need -= quantity
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &createdProducts, nil
+}
+
+func (kr *KitPostgresRepository) FindAllKitOperationData(userId string, warehouseId int) (*[]domain.KitOperation, error) {
+	var operations []domain.KitOperation
+
+	if err := checkWarehouseOwner(kr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := kr.db.GetDb().Preload("Lines", orderKitOperationLines).Preload("Lines.Sku").Preload("KitSku").
+		Where("ware_house_id = ?", warehouseId).
+		Order("created_at DESC, id DESC").
+		Find(&operations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &operations, nil
+}
+
+func (kr *KitPostgresRepository) FindKitOperationData(userId string, warehouseId int, operationId uint64) (*domain.KitOperation, error) {
+	var operation domain.KitOperation
+
+	if err := checkWarehouseOwner(kr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := kr.db.GetDb().Preload("Lines", orderKitOperationLines).Preload("Lines.Sku").Preload("KitSku").
+		Where("id = ? AND ware_house_id = ?", operationId, warehouseId).
+		First(&operation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrKitOperationNotFound
+		}
+		return nil, err
+	}
+
+	return &operation, nil
+}
+
+// insertKitMovement меняет остаток строки товара и записывает движение строкой операции
+func (kr *KitPostgresRepository) insertKitMovement(tx *gorm.DB, operation *domain.KitOperation, productId string, skuId uint64, quantity int64, reason string) error {
+	movement := &domain.StockMovement{
+		ProductUuid: productId,
+		Quantity:    quantity,
+		Reason:      reason,
+		ActorUuid:   operation.CreatedBy,
+	}
+	if err := applyStockMovement(tx, movement); err != nil {
+		return err
+	}
+
+	line := domain.KitOperationLine{
+		OperationId: operation.Id,
+		ProductUuid: productId,
+		SkuId:       skuId,
+		Quantity:    quantity,
+		MovementId:  movement.Id,
+	}
+	if err := tx.Create(&line).Error; err != nil {
+		return err
+	}
+
+	operation.Lines = append(operation.Lines, line)
+
+	return nil
+}
+
+func findKitComponents(db *gorm.DB, kitSkuId uint64) ([]domain.KitComponent, error) {
+	var components []domain.KitComponent
+	if err := db.Preload("ComponentSku").Where("kit_sku_id = ?", kitSkuId).Order("id").Find(&components).Error; err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+// kitComponentsForOperation возвращает спецификацию набора для сборки или разборки. Позиция могла стать серийной
+// или дробной после того, как попала в спецификацию, такой набор не собирается и не разбирается
+func kitComponentsForOperation(tx *gorm.DB, kitSkuId uint64) ([]domain.KitComponent, error) {
+	components, err := findKitComponents(tx, kitSkuId)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, custom_errors.ErrKitHasNoComponents
+	}
+
+	var kit domain.Sku
+	if err := tx.Select("serial_tracked", "decimals").Where("id = ?", kitSkuId).First(&kit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrSkuNotFound
+		}
+		return nil, err
+	}
+
+	for _, component := range components {
+		if kit.SerialTracked || kit.Decimals > 0 || component.ComponentSku == nil || component.ComponentSku.SerialTracked {
+			return nil, custom_errors.ErrInvalidKit
+		}
+	}
+
+	return components, nil
+}
+
+// availableQuantity - остаток строки товара за вычетом действующих резервов и блокировок
+func availableQuantity(tx *gorm.DB, product *domain.Product) (uint64, error) {
+	productId := string(product.Uuid)
+
+	reserved, err := reservedQuantity(tx, productId)
+	if err != nil {
+		return 0, err
+	}
+
+	held, err := heldQuantity(tx, productId)
+	if err != nil {
+		return 0, err
+	}
+
+	if reserved+held >= product.Count {
+		return 0, nil
+	}
+
+	return product.Count - reserved - held, nil
+}
+
+// kitLot - партия компонента и сколько ее ушло в наборы. Пустая партия - остаток без номера и сроков
+type kitLot struct {
+	LotNumber      string
+	ProductionDate *time.Time
+	ExpiryDate     *time.Time
+	Quantity       uint64
+}
+
+// assembledKitLots возвращает партии компонента componentSkuId, из которых собирались наборы на строку kitProductId,
+// сначала с ближайшим сроком годности
+func assembledKitLots(tx *gorm.DB, kitProductId string, componentSkuId uint64) ([]kitLot, error) {
+	var lots []kitLot
+	err := tx.Table("kit_operation_lines AS component_lines").
+		Select("products.lot_number, products.production_date, products.expiry_date, SUM(-component_lines.quantity) AS quantity").
+		Joins("JOIN kit_operations ON kit_operations.id = component_lines.operation_id").
+		Joins("JOIN kit_operation_lines AS kit_lines ON kit_lines.operation_id = kit_operations.id AND kit_lines.product_uuid = ?", kitProductId).
+		Joins("JOIN products ON products.uuid = component_lines.product_uuid").
+		Where("kit_operations.kind = ? AND component_lines.sku_id = ? AND component_lines.quantity < 0", domain.KitOperationAssembly, componentSkuId).
+		Group("products.lot_number, products.production_date, products.expiry_date").
+		Order("products.expiry_date ASC NULLS LAST, products.lot_number").
+		Scan(&lots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+// kitTargetProduct выбирает строку партии lot без ячейки в зоне zoneId, на которую приходуется результат операции,
+// и создает ее, если такой нет
+func kitTargetProduct(tx *gorm.DB, skuId uint64, zoneId uint64, lot kitLot) (*domain.Product, bool, error) {
+	query := tx.Where("sku_id = ? AND zone_id = ? AND lot_number = ? AND location_id IS NULL", skuId, zoneId, lot.LotNumber)
+	if lot.ProductionDate != nil {
+		query = query.Where("production_date = ?", *lot.ProductionDate)
+	} else {
+		query = query.Where("production_date IS NULL")
+	}
+	if lot.ExpiryDate != nil {
+		query = query.Where("expiry_date = ?", *lot.ExpiryDate)
+	} else {
+		query = query.Where("expiry_date IS NULL")
+	}
+
+	var existing []domain.Product
+	if err := query.Limit(1).Find(&existing).Error; err != nil {
+		return nil, false, err
+	}
+	if len(existing) > 0 {
+		return &existing[0], false, nil
+	}
+
+	product := &domain.Product{
+		SkuId:          skuId,
+		ZoneId:         zoneId,
+		LotNumber:      lot.LotNumber,
+		ProductionDate: lot.ProductionDate,
+		ExpiryDate:     lot.ExpiryDate,
+	}
+	if err := tx.Create(product).Error; err != nil {
+		return nil, false, err
+	}
+
+	return product, true, nil
+}
+
+func orderKitOperationLines(db *gorm.DB) *gorm.DB {
+	return db.Order("kit_operation_lines.id")
+}
@@ -0,0 +1,351 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+// locationKindLevels задает порядок вложенности: узел может содержать только узлы более глубокого уровня
+var locationKindLevels = map[string]int{
+	domain.LocationKindAisle: 1,
+	domain.LocationKindRack:  2,
+	domain.LocationKindShelf: 3,
+	domain.LocationKindBin:   4,
+}
+
+type LocationRepository interface {
+	InsertLocationData(in *domain.Location, userId string, warehouseId int) error
+	UpdateLocationData(in *domain.Location, userId string, warehouseId int) error
+	FindAllLocationData(userId string, warehouseId int, zoneId uint64) (*[]domain.Location, error)
+	FindLocationData(userId string, warehouseId int, zoneId uint64, locationId uint64) (*domain.Location, error)
+	FindLocationStockData(zoneId uint64) (map[uint64]uint64, error)
+	DeleteLocationData(userId string, warehouseId int, zoneId uint64, locationId uint64) error
+	UpdateLocationQrData(locationId uint64, qrPath string) error
+}
+
+type LocationPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewLocationPostgresRepository(db database.Database, logger slog.Logger) *LocationPostgresRepository {
+	return &LocationPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (lr *LocationPostgresRepository) InsertLocationData(in *domain.Location, userId string, warehouseId int) error {
+	tx := lr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkZonesInWarehouse(tx, warehouseId, []uint64{in.ZoneId}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	level, ok := locationKindLevels[in.Kind]
+	if !ok {
+		tx.Rollback()
+		return custom_errors.ErrInvalidLocation
+	}
+
+	if in.ParentId != nil {
+		parent, err := findLocation(tx, in.ZoneId, *in.ParentId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if locationKindLevels[parent.Kind] >= level {
+			tx.Rollback()
+			return custom_errors.ErrInvalidLocation
+		}
+	}
+
+	if err := checkLocationUnique(tx, in); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdateLocationData меняет код и вместимость узла. Вместимость нельзя сделать меньше уже размещенного остатка
+func (lr *LocationPostgresRepository) UpdateLocationData(in *domain.Location, userId string, warehouseId int) error {
+	tx := lr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	location, err := lockLocation(tx, in.Id)
+	if err != nil || location.ZoneId != in.ZoneId {
+		tx.Rollback()
+		return custom_errors.ErrLocationNotFound
+	}
+
+	in.ParentId = location.ParentId
+	if err := checkLocationUnique(tx, in); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if in.Capacity != nil {
+		occupied, err := locationOccupied(tx, location.Id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if occupied > *in.Capacity {
+			tx.Rollback()
+			return custom_errors.ErrLocationCapacityExceeded
+		}
+	}
+
+	err = tx.Model(&domain.Location{}).Where("id = ?", location.Id).
+		Select("code", "capacity").
+		Updates(in).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (lr *LocationPostgresRepository) FindAllLocationData(userId string, warehouseId int, zoneId uint64) (*[]domain.Location, error) {
+	var locations []domain.Location
+
+	if err := checkWarehouseOwner(lr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := checkZonesInWarehouse(lr.db.GetDb(), warehouseId, []uint64{zoneId}); err != nil {
+		return nil, err
+	}
+
+	if err := lr.db.GetDb().Where("zone_id = ?", zoneId).Order("code, id").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	return &locations, nil
+}
+
+func (lr *LocationPostgresRepository) FindLocationData(userId string, warehouseId int, zoneId uint64, locationId uint64) (*domain.Location, error) {
+	if err := checkWarehouseOwner(lr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := checkZonesInWarehouse(lr.db.GetDb(), warehouseId, []uint64{zoneId}); err != nil {
+		return nil, err
+	}
+
+	return findLocation(lr.db.GetDb(), zoneId, locationId)
+}
+
+// FindLocationStockData возвращает остаток, лежащий непосредственно в каждой ячейке зоны
+func (lr *LocationPostgresRepository) FindLocationStockData(zoneId uint64) (map[uint64]uint64, error) {
+	var rows []struct {
+		LocationId uint64
+		Count      uint64
+	}
+
+	err := lr.db.GetDb().Model(&domain.Product{}).
+		Select("location_id, SUM(count) AS count").
+		Where("zone_id = ? AND location_id IS NOT NULL", zoneId).
+		Group("location_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stock := make(map[uint64]uint64, len(rows))
+	for _, row := range rows {
+		stock[row.LocationId] = row.Count
+	}
+
+	return stock, nil
+}
+
+// DeleteLocationData удаляет узел вместе с поддеревом, если в нем не осталось остатков
+func (lr *LocationPostgresRepository) DeleteLocationData(userId string, warehouseId int, zoneId uint64, locationId uint64) error {
+	tx := lr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	location, err := lockLocation(tx, locationId)
+	if err != nil || location.ZoneId != zoneId {
+		tx.Rollback()
+		return custom_errors.ErrLocationNotFound
+	}
+
+	occupied, err := locationOccupied(tx, location.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if occupied > 0 {
+		tx.Rollback()
+		return custom_errors.ErrLocationNotEmpty
+	}
+
+	// Строки товара с нулевым остатком остаются в зоне без ячейки
+	err = tx.Model(&domain.Product{}).
+		Where("location_id IN (?)", locationSubtree(tx, location.Id)).
+		Update("location_id", nil).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&domain.Location{}, location.Id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (lr *LocationPostgresRepository) UpdateLocationQrData(locationId uint64, qrPath string) error {
+	return lr.db.GetDb().Model(&domain.Location{}).Where("id = ?", locationId).Update("qr", qrPath).Error
+}
+
+func findLocation(db *gorm.DB, zoneId uint64, locationId uint64) (*domain.Location, error) {
+	var location domain.Location
+	if err := db.Where("id = ? AND zone_id = ?", locationId, zoneId).First(&location).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrLocationNotFound
+		}
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+func lockLocation(tx *gorm.DB, locationId uint64) (*domain.Location, error) {
+	var location domain.Location
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", locationId).First(&location).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrLocationNotFound
+		}
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// findBin проверяет, что locationId - ячейка зоны zoneId, в которую можно положить товар
+func findBin(db *gorm.DB, zoneId uint64, locationId uint64) (*domain.Location, error) {
+	location, err := findLocation(db, zoneId, locationId)
+	if err != nil {
+		return nil, err
+	}
+	if location.Kind != domain.LocationKindBin {
+		return nil, custom_errors.ErrInvalidLocation
+	}
+
+	return location, nil
+}
+
+// checkLocationUnique не дает завести у одного родителя два узла с одинаковым кодом
+func checkLocationUnique(tx *gorm.DB, in *domain.Location) error {
+	query := tx.Model(&domain.Location{}).Where("zone_id = ? AND code = ? AND id <> ?", in.ZoneId, in.Code, in.Id)
+	if in.ParentId != nil {
+		query = query.Where("parent_id = ?", *in.ParentId)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return custom_errors.ErrLocationAlreadyExists
+	}
+
+	return nil
+}
+
+// checkLocationCapacity блокирует ячейку и всех ее родителей до конца транзакции и проверяет,
+// что в каждом узле с ограниченной вместимостью поместится еще quantity единиц
+func checkLocationCapacity(tx *gorm.DB, locationId uint64, quantity uint64) error {
+	nextId := &locationId
+	for nextId != nil {
+		location, err := lockLocation(tx, *nextId)
+		if err != nil {
+			return err
+		}
+
+		if location.Capacity != nil {
+			occupied, err := locationOccupied(tx, location.Id)
+			if err != nil {
+				return err
+			}
+			if occupied+quantity > *location.Capacity {
+				return custom_errors.ErrLocationCapacityExceeded
+			}
+		}
+
+		nextId = location.ParentId
+	}
+
+	return nil
+}
+
+// locationOccupied считает остаток во всем поддереве узла
+func locationOccupied(tx *gorm.DB, locationId uint64) (uint64, error) {
+	var occupied uint64
+	err := tx.Model(&domain.Product{}).
+		Select("COALESCE(SUM(count), 0)").
+		Where("location_id IN (?)", locationSubtree(tx, locationId)).
+		Scan(&occupied).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return occupied, nil
+}
+
+// locationSubtree - подзапрос с идентификаторами узла и всех его потомков
+func locationSubtree(db *gorm.DB, locationId uint64) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Raw(`WITH RECURSIVE subtree AS (
+		SELECT id FROM locations WHERE id = ?
+		UNION ALL
+		SELECT locations.id FROM locations JOIN subtree ON locations.parent_id = subtree.id
+	) SELECT id FROM subtree`, locationId)
+}
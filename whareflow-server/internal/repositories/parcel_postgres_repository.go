@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"math"
+)
+
+type ParcelRepository interface {
+	InsertParcelData(in *domain.Parcel, userId string, warehouseId int) error
+	DeleteParcelData(userId string, warehouseId int, shipmentId uint64, parcelId uint64) error
+	FindAllParcelData(userId string, warehouseId int, shipmentId uint64) (*[]domain.Parcel, error)
+	FindShipmentOrderData(shipmentId uint64) (*domain.SalesOrder, error)
+}
+
+type ParcelPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewParcelPostgresRepository(db database.Database, logger slog.Logger) *ParcelPostgresRepository {
+	return &ParcelPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// InsertParcelData добавляет грузовое место в упакованную отгрузку. В места нельзя уложить больше, чем собрано по строке.
+// Если вес не указан, он считается по весу единицы товара из карточки SKU
+func (pr *ParcelPostgresRepository) InsertParcelData(in *domain.Parcel, userId string, warehouseId int) error {
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	shipment, err := lockPackedShipment(tx, userId, warehouseId, in.ShipmentId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var lines []domain.ShipmentLine
+	if err := tx.Where("shipment_id = ?", shipment.Id).Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	linesById := make(map[uint64]domain.ShipmentLine, len(lines))
+	for _, line := range lines {
+		linesById[line.Id] = line
+	}
+
+	parceled, err := parceledQuantities(tx, shipment.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	seen := make(map[uint64]struct{}, len(in.Lines))
+	estimateWeight := in.WeightKg == 0
+	for _, parcelLine := range in.Lines {
+		line, ok := linesById[parcelLine.ShipmentLineId]
+		if !ok || parcelLine.Quantity == 0 || parceled[line.Id]+parcelLine.Quantity > line.PickedQuantity {
+			tx.Rollback()
+			return custom_errors.ErrInvalidDocumentLine
+		}
+
+		if _, duplicate := seen[line.Id]; duplicate {
+			tx.Rollback()
+			return custom_errors.ErrInvalidDocumentLine
+		}
+		seen[line.Id] = struct{}{}
+
+		if estimateWeight {
+			var product domain.Product
+			if err := tx.Preload("Sku").Where("uuid = ?", line.ProductUuid).First(&product).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+
+			if product.Sku != nil {
+				in.WeightKg += product.Sku.WeightKg * float64(parcelLine.Quantity) / math.Pow10(int(product.Sku.Decimals))
+			}
+		}
+	}
+
+	if len(in.Lines) == 0 || in.WeightKg <= 0 {
+		tx.Rollback()
+		return custom_errors.ErrInvalidParcel
+	}
+
+	var maxNumber int
+	if err := tx.Model(&domain.Parcel{}).Where("shipment_id = ?", shipment.Id).Select("COALESCE(MAX(number), 0)").Scan(&maxNumber).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	in.Number = maxNumber + 1
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// DeleteParcelData удаляет место из упакованной отгрузки, следующие за ним места сдвигаются, чтобы нумерация оставалась сплошной
+func (pr *ParcelPostgresRepository) DeleteParcelData(userId string, warehouseId int, shipmentId uint64, parcelId uint64) error {
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := lockPackedShipment(tx, userId, warehouseId, shipmentId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var parcel domain.Parcel
+	if err := tx.Where("id = ? AND shipment_id = ?", parcelId, shipmentId).First(&parcel).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrParcelNotFound
+		}
+		return err
+	}
+
+	if err := tx.Delete(&parcel).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var following []domain.Parcel
+	if err := tx.Where("shipment_id = ? AND number > ?", shipmentId, parcel.Number).Order("number").Find(&following).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// По одному и по возрастанию, чтобы не нарушить уникальность номера места
+	for _, next := range following {
+		if err := tx.Model(&next).Update("number", next.Number-1).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (pr *ParcelPostgresRepository) FindAllParcelData(userId string, warehouseId int, shipmentId uint64) (*[]domain.Parcel, error) {
+	var parcels []domain.Parcel
+
+	if err := checkWarehouseOwner(pr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var shipment domain.Shipment
+	if err := pr.db.GetDb().Where("id = ? AND ware_house_id = ?", shipmentId, warehouseId).First(&shipment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrShipmentNotFound
+		}
+		return nil, err
+	}
+
+	if err := pr.db.GetDb().Preload("Lines").Where("shipment_id = ?", shipmentId).Order("number").Find(&parcels).Error; err != nil {
+		return nil, err
+	}
+
+	return &parcels, nil
+}
+
+// FindShipmentOrderData возвращает заказ покупателя, по которому заведена отгрузка, вместе с покупателем.
+// Для отгрузки без заказа возвращает nil
+func (pr *ParcelPostgresRepository) FindShipmentOrderData(shipmentId uint64) (*domain.SalesOrder, error) {
+	var order domain.SalesOrder
+
+	if err := pr.db.GetDb().Preload("Customer").Where("shipment_id = ?", shipmentId).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func lockPackedShipment(tx *gorm.DB, userId string, warehouseId int, shipmentId uint64) (*domain.Shipment, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var shipment domain.Shipment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", shipmentId, warehouseId).
+		First(&shipment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrShipmentNotFound
+		}
+		return nil, err
+	}
+
+	if shipment.Status != domain.ShipmentStatusPacked {
+		return nil, custom_errors.ErrInvalidDocumentStatus
+	}
+
+	return &shipment, nil
+}
+
+// parceledQuantities возвращает, сколько по каждой строке отгрузки уже уложено в места
+func parceledQuantities(tx *gorm.DB, shipmentId uint64) (map[uint64]uint64, error) {
+	var rows []struct {
+		ShipmentLineId uint64
+		Quantity       uint64
+	}
+
+	err := tx.Model(&domain.ParcelLine{}).
+		Select("parcel_lines.shipment_line_id, SUM(parcel_lines.quantity) AS quantity").
+		Joins("JOIN parcels ON parcels.id = parcel_lines.parcel_id").
+		Where("parcels.shipment_id = ?", shipmentId).
+		Group("parcel_lines.shipment_line_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	parceled := make(map[uint64]uint64, len(rows))
+	for _, row := range rows {
+		parceled[row.ShipmentLineId] = row.Quantity
+	}
+
+	return parceled, nil
+}
+
+// checkShipmentParcels не дает отгрузить частично разложенную по местам отгрузку: если места заведены,
+// в них должно лежать все собранное количество. Отгрузка без мест проходит как раньше
+func checkShipmentParcels(tx *gorm.DB, shipmentId uint64, lines []domain.ShipmentLine) error {
+	var parcels int64
+	if err := tx.Model(&domain.Parcel{}).Where("shipment_id = ?", shipmentId).Count(&parcels).Error; err != nil {
+		return err
+	}
+	if parcels == 0 {
+		return nil
+	}
+
+	parceled, err := parceledQuantities(tx, shipmentId)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if parceled[line.Id] != line.PickedQuantity {
+			return custom_errors.ErrParcelsIncomplete
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type PickListRepository interface {
+	ReplaceWalkPathData(userId string, warehouseId int, steps []domain.WalkPathStep) error
+	FindWalkPathData(userId string, warehouseId int) (*[]domain.WalkPathStep, error)
+	InsertPickListData(in *domain.PickList, userId string, orderIds []uint64, maxLines int) error
+	FindAllPickListData(userId string, warehouseId int, status string) (*[]domain.PickList, error)
+	FindPickListData(userId string, warehouseId int, listId uint64) (*domain.PickList, error)
+	FindWarehouseZonesData(warehouseId int) (*[]domain.Zone, error)
+	FindZoneLocationsData(zoneIds []uint64) (*[]domain.Location, error)
+}
+
+type PickListPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewPickListPostgresRepository(db database.Database, logger slog.Logger) *PickListPostgresRepository {
+	return &PickListPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ReplaceWalkPathData заменяет маршрут обхода склада. Шаги нумеруются в порядке передачи,
+// одно и то же место встречается в маршруте один раз
+func (pr *PickListPostgresRepository) ReplaceWalkPathData(userId string, warehouseId int, steps []domain.WalkPathStep) error {
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var zoneIds []uint64
+	for _, step := range steps {
+		zoneIds = append(zoneIds, step.ZoneId)
+	}
+
+	if err := checkZonesInWarehouse(tx, warehouseId, zoneIds); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	type place struct {
+		zoneId     uint64
+		locationId uint64
+	}
+	seen := make(map[place]struct{}, len(steps))
+
+	for i := range steps {
+		step := &steps[i]
+
+		key := place{zoneId: step.ZoneId}
+		if step.LocationId != nil {
+			if _, err := findLocation(tx, step.ZoneId, *step.LocationId); err != nil {
+				tx.Rollback()
+				return err
+			}
+			key.locationId = *step.LocationId
+		}
+
+		if _, ok := seen[key]; ok {
+			tx.Rollback()
+			return custom_errors.ErrInvalidWalkPath
+		}
+		seen[key] = struct{}{}
+
+		step.Id = 0
+		step.WarehouseId = uint64(warehouseId)
+		step.Sequence = i + 1
+	}
+
+	if err := tx.Where("ware_house_id = ?", warehouseId).Delete(&domain.WalkPathStep{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(steps) > 0 {
+		if err := tx.Create(&steps).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (pr *PickListPostgresRepository) FindWalkPathData(userId string, warehouseId int) (*[]domain.WalkPathStep, error) {
+	var steps []domain.WalkPathStep
+
+	if err := checkWarehouseOwner(pr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := pr.db.GetDb().Where("ware_house_id = ?", warehouseId).Order("sequence").Find(&steps).Error; err != nil {
+		return nil, err
+	}
+
+	return &steps, nil
+}
+
+// InsertPickListData собирает в лист невыполненные задания склада, еще не попавшие в другой лист или волну, - по заказам orderIds
+// или по всем заказам, не больше maxLines заданий (0 - без ограничения). Задание, которое нельзя собрать целиком
+// из незаблокированного остатка товара, в лист не попадает и остается в очереди. Место задания берется из строки товара
+// на момент сборки листа: после переноса или размещения товар лежит уже не там, где был при подтверждении заказа
+func (pr *PickListPostgresRepository) InsertPickListData(in *domain.PickList, userId string, orderIds []uint64, maxLines int) error {
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ware_house_id = ? AND status = ? AND pick_list_id IS NULL AND wave_id IS NULL", in.WarehouseId, domain.PickTaskStatusOpen)
+	if len(orderIds) > 0 {
+		query = query.Where("order_id IN ?", orderIds)
+	}
+
+	var tasks []domain.PickTask
+	if err := query.Order("order_id").Order("id").Find(&tasks).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(tasks) == 0 {
+		tx.Rollback()
+		return custom_errors.ErrNothingToPick
+	}
+
+	productUuids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		productUuids = append(productUuids, task.ProductUuid)
+	}
+
+	// Строки товара блокируются в одном порядке, чтобы параллельные листы не обещали один и тот же остаток
+	var products []domain.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("uuid IN ?", productUuids).
+		Order("uuid").
+		Find(&products).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	productsByUuid := make(map[string]domain.Product, len(products))
+	for _, product := range products {
+		productsByUuid[string(product.Uuid)] = product
+	}
+
+	// Остаток, который уже обещан заданиям этого листа, второй раз не учитывается
+	taken := make(map[string]uint64)
+	held := make(map[string]uint64)
+	var taskIds []uint64
+
+	for _, task := range tasks {
+		if maxLines > 0 && len(taskIds) >= maxLines {
+			break
+		}
+
+		product, ok := productsByUuid[task.ProductUuid]
+		if !ok {
+			tx.Rollback()
+			return custom_errors.ErrProductNotFound
+		}
+
+		productHeld, ok := held[task.ProductUuid]
+		if !ok {
+			productHeld, err = heldQuantity(tx, task.ProductUuid)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			held[task.ProductUuid] = productHeld
+		}
+
+		if productHeld+taken[task.ProductUuid]+task.Quantity > product.Count {
+			continue
+		}
+
+		err = tx.Model(&domain.PickTask{}).Where("id = ?", task.Id).Updates(map[string]interface{}{
+			"zone_id":     product.ZoneId,
+			"location_id": product.LocationId,
+		}).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		taken[task.ProductUuid] += task.Quantity
+		taskIds = append(taskIds, task.Id)
+	}
+
+	if len(taskIds) == 0 {
+		tx.Rollback()
+		return custom_errors.ErrNothingToPick
+	}
+
+	if err := tx.Omit("Tasks").Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(&domain.PickTask{}).Where("id IN ?", taskIds).Update("pick_list_id", in.Id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// FindAllPickListData возвращает листы сборки склада. Если status не пустой, только в этом статусе
+func (pr *PickListPostgresRepository) FindAllPickListData(userId string, warehouseId int, status string) (*[]domain.PickList, error) {
+	var lists []domain.PickList
+
+	if err := checkWarehouseOwner(pr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	query := pr.db.GetDb().Preload("Tasks").Where("ware_house_id = ?", warehouseId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("created_at DESC").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+
+	return &lists, nil
+}
+
+func (pr *PickListPostgresRepository) FindPickListData(userId string, warehouseId int, listId uint64) (*domain.PickList, error) {
+	var list domain.PickList
+
+	if err := checkWarehouseOwner(pr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := pr.db.GetDb().Preload("Tasks").
+		Where("id = ? AND ware_house_id = ?", listId, warehouseId).
+		First(&list).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrPickListNotFound
+		}
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (pr *PickListPostgresRepository) FindWarehouseZonesData(warehouseId int) (*[]domain.Zone, error) {
+	var zones []domain.Zone
+
+	if err := pr.db.GetDb().Where("ware_house_id = ?", warehouseId).Order("id").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+
+	return &zones, nil
+}
+
+// FindZoneLocationsData возвращает все узлы адресов зон, по ним строится путь ячейки от корня
+func (pr *PickListPostgresRepository) FindZoneLocationsData(zoneIds []uint64) (*[]domain.Location, error) {
+	var locations []domain.Location
+
+	if len(zoneIds) == 0 {
+		return &locations, nil
+	}
+
+	if err := pr.db.GetDb().Where("zone_id IN ?", zoneIds).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	return &locations, nil
+}
+
+// closePickList закрывает лист сборки, если в нем не осталось невыполненных заданий
+func closePickList(tx *gorm.DB, listId uint64) error {
+	var open int64
+	err := tx.Model(&domain.PickTask{}).
+		Where("pick_list_id = ? AND status = ?", listId, domain.PickTaskStatusOpen).
+		Count(&open).Error
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return nil
+	}
+
+	return tx.Model(&domain.PickList{}).
+		Where("id = ? AND status = ?", listId, domain.PickListStatusOpen).
+		Updates(map[string]interface{}{
+			"status":  domain.PickListStatusDone,
+			"done_at": time.Now(),
+		}).Error
+}
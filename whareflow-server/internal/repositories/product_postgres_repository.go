@@ -6,17 +6,23 @@ import (
 	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
 	"github.com/Miroslovelife/whareflow/pkg/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log/slog"
+	"time"
 )
 
 type ProductRepository interface {
-	InsertProductData(in *domain.Product, userId string, warehouseId int) (*domain.Product, error)
-	UpdateProductData(in *domain.Product, userId string, warehouseId int) error
+	InsertProductData(in *domain.Product, userId string, warehouseId int, actorId string, serials []string) (*domain.Product, error)
+	UpdateProductData(in *domain.Product, userId string, warehouseId int, actorId string) error
+	UpdateProductQrData(productId string, qrPath string) error
+	MoveProductData(productId string, userId string, warehouseId int, targetZoneId uint64, targetLocationId *uint64, quantity uint64, actorId string, serials []string) (*domain.Product, error)
 	DeleteProductData(in *domain.Product, userId string, warehouseId int) error
 	FindAllProductFromZoneData(userId string, zoneId int) (*[]domain.Product, error)
 	FindAllProductFromWarehouseData(userId string, warehouseId int) (*[]domain.Product, error)
 	FindAllProductData(userId string) (*[]domain.Product, error)
 	FindProductData(userId string, productId string) (*domain.Product, error)
+	FindFefoCandidatesData(userId string, warehouseId int, skuId uint64) (*[]domain.Product, error)
+	FindExpiringProductData(userId string, warehouseId int, until time.Time) (*[]domain.Product, error)
 }
 
 type ProductPostgresRepository struct {
@@ -31,7 +37,7 @@ func NewProductPostgresRepository(db database.Database, logger slog.Logger) *Pro
 	}
 }
 
-func (pr *ProductPostgresRepository) InsertProductData(in *domain.Product, userId string, warehouseId int) (*domain.Product, error) {
+func (pr *ProductPostgresRepository) InsertProductData(in *domain.Product, userId string, warehouseId int, actorId string, serials []string) (*domain.Product, error) {
 	var warehouse domain.WareHouse
 	if err := pr.db.GetDb().Where("id = ? AND uuid_user = ?", warehouseId, userId).First(&warehouse).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -40,6 +46,19 @@ func (pr *ProductPostgresRepository) InsertProductData(in *domain.Product, userI
 		return nil, err
 	}
 
+	sku, err := findSku(pr.db.GetDb(), userId, in.SkuId)
+	if err != nil {
+		return nil, err
+	}
+
+	if sku.SerialTracked {
+		if err := checkSerialList(serials, in.Count); err != nil {
+			return nil, err
+		}
+	} else if len(serials) > 0 {
+		return nil, custom_errors.ErrSerialCountMismatch
+	}
+
 	var zone domain.Zone
 	if err := pr.db.GetDb().Where("id = ? AND ware_house_id = ?", in.ZoneId, warehouseId).First(&zone).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -48,15 +67,79 @@ func (pr *ProductPostgresRepository) InsertProductData(in *domain.Product, userI
 		return nil, err
 	}
 
-	err := pr.db.GetDb().Create(&in)
-	if err.Error != nil {
-		return nil, err.Error
+	if err := checkStorageCompatible(&zone, sku); err != nil {
+		return nil, err
+	}
+
+	if in.LocationId != nil {
+		if _, err := findBin(pr.db.GetDb(), in.ZoneId, *in.LocationId); err != nil {
+			return nil, err
+		}
 	}
 
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Остаток появляется только через журнал движений
+	count := in.Count
+	in.Count = 0
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if count > 0 {
+		movement := &domain.StockMovement{
+			ProductUuid:  string(in.Uuid),
+			Quantity:     int64(count),
+			Reason:       domain.MovementReasonInitial,
+			ActorUuid:    actorId,
+			TargetZoneId: &in.ZoneId,
+		}
+		if err := applyStockMovement(tx, movement); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if sku.SerialTracked {
+			productUuid := string(in.Uuid)
+			serialNumbers := make([]domain.SerialNumber, 0, len(serials))
+			for _, serial := range serials {
+				serialNumbers = append(serialNumbers, domain.SerialNumber{
+					Serial:      serial,
+					WarehouseId: uint64(warehouseId),
+					ProductUuid: &productUuid,
+					Status:      domain.SerialStatusInStock,
+				})
+			}
+
+			if err := insertSerialNumbers(tx, userId, serialNumbers); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if err := linkSerialMovement(tx, serialNumbers, movement.Id); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	in.Count = count
+	in.Sku = sku
+
 	return in, nil
 }
 
-func (pr *ProductPostgresRepository) UpdateProductData(in *domain.Product, userId string, warehouseId int) error {
+func (pr *ProductPostgresRepository) UpdateProductData(in *domain.Product, userId string, warehouseId int, actorId string) error {
 	warehouse := domain.WareHouse{}
 	product := domain.Product{}
 
@@ -75,17 +158,265 @@ func (pr *ProductPostgresRepository) UpdateProductData(in *domain.Product, userI
 		return err
 	}
 
-	resultProduct := pr.db.GetDb().Model(&product).Where("uuid = ?", string(in.Uuid[:])).Select("title", "count", "description", "zone_id", "qr").Updates(in)
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", string(in.Uuid[:])).First(&product).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrProductNotFound
+		}
+		return err
+	}
+
+	// Товар переезжает в другую зону вместе со всем остатком, поэтому новая зона должна подходить позиции
+	if product.ZoneId != in.ZoneId {
+		if err := checkZoneStorage(tx, in.ZoneId, product.SkuId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	resultProduct := tx.Model(&domain.Product{}).Where("uuid = ?", string(in.Uuid[:])).Select("zone_id", "qr", "lot_number", "production_date", "expiry_date").Updates(in)
 	if resultProduct.Error != nil {
+		tx.Rollback()
 		return resultProduct.Error
 	}
-	if resultProduct.RowsAffected == 0 {
+
+	// Количество не перезаписывается напрямую: разница с текущим остатком пишется в журнал
+	delta := int64(in.Count) - int64(product.Count)
+	if delta != 0 {
+		serialTracked, err := productSerialTracked(tx, string(in.Uuid))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if serialTracked {
+			tx.Rollback()
+			return custom_errors.ErrSerialTrackedProduct
+		}
+	}
+	if delta != 0 {
+		movement := &domain.StockMovement{
+			ProductUuid: string(in.Uuid),
+			Quantity:    delta,
+			Reason:      domain.MovementReasonAdjustment,
+			ActorUuid:   actorId,
+		}
+		if delta > 0 {
+			movement.TargetZoneId = &in.ZoneId
+		} else {
+			movement.SourceZoneId = &in.ZoneId
+		}
+
+		if err := applyStockMovement(tx, movement); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (pr *ProductPostgresRepository) UpdateProductQrData(productId string, qrPath string) error {
+	result := pr.db.GetDb().Model(&domain.Product{}).Where("uuid = ?", productId).Update("qr", qrPath)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
 		return custom_errors.ErrProductNotFound
 	}
 
 	return nil
 }
 
+// MoveProductData переносит quantity единиц товара в другую зону склада или в другую ячейку targetLocationId
+// (nil - в зону без ячейки). Если переносится весь остаток, строка товара меняет зону и ячейку, иначе в целевой зоне
+// создается новая строка.
+// У серийного товара при частичном переносе нужно указать переносимые номера.
+// Возвращает строку товара, которая лежит в целевой зоне.
+func (pr *ProductPostgresRepository) MoveProductData(productId string, userId string, warehouseId int, targetZoneId uint64, targetLocationId *uint64, quantity uint64, actorId string, serials []string) (*domain.Product, error) {
+	if err := checkWarehouseOwner(pr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := checkZonesInWarehouse(pr.db.GetDb(), warehouseId, []uint64{targetZoneId}); err != nil {
+		return nil, err
+	}
+
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	target, err := moveProductStock(tx, productId, warehouseId, targetZoneId, targetLocationId, quantity, actorId, serials)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// moveProductStock переносит товар в рамках транзакции tx, правила те же, что у MoveProductData.
+// Целевая зона должна быть проверена на принадлежность складу заранее
+func moveProductStock(tx *gorm.DB, productId string, warehouseId int, targetZoneId uint64, targetLocationId *uint64, quantity uint64, actorId string, serials []string) (*domain.Product, error) {
+	var product domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", productId).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	if err := checkZonesInWarehouse(tx, warehouseId, []uint64{product.ZoneId}); err != nil {
+		return nil, custom_errors.ErrProductNotFound
+	}
+
+	sameLocation := (product.LocationId == nil && targetLocationId == nil) ||
+		(product.LocationId != nil && targetLocationId != nil && *product.LocationId == *targetLocationId)
+	if product.ZoneId == targetZoneId && sameLocation {
+		return nil, custom_errors.ErrInvalidProductMove
+	}
+
+	if targetLocationId != nil {
+		if _, err := findBin(tx, targetZoneId, *targetLocationId); err != nil {
+			return nil, err
+		}
+	}
+
+	if quantity == 0 {
+		quantity = product.Count
+	}
+	if quantity == 0 || quantity > product.Count {
+		return nil, custom_errors.ErrInsufficientStock
+	}
+
+	// Резервы и блокировки привязаны к строке товара, поэтому при разделении строки занятая ими часть остается на месте
+	if quantity < product.Count {
+		reserved, err := reservedQuantity(tx, productId)
+		if err != nil {
+			return nil, err
+		}
+		held, err := heldQuantity(tx, productId)
+		if err != nil {
+			return nil, err
+		}
+		if reserved+held+quantity > product.Count {
+			return nil, custom_errors.ErrInsufficientAvailableStock
+		}
+	}
+
+	// Перенос между ячейками одной зоны заполненность зоны не меняет, вместимость ячеек проверяется при движении
+	if product.ZoneId != targetZoneId {
+		if err := checkZoneCapacity(tx, targetZoneId, product.SkuId, quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	serialTracked, err := productSerialTracked(tx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	var movedSerials []domain.SerialNumber
+	if serialTracked && quantity < product.Count {
+		if err := checkSerialList(serials, quantity); err != nil {
+			return nil, err
+		}
+
+		locked, err := lockStockSerials(tx, productId, serials)
+		if err != nil {
+			return nil, err
+		}
+		movedSerials = locked
+	} else if serialTracked {
+		err := tx.Where("product_uuid = ? AND status = ?", productId, domain.SerialStatusInStock).Find(&movedSerials).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sourceZoneId := product.ZoneId
+	target := product
+
+	if quantity == product.Count {
+		err := tx.Model(&domain.Product{}).Where("uuid = ?", productId).Updates(map[string]interface{}{
+			"zone_id":     targetZoneId,
+			"location_id": targetLocationId,
+		}).Error
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		target = domain.Product{
+			SkuId:          product.SkuId,
+			ZoneId:         targetZoneId,
+			LocationId:     targetLocationId,
+			LotNumber:      product.LotNumber,
+			ProductionDate: product.ProductionDate,
+			ExpiryDate:     product.ExpiryDate,
+		}
+		if err := tx.Create(&target).Error; err != nil {
+			return nil, err
+		}
+
+		if len(movedSerials) > 0 {
+			err := tx.Model(&domain.SerialNumber{}).
+				Where("id IN ?", serialIds(movedSerials)).
+				Update("product_uuid", string(target.Uuid)).Error
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Перемещение пишется парой движений, поэтому сумма по журналу товара не меняется
+	movements := []*domain.StockMovement{
+		{
+			ProductUuid:  productId,
+			Quantity:     -int64(quantity),
+			Reason:       domain.MovementReasonMove,
+			ActorUuid:    actorId,
+			SourceZoneId: &sourceZoneId,
+			TargetZoneId: &targetZoneId,
+		},
+		{
+			ProductUuid:  string(target.Uuid),
+			Quantity:     int64(quantity),
+			Reason:       domain.MovementReasonMove,
+			ActorUuid:    actorId,
+			SourceZoneId: &sourceZoneId,
+			TargetZoneId: &targetZoneId,
+		},
+	}
+	for _, movement := range movements {
+		if err := applyStockMovement(tx, movement); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := linkSerialMovement(tx, movedSerials, movements[1].Id); err != nil {
+		return nil, err
+	}
+
+	target.ZoneId = targetZoneId
+	target.LocationId = targetLocationId
+	target.Count = quantity
+
+	return &target, nil
+}
+
 func (pr *ProductPostgresRepository) DeleteProductData(in *domain.Product, userId string, warehouseId int) error {
 	var warehouse domain.WareHouse
 	if err := pr.db.GetDb().Where("id = ? AND uuid_user = ?", warehouseId, userId).First(&warehouse).Error; err != nil {
@@ -113,7 +444,7 @@ func (pr *ProductPostgresRepository) DeleteProductData(in *domain.Product, userI
 func (pr *ProductPostgresRepository) FindAllProductFromZoneData(userId string, zoneId int) (*[]domain.Product, error) {
 	var products []domain.Product
 
-	result := pr.db.GetDb().Model(&domain.Product{}).
+	result := pr.db.GetDb().Model(&domain.Product{}).Preload("Sku.Units", orderSkuUnits).
 		Joins("JOIN zones ON products.zone_id = zones.id").
 		Joins("JOIN ware_houses ON zones.ware_house_id = ware_houses.id").
 		Where("ware_houses.uuid_user = ? AND zones.id = ?", userId, zoneId).
@@ -129,7 +460,7 @@ func (pr *ProductPostgresRepository) FindAllProductFromZoneData(userId string, z
 func (pr *ProductPostgresRepository) FindAllProductFromWarehouseData(userId string, warehouseId int) (*[]domain.Product, error) {
 	var products []domain.Product
 
-	result := pr.db.GetDb().Model(&domain.Product{}).
+	result := pr.db.GetDb().Model(&domain.Product{}).Preload("Sku.Units", orderSkuUnits).
 		Joins("JOIN zones ON products.zone_id = zones.id").
 		Joins("JOIN ware_houses ON zones.ware_house_id = ware_houses.id").
 		Where("ware_houses.uuid_user = ? AND ware_houses.id = ?", userId, warehouseId).
@@ -144,7 +475,7 @@ func (pr *ProductPostgresRepository) FindAllProductFromWarehouseData(userId stri
 func (pr *ProductPostgresRepository) FindAllProductData(userId string) (*[]domain.Product, error) {
 	var products []domain.Product
 
-	if err := pr.db.GetDb().Model(&domain.Product{}).Joins("JOIN zones ON products.zone_id = zones.id").
+	if err := pr.db.GetDb().Model(&domain.Product{}).Preload("Sku").Joins("JOIN zones ON products.zone_id = zones.id").
 		Joins("JOIN ware_houses ON zones.ware_house_id = ware_houses.id").
 		Where("ware_houses.uuid_user = ?", userId).
 		Find(&products); err != nil {
@@ -157,7 +488,7 @@ func (pr *ProductPostgresRepository) FindAllProductData(userId string) (*[]domai
 func (pr *ProductPostgresRepository) FindProductData(userId string, productId string) (*domain.Product, error) {
 	var product domain.Product
 
-	if err := pr.db.GetDb().Model(&domain.Product{}).Debug().Joins("JOIN zones ON products.zone_id = zones.id").
+	if err := pr.db.GetDb().Model(&domain.Product{}).Debug().Preload("Sku.Units", orderSkuUnits).Joins("JOIN zones ON products.zone_id = zones.id").
 		Joins("JOIN ware_houses ON zones.ware_house_id = ware_houses.id").
 		Where("ware_houses.uuid_user = ? AND products.uuid = ?", userId, productId).
 		First(&product).Error; err != nil {
@@ -166,3 +497,38 @@ func (pr *ProductPostgresRepository) FindProductData(userId string, productId st
 
 	return &product, nil
 }
+
+// FindFefoCandidatesData возвращает партии товара с остатком в порядке FEFO: раньше истекающие первыми, партии без срока годности в конце
+func (pr *ProductPostgresRepository) FindFefoCandidatesData(userId string, warehouseId int, skuId uint64) (*[]domain.Product, error) {
+	var products []domain.Product
+
+	err := pr.db.GetDb().Model(&domain.Product{}).Preload("Sku").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Joins("JOIN ware_houses ON zones.ware_house_id = ware_houses.id").
+		Where("ware_houses.uuid_user = ? AND ware_houses.id = ? AND products.sku_id = ? AND products.count > 0", userId, warehouseId, skuId).
+		Order("products.expiry_date ASC NULLS LAST, products.production_date ASC NULLS LAST").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &products, nil
+}
+
+// FindExpiringProductData возвращает партии с остатком, срок годности которых истекает не позже until, включая уже истекшие
+func (pr *ProductPostgresRepository) FindExpiringProductData(userId string, warehouseId int, until time.Time) (*[]domain.Product, error) {
+	var products []domain.Product
+
+	err := pr.db.GetDb().Model(&domain.Product{}).Preload("Sku").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Joins("JOIN ware_houses ON zones.ware_house_id = ware_houses.id").
+		Where("ware_houses.uuid_user = ? AND ware_houses.id = ?", userId, warehouseId).
+		Where("products.expiry_date IS NOT NULL AND products.expiry_date <= ? AND products.count > 0", until).
+		Order("products.expiry_date").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &products, nil
+}
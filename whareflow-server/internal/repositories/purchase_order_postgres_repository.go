@@ -0,0 +1,302 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type PurchaseOrderRepository interface {
+	InsertPurchaseOrderData(in *domain.PurchaseOrder, userId string) error
+	UpdatePurchaseOrderData(in *domain.PurchaseOrder, userId string) error
+	ClosePurchaseOrderData(userId string, warehouseId int, orderId uint64) error
+	FindAllPurchaseOrderData(userId string, warehouseId int, statuses []string) (*[]domain.PurchaseOrder, error)
+	FindPurchaseOrderData(userId string, warehouseId int, orderId uint64) (*domain.PurchaseOrder, error)
+}
+
+type PurchaseOrderPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewPurchaseOrderPostgresRepository(db database.Database, logger slog.Logger) *PurchaseOrderPostgresRepository {
+	return &PurchaseOrderPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (pr *PurchaseOrderPostgresRepository) InsertPurchaseOrderData(in *domain.PurchaseOrder, userId string) error {
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := findSupplier(tx, userId, in.SupplierId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Omit("Supplier").Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdatePurchaseOrderData меняет поставщика, даты и строки заказа. Пока по заказу заведено хотя бы одно поступление,
+// строки не меняются: поступление ссылается на них
+func (pr *PurchaseOrderPostgresRepository) UpdatePurchaseOrderData(in *domain.PurchaseOrder, userId string) error {
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := lockPurchaseOrder(tx, userId, int(in.WarehouseId), in.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if order.Status != domain.PurchaseOrderStatusOpen {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var receipts int64
+	if err := tx.Model(&domain.Receipt{}).Where("purchase_order_id = ?", order.Id).Count(&receipts).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if receipts > 0 {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if _, err := findSupplier(tx, userId, in.SupplierId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Model(order).Updates(map[string]interface{}{
+		"supplier_id":   in.SupplierId,
+		"expected_date": in.ExpectedDate,
+		"comment":       in.Comment,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("order_id = ?", order.Id).Delete(&domain.PurchaseOrderLine{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range in.Lines {
+		in.Lines[i].Id = 0
+		in.Lines[i].OrderId = order.Id
+	}
+
+	if err := tx.Create(&in.Lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ClosePurchaseOrderData закрывает заказ вручную. Непоставленный остаток по строкам после этого считается недопоставкой
+func (pr *PurchaseOrderPostgresRepository) ClosePurchaseOrderData(userId string, warehouseId int, orderId uint64) error {
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := lockPurchaseOrder(tx, userId, warehouseId, orderId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if order.Status == domain.PurchaseOrderStatusClosed || order.Status == domain.PurchaseOrderStatusReceived {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	err = tx.Model(order).Updates(map[string]interface{}{
+		"status":    domain.PurchaseOrderStatusClosed,
+		"closed_at": time.Now(),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// FindAllPurchaseOrderData возвращает заказы склада. Если statuses не пустой, только в этих статусах,
+// по ближайшей ожидаемой дате поставки
+func (pr *PurchaseOrderPostgresRepository) FindAllPurchaseOrderData(userId string, warehouseId int, statuses []string) (*[]domain.PurchaseOrder, error) {
+	var orders []domain.PurchaseOrder
+
+	if err := checkWarehouseOwner(pr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	query := pr.db.GetDb().Preload("Supplier").Preload("Lines", orderPurchaseOrderLines).
+		Where("ware_house_id = ?", warehouseId)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses).Order("expected_date ASC NULLS LAST")
+	}
+
+	if err := query.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return &orders, nil
+}
+
+func (pr *PurchaseOrderPostgresRepository) FindPurchaseOrderData(userId string, warehouseId int, orderId uint64) (*domain.PurchaseOrder, error) {
+	var order domain.PurchaseOrder
+
+	if err := checkWarehouseOwner(pr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := pr.db.GetDb().Preload("Supplier").Preload("Lines", orderPurchaseOrderLines).
+		Where("id = ? AND ware_house_id = ?", orderId, warehouseId).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrPurchaseOrderNotFound
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func orderPurchaseOrderLines(db *gorm.DB) *gorm.DB {
+	return db.Order("purchase_order_lines.id")
+}
+
+// lockPurchaseOrder блокирует заказ до конца транзакции
+func lockPurchaseOrder(tx *gorm.DB, userId string, warehouseId int, orderId uint64) (*domain.PurchaseOrder, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var order domain.PurchaseOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", orderId, warehouseId).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrPurchaseOrderNotFound
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// checkPurchaseOrderLines проверяет строки поступления по заказу: заказ склада еще ожидает поставку,
+// а каждая строка ссылается на строку этого заказа с той же позицией
+func checkPurchaseOrderLines(tx *gorm.DB, warehouseId int, orderId uint64, lines []domain.ReceiptLine) error {
+	var order domain.PurchaseOrder
+	err := tx.Preload("Lines").
+		Where("id = ? AND ware_house_id = ?", orderId, warehouseId).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrPurchaseOrderNotFound
+		}
+		return err
+	}
+
+	if order.Status != domain.PurchaseOrderStatusOpen && order.Status != domain.PurchaseOrderStatusPartiallyReceived {
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	orderSkus := make(map[uint64]uint64, len(order.Lines))
+	for _, orderLine := range order.Lines {
+		orderSkus[orderLine.Id] = orderLine.SkuId
+	}
+
+	for _, line := range lines {
+		if line.PurchaseOrderLineId == nil {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+		skuId, ok := orderSkus[*line.PurchaseOrderLineId]
+		if !ok || skuId != line.SkuId {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+	}
+
+	return nil
+}
+
+// receivePurchaseOrderLines добавляет принятое поступлением количество к строкам заказа и пересчитывает его статус.
+// Закрытый вручную заказ остается закрытым, но принятое по нему количество учитывается
+func receivePurchaseOrderLines(tx *gorm.DB, orderId uint64, received map[uint64]uint64) error {
+	var order domain.PurchaseOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", orderId).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrPurchaseOrderNotFound
+		}
+		return err
+	}
+
+	for lineId, quantity := range received {
+		err := tx.Model(&domain.PurchaseOrderLine{}).
+			Where("id = ? AND order_id = ?", lineId, order.Id).
+			Update("received_quantity", gorm.Expr("received_quantity + ?", quantity)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if order.Status == domain.PurchaseOrderStatusClosed {
+		return nil
+	}
+
+	var lines []domain.PurchaseOrderLine
+	if err := tx.Where("order_id = ?", order.Id).Find(&lines).Error; err != nil {
+		return err
+	}
+
+	status := domain.PurchaseOrderStatusReceived
+	anyReceived := false
+	for _, line := range lines {
+		if line.ReceivedQuantity < line.Quantity {
+			status = domain.PurchaseOrderStatusPartiallyReceived
+		}
+		if line.ReceivedQuantity > 0 {
+			anyReceived = true
+		}
+	}
+	if !anyReceived {
+		status = domain.PurchaseOrderStatusOpen
+	}
+
+	return tx.Model(&order).Update("status", status).Error
+}
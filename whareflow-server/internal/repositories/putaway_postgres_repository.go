@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+type PutawayRepository interface {
+	InsertPutawayData(in *domain.Putaway, userId string, warehouseId int) error
+	FindPutawayLinesData(userId string, warehouseId int, receiptId uint64) (*[]domain.PutawayLine, error)
+	FindPutawayStockData(warehouseId int) (*[]domain.PutawayStock, error)
+}
+
+type PutawayPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewPutawayPostgresRepository(db database.Database, logger slog.Logger) *PutawayPostgresRepository {
+	return &PutawayPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// InsertPutawayData переносит принятое по строке проведенного поступления количество в зону и ячейку in
+// и записывает размещение. Строка размещается один раз, у серийного товара переносятся номера, принятые по строке.
+// Если товар оставляют там, где его приняли, переноса нет и размещение только фиксирует место
+func (pr *PutawayPostgresRepository) InsertPutawayData(in *domain.Putaway, userId string, warehouseId int) error {
+	tx := pr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var receipt domain.Receipt
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", in.ReceiptId, warehouseId).
+		First(&receipt).Error
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrReceiptNotFound
+		}
+		return err
+	}
+
+	if receipt.Status != domain.ReceiptStatusPosted {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var line domain.ReceiptLine
+	if err := tx.Where("id = ? AND receipt_id = ?", in.ReceiptLineId, receipt.Id).First(&line).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+		return err
+	}
+
+	if line.ReceivedQuantity == 0 || line.ProductUuid == nil {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentLine
+	}
+
+	var done int64
+	if err := tx.Model(&domain.Putaway{}).Where("receipt_line_id = ?", line.Id).Count(&done).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if done > 0 {
+		tx.Rollback()
+		return custom_errors.ErrPutawayDone
+	}
+
+	if err := checkZonesInWarehouse(tx, warehouseId, []uint64{in.ZoneId}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Строка товара могла существовать до поступления, поэтому переносятся только номера, принятые по этой строке
+	var serials []string
+	err = tx.Model(&domain.SerialNumber{}).
+		Where("receipt_line_id = ? AND product_uuid = ? AND status = ?", line.Id, *line.ProductUuid, domain.SerialStatusInStock).
+		Pluck("serial", &serials).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var product domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", *line.ProductUuid).First(&product).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrProductNotFound
+		}
+		return err
+	}
+
+	target := &product
+	leftInPlace := product.ZoneId == in.ZoneId &&
+		((product.LocationId == nil && in.LocationId == nil) ||
+			(product.LocationId != nil && in.LocationId != nil && *product.LocationId == *in.LocationId))
+	if !leftInPlace {
+		target, err = moveProductStock(tx, *line.ProductUuid, warehouseId, in.ZoneId, in.LocationId, line.ReceivedQuantity, in.CreatedBy, serials)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	targetUuid := string(target.Uuid)
+	in.ProductUuid = &targetUuid
+	in.Quantity = line.ReceivedQuantity
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// FindPutawayLinesData возвращает принятые строки поступления вместе с позициями каталога, строками товара
+// и сделанными размещениями. Черновик поступления еще ничего не принял и размещать по нему нечего
+func (pr *PutawayPostgresRepository) FindPutawayLinesData(userId string, warehouseId int, receiptId uint64) (*[]domain.PutawayLine, error) {
+	if err := checkWarehouseOwner(pr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var receipt domain.Receipt
+	if err := pr.db.GetDb().Where("id = ? AND ware_house_id = ?", receiptId, warehouseId).First(&receipt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrReceiptNotFound
+		}
+		return nil, err
+	}
+
+	if receipt.Status == domain.ReceiptStatusDraft {
+		return nil, custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.ReceiptLine
+	if err := pr.db.GetDb().Where("receipt_id = ? AND received_quantity > 0", receipt.Id).Order("id").Find(&lines).Error; err != nil {
+		return nil, err
+	}
+
+	putawayLines := []domain.PutawayLine{}
+	if len(lines) == 0 {
+		return &putawayLines, nil
+	}
+
+	var skuIds []uint64
+	var productUuids []string
+	var lineIds []uint64
+	for _, line := range lines {
+		skuIds = append(skuIds, line.SkuId)
+		lineIds = append(lineIds, line.Id)
+		if line.ProductUuid != nil {
+			productUuids = append(productUuids, *line.ProductUuid)
+		}
+	}
+
+	var skus []domain.Sku
+	if err := pr.db.GetDb().Where("id IN ?", skuIds).Find(&skus).Error; err != nil {
+		return nil, err
+	}
+	skusById := make(map[uint64]domain.Sku, len(skus))
+	for _, sku := range skus {
+		skusById[sku.Id] = sku
+	}
+
+	// До проведения строка может ссылаться на существующий товар, но принятое количество на него еще не пришло
+	productsByUuid := make(map[string]domain.Product)
+	if receipt.Status == domain.ReceiptStatusPosted && len(productUuids) > 0 {
+		var products []domain.Product
+		if err := pr.db.GetDb().Where("uuid IN ?", productUuids).Find(&products).Error; err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			productsByUuid[string(product.Uuid)] = product
+		}
+	}
+
+	var putaways []domain.Putaway
+	if err := pr.db.GetDb().Where("receipt_line_id IN ?", lineIds).Find(&putaways).Error; err != nil {
+		return nil, err
+	}
+	putawaysByLine := make(map[uint64]domain.Putaway, len(putaways))
+	for _, putaway := range putaways {
+		putawaysByLine[putaway.ReceiptLineId] = putaway
+	}
+
+	for _, line := range lines {
+		putawayLine := domain.PutawayLine{
+			Line: line,
+			Sku:  skusById[line.SkuId],
+		}
+		if line.ProductUuid != nil {
+			if product, ok := productsByUuid[*line.ProductUuid]; ok {
+				putawayLine.Product = &product
+			}
+		}
+		if putaway, ok := putawaysByLine[line.Id]; ok {
+			putawayLine.Putaway = &putaway
+		}
+
+		putawayLines = append(putawayLines, putawayLine)
+	}
+
+	return &putawayLines, nil
+}
+
+// FindPutawayStockData возвращает остатки склада по зонам, ячейкам и позициям
+func (pr *PutawayPostgresRepository) FindPutawayStockData(warehouseId int) (*[]domain.PutawayStock, error) {
+	var stock []domain.PutawayStock
+
+	err := pr.db.GetDb().Table("products").
+		Select("products.zone_id, products.location_id, products.sku_id, SUM(products.count) AS count").
+		Joins("JOIN zones ON zones.id = products.zone_id").
+		Where("zones.ware_house_id = ? AND products.count > 0", warehouseId).
+		Group("products.zone_id, products.location_id, products.sku_id").
+		Scan(&stock).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &stock, nil
+}
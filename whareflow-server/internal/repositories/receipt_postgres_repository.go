@@ -0,0 +1,441 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type ReceiptRepository interface {
+	InsertReceiptData(in *domain.Receipt, userId string) error
+	UpdateReceiptLinesData(in *domain.Receipt, userId string) error
+	FindAllReceiptData(userId string, warehouseId int) (*[]domain.Receipt, error)
+	FindReceiptData(userId string, warehouseId int, receiptId uint64) (*domain.Receipt, error)
+	ReceiveReceiptData(userId string, warehouseId int, receiptId uint64, received map[uint64]uint64, serials map[uint64][]string) error
+	PostReceiptData(userId string, warehouseId int, receiptId uint64, actorId string) (*[]domain.Product, error)
+}
+
+type ReceiptPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewReceiptPostgresRepository(db database.Database, logger slog.Logger) *ReceiptPostgresRepository {
+	return &ReceiptPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (rr *ReceiptPostgresRepository) InsertReceiptData(in *domain.Receipt, userId string) error {
+	tx := rr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := rr.checkReceiptLines(tx, int(in.WarehouseId), userId, in.PurchaseOrderId, in.Lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (rr *ReceiptPostgresRepository) UpdateReceiptLinesData(in *domain.Receipt, userId string) error {
+	tx := rr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	receipt, err := rr.lockReceipt(tx, userId, int(in.WarehouseId), in.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if receipt.Status != domain.ReceiptStatusDraft {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if err := rr.checkReceiptLines(tx, int(in.WarehouseId), userId, receipt.PurchaseOrderId, in.Lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(receipt).Update("comment", in.Comment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("receipt_id = ?", receipt.Id).Delete(&domain.ReceiptLine{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range in.Lines {
+		in.Lines[i].Id = 0
+		in.Lines[i].ReceiptId = receipt.Id
+	}
+
+	if len(in.Lines) > 0 {
+		if err := tx.Create(&in.Lines).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (rr *ReceiptPostgresRepository) FindAllReceiptData(userId string, warehouseId int) (*[]domain.Receipt, error) {
+	var receipts []domain.Receipt
+
+	if err := checkWarehouseOwner(rr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := rr.db.GetDb().Preload("Lines", orderReceiptLines).
+		Where("ware_house_id = ?", warehouseId).
+		Order("created_at DESC").
+		Find(&receipts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &receipts, nil
+}
+
+func (rr *ReceiptPostgresRepository) FindReceiptData(userId string, warehouseId int, receiptId uint64) (*domain.Receipt, error) {
+	var receipt domain.Receipt
+
+	if err := checkWarehouseOwner(rr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := rr.db.GetDb().Preload("Lines", orderReceiptLines).
+		Where("id = ? AND ware_house_id = ?", receiptId, warehouseId).
+		First(&receipt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrReceiptNotFound
+		}
+		return nil, err
+	}
+
+	return &receipt, nil
+}
+
+// ReceiveReceiptData фиксирует фактически принятое количество. Серийные номера регистрируются со статусом pending
+// и попадают на остаток только при проведении
+func (rr *ReceiptPostgresRepository) ReceiveReceiptData(userId string, warehouseId int, receiptId uint64, received map[uint64]uint64, serials map[uint64][]string) error {
+	tx := rr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	receipt, err := rr.lockReceipt(tx, userId, warehouseId, receiptId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if receipt.Status != domain.ReceiptStatusDraft {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.ReceiptLine
+	if err := tx.Where("receipt_id = ?", receipt.Id).Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(lines) == 0 {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentLine
+	}
+
+	for lineId := range received {
+		found := false
+		for _, line := range lines {
+			if line.Id == lineId {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tx.Rollback()
+			return custom_errors.ErrInvalidDocumentLine
+		}
+	}
+
+	// Если фактическое количество по строке не передано, считаем что пришло ожидаемое
+	for _, line := range lines {
+		quantity, ok := received[line.Id]
+		if !ok {
+			quantity = line.Quantity
+		}
+
+		if err := tx.Model(&domain.ReceiptLine{}).Where("id = ?", line.Id).Update("received_quantity", quantity).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		serialTracked, err := skuSerialTracked(tx, line.SkuId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if !serialTracked {
+			if len(serials[line.Id]) > 0 {
+				tx.Rollback()
+				return custom_errors.ErrSerialCountMismatch
+			}
+			continue
+		}
+
+		if err := checkSerialList(serials[line.Id], quantity); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		lineId := line.Id
+		serialNumbers := make([]domain.SerialNumber, 0, quantity)
+		for _, serial := range serials[line.Id] {
+			serialNumbers = append(serialNumbers, domain.SerialNumber{
+				Serial:        serial,
+				WarehouseId:   uint64(warehouseId),
+				Status:        domain.SerialStatusPending,
+				ReceiptLineId: &lineId,
+			})
+		}
+		if err := insertSerialNumbers(tx, userId, serialNumbers); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	now := time.Now()
+	err = tx.Model(receipt).Updates(map[string]interface{}{
+		"status":      domain.ReceiptStatusReceived,
+		"received_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// PostReceiptData приходует принятое количество. Для поступления по заказу поставщику принятое добавляется
+// к строкам заказа, включая перепоставку сверх заказанного
+func (rr *ReceiptPostgresRepository) PostReceiptData(userId string, warehouseId int, receiptId uint64, actorId string) (*[]domain.Product, error) {
+	tx := rr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	receipt, err := rr.lockReceipt(tx, userId, warehouseId, receiptId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if receipt.Status != domain.ReceiptStatusReceived {
+		tx.Rollback()
+		return nil, custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.ReceiptLine
+	if err := tx.Where("receipt_id = ?", receipt.Id).Order("id").Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	createdProducts := []domain.Product{}
+	orderReceived := make(map[uint64]uint64)
+	for _, line := range lines {
+		if line.ReceivedQuantity == 0 {
+			continue
+		}
+
+		if line.PurchaseOrderLineId != nil {
+			orderReceived[*line.PurchaseOrderLineId] += line.ReceivedQuantity
+		}
+
+		productUuid := line.ProductUuid
+		if productUuid == nil {
+			product := domain.Product{
+				SkuId:          line.SkuId,
+				ZoneId:         line.ZoneId,
+				LotNumber:      line.LotNumber,
+				ProductionDate: line.ProductionDate,
+				ExpiryDate:     line.ExpiryDate,
+			}
+			if err := tx.Create(&product).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+
+			createdUuid := string(product.Uuid)
+			productUuid = &createdUuid
+
+			if err := tx.Model(&domain.ReceiptLine{}).Where("id = ?", line.Id).Update("product_uuid", createdUuid).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+
+			product.Count = line.ReceivedQuantity
+			createdProducts = append(createdProducts, product)
+		}
+
+		zoneId := line.ZoneId
+		movement := &domain.StockMovement{
+			ProductUuid:  *productUuid,
+			Quantity:     int64(line.ReceivedQuantity),
+			Reason:       domain.MovementReasonReceipt,
+			ActorUuid:    actorId,
+			TargetZoneId: &zoneId,
+		}
+		if err := applyStockMovement(tx, movement); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		serialTracked, err := skuSerialTracked(tx, line.SkuId)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if serialTracked {
+			if err := rr.stockReceivedSerials(tx, line.Id, *productUuid, movement.Id); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	}
+
+	if receipt.PurchaseOrderId != nil {
+		if err := receivePurchaseOrderLines(tx, *receipt.PurchaseOrderId, orderReceived); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	err = tx.Model(receipt).Updates(map[string]interface{}{
+		"status":    domain.ReceiptStatusPosted,
+		"posted_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &createdProducts, nil
+}
+
+// stockReceivedSerials переводит номера, принятые по строке поступления, на остаток строки товара
+func (rr *ReceiptPostgresRepository) stockReceivedSerials(tx *gorm.DB, lineId uint64, productUuid string, movementId uint64) error {
+	var serials []domain.SerialNumber
+	if err := tx.Where("receipt_line_id = ? AND status = ?", lineId, domain.SerialStatusPending).Find(&serials).Error; err != nil {
+		return err
+	}
+
+	err := tx.Model(&domain.SerialNumber{}).
+		Where("id IN ?", serialIds(serials)).
+		Updates(map[string]interface{}{
+			"status":       domain.SerialStatusInStock,
+			"product_uuid": productUuid,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	return linkSerialMovement(tx, serials, movementId)
+}
+
+func orderReceiptLines(db *gorm.DB) *gorm.DB {
+	return db.Order("receipt_lines.id")
+}
+
+// lockReceipt блокирует документ до конца транзакции, чтобы его нельзя было провести дважды
+func (rr *ReceiptPostgresRepository) lockReceipt(tx *gorm.DB, userId string, warehouseId int, receiptId uint64) (*domain.Receipt, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var receipt domain.Receipt
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", receiptId, warehouseId).
+		First(&receipt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrReceiptNotFound
+		}
+		return nil, err
+	}
+
+	return &receipt, nil
+}
+
+// checkReceiptLines проверяет зоны и товары строк. Строки поступления по заказу сверяются с заказом purchaseOrderId,
+// строки обычного поступления не могут ссылаться на заказ
+func (rr *ReceiptPostgresRepository) checkReceiptLines(tx *gorm.DB, warehouseId int, userId string, purchaseOrderId *uint64, lines []domain.ReceiptLine) error {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return err
+	}
+
+	if purchaseOrderId != nil {
+		if err := checkPurchaseOrderLines(tx, warehouseId, *purchaseOrderId, lines); err != nil {
+			return err
+		}
+	} else {
+		for _, line := range lines {
+			if line.PurchaseOrderLineId != nil {
+				return custom_errors.ErrInvalidDocumentLine
+			}
+		}
+	}
+
+	var zoneIds []uint64
+	var productIds []string
+	for _, line := range lines {
+		zoneIds = append(zoneIds, line.ZoneId)
+		if line.ProductUuid != nil {
+			productIds = append(productIds, *line.ProductUuid)
+		}
+	}
+
+	if err := checkZonesInWarehouse(tx, warehouseId, zoneIds); err != nil {
+		return err
+	}
+
+	return checkProductsInWarehouse(tx, warehouseId, productIds)
+}
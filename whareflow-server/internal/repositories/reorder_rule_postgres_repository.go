@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+type ReorderRuleRepository interface {
+	UpsertReorderRuleData(in *domain.ReorderRule, userId string) error
+	FindAllReorderRuleData(userId string, warehouseId int) (*[]domain.ReorderRule, error)
+	DeleteReorderRuleData(userId string, warehouseId int, ruleId uint64) error
+	FindLowStockData(userId string, warehouseId int) (*[]domain.StockLevel, error)
+	EvaluateReorderRulesData(warehouseId int, skuIds []uint64) (*[]domain.StockLevel, error)
+	ClaimReorderAlertData(ruleId uint64) (bool, error)
+	ReleaseReorderAlertData(ruleId uint64) error
+}
+
+type ReorderRulePostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewReorderRulePostgresRepository(db database.Database, logger slog.Logger) *ReorderRulePostgresRepository {
+	return &ReorderRulePostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// UpsertReorderRuleData задает уровни позиции на складе. Правило на позицию одно, повторный вызов меняет уровни
+func (rr *ReorderRulePostgresRepository) UpsertReorderRuleData(in *domain.ReorderRule, userId string) error {
+	tx := rr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := findSku(tx, userId, in.SkuId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ware_house_id"}, {Name: "sku_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min_level", "max_level"}),
+	}).Create(in).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (rr *ReorderRulePostgresRepository) FindAllReorderRuleData(userId string, warehouseId int) (*[]domain.ReorderRule, error) {
+	var rules []domain.ReorderRule
+
+	if err := checkWarehouseOwner(rr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := rr.db.GetDb().Where("ware_house_id = ?", warehouseId).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+func (rr *ReorderRulePostgresRepository) DeleteReorderRuleData(userId string, warehouseId int, ruleId uint64) error {
+	if err := checkWarehouseOwner(rr.db.GetDb(), warehouseId, userId); err != nil {
+		return err
+	}
+
+	result := rr.db.GetDb().Where("id = ? AND ware_house_id = ?", ruleId, warehouseId).Delete(&domain.ReorderRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return custom_errors.ErrReorderRuleNotFound
+	}
+
+	return nil
+}
+
+// FindLowStockData возвращает позиции склада, остаток которых опустился ниже точки перезаказа
+func (rr *ReorderRulePostgresRepository) FindLowStockData(userId string, warehouseId int) (*[]domain.StockLevel, error) {
+	if err := checkWarehouseOwner(rr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var levels []domain.StockLevel
+	err := stockLevelQuery(rr.db.GetDb(), warehouseId).
+		Where("reorder_rules.min_level > (?)", onHandSubquery(rr.db.GetDb())).
+		Order("skus.code").
+		Scan(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &levels, nil
+}
+
+// EvaluateReorderRulesData сверяет остатки с правилами склада (skuIds пустой - все правила) и возвращает те,
+// по которым остаток ниже точки перезаказа, а оповещение еще не отправлено. Отметку об оповещении ставит
+// ClaimReorderAlertData перед отправкой, а снимает эта проверка, когда остаток снова достигает min_level
+func (rr *ReorderRulePostgresRepository) EvaluateReorderRulesData(warehouseId int, skuIds []uint64) (*[]domain.StockLevel, error) {
+	tx := rr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	query := stockLevelQuery(tx, warehouseId).Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "reorder_rules"}})
+	if len(skuIds) > 0 {
+		query = query.Where("reorder_rules.sku_id IN ?", skuIds)
+	}
+
+	var levels []domain.StockLevel
+	if err := query.Scan(&levels).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	crossed := []domain.StockLevel{}
+	var restored []uint64
+	for _, level := range levels {
+		switch {
+		case level.OnHand < level.MinLevel && level.AlertedAt == nil:
+			crossed = append(crossed, level)
+		case level.OnHand >= level.MinLevel && level.AlertedAt != nil:
+			restored = append(restored, level.RuleId)
+		}
+	}
+
+	if len(restored) > 0 {
+		if err := tx.Model(&domain.ReorderRule{}).Where("id IN ?", restored).Update("alerted_at", nil).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &crossed, nil
+}
+
+// ClaimReorderAlertData отмечает правило как оповещенное одним запросом. Из параллельных проверок, увидевших
+// одно и то же падение остатка, отметку получает только одна, и только она отправляет оповещение
+func (rr *ReorderRulePostgresRepository) ClaimReorderAlertData(ruleId uint64) (bool, error) {
+	result := rr.db.GetDb().Model(&domain.ReorderRule{}).
+		Where("id = ? AND alerted_at IS NULL", ruleId).
+		Update("alerted_at", gorm.Expr("now()"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseReorderAlertData снимает отметку, если оповещение отправить не удалось, чтобы следующая проверка его повторила
+func (rr *ReorderRulePostgresRepository) ReleaseReorderAlertData(ruleId uint64) error {
+	return rr.db.GetDb().Model(&domain.ReorderRule{}).
+		Where("id = ?", ruleId).
+		Update("alerted_at", nil).Error
+}
+
+// stockLevelQuery выбирает правила склада вместе с суммарным остатком позиции по всем зонам склада
+func stockLevelQuery(db *gorm.DB, warehouseId int) *gorm.DB {
+	return db.Table("reorder_rules").
+		Select("reorder_rules.id AS rule_id, reorder_rules.ware_house_id, reorder_rules.sku_id, "+
+			"skus.code AS sku_code, skus.name AS sku_name, skus.unit, skus.decimals, reorder_rules.min_level, reorder_rules.max_level, "+
+			"reorder_rules.alerted_at, (?) AS on_hand", onHandSubquery(db)).
+		Joins("JOIN skus ON reorder_rules.sku_id = skus.id").
+		Where("reorder_rules.ware_house_id = ?", warehouseId)
+}
+
+func onHandSubquery(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Table("products").
+		Select("COALESCE(SUM(products.count), 0)").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Where("zones.ware_house_id = reorder_rules.ware_house_id AND products.sku_id = reorder_rules.sku_id")
+}
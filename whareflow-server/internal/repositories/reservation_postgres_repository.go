@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type ReservationRepository interface {
+	InsertReservationData(in *domain.Reservation, userId string, warehouseId int) error
+	ReleaseReservationData(userId string, warehouseId int, reservationId uint64) error
+	FindAllReservationData(userId string, warehouseId int, productId string) (*[]domain.Reservation, error)
+	FindReservedQuantityData(productIds []string) (map[string]uint64, error)
+	ReleaseExpiredReservationsData() (int64, error)
+}
+
+type ReservationPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewReservationPostgresRepository(db database.Database, logger slog.Logger) *ReservationPostgresRepository {
+	return &ReservationPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (rr *ReservationPostgresRepository) InsertReservationData(in *domain.Reservation, userId string, warehouseId int) error {
+	tx := rr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkProductsInWarehouse(tx, warehouseId, []string{in.ProductUuid}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := reserveStock(tx, in); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (rr *ReservationPostgresRepository) ReleaseReservationData(userId string, warehouseId int, reservationId uint64) error {
+	if err := checkWarehouseOwner(rr.db.GetDb(), warehouseId, userId); err != nil {
+		return err
+	}
+
+	result := rr.db.GetDb().Model(&domain.Reservation{}).
+		Where("id = ? AND status = ?", reservationId, domain.ReservationStatusActive).
+		Where("product_uuid IN (?)", rr.db.GetDb().Model(&domain.Product{}).
+			Select("products.uuid").
+			Joins("JOIN zones ON products.zone_id = zones.id").
+			Where("zones.ware_house_id = ?", warehouseId)).
+		Updates(map[string]interface{}{
+			"status":      domain.ReservationStatusReleased,
+			"released_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return custom_errors.ErrReservationNotFound
+	}
+
+	return nil
+}
+
+// FindAllReservationData возвращает действующие резервы склада. Если productId не пустой, только по этому товару
+func (rr *ReservationPostgresRepository) FindAllReservationData(userId string, warehouseId int, productId string) (*[]domain.Reservation, error) {
+	var reservations []domain.Reservation
+
+	if err := checkWarehouseOwner(rr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	query := rr.db.GetDb().Model(&domain.Reservation{}).
+		Joins("JOIN products ON reservations.product_uuid = products.uuid").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Where("zones.ware_house_id = ?", warehouseId).
+		Scopes(activeReservations)
+	if productId != "" {
+		query = query.Where("reservations.product_uuid = ?", productId)
+	}
+
+	if err := query.Order("reservations.expires_at").Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+
+	return &reservations, nil
+}
+
+// FindReservedQuantityData возвращает зарезервированное количество по товарам. Товаров без резерва в ответе нет
+func (rr *ReservationPostgresRepository) FindReservedQuantityData(productIds []string) (map[string]uint64, error) {
+	reserved := make(map[string]uint64, len(productIds))
+	if len(productIds) == 0 {
+		return reserved, nil
+	}
+
+	var rows []struct {
+		ProductUuid string
+		Reserved    uint64
+	}
+
+	err := rr.db.GetDb().Model(&domain.Reservation{}).
+		Select("reservations.product_uuid, SUM(reservations.quantity) AS reserved").
+		Where("reservations.product_uuid IN ?", productIds).
+		Scopes(activeReservations).
+		Group("reservations.product_uuid").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		reserved[row.ProductUuid] = row.Reserved
+	}
+
+	return reserved, nil
+}
+
+// ReleaseExpiredReservationsData переводит истекшие резервы в статус expired и возвращает их количество.
+// Черновики заказов, резерв которых истек, переходят в статус expired. Шаги идут отдельными запросами,
+// чтобы не держать блокировки резервов, пока ждем блокировку заказа, который в это время подтверждают
+func (rr *ReservationPostgresRepository) ReleaseExpiredReservationsData() (int64, error) {
+	result := rr.db.GetDb().Model(&domain.Reservation{}).
+		Where("status = ? AND expires_at <= now()", domain.ReservationStatusActive).
+		Updates(map[string]interface{}{
+			"status":      domain.ReservationStatusExpired,
+			"released_at": time.Now(),
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	err := rr.db.GetDb().Model(&domain.SalesOrder{}).
+		Where("status = ?", domain.SalesOrderStatusDraft).
+		Where("id IN (?)", rr.db.GetDb().Model(&domain.SalesOrderLine{}).
+			Select("sales_order_lines.order_id").
+			Joins("JOIN reservations ON sales_order_lines.reservation_id = reservations.id").
+			Where("reservations.status = ?", domain.ReservationStatusExpired)).
+		Update("status", domain.SalesOrderStatusExpired).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+// activeReservations оставляет только действующие резервы. Истекший резерв не учитывается,
+// даже если фоновая очистка еще не успела перевести его в статус expired. Резерв без срока не истекает
+func activeReservations(db *gorm.DB) *gorm.DB {
+	return db.Where("reservations.status = ? AND (reservations.expires_at IS NULL OR reservations.expires_at > now())", domain.ReservationStatusActive)
+}
+
+// reserveStock создает резерв внутри транзакции, если свободного остатка товара хватает
+func reserveStock(tx *gorm.DB, in *domain.Reservation) error {
+	// Блокировка строки товара не дает двум резервам одновременно занять один и тот же остаток
+	var product domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", in.ProductUuid).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrProductNotFound
+		}
+		return err
+	}
+
+	reserved, err := reservedQuantity(tx, in.ProductUuid)
+	if err != nil {
+		return err
+	}
+
+	// Заблокированный остаток зарезервировать нельзя
+	held, err := heldQuantity(tx, in.ProductUuid)
+	if err != nil {
+		return err
+	}
+
+	if reserved+held+in.Quantity > product.Count {
+		return custom_errors.ErrInsufficientAvailableStock
+	}
+
+	return tx.Create(in).Error
+}
+
+// reservedQuantity считает действующий резерв товара внутри транзакции
+func reservedQuantity(tx *gorm.DB, productId string) (uint64, error) {
+	var reserved uint64
+	err := tx.Model(&domain.Reservation{}).
+		Where("reservations.product_uuid = ?", productId).
+		Scopes(activeReservations).
+		Select("COALESCE(SUM(reservations.quantity), 0)").
+		Scan(&reserved).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return reserved, nil
+}
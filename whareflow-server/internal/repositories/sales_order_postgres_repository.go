@@ -0,0 +1,599 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type SalesOrderRepository interface {
+	InsertSalesOrderData(in *domain.SalesOrder, userId string) error
+	ConfirmSalesOrderData(userId string, warehouseId int, orderId uint64, actorId string) error
+	CancelSalesOrderData(userId string, warehouseId int, orderId uint64) error
+	FindAllSalesOrderData(userId string, warehouseId int) (*[]domain.SalesOrder, error)
+	FindSalesOrderData(userId string, warehouseId int, orderId uint64) (*domain.SalesOrder, error)
+	FindAllPickTaskData(userId string, warehouseId int, orderId uint64) (*[]domain.PickTask, error)
+	FindPickTaskData(userId string, warehouseId int, taskId uint64) (*domain.PickTask, error)
+	CompletePickTaskData(userId string, warehouseId int, taskId uint64, picked uint64, actorId string) error
+	FindPickersData(warehouseId int) (*[]domain.User, error)
+}
+
+type SalesOrderPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewSalesOrderPostgresRepository(db database.Database, logger slog.Logger) *SalesOrderPostgresRepository {
+	return &SalesOrderPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// InsertSalesOrderData создает черновик заказа и резервирует товар под каждую строку. Если свободного остатка
+// хотя бы по одной строке не хватает, заказ не создается
+func (sr *SalesOrderPostgresRepository) InsertSalesOrderData(in *domain.SalesOrder, userId string) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := findCustomer(tx, userId, in.CustomerId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var productIds []string
+	for _, line := range in.Lines {
+		productIds = append(productIds, line.ProductUuid)
+	}
+
+	if err := checkProductsInWarehouse(tx, int(in.WarehouseId), productIds); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Omit("Customer").Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Строки товаров блокируются в одном порядке, чтобы параллельные заказы не ловили deadlock
+	lines := make([]*domain.SalesOrderLine, 0, len(in.Lines))
+	for i := range in.Lines {
+		lines = append(lines, &in.Lines[i])
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].ProductUuid < lines[j].ProductUuid
+	})
+
+	for _, line := range lines {
+		reservation := &domain.Reservation{
+			ProductUuid: line.ProductUuid,
+			Quantity:    line.Quantity,
+			OwnerRef:    salesOrderRef(in.Id),
+			Status:      domain.ReservationStatusActive,
+			ExpiresAt:   &in.ReservedUntil,
+			CreatedBy:   in.CreatedBy,
+		}
+		if err := reserveStock(tx, reservation); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		line.ReservationId = &reservation.Id
+		if err := tx.Model(line).Update("reservation_id", reservation.Id).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// ConfirmSalesOrderData заводит по заказу отгрузку в статусе picking и задание на сборку для каждой ее строки.
+// Подтвердить можно только заказ, резервы которого еще действуют
+func (sr *SalesOrderPostgresRepository) ConfirmSalesOrderData(userId string, warehouseId int, orderId uint64, actorId string) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := lockSalesOrder(tx, userId, warehouseId, orderId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if order.Status != domain.SalesOrderStatusDraft {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.SalesOrderLine
+	if err := tx.Where("order_id = ?", order.Id).Order("id").Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var reservationIds []uint64
+	for _, line := range lines {
+		if line.ReservationId == nil {
+			tx.Rollback()
+			return custom_errors.ErrReservationNotFound
+		}
+		reservationIds = append(reservationIds, *line.ReservationId)
+	}
+
+	// Резервы подтвержденного заказа не истекают: их снимет отгрузка или отмена заказа.
+	// Если какой-то резерв уже истек, подтверждать заказ нельзя
+	result := tx.Model(&domain.Reservation{}).
+		Where("reservations.id IN ?", reservationIds).
+		Scopes(activeReservations).
+		Update("expires_at", nil)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if int(result.RowsAffected) != len(lines) {
+		tx.Rollback()
+		return custom_errors.ErrReservationNotFound
+	}
+
+	products := make(map[string]domain.Product, len(lines))
+	for _, line := range lines {
+		var product domain.Product
+		if err := tx.Where("uuid = ?", line.ProductUuid).First(&product).Error; err != nil {
+			tx.Rollback()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return custom_errors.ErrProductNotFound
+			}
+			return err
+		}
+		products[line.ProductUuid] = product
+	}
+
+	shipment := &domain.Shipment{
+		WarehouseId: order.WarehouseId,
+		Status:      domain.ShipmentStatusPicking,
+		Comment:     order.Comment,
+		CreatedBy:   actorId,
+	}
+	for _, line := range lines {
+		shipment.Lines = append(shipment.Lines, domain.ShipmentLine{
+			ProductUuid: line.ProductUuid,
+			SkuId:       products[line.ProductUuid].SkuId,
+			Quantity:    line.Quantity,
+			Unit:        line.Unit,
+			UnitFactor:  line.UnitFactor,
+		})
+	}
+
+	if err := tx.Create(shipment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, shipmentLine := range shipment.Lines {
+		product := products[shipmentLine.ProductUuid]
+		shipmentLineId := shipmentLine.Id
+		task := &domain.PickTask{
+			WarehouseId:    order.WarehouseId,
+			OrderId:        order.Id,
+			ShipmentLineId: &shipmentLineId,
+			ProductUuid:    shipmentLine.ProductUuid,
+			ZoneId:         product.ZoneId,
+			LocationId:     product.LocationId,
+			Quantity:       shipmentLine.Quantity,
+			Unit:           shipmentLine.Unit,
+			UnitFactor:     shipmentLine.UnitFactor,
+			Status:         domain.PickTaskStatusOpen,
+		}
+		if err := tx.Create(task).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Model(order).Updates(map[string]interface{}{
+		"status":       domain.SalesOrderStatusConfirmed,
+		"shipment_id":  shipment.Id,
+		"confirmed_at": time.Now(),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CancelSalesOrderData отменяет заказ до упаковки: снимает резервы, отменяет невыполненные задания на сборку
+// и удаляет отгрузку заказа, которая еще не была упакована
+func (sr *SalesOrderPostgresRepository) CancelSalesOrderData(userId string, warehouseId int, orderId uint64) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := lockSalesOrder(tx, userId, warehouseId, orderId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	switch order.Status {
+	case domain.SalesOrderStatusDraft, domain.SalesOrderStatusConfirmed, domain.SalesOrderStatusPicking:
+	default:
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if err := releaseSalesOrderReservations(tx, order.Id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var listIds []uint64
+	err = tx.Model(&domain.PickTask{}).
+		Where("order_id = ? AND status = ? AND pick_list_id IS NOT NULL", order.Id, domain.PickTaskStatusOpen).
+		Distinct().Pluck("pick_list_id", &listIds).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Model(&domain.PickTask{}).
+		Where("order_id = ? AND status = ?", order.Id, domain.PickTaskStatusOpen).
+		Update("status", domain.PickTaskStatusCancelled).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Листы сборки, в которых остались только задания отмененного заказа, закрываются
+	for _, listId := range listIds {
+		if err := closePickList(tx, listId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if order.ShipmentId != nil {
+		var shipment domain.Shipment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", *order.ShipmentId).First(&shipment).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			tx.Rollback()
+			return err
+		}
+		if err == nil {
+			if shipment.Status != domain.ShipmentStatusPicking {
+				tx.Rollback()
+				return custom_errors.ErrInvalidDocumentStatus
+			}
+			if err := tx.Delete(&shipment).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	err = tx.Model(order).Updates(map[string]interface{}{
+		"status":       domain.SalesOrderStatusCancelled,
+		"shipment_id":  nil,
+		"cancelled_at": time.Now(),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (sr *SalesOrderPostgresRepository) FindAllSalesOrderData(userId string, warehouseId int) (*[]domain.SalesOrder, error) {
+	var orders []domain.SalesOrder
+
+	if err := checkWarehouseOwner(sr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := sr.db.GetDb().Preload("Customer").Preload("Lines", orderSalesOrderLines).
+		Where("ware_house_id = ?", warehouseId).
+		Order("created_at DESC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &orders, nil
+}
+
+func (sr *SalesOrderPostgresRepository) FindSalesOrderData(userId string, warehouseId int, orderId uint64) (*domain.SalesOrder, error) {
+	var order domain.SalesOrder
+
+	if err := checkWarehouseOwner(sr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := sr.db.GetDb().Preload("Customer").Preload("Lines", orderSalesOrderLines).
+		Where("id = ? AND ware_house_id = ?", orderId, warehouseId).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrSalesOrderNotFound
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// FindAllPickTaskData возвращает задания на сборку склада. Если orderId не 0 - все задания этого заказа,
+// иначе только невыполненные, сгруппированные по зонам и ячейкам
+func (sr *SalesOrderPostgresRepository) FindAllPickTaskData(userId string, warehouseId int, orderId uint64) (*[]domain.PickTask, error) {
+	var tasks []domain.PickTask
+
+	if err := checkWarehouseOwner(sr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	query := sr.db.GetDb().Where("ware_house_id = ?", warehouseId)
+	if orderId != 0 {
+		query = query.Where("order_id = ?", orderId).Order("id")
+	} else {
+		query = query.Where("status = ? AND wave_id IS NULL", domain.PickTaskStatusOpen).
+			Order("zone_id").Order("location_id NULLS LAST").Order("id")
+	}
+
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return &tasks, nil
+}
+
+func (sr *SalesOrderPostgresRepository) FindPickTaskData(userId string, warehouseId int, taskId uint64) (*domain.PickTask, error) {
+	var task domain.PickTask
+
+	if err := checkWarehouseOwner(sr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := sr.db.GetDb().Where("id = ? AND ware_house_id = ?", taskId, warehouseId).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrPickTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// CompletePickTaskData фиксирует собранное по заданию количество в строке отгрузки заказа.
+// Первое выполненное задание переводит заказ в статус picking
+func (sr *SalesOrderPostgresRepository) CompletePickTaskData(userId string, warehouseId int, taskId uint64, picked uint64, actorId string) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var task domain.PickTask
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", taskId, warehouseId).
+		First(&task).Error
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrPickTaskNotFound
+		}
+		return err
+	}
+
+	// Задания волны закрываются раскладкой на стене сортировки
+	if task.Status != domain.PickTaskStatusOpen || task.ShipmentLineId == nil || task.WaveId != nil {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if picked > task.Quantity {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentLine
+	}
+
+	if err := recordPickedQuantity(tx, &task, picked, actorId, true); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// recordPickedQuantity фиксирует собранное по заданию количество в задании и строке отгрузки заказа. done закрывает
+// задание и его лист сборки, первое собранное по заказу количество переводит заказ в статус picking
+func recordPickedQuantity(tx *gorm.DB, task *domain.PickTask, picked uint64, actorId string, done bool) error {
+	var order domain.SalesOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", task.OrderId).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrSalesOrderNotFound
+		}
+		return err
+	}
+
+	if order.Status != domain.SalesOrderStatusConfirmed && order.Status != domain.SalesOrderStatusPicking {
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	updates := map[string]interface{}{
+		"picked_quantity": picked,
+		"picked_by":       actorId,
+	}
+	if done {
+		updates["status"] = domain.PickTaskStatusDone
+		updates["done_at"] = time.Now()
+	}
+
+	if err := tx.Model(task).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&domain.ShipmentLine{}).Where("id = ?", *task.ShipmentLineId).Update("picked_quantity", picked).Error; err != nil {
+		return err
+	}
+
+	if done && task.PickListId != nil {
+		if err := closePickList(tx, *task.PickListId); err != nil {
+			return err
+		}
+	}
+
+	if order.Status == domain.SalesOrderStatusConfirmed {
+		return tx.Model(&order).Update("status", domain.SalesOrderStatusPicking).Error
+	}
+
+	return nil
+}
+
+// FindPickersData возвращает работников, которым выдано право product_manage на склад, - исполнителей заданий на сборку
+func (sr *SalesOrderPostgresRepository) FindPickersData(warehouseId int) (*[]domain.User, error) {
+	var users []domain.User
+
+	err := sr.db.GetDb().Model(&domain.User{}).
+		Distinct("users.uuid", "users.username", "users.email").
+		Joins("JOIN warehouse_user_roles ON warehouse_user_roles.user_id = users.uuid").
+		Joins("JOIN role_permissions ON role_permissions.role_id = warehouse_user_roles.role_id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("warehouse_user_roles.ware_house_id = ?", warehouseId).
+		Where("permissions.name = ?", "product_manage").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &users, nil
+}
+
+func orderSalesOrderLines(db *gorm.DB) *gorm.DB {
+	return db.Order("sales_order_lines.id")
+}
+
+// salesOrderRef - OwnerRef резервов заказа
+func salesOrderRef(orderId uint64) string {
+	return fmt.Sprintf("sales_order:%d", orderId)
+}
+
+// lockSalesOrder блокирует заказ до конца транзакции
+func lockSalesOrder(tx *gorm.DB, userId string, warehouseId int, orderId uint64) (*domain.SalesOrder, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var order domain.SalesOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", orderId, warehouseId).
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrSalesOrderNotFound
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// releaseSalesOrderReservations снимает действующие резервы строк заказа
+func releaseSalesOrderReservations(tx *gorm.DB, orderId uint64) error {
+	return tx.Model(&domain.Reservation{}).
+		Where("id IN (?)", tx.Model(&domain.SalesOrderLine{}).Select("reservation_id").Where("order_id = ?", orderId)).
+		Where("status = ?", domain.ReservationStatusActive).
+		Updates(map[string]interface{}{
+			"status":      domain.ReservationStatusReleased,
+			"released_at": time.Now(),
+		}).Error
+}
+
+// salesOrderPickedLines возвращает строки отгрузки, собранные по заданиям заказа. Пока хотя бы одно задание
+// не выполнено, отгрузку упаковывать нельзя. У отгрузки без заказа заданий нет
+func salesOrderPickedLines(tx *gorm.DB, shipmentId uint64) (map[uint64]struct{}, error) {
+	var tasks []domain.PickTask
+	err := tx.Where("shipment_line_id IN (?)", tx.Model(&domain.ShipmentLine{}).Select("id").Where("shipment_id = ?", shipmentId)).
+		Where("status <> ?", domain.PickTaskStatusCancelled).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	picked := make(map[uint64]struct{}, len(tasks))
+	for _, task := range tasks {
+		if task.Status == domain.PickTaskStatusOpen {
+			return nil, custom_errors.ErrInvalidDocumentStatus
+		}
+		picked[*task.ShipmentLineId] = struct{}{}
+	}
+
+	return picked, nil
+}
+
+// isSalesOrderShipment сообщает, заведена ли отгрузка по заказу покупателя
+func isSalesOrderShipment(tx *gorm.DB, shipmentId uint64) (bool, error) {
+	var count int64
+	if err := tx.Model(&domain.SalesOrder{}).Where("shipment_id = ?", shipmentId).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// packSalesOrder переводит заказ упакованной отгрузки в статус packed
+func packSalesOrder(tx *gorm.DB, shipmentId uint64) error {
+	return tx.Model(&domain.SalesOrder{}).
+		Where("shipment_id = ? AND status IN ?", shipmentId, []string{domain.SalesOrderStatusConfirmed, domain.SalesOrderStatusPicking}).
+		Update("status", domain.SalesOrderStatusPacked).Error
+}
+
+// shipSalesOrder закрывает заказ отгружаемой отгрузки и снимает его резервы: товар списывается той же транзакцией
+func shipSalesOrder(tx *gorm.DB, shipmentId uint64) error {
+	var order domain.SalesOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("shipment_id = ?", shipmentId).First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := releaseSalesOrderReservations(tx, order.Id); err != nil {
+		return err
+	}
+
+	return tx.Model(&order).Updates(map[string]interface{}{
+		"status":     domain.SalesOrderStatusShipped,
+		"shipped_at": time.Now(),
+	}).Error
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+type SerialNumberRepository interface {
+	FindSerialData(userId string, warehouseId int, serial string) (*[]domain.SerialNumber, error)
+	FindAllSerialFromProductData(userId string, productId string) (*[]domain.SerialNumber, error)
+	FindAllSerialWithoutQrData(warehouseId int) (*[]domain.SerialNumber, error)
+	FindSerialMovementsData(serialIds []uint64) (*[]domain.StockMovement, error)
+	UpdateSerialQrData(serialId uint64, qrPath string) error
+}
+
+type SerialNumberPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewSerialNumberPostgresRepository(db database.Database, logger slog.Logger) *SerialNumberPostgresRepository {
+	return &SerialNumberPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// FindSerialData возвращает все записи номера на складе: номер мог быть выдан и принят снова
+func (sr *SerialNumberPostgresRepository) FindSerialData(userId string, warehouseId int, serial string) (*[]domain.SerialNumber, error) {
+	if err := checkWarehouseOwner(sr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var serials []domain.SerialNumber
+	err := sr.db.GetDb().
+		Where("ware_house_id = ? AND serial = ?", warehouseId, serial).
+		Order("id").
+		Find(&serials).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(serials) == 0 {
+		return nil, custom_errors.ErrSerialNotFound
+	}
+
+	return &serials, nil
+}
+
+func (sr *SerialNumberPostgresRepository) FindAllSerialFromProductData(userId string, productId string) (*[]domain.SerialNumber, error) {
+	var serials []domain.SerialNumber
+
+	err := sr.db.GetDb().Model(&domain.SerialNumber{}).
+		Joins("JOIN ware_houses ON serial_numbers.ware_house_id = ware_houses.id").
+		Where("ware_houses.uuid_user = ? AND serial_numbers.product_uuid = ? AND serial_numbers.status = ?", userId, productId, domain.SerialStatusInStock).
+		Order("serial_numbers.serial").
+		Find(&serials).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &serials, nil
+}
+
+// FindAllSerialWithoutQrData возвращает номера на остатке склада, для которых еще не создан QR-код
+func (sr *SerialNumberPostgresRepository) FindAllSerialWithoutQrData(warehouseId int) (*[]domain.SerialNumber, error) {
+	var serials []domain.SerialNumber
+
+	err := sr.db.GetDb().
+		Where("ware_house_id = ? AND status = ? AND qr = ''", warehouseId, domain.SerialStatusInStock).
+		Order("id").
+		Find(&serials).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &serials, nil
+}
+
+func (sr *SerialNumberPostgresRepository) FindSerialMovementsData(serialIds []uint64) (*[]domain.StockMovement, error) {
+	var movements []domain.StockMovement
+
+	err := sr.db.GetDb().Model(&domain.StockMovement{}).
+		Joins("JOIN serial_movements ON serial_movements.stock_movement_id = stock_movements.id").
+		Where("serial_movements.serial_number_id IN ?", serialIds).
+		Order("stock_movements.created_at, stock_movements.id").
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &movements, nil
+}
+
+func (sr *SerialNumberPostgresRepository) UpdateSerialQrData(serialId uint64, qrPath string) error {
+	return sr.db.GetDb().Model(&domain.SerialNumber{}).Where("id = ?", serialId).Update("qr", qrPath).Error
+}
+
+// checkSerialList проверяет, что номеров ровно quantity и среди них нет повторов
+func checkSerialList(serials []string, quantity uint64) error {
+	if uint64(len(serials)) != quantity {
+		return custom_errors.ErrSerialCountMismatch
+	}
+
+	seen := make(map[string]struct{}, len(serials))
+	for _, serial := range serials {
+		if serial == "" {
+			return custom_errors.ErrSerialCountMismatch
+		}
+		if _, ok := seen[serial]; ok {
+			return custom_errors.ErrSerialCountMismatch
+		}
+		seen[serial] = struct{}{}
+	}
+
+	return nil
+}
+
+// insertSerialNumbers регистрирует новые номера владельца userId. Номер, который уже числится у него на остатке
+// или ожидает проведения, повторно не принимается. Номера других владельцев не мешают
+func insertSerialNumbers(tx *gorm.DB, userId string, serials []domain.SerialNumber) error {
+	if len(serials) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(serials))
+	for i := range serials {
+		serials[i].UuidUser = userId
+		values = append(values, serials[i].Serial)
+	}
+
+	var existing int64
+	err := tx.Model(&domain.SerialNumber{}).
+		Where("uuid_user = ? AND serial IN ? AND status <> ?", userId, values, domain.SerialStatusIssued).
+		Count(&existing).Error
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		return custom_errors.ErrSerialAlreadyExists
+	}
+
+	return tx.Create(&serials).Error
+}
+
+// lockStockSerials блокирует указанные номера, лежащие на строке остатка productUuid.
+// Номера, уже собранные в отгрузку, считаются занятыми
+func lockStockSerials(tx *gorm.DB, productUuid string, serials []string) ([]domain.SerialNumber, error) {
+	var locked []domain.SerialNumber
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_uuid = ? AND status = ? AND shipment_line_id IS NULL AND serial IN ?", productUuid, domain.SerialStatusInStock, serials).
+		Order("id").
+		Find(&locked).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(locked) != len(serials) {
+		return nil, custom_errors.ErrSerialNotInStock
+	}
+
+	return locked, nil
+}
+
+// linkSerialMovement записывает участие номеров в движении остатка, по этим записям строится история номера
+func linkSerialMovement(tx *gorm.DB, serials []domain.SerialNumber, movementId uint64) error {
+	if len(serials) == 0 {
+		return nil
+	}
+
+	links := make([]domain.SerialMovement, 0, len(serials))
+	for _, serial := range serials {
+		links = append(links, domain.SerialMovement{
+			SerialNumberId:  serial.Id,
+			StockMovementId: movementId,
+		})
+	}
+
+	return tx.Create(&links).Error
+}
+
+func serialIds(serials []domain.SerialNumber) []uint64 {
+	ids := make([]uint64, 0, len(serials))
+	for _, serial := range serials {
+		ids = append(ids, serial.Id)
+	}
+
+	return ids
+}
@@ -0,0 +1,402 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type ShipmentRepository interface {
+	InsertShipmentData(in *domain.Shipment, userId string) error
+	UpdateShipmentLinesData(in *domain.Shipment, userId string) error
+	FindAllShipmentData(userId string, warehouseId int) (*[]domain.Shipment, error)
+	FindShipmentData(userId string, warehouseId int, shipmentId uint64) (*domain.Shipment, error)
+	PackShipmentData(userId string, warehouseId int, shipmentId uint64, picked map[uint64]uint64, serials map[uint64][]string) error
+	ShipShipmentData(userId string, warehouseId int, shipmentId uint64, actorId string) error
+}
+
+type ShipmentPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewShipmentPostgresRepository(db database.Database, logger slog.Logger) *ShipmentPostgresRepository {
+	return &ShipmentPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (sr *ShipmentPostgresRepository) InsertShipmentData(in *domain.Shipment, userId string) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := sr.checkShipmentLines(tx, int(in.WarehouseId), userId, in.Lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (sr *ShipmentPostgresRepository) UpdateShipmentLinesData(in *domain.Shipment, userId string) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	shipment, err := sr.lockShipment(tx, userId, int(in.WarehouseId), in.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if shipment.Status != domain.ShipmentStatusPicking {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	// Строки отгрузки по заказу покупателя повторяют заказ, на них ссылаются задания на сборку
+	orderShipment, err := isSalesOrderShipment(tx, shipment.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if orderShipment {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if err := sr.checkShipmentLines(tx, int(in.WarehouseId), userId, in.Lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(shipment).Update("comment", in.Comment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("shipment_id = ?", shipment.Id).Delete(&domain.ShipmentLine{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range in.Lines {
+		in.Lines[i].Id = 0
+		in.Lines[i].ShipmentId = shipment.Id
+	}
+
+	if len(in.Lines) > 0 {
+		if err := tx.Create(&in.Lines).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (sr *ShipmentPostgresRepository) FindAllShipmentData(userId string, warehouseId int) (*[]domain.Shipment, error) {
+	var shipments []domain.Shipment
+
+	if err := checkWarehouseOwner(sr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := sr.db.GetDb().Preload("Lines", orderShipmentLines).
+		Where("ware_house_id = ?", warehouseId).
+		Order("created_at DESC").
+		Find(&shipments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &shipments, nil
+}
+
+func (sr *ShipmentPostgresRepository) FindShipmentData(userId string, warehouseId int, shipmentId uint64) (*domain.Shipment, error) {
+	var shipment domain.Shipment
+
+	if err := checkWarehouseOwner(sr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := sr.db.GetDb().Preload("Lines", orderShipmentLines).
+		Where("id = ? AND ware_house_id = ?", shipmentId, warehouseId).
+		First(&shipment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrShipmentNotFound
+		}
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+// PackShipmentData фиксирует собранное количество. Номера серийного товара закрепляются за строкой отгрузки
+// и списываются с остатка при отгрузке
+func (sr *ShipmentPostgresRepository) PackShipmentData(userId string, warehouseId int, shipmentId uint64, picked map[uint64]uint64, serials map[uint64][]string) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	shipment, err := sr.lockShipment(tx, userId, warehouseId, shipmentId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if shipment.Status != domain.ShipmentStatusPicking {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.ShipmentLine
+	if err := tx.Where("shipment_id = ?", shipment.Id).Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(lines) == 0 {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentLine
+	}
+
+	linesById := make(map[uint64]domain.ShipmentLine, len(lines))
+	for _, line := range lines {
+		linesById[line.Id] = line
+	}
+
+	for lineId, quantity := range picked {
+		line, ok := linesById[lineId]
+		if !ok || quantity > line.Quantity {
+			tx.Rollback()
+			return custom_errors.ErrInvalidDocumentLine
+		}
+	}
+
+	pickedByTask, err := salesOrderPickedLines(tx, shipment.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Если собранное количество по строке не передано, берем собранное по заданию заказа,
+	// а без задания считаем, что строка собрана полностью
+	for _, line := range lines {
+		quantity, ok := picked[line.Id]
+		if !ok {
+			quantity = line.Quantity
+			if _, ok := pickedByTask[line.Id]; ok {
+				quantity = line.PickedQuantity
+			}
+		}
+
+		if err := tx.Model(&domain.ShipmentLine{}).Where("id = ?", line.Id).Update("picked_quantity", quantity).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		serialTracked, err := productSerialTracked(tx, line.ProductUuid)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if !serialTracked {
+			if len(serials[line.Id]) > 0 {
+				tx.Rollback()
+				return custom_errors.ErrSerialCountMismatch
+			}
+			continue
+		}
+
+		if err := checkSerialList(serials[line.Id], quantity); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if quantity == 0 {
+			continue
+		}
+
+		locked, err := lockStockSerials(tx, line.ProductUuid, serials[line.Id])
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Model(&domain.SerialNumber{}).Where("id IN ?", serialIds(locked)).Update("shipment_line_id", line.Id).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	now := time.Now()
+	err = tx.Model(shipment).Updates(map[string]interface{}{
+		"status":    domain.ShipmentStatusPacked,
+		"packed_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := packSalesOrder(tx, shipment.Id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (sr *ShipmentPostgresRepository) ShipShipmentData(userId string, warehouseId int, shipmentId uint64, actorId string) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	shipment, err := sr.lockShipment(tx, userId, warehouseId, shipmentId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if shipment.Status != domain.ShipmentStatusPacked {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.ShipmentLine
+	if err := tx.Where("shipment_id = ?", shipment.Id).Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkShipmentParcels(tx, shipment.Id, lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Заказ закрывается до списания: его резервы снимаются, иначе они не дали бы списать собранный товар
+	if err := shipSalesOrder(tx, shipment.Id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Строки товаров блокируются в одном порядке, чтобы параллельные отгрузки не ловили deadlock
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].ProductUuid < lines[j].ProductUuid
+	})
+
+	for _, line := range lines {
+		if line.PickedQuantity == 0 {
+			continue
+		}
+
+		movement := &domain.StockMovement{
+			ProductUuid: line.ProductUuid,
+			Quantity:    -int64(line.PickedQuantity),
+			Reason:      domain.MovementReasonShipment,
+			ActorUuid:   actorId,
+		}
+		if err := applyStockMovement(tx, movement); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := sr.issuePackedSerials(tx, line.Id, movement.Id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	now := time.Now()
+	err = tx.Model(shipment).Updates(map[string]interface{}{
+		"status":     domain.ShipmentStatusShipped,
+		"shipped_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// issuePackedSerials списывает номера, собранные по строке отгрузки. Для несерийного товара номеров нет
+func (sr *ShipmentPostgresRepository) issuePackedSerials(tx *gorm.DB, lineId uint64, movementId uint64) error {
+	var serials []domain.SerialNumber
+	if err := tx.Where("shipment_line_id = ? AND status = ?", lineId, domain.SerialStatusInStock).Find(&serials).Error; err != nil {
+		return err
+	}
+	if len(serials) == 0 {
+		return nil
+	}
+
+	if err := tx.Model(&domain.SerialNumber{}).Where("id IN ?", serialIds(serials)).Update("status", domain.SerialStatusIssued).Error; err != nil {
+		return err
+	}
+
+	return linkSerialMovement(tx, serials, movementId)
+}
+
+func orderShipmentLines(db *gorm.DB) *gorm.DB {
+	return db.Order("shipment_lines.id")
+}
+
+// lockShipment блокирует документ до конца транзакции, чтобы его нельзя было отгрузить дважды
+func (sr *ShipmentPostgresRepository) lockShipment(tx *gorm.DB, userId string, warehouseId int, shipmentId uint64) (*domain.Shipment, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var shipment domain.Shipment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", shipmentId, warehouseId).
+		First(&shipment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrShipmentNotFound
+		}
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+func (sr *ShipmentPostgresRepository) checkShipmentLines(tx *gorm.DB, warehouseId int, userId string, lines []domain.ShipmentLine) error {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return err
+	}
+
+	var productIds []string
+	for _, line := range lines {
+		productIds = append(productIds, line.ProductUuid)
+	}
+
+	return checkProductsInWarehouse(tx, warehouseId, productIds)
+}
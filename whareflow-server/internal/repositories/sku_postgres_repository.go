@@ -0,0 +1,341 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+type SkuRepository interface {
+	InsertSkuData(in *domain.Sku) error
+	UpdateSkuData(in *domain.Sku, userId string) error
+	FindAllSkuData(userId string) (*[]domain.Sku, error)
+	FindSkuData(userId string, skuId uint64) (*domain.Sku, error)
+	FindSkusData(userId string, skuIds []uint64) (map[uint64]*domain.Sku, error)
+	FindProductSkusData(userId string, productIds []string) (map[string]*domain.Sku, error)
+}
+
+type SkuPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewSkuPostgresRepository(db database.Database, logger slog.Logger) *SkuPostgresRepository {
+	return &SkuPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (sr *SkuPostgresRepository) InsertSkuData(in *domain.Sku) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkSkuUnique(tx, in); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdateSkuData меняет карточку позиции и заменяет список ее единиц. Признак серийного учета и точность базовой
+// единицы меняются только пока позиции нет на остатке, иначе номера и остатки перестанут сходиться с количеством
+func (sr *SkuPostgresRepository) UpdateSkuData(in *domain.Sku, userId string) error {
+	tx := sr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var sku domain.Sku
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND uuid_user = ?", in.Id, userId).
+		First(&sku).Error
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrSkuNotFound
+		}
+		return err
+	}
+
+	in.UuidUser = sku.UuidUser
+	if err := checkSkuUnique(tx, in); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if in.SerialTracked != sku.SerialTracked || in.Decimals != sku.Decimals {
+		var inStock int64
+		if err := tx.Model(&domain.Product{}).Where("sku_id = ? AND count > 0", sku.Id).Count(&inStock).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if inStock > 0 {
+			tx.Rollback()
+			return custom_errors.ErrSkuInStock
+		}
+	}
+
+	if in.Decimals != sku.Decimals {
+		inDocuments, err := skuInOpenDocuments(tx, sku.Id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if inDocuments {
+			tx.Rollback()
+			return custom_errors.ErrSkuInDocuments
+		}
+	}
+
+	// Новые требования к хранению должны подходить всем зонам, где позиция уже лежит
+	if in.TemperatureClass != sku.TemperatureClass || in.HazardClass != sku.HazardClass {
+		var zones []domain.Zone
+		err := tx.Where("id IN (?)", tx.Model(&domain.Product{}).Select("zone_id").Where("sku_id = ? AND count > 0", sku.Id)).
+			Find(&zones).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		for i := range zones {
+			if err := checkStorageCompatible(&zones[i], in); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	err = tx.Model(&domain.Sku{}).Where("id = ?", sku.Id).
+		Select("code", "name", "description", "unit", "barcode", "attributes", "serial_tracked", "decimals",
+			"length_cm", "width_cm", "height_cm", "weight_kg", "units_per_pallet",
+			"temperature_class", "hazard_class").
+		Updates(in).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Строки документов хранят коэффициент пересчета, поэтому единицы можно пересоздать целиком
+	if err := tx.Where("sku_id = ?", sku.Id).Delete(&domain.SkuUnit{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range in.Units {
+		in.Units[i].Id = 0
+		in.Units[i].SkuId = sku.Id
+	}
+
+	if len(in.Units) > 0 {
+		if err := tx.Create(&in.Units).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (sr *SkuPostgresRepository) FindAllSkuData(userId string) (*[]domain.Sku, error) {
+	var skus []domain.Sku
+
+	if err := sr.db.GetDb().Preload("Units", orderSkuUnits).Where("uuid_user = ?", userId).Order("code").Find(&skus).Error; err != nil {
+		return nil, err
+	}
+
+	return &skus, nil
+}
+
+func (sr *SkuPostgresRepository) FindSkuData(userId string, skuId uint64) (*domain.Sku, error) {
+	return findSku(sr.db.GetDb(), userId, skuId)
+}
+
+// FindSkusData возвращает позиции владельца с единицами измерения по id. Позиции, которых нет, в ответ не попадают
+func (sr *SkuPostgresRepository) FindSkusData(userId string, skuIds []uint64) (map[uint64]*domain.Sku, error) {
+	skus := make(map[uint64]*domain.Sku, len(skuIds))
+	if len(skuIds) == 0 {
+		return skus, nil
+	}
+
+	var found []domain.Sku
+	if err := sr.db.GetDb().Preload("Units", orderSkuUnits).Where("id IN ? AND uuid_user = ?", skuIds, userId).Find(&found).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range found {
+		skus[found[i].Id] = &found[i]
+	}
+
+	return skus, nil
+}
+
+// FindProductSkusData возвращает позиции каталога строк товара владельца по uuid строки
+func (sr *SkuPostgresRepository) FindProductSkusData(userId string, productIds []string) (map[string]*domain.Sku, error) {
+	productSkus := make(map[string]*domain.Sku, len(productIds))
+	if len(productIds) == 0 {
+		return productSkus, nil
+	}
+
+	var rows []struct {
+		Uuid  string
+		SkuId uint64
+	}
+	err := sr.db.GetDb().Model(&domain.Product{}).
+		Select("products.uuid, products.sku_id").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Joins("JOIN ware_houses ON zones.ware_house_id = ware_houses.id").
+		Where("ware_houses.uuid_user = ? AND products.uuid IN ?", userId, productIds).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	skuIds := make([]uint64, 0, len(rows))
+	for _, row := range rows {
+		skuIds = append(skuIds, row.SkuId)
+	}
+
+	skus, err := sr.FindSkusData(userId, skuIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if sku, ok := skus[row.SkuId]; ok {
+			productSkus[row.Uuid] = sku
+		}
+	}
+
+	return productSkus, nil
+}
+
+func findSku(db *gorm.DB, userId string, skuId uint64) (*domain.Sku, error) {
+	var sku domain.Sku
+
+	if err := db.Preload("Units", orderSkuUnits).Where("id = ? AND uuid_user = ?", skuId, userId).First(&sku).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrSkuNotFound
+		}
+		return nil, err
+	}
+
+	return &sku, nil
+}
+
+func orderSkuUnits(db *gorm.DB) *gorm.DB {
+	return db.Order("sku_units.factor")
+}
+
+// checkSkuUnique не дает завести у владельца две позиции с одним кодом или штрихкодом
+func checkSkuUnique(tx *gorm.DB, in *domain.Sku) error {
+	query := tx.Model(&domain.Sku{}).Where("uuid_user = ? AND id <> ?", in.UuidUser, in.Id)
+	if in.Barcode != nil {
+		query = query.Where("code = ? OR barcode = ?", in.Code, *in.Barcode)
+	} else {
+		query = query.Where("code = ?", in.Code)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return custom_errors.ErrSkuAlreadyExists
+	}
+
+	return nil
+}
+
+// skuSerialTracked сообщает, ведется ли по позиции серийный учет
+func skuSerialTracked(tx *gorm.DB, skuId uint64) (bool, error) {
+	var sku domain.Sku
+	if err := tx.Select("serial_tracked").Where("id = ?", skuId).First(&sku).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, custom_errors.ErrSkuNotFound
+		}
+		return false, err
+	}
+
+	return sku.SerialTracked, nil
+}
+
+// productSerialTracked сообщает, ведется ли серийный учет по позиции каталога строки товара
+func productSerialTracked(tx *gorm.DB, productUuid string) (bool, error) {
+	var serialTracked []bool
+	err := tx.Model(&domain.Product{}).
+		Joins("JOIN skus ON products.sku_id = skus.id").
+		Where("products.uuid = ?", productUuid).
+		Pluck("skus.serial_tracked", &serialTracked).Error
+	if err != nil {
+		return false, err
+	}
+	if len(serialTracked) == 0 {
+		return false, custom_errors.ErrProductNotFound
+	}
+
+	return serialTracked[0], nil
+}
+
+// skuInOpenDocuments сообщает, есть ли незакрытые документы или правила с количествами позиции.
+// Они хранят количества в долях базовой единицы, и смена точности пересчитала бы их без ведома пользователя
+func skuInOpenDocuments(tx *gorm.DB, skuId uint64) (bool, error) {
+	productIds := tx.Model(&domain.Product{}).Select("uuid").Where("sku_id = ?", skuId)
+
+	queries := []*gorm.DB{
+		tx.Table("reorder_rules").Where("sku_id = ?", skuId),
+		tx.Table("kit_components").Where("kit_sku_id = ? OR component_sku_id = ?", skuId, skuId),
+		tx.Table("receipt_lines").
+			Joins("JOIN receipts ON receipts.id = receipt_lines.receipt_id").
+			Where("receipt_lines.sku_id = ? AND receipts.status <> ?", skuId, domain.ReceiptStatusPosted),
+		tx.Table("purchase_order_lines").
+			Joins("JOIN purchase_orders ON purchase_orders.id = purchase_order_lines.order_id").
+			Where("purchase_order_lines.sku_id = ? AND purchase_orders.status IN ?", skuId,
+				[]string{domain.PurchaseOrderStatusOpen, domain.PurchaseOrderStatusPartiallyReceived}),
+		tx.Table("reservations").Where("product_uuid IN (?) AND status = ?", productIds, domain.ReservationStatusActive),
+		tx.Table("stock_holds").Where("product_uuid IN (?) AND status <> ?", productIds, domain.StockStatusReleased),
+		tx.Table("sales_order_lines").
+			Joins("JOIN sales_orders ON sales_orders.id = sales_order_lines.order_id").
+			Where("sales_order_lines.product_uuid IN (?) AND sales_orders.status IN ?", productIds,
+				[]string{domain.SalesOrderStatusDraft, domain.SalesOrderStatusConfirmed, domain.SalesOrderStatusPicking, domain.SalesOrderStatusPacked}),
+		tx.Table("shipment_lines").
+			Joins("JOIN shipments ON shipments.id = shipment_lines.shipment_id").
+			Where("shipment_lines.sku_id = ? AND shipments.status <> ?", skuId, domain.ShipmentStatusShipped),
+		tx.Table("transfer_lines").
+			Joins("JOIN transfers ON transfers.id = transfer_lines.transfer_id").
+			Where("transfer_lines.sku_id = ? AND transfers.status <> ?", skuId, domain.TransferStatusReceived),
+		tx.Table("customer_return_lines").
+			Joins("JOIN customer_returns ON customer_returns.id = customer_return_lines.return_id").
+			Where("customer_return_lines.sku_id = ? AND customer_returns.status = ?", skuId, domain.ReturnStatusDraft),
+		tx.Table("wave_lines").
+			Joins("JOIN waves ON waves.id = wave_lines.wave_id").
+			Where("wave_lines.product_uuid IN (?) AND waves.status <> ?", productIds, domain.WaveStatusDone),
+	}
+
+	for _, query := range queries {
+		var found int64
+		if err := query.Limit(1).Count(&found).Error; err != nil {
+			return false, err
+		}
+		if found > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
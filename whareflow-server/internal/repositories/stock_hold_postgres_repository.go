@@ -0,0 +1,368 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type StockHoldRepository interface {
+	InsertStockHoldData(in *domain.StockHold, userId string, warehouseId int) error
+	ChangeStockHoldStatusData(userId string, warehouseId int, holdId uint64, status, reason, actorId string) (*domain.StockHold, error)
+	ReleaseStockHoldData(userId string, warehouseId int, holdId uint64, quantity uint64, reason, actorId string) (*domain.StockHold, error)
+	FindStockHoldData(userId string, warehouseId int, holdId uint64) (*domain.StockHold, error)
+	FindAllStockHoldData(userId string, warehouseId int, productId string) (*[]domain.StockHold, error)
+	FindStockStatusHistoryData(userId string, warehouseId int, productId string) (*[]domain.StockStatusChange, error)
+	FindHeldQuantityData(productIds []string) (map[string]uint64, error)
+}
+
+type StockHoldPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewStockHoldPostgresRepository(db database.Database, logger slog.Logger) *StockHoldPostgresRepository {
+	return &StockHoldPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// InsertStockHoldData блокирует часть доступного остатка товара. Заблокировать можно только то,
+// что не занято резервами и другими блокировками
+func (hr *StockHoldPostgresRepository) InsertStockHoldData(in *domain.StockHold, userId string, warehouseId int) error {
+	tx := hr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkProductsInWarehouse(tx, warehouseId, []string{in.ProductUuid}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var product domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", in.ProductUuid).First(&product).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrProductNotFound
+		}
+		return err
+	}
+
+	reserved, err := reservedQuantity(tx, in.ProductUuid)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	held, err := heldQuantity(tx, in.ProductUuid)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if reserved+held+in.Quantity > product.Count {
+		tx.Rollback()
+		return custom_errors.ErrInsufficientAvailableStock
+	}
+
+	if err := insertStockHold(tx, in); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ChangeStockHoldStatusData переводит действующую блокировку в другой статус блокировки, например quarantine -> damaged
+func (hr *StockHoldPostgresRepository) ChangeStockHoldStatusData(userId string, warehouseId int, holdId uint64, status, reason, actorId string) (*domain.StockHold, error) {
+	tx := hr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	hold, err := lockStockHold(tx, warehouseId, holdId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if hold.Status == status {
+		tx.Rollback()
+		return nil, custom_errors.ErrInvalidStockHold
+	}
+
+	change := &domain.StockStatusChange{
+		HoldId:      hold.Id,
+		ProductUuid: hold.ProductUuid,
+		FromStatus:  hold.Status,
+		ToStatus:    status,
+		Quantity:    hold.Quantity,
+		Reason:      reason,
+		ActorUuid:   actorId,
+	}
+
+	if err := tx.Model(&domain.StockHold{}).Where("id = ?", hold.Id).Update("status", status).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	hold.Status = status
+
+	if err := tx.Create(change).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// ReleaseStockHoldData возвращает quantity единиц блокировки в доступный остаток. Нулевое quantity
+// или quantity не меньше заблокированного снимает блокировку целиком
+func (hr *StockHoldPostgresRepository) ReleaseStockHoldData(userId string, warehouseId int, holdId uint64, quantity uint64, reason, actorId string) (*domain.StockHold, error) {
+	tx := hr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	hold, err := lockStockHold(tx, warehouseId, holdId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if quantity == 0 || quantity > hold.Quantity {
+		quantity = hold.Quantity
+	}
+
+	change := &domain.StockStatusChange{
+		HoldId:      hold.Id,
+		ProductUuid: hold.ProductUuid,
+		FromStatus:  hold.Status,
+		ToStatus:    domain.StockStatusAvailable,
+		Quantity:    quantity,
+		Reason:      reason,
+		ActorUuid:   actorId,
+	}
+
+	// Частично снятая блокировка остается действующей на оставшееся количество
+	updates := map[string]interface{}{
+		"quantity": hold.Quantity - quantity,
+	}
+	if quantity == hold.Quantity {
+		now := time.Now()
+		updates = map[string]interface{}{
+			"status":      domain.StockStatusReleased,
+			"released_by": actorId,
+			"released_at": now,
+		}
+		hold.Status = domain.StockStatusReleased
+		hold.ReleasedBy = &actorId
+		hold.ReleasedAt = &now
+	} else {
+		hold.Quantity -= quantity
+	}
+
+	if err := tx.Model(&domain.StockHold{}).Where("id = ?", hold.Id).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Create(change).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// FindStockHoldData возвращает действующую блокировку склада
+func (hr *StockHoldPostgresRepository) FindStockHoldData(userId string, warehouseId int, holdId uint64) (*domain.StockHold, error) {
+	var hold domain.StockHold
+
+	if err := checkWarehouseOwner(hr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := hr.db.GetDb().Model(&domain.StockHold{}).
+		Joins("JOIN products ON stock_holds.product_uuid = products.uuid").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Where("stock_holds.id = ? AND zones.ware_house_id = ?", holdId, warehouseId).
+		Scopes(activeStockHolds).
+		First(&hold).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrStockHoldNotFound
+		}
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// FindAllStockHoldData возвращает действующие блокировки склада. Если productId не пустой, только по этому товару
+func (hr *StockHoldPostgresRepository) FindAllStockHoldData(userId string, warehouseId int, productId string) (*[]domain.StockHold, error) {
+	var holds []domain.StockHold
+
+	if err := checkWarehouseOwner(hr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	query := hr.db.GetDb().Model(&domain.StockHold{}).
+		Joins("JOIN products ON stock_holds.product_uuid = products.uuid").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Where("zones.ware_house_id = ?", warehouseId).
+		Scopes(activeStockHolds)
+	if productId != "" {
+		query = query.Where("stock_holds.product_uuid = ?", productId)
+	}
+
+	if err := query.Order("stock_holds.created_at").Find(&holds).Error; err != nil {
+		return nil, err
+	}
+
+	return &holds, nil
+}
+
+// FindStockStatusHistoryData возвращает историю смены статусов остатка склада от новых к старым
+func (hr *StockHoldPostgresRepository) FindStockStatusHistoryData(userId string, warehouseId int, productId string) (*[]domain.StockStatusChange, error) {
+	var changes []domain.StockStatusChange
+
+	if err := checkWarehouseOwner(hr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	query := hr.db.GetDb().Model(&domain.StockStatusChange{}).
+		Joins("JOIN products ON stock_status_changes.product_uuid = products.uuid").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Where("zones.ware_house_id = ?", warehouseId)
+	if productId != "" {
+		query = query.Where("stock_status_changes.product_uuid = ?", productId)
+	}
+
+	if err := query.Order("stock_status_changes.created_at DESC, stock_status_changes.id DESC").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	return &changes, nil
+}
+
+// FindHeldQuantityData возвращает заблокированное количество по товарам. Товаров без блокировок в ответе нет
+func (hr *StockHoldPostgresRepository) FindHeldQuantityData(productIds []string) (map[string]uint64, error) {
+	held := make(map[string]uint64, len(productIds))
+	if len(productIds) == 0 {
+		return held, nil
+	}
+
+	var rows []struct {
+		ProductUuid string
+		Held        uint64
+	}
+
+	err := hr.db.GetDb().Model(&domain.StockHold{}).
+		Select("stock_holds.product_uuid, SUM(stock_holds.quantity) AS held").
+		Where("stock_holds.product_uuid IN ?", productIds).
+		Scopes(activeStockHolds).
+		Group("stock_holds.product_uuid").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		held[row.ProductUuid] = row.Held
+	}
+
+	return held, nil
+}
+
+// insertStockHold создает блокировку и запись истории о переводе остатка из available в ее статус
+func insertStockHold(tx *gorm.DB, hold *domain.StockHold) error {
+	if err := tx.Create(hold).Error; err != nil {
+		return err
+	}
+
+	change := &domain.StockStatusChange{
+		HoldId:      hold.Id,
+		ProductUuid: hold.ProductUuid,
+		FromStatus:  domain.StockStatusAvailable,
+		ToStatus:    hold.Status,
+		Quantity:    hold.Quantity,
+		Reason:      hold.Reason,
+		ActorUuid:   hold.CreatedBy,
+	}
+
+	return tx.Create(change).Error
+}
+
+func activeStockHolds(db *gorm.DB) *gorm.DB {
+	return db.Where("stock_holds.status <> ?", domain.StockStatusReleased)
+}
+
+// lockStockHold блокирует действующую блокировку остатка склада до конца транзакции
+func lockStockHold(tx *gorm.DB, warehouseId int, holdId uint64) (*domain.StockHold, error) {
+	var hold domain.StockHold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "stock_holds"}}).
+		Joins("JOIN products ON stock_holds.product_uuid = products.uuid").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Where("stock_holds.id = ? AND zones.ware_house_id = ?", holdId, warehouseId).
+		Scopes(activeStockHolds).
+		First(&hold).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrStockHoldNotFound
+		}
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// heldQuantity считает заблокированный остаток товара внутри транзакции
+func heldQuantity(tx *gorm.DB, productId string) (uint64, error) {
+	var held uint64
+	err := tx.Model(&domain.StockHold{}).
+		Where("stock_holds.product_uuid = ?", productId).
+		Scopes(activeStockHolds).
+		Select("COALESCE(SUM(stock_holds.quantity), 0)").
+		Scan(&held).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return held, nil
+}
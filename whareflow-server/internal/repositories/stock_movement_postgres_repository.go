@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+)
+
+type StockMovementRepository interface {
+	FindAllStockMovementData(userId string, productId string) (*[]domain.StockMovement, error)
+}
+
+type StockMovementPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewStockMovementPostgresRepository(db database.Database, logger slog.Logger) *StockMovementPostgresRepository {
+	return &StockMovementPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (sr *StockMovementPostgresRepository) FindAllStockMovementData(userId string, productId string) (*[]domain.StockMovement, error) {
+	var movements []domain.StockMovement
+
+	err := sr.db.GetDb().Model(&domain.StockMovement{}).
+		Joins("JOIN products ON stock_movements.product_uuid = products.uuid").
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Joins("JOIN ware_houses ON zones.ware_house_id = ware_houses.id").
+		Where("ware_houses.uuid_user = ? AND products.uuid = ?", userId, productId).
+		Order("stock_movements.created_at, stock_movements.id").
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &movements, nil
+}
+
+// applyStockMovement меняет остаток товара на movement.Quantity и записывает движение в журнал.
+// Вызывается только внутри транзакции: строка товара блокируется до её завершения.
+func applyStockMovement(tx *gorm.DB, movement *domain.StockMovement) error {
+	var product domain.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("uuid = ?", movement.ProductUuid).
+		First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrProductNotFound
+		}
+		return err
+	}
+
+	// Приход в ячейку проверяется по вместимости ячейки и ее родителей
+	if movement.Quantity > 0 && product.LocationId != nil {
+		if err := checkLocationCapacity(tx, *product.LocationId, uint64(movement.Quantity)); err != nil {
+			return err
+		}
+	}
+
+	var held, reserved uint64
+	if stockMovementTakesFree(movement) {
+		held, err = heldQuantity(tx, movement.ProductUuid)
+		if err != nil {
+			return err
+		}
+		reserved, err = reservedQuantity(tx, movement.ProductUuid)
+		if err != nil {
+			return err
+		}
+	}
+	if err := checkStockMovement(product.Count, movement, held, reserved); err != nil {
+		return err
+	}
+
+	// Инвентаризация фиксирует фактический остаток, поэтому пределы и совместимость зоны для нее не проверяются
+	if movement.Quantity > 0 && movement.Reason != domain.MovementReasonInventory {
+		if err := checkZoneStorage(tx, product.ZoneId, product.SkuId); err != nil {
+			return err
+		}
+		if err := checkZoneLoad(tx, product.ZoneId, product.SkuId, uint64(movement.Quantity)); err != nil {
+			return err
+		}
+	}
+
+	if movement.Quantity < 0 && movement.SourceZoneId == nil {
+		movement.SourceZoneId = &product.ZoneId
+	}
+	if movement.Quantity > 0 && movement.TargetZoneId == nil {
+		movement.TargetZoneId = &product.ZoneId
+	}
+
+	err = tx.Model(&domain.Product{}).
+		Where("uuid = ?", movement.ProductUuid).
+		Update("count", gorm.Expr("count + ?", movement.Quantity)).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(movement).Error
+}
+
+// stockMovementTakesFree сообщает, списывает ли движение только свободный остаток. Перемещение целой строки
+// уносит резервы и блокировки вместе с ней, а инвентаризация фиксирует факт, поэтому для них это не так
+func stockMovementTakesFree(movement *domain.StockMovement) bool {
+	return movement.Quantity < 0 && movement.Reason != domain.MovementReasonMove && movement.Reason != domain.MovementReasonInventory
+}
+
+// checkStockMovement проверяет, что движение не уводит остаток count в минус, а списание не задевает
+// заблокированное held и зарезервированное reserved количество
+func checkStockMovement(count uint64, movement *domain.StockMovement, held uint64, reserved uint64) error {
+	left := int64(count) + movement.Quantity
+	if left < 0 {
+		return custom_errors.ErrInsufficientStock
+	}
+
+	if !stockMovementTakesFree(movement) {
+		return nil
+	}
+	if left < int64(held) {
+		return custom_errors.ErrStockOnHold
+	}
+	if left < int64(held+reserved) {
+		return custom_errors.ErrStockReserved
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"testing"
+)
+
+func TestStockMovementTakesFree(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity int64
+		reason   string
+		want     bool
+	}{
+		{name: "shipment", quantity: -5, reason: domain.MovementReasonShipment, want: true},
+		{name: "adjustment write-off", quantity: -1, reason: domain.MovementReasonAdjustment, want: true},
+		{name: "assembly", quantity: -2, reason: domain.MovementReasonAssembly, want: true},
+		{name: "transfer out", quantity: -3, reason: domain.MovementReasonTransferOut, want: true},
+		{name: "move carries holds", quantity: -5, reason: domain.MovementReasonMove, want: false},
+		{name: "inventory records the fact", quantity: -5, reason: domain.MovementReasonInventory, want: false},
+		{name: "receipt", quantity: 5, reason: domain.MovementReasonReceipt, want: false},
+		{name: "zero adjustment", quantity: 0, reason: domain.MovementReasonAdjustment, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movement := &domain.StockMovement{Quantity: tt.quantity, Reason: tt.reason}
+			if got := stockMovementTakesFree(movement); got != tt.want {
+				t.Errorf("stockMovementTakesFree() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckStockMovement(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    uint64
+		quantity int64
+		reason   string
+		held     uint64
+		reserved uint64
+		want     error
+	}{
+		{name: "receipt into empty row", count: 0, quantity: 10, reason: domain.MovementReasonReceipt},
+		{name: "ship free stock", count: 10, quantity: -4, reason: domain.MovementReasonShipment, held: 2, reserved: 4},
+		{name: "ship all free stock", count: 10, quantity: -10, reason: domain.MovementReasonShipment},
+		{name: "ship more than count", count: 3, quantity: -4, reason: domain.MovementReasonShipment, want: custom_errors.ErrInsufficientStock},
+		{name: "ship into held stock", count: 10, quantity: -9, reason: domain.MovementReasonShipment, held: 2, want: custom_errors.ErrStockOnHold},
+		{name: "ship into reserved stock", count: 10, quantity: -7, reason: domain.MovementReasonShipment, held: 2, reserved: 2, want: custom_errors.ErrStockReserved},
+		{name: "held checked before reserved", count: 10, quantity: -10, reason: domain.MovementReasonAdjustment, held: 1, reserved: 5, want: custom_errors.ErrStockOnHold},
+		{name: "move takes held and reserved stock", count: 10, quantity: -10, reason: domain.MovementReasonMove, held: 3, reserved: 3},
+		{name: "inventory ignores holds", count: 10, quantity: -8, reason: domain.MovementReasonInventory, held: 5, reserved: 5},
+		{name: "move cannot go negative", count: 2, quantity: -3, reason: domain.MovementReasonMove, want: custom_errors.ErrInsufficientStock},
+		{name: "inventory cannot go negative", count: 0, quantity: -1, reason: domain.MovementReasonInventory, want: custom_errors.ErrInsufficientStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movement := &domain.StockMovement{Quantity: tt.quantity, Reason: tt.reason}
+			err := checkStockMovement(tt.count, movement, tt.held, tt.reserved)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("checkStockMovement() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
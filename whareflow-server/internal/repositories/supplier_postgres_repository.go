@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"log/slog"
+)
+
+type SupplierRepository interface {
+	InsertSupplierData(in *domain.Supplier) error
+	UpdateSupplierData(in *domain.Supplier, userId string) error
+	FindAllSupplierData(userId string) (*[]domain.Supplier, error)
+	FindSupplierData(userId string, supplierId uint64) (*domain.Supplier, error)
+}
+
+type SupplierPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewSupplierPostgresRepository(db database.Database, logger slog.Logger) *SupplierPostgresRepository {
+	return &SupplierPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (sr *SupplierPostgresRepository) InsertSupplierData(in *domain.Supplier) error {
+	if err := checkSupplierUnique(sr.db.GetDb(), in); err != nil {
+		return err
+	}
+
+	return sr.db.GetDb().Create(in).Error
+}
+
+func (sr *SupplierPostgresRepository) UpdateSupplierData(in *domain.Supplier, userId string) error {
+	supplier, err := findSupplier(sr.db.GetDb(), userId, in.Id)
+	if err != nil {
+		return err
+	}
+
+	in.UuidUser = supplier.UuidUser
+	if err := checkSupplierUnique(sr.db.GetDb(), in); err != nil {
+		return err
+	}
+
+	return sr.db.GetDb().Model(&domain.Supplier{}).Where("id = ?", supplier.Id).
+		Select("name", "tax_id", "contact_name", "email", "phone", "address").
+		Updates(in).Error
+}
+
+func (sr *SupplierPostgresRepository) FindAllSupplierData(userId string) (*[]domain.Supplier, error) {
+	var suppliers []domain.Supplier
+
+	if err := sr.db.GetDb().Where("uuid_user = ?", userId).Order("name").Find(&suppliers).Error; err != nil {
+		return nil, err
+	}
+
+	return &suppliers, nil
+}
+
+func (sr *SupplierPostgresRepository) FindSupplierData(userId string, supplierId uint64) (*domain.Supplier, error) {
+	return findSupplier(sr.db.GetDb(), userId, supplierId)
+}
+
+func findSupplier(db *gorm.DB, userId string, supplierId uint64) (*domain.Supplier, error) {
+	var supplier domain.Supplier
+
+	if err := db.Where("id = ? AND uuid_user = ?", supplierId, userId).First(&supplier).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrSupplierNotFound
+		}
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+// checkSupplierUnique не дает завести у владельца двух поставщиков с одним названием
+func checkSupplierUnique(db *gorm.DB, in *domain.Supplier) error {
+	var count int64
+	err := db.Model(&domain.Supplier{}).
+		Where("uuid_user = ? AND name = ? AND id <> ?", in.UuidUser, in.Name, in.Id).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return custom_errors.ErrSupplierAlreadyExists
+	}
+
+	return nil
+}
@@ -0,0 +1,459 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type TransferRepository interface {
+	InsertTransferData(in *domain.Transfer, userId string) error
+	UpdateTransferLinesData(in *domain.Transfer, userId string) error
+	FindAllTransferData(userId string, warehouseId int) (*[]domain.Transfer, error)
+	FindTransferData(userId string, warehouseId int, transferId uint64) (*domain.Transfer, error)
+	ShipTransferData(userId string, warehouseId int, transferId uint64, actorId string) error
+	ReceiveTransferData(userId string, warehouseId int, transferId uint64, received map[uint64]domain.TransferLine, defaultZoneId uint64, actorId string) (*[]domain.Product, error)
+}
+
+type TransferPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewTransferPostgresRepository(db database.Database, logger slog.Logger) *TransferPostgresRepository {
+	return &TransferPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (tr *TransferPostgresRepository) InsertTransferData(in *domain.Transfer, userId string) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tr.checkTransferTarget(tx, in, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tr.checkTransferLines(tx, int(in.SourceWarehouseId), userId, in.Lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (tr *TransferPostgresRepository) UpdateTransferLinesData(in *domain.Transfer, userId string) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	transfer, err := tr.lockTransfer(tx, userId, int(in.SourceWarehouseId), in.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if transfer.SourceWarehouseId != in.SourceWarehouseId {
+		tx.Rollback()
+		return custom_errors.ErrTransferSideNotAllowed
+	}
+
+	if transfer.Status != domain.TransferStatusDraft {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if err := tr.checkTransferLines(tx, int(in.SourceWarehouseId), userId, in.Lines); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(transfer).Update("comment", in.Comment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Where("transfer_id = ?", transfer.Id).Delete(&domain.TransferLine{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range in.Lines {
+		in.Lines[i].Id = 0
+		in.Lines[i].TransferId = transfer.Id
+	}
+
+	if len(in.Lines) > 0 {
+		if err := tx.Create(&in.Lines).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (tr *TransferPostgresRepository) FindAllTransferData(userId string, warehouseId int) (*[]domain.Transfer, error) {
+	var transfers []domain.Transfer
+
+	if err := checkWarehouseOwner(tr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := tr.db.GetDb().Preload("Lines", orderTransferLines).
+		Where("source_ware_house_id = ? OR target_ware_house_id = ?", warehouseId, warehouseId).
+		Order("created_at DESC").
+		Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfers, nil
+}
+
+func (tr *TransferPostgresRepository) FindTransferData(userId string, warehouseId int, transferId uint64) (*domain.Transfer, error) {
+	var transfer domain.Transfer
+
+	if err := checkWarehouseOwner(tr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := tr.db.GetDb().Preload("Lines", orderTransferLines).
+		Where("id = ? AND (source_ware_house_id = ? OR target_ware_house_id = ?)", transferId, warehouseId, warehouseId).
+		First(&transfer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrTransferNotFound
+		}
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// ShipTransferData списывает товар со склада-отправителя, после чего он числится "в пути" в строках перемещения
+func (tr *TransferPostgresRepository) ShipTransferData(userId string, warehouseId int, transferId uint64, actorId string) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	transfer, err := tr.lockTransfer(tx, userId, warehouseId, transferId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if transfer.SourceWarehouseId != uint64(warehouseId) {
+		tx.Rollback()
+		return custom_errors.ErrTransferSideNotAllowed
+	}
+
+	if transfer.Status != domain.TransferStatusDraft {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.TransferLine
+	if err := tx.Where("transfer_id = ?", transfer.Id).Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(lines) == 0 {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentLine
+	}
+
+	// Товар мог быть удален, пока перемещение было черновиком
+	for _, line := range lines {
+		if line.ProductUuid == nil {
+			tx.Rollback()
+			return custom_errors.ErrProductNotFound
+		}
+	}
+
+	// Строки товаров блокируются в одном порядке, чтобы параллельные списания не ловили deadlock
+	sort.Slice(lines, func(i, j int) bool {
+		return *lines[i].ProductUuid < *lines[j].ProductUuid
+	})
+
+	for _, line := range lines {
+		movement := &domain.StockMovement{
+			ProductUuid: *line.ProductUuid,
+			Quantity:    -int64(line.Quantity),
+			Reason:      domain.MovementReasonTransferOut,
+			ActorUuid:   actorId,
+		}
+		if err := applyStockMovement(tx, movement); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		// Фиксируем карточку товара на момент отгрузки, по ней товар будет принят на другом складе
+		var product domain.Product
+		if err := tx.Preload("Sku").Where("uuid = ?", *line.ProductUuid).First(&product).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err := tx.Model(&domain.TransferLine{}).Where("id = ?", line.Id).Updates(map[string]interface{}{
+			"sku_id":          product.SkuId,
+			"title":           product.Sku.Name,
+			"description":     product.Sku.Description,
+			"lot_number":      product.LotNumber,
+			"production_date": product.ProductionDate,
+			"expiry_date":     product.ExpiryDate,
+		}).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	now := time.Now()
+	err = tx.Model(transfer).Updates(map[string]interface{}{
+		"status":     domain.TransferStatusInTransit,
+		"shipped_by": actorId,
+		"shipped_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ReceiveTransferData принимает товар "в пути" на склад-получатель.
+// Для строк, не переданных в received, считается что пришло отгруженное количество в зону defaultZoneId.
+// Возвращает товары, созданные на складе-получателе.
+func (tr *TransferPostgresRepository) ReceiveTransferData(userId string, warehouseId int, transferId uint64, received map[uint64]domain.TransferLine, defaultZoneId uint64, actorId string) (*[]domain.Product, error) {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	transfer, err := tr.lockTransfer(tx, userId, warehouseId, transferId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if transfer.TargetWarehouseId != uint64(warehouseId) {
+		tx.Rollback()
+		return nil, custom_errors.ErrTransferSideNotAllowed
+	}
+
+	if transfer.Status != domain.TransferStatusInTransit {
+		tx.Rollback()
+		return nil, custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.TransferLine
+	if err := tx.Where("transfer_id = ?", transfer.Id).Order("id").Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	linesById := make(map[uint64]domain.TransferLine, len(lines))
+	for _, line := range lines {
+		linesById[line.Id] = line
+	}
+
+	for lineId := range received {
+		if _, ok := linesById[lineId]; !ok {
+			tx.Rollback()
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+	}
+
+	var zoneIds []uint64
+	for i, line := range lines {
+		zoneId := defaultZoneId
+		quantity := line.Quantity
+		if receivedLine, ok := received[line.Id]; ok {
+			quantity = receivedLine.ReceivedQuantity
+			if receivedLine.TargetZoneId != nil && *receivedLine.TargetZoneId != 0 {
+				zoneId = *receivedLine.TargetZoneId
+			}
+		}
+
+		if quantity > 0 && zoneId == 0 {
+			tx.Rollback()
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		lines[i].ReceivedQuantity = quantity
+		lines[i].TargetZoneId = &zoneId
+		if quantity > 0 {
+			zoneIds = append(zoneIds, zoneId)
+		}
+	}
+
+	if err := checkZonesInWarehouse(tx, warehouseId, zoneIds); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	createdProducts := []domain.Product{}
+	for _, line := range lines {
+		updates := map[string]interface{}{
+			"received_quantity": line.ReceivedQuantity,
+		}
+
+		if line.ReceivedQuantity > 0 {
+			if line.SkuId == nil {
+				tx.Rollback()
+				return nil, custom_errors.ErrInvalidSku
+			}
+
+			product := domain.Product{
+				SkuId:          *line.SkuId,
+				ZoneId:         *line.TargetZoneId,
+				LotNumber:      line.LotNumber,
+				ProductionDate: line.ProductionDate,
+				ExpiryDate:     line.ExpiryDate,
+			}
+			if err := tx.Create(&product).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+
+			movement := &domain.StockMovement{
+				ProductUuid:  string(product.Uuid),
+				Quantity:     int64(line.ReceivedQuantity),
+				Reason:       domain.MovementReasonTransferIn,
+				ActorUuid:    actorId,
+				TargetZoneId: line.TargetZoneId,
+			}
+			if err := applyStockMovement(tx, movement); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+
+			updates["target_zone_id"] = *line.TargetZoneId
+			updates["target_product_uuid"] = string(product.Uuid)
+
+			product.Count = line.ReceivedQuantity
+			createdProducts = append(createdProducts, product)
+		}
+
+		if err := tx.Model(&domain.TransferLine{}).Where("id = ?", line.Id).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	err = tx.Model(transfer).Updates(map[string]interface{}{
+		"status":      domain.TransferStatusReceived,
+		"received_by": actorId,
+		"received_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &createdProducts, nil
+}
+
+func orderTransferLines(db *gorm.DB) *gorm.DB {
+	return db.Order("transfer_lines.id")
+}
+
+// lockTransfer блокирует документ до конца транзакции. Документ доступен с обеих сторон перемещения,
+// какая сторона может выполнять операцию, проверяет вызывающий метод
+func (tr *TransferPostgresRepository) lockTransfer(tx *gorm.DB, userId string, warehouseId int, transferId uint64) (*domain.Transfer, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var transfer domain.Transfer
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND (source_ware_house_id = ? OR target_ware_house_id = ?)", transferId, warehouseId, warehouseId).
+		First(&transfer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrTransferNotFound
+		}
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// checkTransferTarget проверяет, что склад-получатель отличается от отправителя и принадлежит тому же владельцу
+func (tr *TransferPostgresRepository) checkTransferTarget(tx *gorm.DB, in *domain.Transfer, userId string) error {
+	if in.SourceWarehouseId == in.TargetWarehouseId {
+		return custom_errors.ErrInvalidTransferTarget
+	}
+
+	if err := checkWarehouseOwner(tx, int(in.TargetWarehouseId), userId); err != nil {
+		if errors.Is(err, custom_errors.ErrWareHouseNotFound) {
+			return custom_errors.ErrInvalidTransferTarget
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (tr *TransferPostgresRepository) checkTransferLines(tx *gorm.DB, warehouseId int, userId string, lines []domain.TransferLine) error {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return err
+	}
+
+	var productIds []string
+	for _, line := range lines {
+		productIds = append(productIds, *line.ProductUuid)
+	}
+
+	if err := checkProductsInWarehouse(tx, warehouseId, productIds); err != nil {
+		return err
+	}
+
+	// Строки перемещения не несут серийных номеров, поэтому серийный товар между складами не перевозится
+	var serialTracked int64
+	err := tx.Model(&domain.Product{}).
+		Joins("JOIN skus ON products.sku_id = skus.id").
+		Where("products.uuid IN ? AND skus.serial_tracked", productIds).
+		Count(&serialTracked).Error
+	if err != nil {
+		return err
+	}
+	if serialTracked > 0 {
+		return custom_errors.ErrSerialTrackedProduct
+	}
+
+	return nil
+}
@@ -52,7 +52,7 @@ func (ur *UserPostgresRepository) InsertUserData(in *domain.User) error {
 	result := ur.db.GetDb().Create(data)
 
 	if result.Error != nil {
-		ur.logger.Error("error while inserting user", result.Error)
+		ur.logger.Error("error while inserting user", "error", result.Error)
 		return result.Error
 	}
 
@@ -119,7 +119,7 @@ func (ur *UserPostgresRepository) FindUserData(filter map[string]interface{}) (*
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("пользователь не найден")
 		}
-		ur.logger.Error("ошибка при поиске пользователя", result.Error)
+		ur.logger.Error("ошибка при поиске пользователя", "error", result.Error)
 		return nil, result.Error
 	}
 
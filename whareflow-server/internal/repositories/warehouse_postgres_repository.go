@@ -7,7 +7,6 @@ import (
 	"github.com/Miroslovelife/whareflow/pkg/database"
 	"gorm.io/gorm"
 	"log/slog"
-	"fmt"
 )
 
 type WareHouseRepository interface {
@@ -128,41 +127,98 @@ func (wr *WareHousePostgresRepository) FindWareHouseOwner(warehouseId uint) (*do
 func (wr *WareHousePostgresRepository) FindAllEmployers(warehouseId uint, ownerId string) (*[]domain.User, error) {
 	var employers []domain.User
 
-    // Проверяем наличие склада
-    err := wr.db.GetDb().Model(&domain.WareHouse{}).
-        Where("id = ? AND uuid_user = ?", warehouseId, ownerId).
-        First(&domain.WareHouse{}).Error
-    if err != nil {
-        return nil, err
-    }
-
-    // Получаем уникальных пользователей
-   resultUsers := wr.db.GetDb().Model(&domain.User{}).
-       Joins("JOIN warehouse_user_roles ON users.uuid = warehouse_user_roles.user_id").
-       Where("warehouse_user_roles.ware_house_id = ?", warehouseId).
-       Group("users.uuid"). // Группируем по uuid, чтобы избежать дубликатов
-       Find(&employers).Error
-   if resultUsers != nil {
-       return nil, resultUsers
-   }
-
-    fmt.Println("dwada", employers)
-
-    return &employers, nil
+	// Проверяем наличие склада
+	err := wr.db.GetDb().Model(&domain.WareHouse{}).
+		Where("id = ? AND uuid_user = ?", warehouseId, ownerId).
+		First(&domain.WareHouse{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Получаем уникальных пользователей
+	resultUsers := wr.db.GetDb().Model(&domain.User{}).
+		Joins("JOIN warehouse_user_roles ON users.uuid = warehouse_user_roles.user_id").
+		Where("warehouse_user_roles.ware_house_id = ?", warehouseId).
+		Group("users.uuid"). // Группируем по uuid, чтобы избежать дубликатов
+		Find(&employers).Error
+	if resultUsers != nil {
+		return nil, resultUsers
+	}
+
+	return &employers, nil
 }
 
 func (wr *WareHousePostgresRepository) FindWhsEmployers(employerId string) (*[]domain.WareHouse, error) {
 	var warehouses []domain.WareHouse
 
 	err := wr.db.GetDb().
-				Model(&domain.WareHouse{}).
-				Joins("JOIN warehouse_user_roles ON ware_houses.id = warehouse_user_roles.ware_house_id").
-				Where("warehouse_user_roles.user_id = ?", employerId).
-				Group("ware_houses.id").
-				Find(&warehouses).Error
+		Model(&domain.WareHouse{}).
+		Joins("JOIN warehouse_user_roles ON ware_houses.id = warehouse_user_roles.ware_house_id").
+		Where("warehouse_user_roles.user_id = ?", employerId).
+		Group("ware_houses.id").
+		Find(&warehouses).Error
 	if err != nil {
 		return nil, err
 	}
 
 	return &warehouses, nil
 }
+
+// checkWarehouseOwner проверяет, что склад принадлежит владельцу userId
+func checkWarehouseOwner(db *gorm.DB, warehouseId int, userId string) error {
+	var count int64
+	if err := db.Model(&domain.WareHouse{}).Where("id = ? AND uuid_user = ?", warehouseId, userId).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != 1 {
+		return custom_errors.ErrWareHouseNotFound
+	}
+
+	return nil
+}
+
+// checkZonesInWarehouse проверяет, что все зоны относятся к складу
+func checkZonesInWarehouse(db *gorm.DB, warehouseId int, zoneIds []uint64) error {
+	unique := make(map[uint64]struct{}, len(zoneIds))
+	for _, zoneId := range zoneIds {
+		unique[zoneId] = struct{}{}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	var count int64
+	if err := db.Model(&domain.Zone{}).Where("id IN ? AND ware_house_id = ?", zoneIds, warehouseId).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(unique) {
+		return custom_errors.ErrZoneNotFound
+	}
+
+	return nil
+}
+
+// checkProductsInWarehouse проверяет, что все товары лежат в зонах склада
+func checkProductsInWarehouse(db *gorm.DB, warehouseId int, productIds []string) error {
+	unique := make(map[string]struct{}, len(productIds))
+	for _, productId := range productIds {
+		unique[productId] = struct{}{}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	var count int64
+	err := db.Model(&domain.Product{}).
+		Joins("JOIN zones ON products.zone_id = zones.id").
+		Where("products.uuid IN ? AND zones.ware_house_id = ?", productIds, warehouseId).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if int(count) != len(unique) {
+		return custom_errors.ErrProductNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,555 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"time"
+)
+
+type WarehouseTaskRepository interface {
+	InsertWarehouseTaskData(in *domain.WarehouseTask, userId string) error
+	AssignWarehouseTaskData(userId string, warehouseId int, taskId uint64, assigneeId *string) error
+	CancelWarehouseTaskData(userId string, warehouseId int, taskId uint64) error
+	ClaimWarehouseTaskData(userId string, warehouseId int, taskId uint64, actorId string) error
+	CompleteWarehouseTaskData(userId string, warehouseId int, taskId uint64, actorId string) error
+	AbandonWarehouseTaskData(userId string, warehouseId int, taskId uint64, actorId string, reason string) error
+	FindAllWarehouseTaskData(userId string, warehouseId int, status string, taskType string, assigneeId string) (*[]domain.WarehouseTask, error)
+	FindWorkerQueueData(userId string, warehouseId int, actorId string) (*[]domain.WarehouseTask, error)
+	FindWarehouseTaskData(userId string, warehouseId int, taskId uint64) (*domain.WarehouseTask, error)
+	FindWorkerTaskData(userId string, warehouseId int, taskId uint64, actorId string) (*domain.WarehouseTask, error)
+	FindTaskWorkerData(warehouseId int, workerId string) (*domain.User, error)
+	FindTaskStatsData(userId string, warehouseId int, from time.Time, to time.Time) (*[]domain.WorkerTaskStat, error)
+}
+
+type WarehouseTaskPostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewWarehouseTaskPostgresRepository(db database.Database, logger slog.Logger) *WarehouseTaskPostgresRepository {
+	return &WarehouseTaskPostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// InsertWarehouseTaskData заводит задание в очередь склада. Зона, ячейка, товар и документ задания должны быть на этом складе,
+// назначенный работник - иметь право task_work на склад
+func (tr *WarehouseTaskPostgresRepository) InsertWarehouseTaskData(in *domain.WarehouseTask, userId string) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	warehouseId := int(in.WarehouseId)
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if in.ZoneId != nil {
+		if err := checkZonesInWarehouse(tx, warehouseId, []uint64{*in.ZoneId}); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if in.LocationId != nil {
+			if _, err := findLocation(tx, *in.ZoneId, *in.LocationId); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	} else if in.LocationId != nil {
+		tx.Rollback()
+		return custom_errors.ErrInvalidWarehouseTask
+	}
+
+	if in.ProductUuid != nil {
+		if err := checkProductsInWarehouse(tx, warehouseId, []string{*in.ProductUuid}); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := checkTaskDocument(tx, in); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if in.AssigneeId != nil {
+		if _, err := findTaskWorker(tx, warehouseId, *in.AssigneeId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// AssignWarehouseTaskData назначает открытое задание работнику, assigneeId = nil возвращает его в общую очередь
+func (tr *WarehouseTaskPostgresRepository) AssignWarehouseTaskData(userId string, warehouseId int, taskId uint64, assigneeId *string) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	task, err := lockWarehouseTask(tx, userId, warehouseId, taskId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if task.Status != domain.WarehouseTaskStatusOpen {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if assigneeId != nil {
+		if _, err := findTaskWorker(tx, warehouseId, *assigneeId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Model(task).Update("assignee_id", assigneeId).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CancelWarehouseTaskData отменяет задание, в том числе взятое в работу
+func (tr *WarehouseTaskPostgresRepository) CancelWarehouseTaskData(userId string, warehouseId int, taskId uint64) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	task, err := lockWarehouseTask(tx, userId, warehouseId, taskId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if task.Status != domain.WarehouseTaskStatusOpen && task.Status != domain.WarehouseTaskStatusClaimed {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	err = tx.Model(task).Updates(map[string]interface{}{
+		"status":       domain.WarehouseTaskStatusCancelled,
+		"cancelled_at": time.Now(),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ClaimWarehouseTaskData берет открытое задание в работу. Задание, назначенное другому работнику, взять нельзя
+func (tr *WarehouseTaskPostgresRepository) ClaimWarehouseTaskData(userId string, warehouseId int, taskId uint64, actorId string) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	task, err := lockWarehouseTask(tx, userId, warehouseId, taskId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if task.Status != domain.WarehouseTaskStatusOpen {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if task.AssigneeId != nil && *task.AssigneeId != actorId {
+		tx.Rollback()
+		return custom_errors.ErrWarehouseTaskAssigned
+	}
+
+	now := time.Now()
+	err = tx.Model(task).Updates(map[string]interface{}{
+		"status":     domain.WarehouseTaskStatusClaimed,
+		"claimed_by": actorId,
+		"claimed_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordTaskEvent(tx, task, domain.WarehouseTaskActionClaim, actorId, "", 0); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CompleteWarehouseTaskData закрывает задание. Закрыть его может только тот, кто взял его в работу,
+// и только когда документ задания уже обработан
+func (tr *WarehouseTaskPostgresRepository) CompleteWarehouseTaskData(userId string, warehouseId int, taskId uint64, actorId string) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	task, err := lockClaimedTask(tx, userId, warehouseId, taskId, actorId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := checkTaskDocumentDone(tx, task); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	now := time.Now()
+	err = tx.Model(task).Updates(map[string]interface{}{
+		"status":       domain.WarehouseTaskStatusDone,
+		"completed_at": now,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordTaskEvent(tx, task, domain.WarehouseTaskActionComplete, actorId, "", claimDuration(task, now)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// AbandonWarehouseTaskData возвращает взятое задание в очередь. Назначение работнику при этом сохраняется,
+// снять его может руководитель
+func (tr *WarehouseTaskPostgresRepository) AbandonWarehouseTaskData(userId string, warehouseId int, taskId uint64, actorId string, reason string) error {
+	tx := tr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	task, err := lockClaimedTask(tx, userId, warehouseId, taskId, actorId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	duration := claimDuration(task, time.Now())
+
+	err = tx.Model(task).Updates(map[string]interface{}{
+		"status":     domain.WarehouseTaskStatusOpen,
+		"claimed_by": nil,
+		"claimed_at": nil,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordTaskEvent(tx, task, domain.WarehouseTaskActionAbandon, actorId, reason, duration); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// FindAllWarehouseTaskData возвращает задания склада. Непустые status, taskType и assigneeId сужают выборку
+func (tr *WarehouseTaskPostgresRepository) FindAllWarehouseTaskData(userId string, warehouseId int, status string, taskType string, assigneeId string) (*[]domain.WarehouseTask, error) {
+	var tasks []domain.WarehouseTask
+
+	if err := checkWarehouseOwner(tr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	query := tr.db.GetDb().Where("ware_house_id = ?", warehouseId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if taskType != "" {
+		query = query.Where("type = ?", taskType)
+	}
+	if assigneeId != "" {
+		query = query.Where("assignee_id = ?", assigneeId)
+	}
+
+	if err := query.Order("created_at DESC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return &tasks, nil
+}
+
+// FindWorkerQueueData возвращает очередь работника: взятые им задания, затем открытые задания общей очереди
+// и назначенные ему - по приоритету и сроку
+func (tr *WarehouseTaskPostgresRepository) FindWorkerQueueData(userId string, warehouseId int, actorId string) (*[]domain.WarehouseTask, error) {
+	var tasks []domain.WarehouseTask
+
+	if err := checkWarehouseOwner(tr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := tr.db.GetDb().
+		Where("ware_house_id = ?", warehouseId).
+		Where("(status = ? AND claimed_by = ?) OR (status = ? AND (assignee_id IS NULL OR assignee_id = ?))",
+			domain.WarehouseTaskStatusClaimed, actorId, domain.WarehouseTaskStatusOpen, actorId).
+		Order("status = 'claimed' DESC").
+		Order("priority DESC").
+		Order("due_at NULLS LAST").
+		Order("id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &tasks, nil
+}
+
+func (tr *WarehouseTaskPostgresRepository) FindWarehouseTaskData(userId string, warehouseId int, taskId uint64) (*domain.WarehouseTask, error) {
+	var task domain.WarehouseTask
+
+	if err := checkWarehouseOwner(tr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := tr.db.GetDb().Where("id = ? AND ware_house_id = ?", taskId, warehouseId).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrWarehouseTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// FindWorkerTaskData возвращает задание так, как его видит работник actorId: задание, назначенное другому, не найдется
+func (tr *WarehouseTaskPostgresRepository) FindWorkerTaskData(userId string, warehouseId int, taskId uint64, actorId string) (*domain.WarehouseTask, error) {
+	var task domain.WarehouseTask
+
+	if err := checkWarehouseOwner(tr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := tr.db.GetDb().
+		Where("id = ? AND ware_house_id = ?", taskId, warehouseId).
+		Where("assignee_id IS NULL OR assignee_id = ?", actorId).
+		First(&task).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrWarehouseTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (tr *WarehouseTaskPostgresRepository) FindTaskWorkerData(warehouseId int, workerId string) (*domain.User, error) {
+	return findTaskWorker(tr.db.GetDb(), warehouseId, workerId)
+}
+
+// FindTaskStatsData сводит журнал заданий склада за [from, to) по работникам, типам заданий и действиям
+func (tr *WarehouseTaskPostgresRepository) FindTaskStatsData(userId string, warehouseId int, from time.Time, to time.Time) (*[]domain.WorkerTaskStat, error) {
+	var stats []domain.WorkerTaskStat
+
+	if err := checkWarehouseOwner(tr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := tr.db.GetDb().Model(&domain.WarehouseTaskEvent{}).
+		Select("warehouse_task_events.actor_id, users.username, users.first_name, users.last_name, "+
+			"warehouse_task_events.task_type, warehouse_task_events.action, "+
+			"COUNT(*) AS count, COALESCE(SUM(warehouse_task_events.duration_seconds), 0) AS duration_seconds").
+		Joins("LEFT JOIN users ON users.uuid = warehouse_task_events.actor_id").
+		Where("warehouse_task_events.ware_house_id = ?", warehouseId).
+		Where("warehouse_task_events.created_at >= ? AND warehouse_task_events.created_at < ?", from, to).
+		Group("warehouse_task_events.actor_id, users.username, users.first_name, users.last_name, " +
+			"warehouse_task_events.task_type, warehouse_task_events.action").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+func lockWarehouseTask(tx *gorm.DB, userId string, warehouseId int, taskId uint64) (*domain.WarehouseTask, error) {
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	var task domain.WarehouseTask
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", taskId, warehouseId).
+		First(&task).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrWarehouseTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// lockClaimedTask блокирует задание, которое работник actorId держит в работе
+func lockClaimedTask(tx *gorm.DB, userId string, warehouseId int, taskId uint64, actorId string) (*domain.WarehouseTask, error) {
+	task, err := lockWarehouseTask(tx, userId, warehouseId, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Status != domain.WarehouseTaskStatusClaimed {
+		return nil, custom_errors.ErrInvalidDocumentStatus
+	}
+
+	if task.ClaimedBy == nil || *task.ClaimedBy != actorId {
+		return nil, custom_errors.ErrWarehouseTaskNotClaimed
+	}
+
+	return task, nil
+}
+
+// findTaskWorker ищет работника, которому выдано право task_work на склад
+func findTaskWorker(db *gorm.DB, warehouseId int, workerId string) (*domain.User, error) {
+	var user domain.User
+
+	err := db.Model(&domain.User{}).
+		Joins("JOIN warehouse_user_roles ON warehouse_user_roles.user_id = users.uuid").
+		Joins("JOIN role_permissions ON role_permissions.role_id = warehouse_user_roles.role_id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("users.uuid = ? AND warehouse_user_roles.ware_house_id = ?", workerId, warehouseId).
+		Where("permissions.name = ?", "task_work").
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrTaskWorkerNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// checkTaskDocument проверяет ссылку задания на документ склада: приемка и размещение ссылаются на поступление,
+// сборка - на задание на сборку заказа, у остальных типов документа нет
+func checkTaskDocument(db *gorm.DB, task *domain.WarehouseTask) error {
+	var count int64
+
+	switch task.Type {
+	case domain.WarehouseTaskTypeReceive, domain.WarehouseTaskTypePutaway:
+		if task.ReceiptId == nil || task.PickTaskId != nil {
+			return custom_errors.ErrInvalidWarehouseTask
+		}
+
+		err := db.Model(&domain.Receipt{}).
+			Where("id = ? AND ware_house_id = ?", *task.ReceiptId, task.WarehouseId).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return custom_errors.ErrReceiptNotFound
+		}
+	case domain.WarehouseTaskTypePick:
+		if task.PickTaskId == nil || task.ReceiptId != nil {
+			return custom_errors.ErrInvalidWarehouseTask
+		}
+
+		err := db.Model(&domain.PickTask{}).
+			Where("id = ? AND ware_house_id = ?", *task.PickTaskId, task.WarehouseId).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return custom_errors.ErrPickTaskNotFound
+		}
+	default:
+		if task.ReceiptId != nil || task.PickTaskId != nil {
+			return custom_errors.ErrInvalidWarehouseTask
+		}
+	}
+
+	return nil
+}
+
+// checkTaskDocumentDone проверяет, что документ задания обработан: товар по поступлению принят,
+// поступление для размещения проведено, сборка выполнена
+func checkTaskDocumentDone(db *gorm.DB, task *domain.WarehouseTask) error {
+	switch {
+	case task.ReceiptId != nil:
+		var receipt domain.Receipt
+		if err := db.Select("status").Where("id = ?", *task.ReceiptId).First(&receipt).Error; err != nil {
+			return err
+		}
+
+		if receipt.Status == domain.ReceiptStatusDraft ||
+			(task.Type == domain.WarehouseTaskTypePutaway && receipt.Status != domain.ReceiptStatusPosted) {
+			return custom_errors.ErrTaskDocumentPending
+		}
+	case task.PickTaskId != nil:
+		var pickTask domain.PickTask
+		if err := db.Select("status").Where("id = ?", *task.PickTaskId).First(&pickTask).Error; err != nil {
+			return err
+		}
+
+		if pickTask.Status != domain.PickTaskStatusDone {
+			return custom_errors.ErrTaskDocumentPending
+		}
+	}
+
+	return nil
+}
+
+func recordTaskEvent(tx *gorm.DB, task *domain.WarehouseTask, action string, actorId string, reason string, duration int64) error {
+	return tx.Create(&domain.WarehouseTaskEvent{
+		TaskId:          task.Id,
+		WarehouseId:     task.WarehouseId,
+		TaskType:        task.Type,
+		Action:          action,
+		ActorId:         actorId,
+		Reason:          reason,
+		DurationSeconds: duration,
+	}).Error
+}
+
+// claimDuration - сколько секунд задание было в работе к моменту now
+func claimDuration(task *domain.WarehouseTask, now time.Time) int64 {
+	if task.ClaimedAt == nil || now.Before(*task.ClaimedAt) {
+		return 0
+	}
+
+	return int64(now.Sub(*task.ClaimedAt).Seconds())
+}
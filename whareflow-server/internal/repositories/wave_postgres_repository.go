@@ -0,0 +1,594 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type WaveRepository interface {
+	InsertWaveData(in *domain.Wave, userId string, orderIds []uint64, maxOrders int) error
+	PickWaveData(userId string, warehouseId int, waveId uint64, picked map[uint64]uint64) error
+	SortWaveData(userId string, warehouseId int, waveId uint64, productUuid string, quantity uint64, actorId string) (*[]domain.WaveSortPut, error)
+	CompleteWaveData(userId string, warehouseId int, waveId uint64, actorId string) error
+	FindAllWaveData(userId string, warehouseId int, status string) (*[]domain.Wave, error)
+	FindWaveData(userId string, warehouseId int, waveId uint64) (*domain.Wave, error)
+}
+
+type WavePostgresRepository struct {
+	db     database.Database
+	logger slog.Logger
+}
+
+func NewWavePostgresRepository(db database.Database, logger slog.Logger) *WavePostgresRepository {
+	return &WavePostgresRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// InsertWaveData собирает в волну подтвержденные заказы склада, ни одно задание которых еще не попало в лист сборки
+// или другую волну, - заказы orderIds или самые ранние из подходящих, не больше maxOrders (0 - без ограничения).
+// Заказы получают ячейки стены сортировки в порядке подтверждения, задания сворачиваются в строки волны по товару.
+// Как и в листе сборки, задание, которое нельзя собрать из незаблокированного остатка, в волну не попадает и остается
+// в очереди, а место берется из строки товара. Заказ, у которого не осталось таких заданий, в волну не включается
+func (wr *WavePostgresRepository) InsertWaveData(in *domain.Wave, userId string, orderIds []uint64, maxOrders int) error {
+	tx := wr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, int(in.WarehouseId), userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ware_house_id = ? AND status = ?", in.WarehouseId, domain.SalesOrderStatusConfirmed).
+		Where("NOT EXISTS (SELECT 1 FROM pick_tasks WHERE pick_tasks.order_id = sales_orders.id AND (pick_tasks.pick_list_id IS NOT NULL OR pick_tasks.wave_id IS NOT NULL))")
+	if len(orderIds) > 0 {
+		query = query.Where("id IN ?", orderIds)
+	}
+	if maxOrders > 0 {
+		query = query.Limit(maxOrders)
+	}
+
+	var orders []domain.SalesOrder
+	if err := query.Order("confirmed_at").Order("id").Find(&orders).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(orderIds) > 0 {
+		requested := make(map[uint64]struct{}, len(orderIds))
+		for _, orderId := range orderIds {
+			requested[orderId] = struct{}{}
+		}
+		if len(orders) != len(requested) {
+			tx.Rollback()
+			return custom_errors.ErrInvalidWave
+		}
+	}
+
+	if len(orders) == 0 {
+		tx.Rollback()
+		return custom_errors.ErrNothingToPick
+	}
+
+	var waveOrderIds []uint64
+	for _, order := range orders {
+		waveOrderIds = append(waveOrderIds, order.Id)
+	}
+
+	var tasks []domain.PickTask
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id IN ? AND status = ?", waveOrderIds, domain.PickTaskStatusOpen).
+		Order("id").
+		Find(&tasks).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(tasks) == 0 {
+		tx.Rollback()
+		return custom_errors.ErrNothingToPick
+	}
+
+	productUuids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		productUuids = append(productUuids, task.ProductUuid)
+	}
+
+	var products []domain.Product
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("uuid IN ?", productUuids).
+		Order("uuid").
+		Find(&products).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	productsByUuid := make(map[string]domain.Product, len(products))
+	for _, product := range products {
+		productsByUuid[string(product.Uuid)] = product
+	}
+
+	taken := make(map[string]uint64)
+	held := make(map[string]uint64)
+	pickable := make([]domain.PickTask, 0, len(tasks))
+	ordersWithTasks := make(map[uint64]struct{})
+	for _, task := range tasks {
+		product, ok := productsByUuid[task.ProductUuid]
+		if !ok {
+			tx.Rollback()
+			return custom_errors.ErrProductNotFound
+		}
+
+		productHeld, ok := held[task.ProductUuid]
+		if !ok {
+			productHeld, err = heldQuantity(tx, task.ProductUuid)
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+			held[task.ProductUuid] = productHeld
+		}
+
+		if productHeld+taken[task.ProductUuid]+task.Quantity > product.Count {
+			continue
+		}
+
+		task.ZoneId = product.ZoneId
+		task.LocationId = product.LocationId
+		taken[task.ProductUuid] += task.Quantity
+		pickable = append(pickable, task)
+		ordersWithTasks[task.OrderId] = struct{}{}
+	}
+	tasks = pickable
+
+	if len(tasks) == 0 {
+		tx.Rollback()
+		return custom_errors.ErrNothingToPick
+	}
+
+	if err := tx.Omit("Orders", "Lines").Create(in).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	waveOrders := make([]domain.WaveOrder, 0, len(ordersWithTasks))
+	for _, order := range orders {
+		if _, ok := ordersWithTasks[order.Id]; !ok {
+			continue
+		}
+
+		waveOrders = append(waveOrders, domain.WaveOrder{
+			WaveId:  in.Id,
+			OrderId: order.Id,
+			Slot:    len(waveOrders) + 1,
+		})
+	}
+
+	if err := tx.Create(&waveOrders).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	linesByProduct := make(map[string]*domain.WaveLine)
+	for _, task := range tasks {
+		line, ok := linesByProduct[task.ProductUuid]
+		if !ok {
+			line = &domain.WaveLine{
+				WaveId:      in.Id,
+				ProductUuid: task.ProductUuid,
+				ZoneId:      task.ZoneId,
+				LocationId:  task.LocationId,
+				Unit:        task.Unit,
+				UnitFactor:  task.UnitFactor,
+			}
+			linesByProduct[task.ProductUuid] = line
+		}
+
+		line.Quantity += task.Quantity
+		if task.UnitFactor < line.UnitFactor {
+			line.Unit = task.Unit
+			line.UnitFactor = task.UnitFactor
+		}
+	}
+
+	lines := make([]domain.WaveLine, 0, len(linesByProduct))
+	for _, line := range linesByProduct {
+		lines = append(lines, *line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].ProductUuid < lines[j].ProductUuid
+	})
+
+	if err := tx.Create(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, task := range tasks {
+		err := tx.Model(&domain.PickTask{}).Where("id = ?", task.Id).Updates(map[string]interface{}{
+			"wave_id":     in.Id,
+			"zone_id":     task.ZoneId,
+			"location_id": task.LocationId,
+		}).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// PickWaveData фиксирует итог обхода: picked - собранное количество по строкам волны, строка без записи
+// считается собранной полностью. После этого волна переходит к раскладке
+func (wr *WavePostgresRepository) PickWaveData(userId string, warehouseId int, waveId uint64, picked map[uint64]uint64) error {
+	tx := wr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	wave, err := lockWave(tx, warehouseId, waveId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if wave.Status != domain.WaveStatusPicking {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var lines []domain.WaveLine
+	if err := tx.Where("wave_id = ?", waveId).Find(&lines).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	linesById := make(map[uint64]domain.WaveLine, len(lines))
+	for _, line := range lines {
+		linesById[line.Id] = line
+	}
+
+	for lineId, quantity := range picked {
+		line, ok := linesById[lineId]
+		if !ok || quantity > line.Quantity {
+			tx.Rollback()
+			return custom_errors.ErrInvalidDocumentLine
+		}
+	}
+
+	for _, line := range lines {
+		quantity, ok := picked[line.Id]
+		if !ok {
+			quantity = line.Quantity
+		}
+
+		if err := tx.Model(&line).Update("picked_quantity", quantity).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Model(wave).Updates(map[string]interface{}{
+		"status":    domain.WaveStatusSorting,
+		"picked_at": time.Now(),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// SortWaveData раскладывает quantity собранного товара по ячейкам стены сортировки: заказы получают товар
+// по порядку ячеек, пока не наберется количество их заданий. Задание, набравшее свое количество, закрывается.
+// Товар заказов, отмененных после сборки, возвращается на место хранения строки волны - раскладка со Slot = 0
+func (wr *WavePostgresRepository) SortWaveData(userId string, warehouseId int, waveId uint64, productUuid string, quantity uint64, actorId string) (*[]domain.WaveSortPut, error) {
+	tx := wr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	wave, err := lockWave(tx, warehouseId, waveId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if wave.Status != domain.WaveStatusSorting {
+		tx.Rollback()
+		return nil, custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var line domain.WaveLine
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("wave_id = ? AND product_uuid = ?", waveId, productUuid).
+		First(&line).Error
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrNothingToSort
+		}
+		return nil, err
+	}
+
+	if line.SortedQuantity >= line.PickedQuantity {
+		tx.Rollback()
+		return nil, custom_errors.ErrNothingToSort
+	}
+
+	if quantity == 0 || line.SortedQuantity+quantity > line.PickedQuantity {
+		tx.Rollback()
+		return nil, custom_errors.ErrInvalidDocumentLine
+	}
+
+	slots, err := waveSlots(tx, waveId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var tasks []domain.PickTask
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("wave_id = ? AND product_uuid = ? AND status = ?", waveId, productUuid, domain.PickTaskStatusOpen).
+		Order("id").
+		Find(&tasks).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	surplus, err := waveSurplus(tx, &line)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return slots[tasks[i].OrderId] < slots[tasks[j].OrderId]
+	})
+
+	puts := []domain.WaveSortPut{}
+	remaining := quantity
+	for i := range tasks {
+		if remaining == 0 {
+			break
+		}
+
+		task := &tasks[i]
+		put := min(task.Quantity-task.PickedQuantity, remaining)
+		if put == 0 {
+			continue
+		}
+
+		picked := task.PickedQuantity + put
+		if err := recordPickedQuantity(tx, task, picked, actorId, picked == task.Quantity); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		puts = append(puts, domain.WaveSortPut{
+			Slot:       slots[task.OrderId],
+			OrderId:    task.OrderId,
+			TaskId:     task.Id,
+			Quantity:   put,
+			Unit:       task.Unit,
+			UnitFactor: task.UnitFactor,
+		})
+		remaining -= put
+	}
+
+	// Товар сверх потребности заказов на стену не раскладывается. Собранное для отмененных заказов возвращается
+	// в остаток: списания при сборке не было, поэтому товар просто кладут обратно на место
+	if remaining > 0 {
+		if remaining > surplus {
+			tx.Rollback()
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		puts = append(puts, domain.WaveSortPut{
+			Quantity:   remaining,
+			Unit:       line.Unit,
+			UnitFactor: line.UnitFactor,
+		})
+	}
+
+	if err := tx.Model(&line).Update("sorted_quantity", line.SortedQuantity+quantity).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &puts, nil
+}
+
+// CompleteWaveData завершает раскладку. Задания, которые так и не набрали свое количество, закрываются с тем,
+// что успели получить, - недобор остается в строке отгрузки заказа
+func (wr *WavePostgresRepository) CompleteWaveData(userId string, warehouseId int, waveId uint64, actorId string) error {
+	tx := wr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := checkWarehouseOwner(tx, warehouseId, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	wave, err := lockWave(tx, warehouseId, waveId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if wave.Status != domain.WaveStatusSorting {
+		tx.Rollback()
+		return custom_errors.ErrInvalidDocumentStatus
+	}
+
+	var tasks []domain.PickTask
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("wave_id = ? AND status = ?", waveId, domain.PickTaskStatusOpen).
+		Order("id").
+		Find(&tasks).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for i := range tasks {
+		if err := recordPickedQuantity(tx, &tasks[i], tasks[i].PickedQuantity, actorId, true); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Model(wave).Updates(map[string]interface{}{
+		"status":  domain.WaveStatusDone,
+		"done_at": time.Now(),
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// FindAllWaveData возвращает волны склада. Если status не пустой, только в этом статусе
+func (wr *WavePostgresRepository) FindAllWaveData(userId string, warehouseId int, status string) (*[]domain.Wave, error) {
+	var waves []domain.Wave
+
+	if err := checkWarehouseOwner(wr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	query := wr.db.GetDb().Preload("Orders", orderWaveOrders).Where("ware_house_id = ?", warehouseId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Order("created_at DESC").Find(&waves).Error; err != nil {
+		return nil, err
+	}
+
+	return &waves, nil
+}
+
+func (wr *WavePostgresRepository) FindWaveData(userId string, warehouseId int, waveId uint64) (*domain.Wave, error) {
+	var wave domain.Wave
+
+	if err := checkWarehouseOwner(wr.db.GetDb(), warehouseId, userId); err != nil {
+		return nil, err
+	}
+
+	err := wr.db.GetDb().Preload("Orders", orderWaveOrders).Preload("Lines").
+		Where("id = ? AND ware_house_id = ?", waveId, warehouseId).
+		First(&wave).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrWaveNotFound
+		}
+		return nil, err
+	}
+
+	return &wave, nil
+}
+
+func orderWaveOrders(db *gorm.DB) *gorm.DB {
+	return db.Order("wave_orders.slot")
+}
+
+func lockWave(tx *gorm.DB, warehouseId int, waveId uint64) (*domain.Wave, error) {
+	var wave domain.Wave
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND ware_house_id = ?", waveId, warehouseId).
+		First(&wave).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, custom_errors.ErrWaveNotFound
+		}
+		return nil, err
+	}
+
+	return &wave, nil
+}
+
+// waveSurplus - сколько собранного по строке волны товара еще можно вернуть на место хранения: недобранное отмененными
+// заданиями строки за вычетом уже возвращенного. Возвращено то, что разложено, но не попало в задания
+func waveSurplus(tx *gorm.DB, line *domain.WaveLine) (uint64, error) {
+	var totals struct {
+		Cancelled uint64
+		Put       uint64
+	}
+
+	err := tx.Model(&domain.PickTask{}).
+		Select("COALESCE(SUM(CASE WHEN status = ? THEN quantity - picked_quantity ELSE 0 END), 0) AS cancelled, "+
+			"COALESCE(SUM(picked_quantity), 0) AS put", domain.PickTaskStatusCancelled).
+		Where("wave_id = ? AND product_uuid = ?", line.WaveId, line.ProductUuid).
+		Scan(&totals).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var returned uint64
+	if line.SortedQuantity > totals.Put {
+		returned = line.SortedQuantity - totals.Put
+	}
+	if returned >= totals.Cancelled {
+		return 0, nil
+	}
+
+	return totals.Cancelled - returned, nil
+}
+
+// waveSlots возвращает ячейку стены сортировки для каждого заказа волны
+func waveSlots(tx *gorm.DB, waveId uint64) (map[uint64]int, error) {
+	var waveOrders []domain.WaveOrder
+	if err := tx.Where("wave_id = ?", waveId).Find(&waveOrders).Error; err != nil {
+		return nil, err
+	}
+
+	slots := make(map[uint64]int, len(waveOrders))
+	for _, waveOrder := range waveOrders {
+		slots[waveOrder.OrderId] = waveOrder.Slot
+	}
+
+	return slots, nil
+}
@@ -1,10 +1,14 @@
 package repositories
 
 import (
+	"errors"
 	"github.com/Miroslovelife/whareflow/internal/domain"
 	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
 	"github.com/Miroslovelife/whareflow/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log/slog"
+	"math"
 )
 
 type ZoneRepository interface {
@@ -13,6 +17,7 @@ type ZoneRepository interface {
 	FindAllZoneData(userId string, warehouseId int) (*[]domain.Zone, error)
 	FindZoneData(userId string, warehouseId, zoneId int) (*domain.Zone, error)
 	DeleteZoneData(userId string, warehouseId, zoneId int) error
+	FindZoneLoadData(zoneIds []int) (map[int]domain.ZoneLoad, error)
 }
 
 type ZonePostgresRepository struct {
@@ -51,12 +56,44 @@ func (wr *ZonePostgresRepository) UpdateZoneData(zone *domain.Zone, userId strin
 		return custom_errors.ErrWareHouseNotFound
 	}
 
-	resultZone := wr.db.GetDb().Model(zone).Where("id = ?", zone.Id).Updates(zone)
+	tx := wr.db.GetDb().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Смена типа или режима зоны не должна сделать несовместимыми уже лежащие в ней позиции
+	var skus []domain.Sku
+	err := tx.Where("id IN (?)", tx.Model(&domain.Product{}).Select("sku_id").Where("zone_id = ? AND count > 0", zone.Id)).
+		Find(&skus).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range skus {
+		if err := checkStorageCompatible(zone, &skus[i]); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// Пределы пишутся явно, чтобы nil снимал ограничение
+	resultZone := tx.Model(zone).Where("id = ?", zone.Id).Updates(map[string]interface{}{
+		"name":              zone.Name,
+		"capacity":          zone.Capacity,
+		"max_volume":        zone.MaxVolume,
+		"max_weight":        zone.MaxWeight,
+		"max_pallets":       zone.MaxPallets,
+		"zone_type":         zone.ZoneType,
+		"temperature_class": zone.TemperatureClass,
+	})
 	if resultZone.Error != nil {
+		tx.Rollback()
 		return resultZone.Error
 	}
 
-	return nil
+	return tx.Commit().Error
 }
 
 func (wr *ZonePostgresRepository) FindAllZoneData(userId string, warehouseId int) (*[]domain.Zone, error) {
@@ -102,10 +139,223 @@ func (wr *ZonePostgresRepository) DeleteZoneData(userId string, warehouseId, zon
 		return custom_errors.ErrWareHouseNotFound
 	}
 
-	err := wr.db.GetDb().Where("ware_house_id = ? AND id = ?", warehouseId, zoneId).Delete(&zone)
-	if err.Error != nil {
-		return err.Error
+	result := wr.db.GetDb().Where("ware_house_id = ? AND id = ?", warehouseId, zoneId).Delete(&zone)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// FindZoneLoadData возвращает текущую загрузку зон, зона без остатков в результат не попадает
+func (wr *ZonePostgresRepository) FindZoneLoadData(zoneIds []int) (map[int]domain.ZoneLoad, error) {
+	loads := make(map[int]domain.ZoneLoad, len(zoneIds))
+	if len(zoneIds) == 0 {
+		return loads, nil
+	}
+
+	rows, err := findZoneSkuStock(wr.db.GetDb(), zoneIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		load := loads[row.ZoneId]
+		addZoneLoad(&load, row)
+		loads[row.ZoneId] = load
+	}
+
+	return loads, nil
+}
+
+// checkZoneCapacity блокирует зону до конца транзакции и проверяет, что в нее поместится quantity позиции skuId.
+// Вместимость зоны задана в базовых единицах, поэтому остатки дробных позиций пересчитываются по их точности
+func checkZoneCapacity(tx *gorm.DB, zoneId uint64, skuId uint64, quantity uint64) error {
+	var zone domain.Zone
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", zoneId).
+		First(&zone).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrZoneNotFound
+		}
+		return err
+	}
+
+	rows, err := findZoneIncomingStock(tx, zone.Id, skuId, quantity)
+	if err != nil {
+		return err
+	}
+
+	return checkZoneUnits(&zone, rows)
+}
+
+// checkZoneUnits сравнивает остатки зоны в базовых единицах с ее вместимостью
+func checkZoneUnits(zone *domain.Zone, rows []zoneSkuStock) error {
+	var units float64
+	for _, row := range rows {
+		units += zoneStockUnits(row)
+	}
+
+	if units > float64(zone.Capacity) {
+		return custom_errors.ErrZoneCapacityExceeded
+	}
+
+	return nil
+}
+
+// zoneSkuStock - суммарный остаток позиции в зоне вместе с ее габаритами
+type zoneSkuStock struct {
+	ZoneId         int
+	SkuId          uint64
+	Count          uint64
+	Decimals       uint8
+	LengthCm       float64
+	WidthCm        float64
+	HeightCm       float64
+	WeightKg       float64
+	UnitsPerPallet uint64
+}
+
+func findZoneSkuStock(db *gorm.DB, zoneIds []int) ([]zoneSkuStock, error) {
+	var rows []zoneSkuStock
+	err := db.Table("products").
+		Select("products.zone_id, products.sku_id, SUM(products.count) AS count, skus.decimals, "+
+			"skus.length_cm, skus.width_cm, skus.height_cm, skus.weight_kg, skus.units_per_pallet").
+		Joins("JOIN skus ON skus.id = products.sku_id").
+		Where("products.zone_id IN ? AND products.count > 0", zoneIds).
+		Group("products.zone_id, products.sku_id, skus.decimals, skus.length_cm, skus.width_cm, " +
+			"skus.height_cm, skus.weight_kg, skus.units_per_pallet").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// findZoneIncomingStock возвращает остатки зоны по позициям, добавив к позиции skuId приход quantity
+func findZoneIncomingStock(tx *gorm.DB, zoneId int, skuId uint64, quantity uint64) ([]zoneSkuStock, error) {
+	rows, err := findZoneSkuStock(tx, []int{zoneId})
+	if err != nil {
+		return nil, err
+	}
+
+	var incoming *zoneSkuStock
+	for i := range rows {
+		if rows[i].SkuId == skuId {
+			incoming = &rows[i]
+		}
+	}
+	if incoming == nil {
+		var sku domain.Sku
+		if err := tx.Where("id = ?", skuId).First(&sku).Error; err != nil {
+			return nil, err
+		}
+		rows = append(rows, zoneSkuStock{
+			ZoneId:         zoneId,
+			SkuId:          sku.Id,
+			Decimals:       sku.Decimals,
+			LengthCm:       sku.LengthCm,
+			WidthCm:        sku.WidthCm,
+			HeightCm:       sku.HeightCm,
+			WeightKg:       sku.WeightKg,
+			UnitsPerPallet: sku.UnitsPerPallet,
+		})
+		incoming = &rows[len(rows)-1]
+	}
+	incoming.Count += quantity
+
+	return rows, nil
+}
+
+// zoneStockUnits переводит остаток позиции из долей базовой единицы в базовые единицы
+func zoneStockUnits(stock zoneSkuStock) float64 {
+	return float64(stock.Count) / math.Pow10(int(stock.Decimals))
+}
+
+// addZoneLoad учитывает остаток позиции в загрузке. Count хранится в долях базовой единицы,
+// паллеты считаются по каждой позиции отдельно с округлением вверх
+func addZoneLoad(load *domain.ZoneLoad, stock zoneSkuStock) {
+	scale := math.Pow10(int(stock.Decimals))
+	units := zoneStockUnits(stock)
+
+	load.Volume += units * stock.LengthCm * stock.WidthCm * stock.HeightCm / 1e6
+	load.Weight += units * stock.WeightKg
+
+	if stock.UnitsPerPallet > 0 {
+		perPallet := stock.UnitsPerPallet * uint64(scale)
+		load.Pallets += (stock.Count + perPallet - 1) / perPallet
+	}
+}
+
+// checkZoneLoad блокирует зону и проверяет, что после прихода quantity позиции skuId
+// зона не превысит пределы по объему, весу и паллетоместам
+func checkZoneLoad(tx *gorm.DB, zoneId uint64, skuId uint64, quantity uint64) error {
+	var zone domain.Zone
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", zoneId).
+		First(&zone).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrZoneNotFound
+		}
+		return err
+	}
+
+	if zone.MaxVolume == nil && zone.MaxWeight == nil && zone.MaxPallets == nil {
+		return nil
+	}
+
+	rows, err := findZoneIncomingStock(tx, zone.Id, skuId, quantity)
+	if err != nil {
+		return err
+	}
+
+	var load domain.ZoneLoad
+	for _, row := range rows {
+		addZoneLoad(&load, row)
+	}
+
+	if zone.MaxVolume != nil && load.Volume > *zone.MaxVolume {
+		return custom_errors.ErrZoneVolumeExceeded
+	}
+	if zone.MaxWeight != nil && load.Weight > *zone.MaxWeight {
+		return custom_errors.ErrZoneWeightExceeded
+	}
+	if zone.MaxPallets != nil && load.Pallets > *zone.MaxPallets {
+		return custom_errors.ErrZonePalletsExceeded
 	}
 
 	return nil
 }
+
+// checkStorageCompatible проверяет, что позицию можно хранить в зоне, по правилам domain.Zone.CanStore
+func checkStorageCompatible(zone *domain.Zone, sku *domain.Sku) error {
+	if !zone.CanStore(sku) {
+		return custom_errors.ErrIncompatibleStorage
+	}
+
+	return nil
+}
+
+// checkZoneStorage загружает зону и позицию и проверяет их совместимость
+func checkZoneStorage(db *gorm.DB, zoneId uint64, skuId uint64) error {
+	var zone domain.Zone
+	if err := db.Where("id = ?", zoneId).First(&zone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrZoneNotFound
+		}
+		return err
+	}
+
+	var sku domain.Sku
+	if err := db.Where("id = ?", skuId).First(&sku).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return custom_errors.ErrSkuNotFound
+		}
+		return err
+	}
+
+	return checkStorageCompatible(&zone, &sku)
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"testing"
+)
+
+func TestCheckZoneUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		rows     []zoneSkuStock
+		want     error
+	}{
+		{name: "empty zone", capacity: 10},
+		{name: "whole pieces fit", capacity: 10, rows: []zoneSkuStock{{Count: 10}}},
+		{name: "whole pieces overflow", capacity: 10, rows: []zoneSkuStock{{Count: 11}}, want: custom_errors.ErrZoneCapacityExceeded},
+		{name: "kilograms are counted in base units", capacity: 10, rows: []zoneSkuStock{{Count: 9500, Decimals: 3}}},
+		{name: "kilograms overflow", capacity: 10, rows: []zoneSkuStock{{Count: 10001, Decimals: 3}}, want: custom_errors.ErrZoneCapacityExceeded},
+		{
+			name:     "pieces and liters together",
+			capacity: 10,
+			rows:     []zoneSkuStock{{Count: 6}, {Count: 40, Decimals: 1}},
+		},
+		{
+			name:     "pieces and liters overflow",
+			capacity: 10,
+			rows:     []zoneSkuStock{{Count: 6}, {Count: 41, Decimals: 1}},
+			want:     custom_errors.ErrZoneCapacityExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := &domain.Zone{Capacity: tt.capacity}
+			if err := checkZoneUnits(zone, tt.rows); !errors.Is(err, tt.want) {
+				t.Errorf("checkZoneUnits() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddZoneLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		stock zoneSkuStock
+		want  domain.ZoneLoad
+	}{
+		{
+			name:  "pieces",
+			stock: zoneSkuStock{Count: 10, LengthCm: 100, WidthCm: 50, HeightCm: 20, WeightKg: 2, UnitsPerPallet: 4},
+			want:  domain.ZoneLoad{Volume: 1, Weight: 20, Pallets: 3},
+		},
+		{
+			name:  "kilograms with gram precision",
+			stock: zoneSkuStock{Count: 1500, Decimals: 3, WeightKg: 1, UnitsPerPallet: 1},
+			want:  domain.ZoneLoad{Weight: 1.5, Pallets: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var load domain.ZoneLoad
+			addZoneLoad(&load, tt.stock)
+			if load != tt.want {
+				t.Errorf("addZoneLoad() = %+v, want %+v", load, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/config"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"github.com/Miroslovelife/whareflow/pkg/qr"
+	"log/slog"
+)
+
+type CustomerReturnUsecase interface {
+	CreateReturn(in *delivery.ReturnModelRequest, userId string, warehouseId int, actorId string) (*delivery.ReturnModelResponse, error)
+	GetAllReturns(userId string, warehouseId int) ([]delivery.ReturnModelResponse, error)
+	GetReturn(userId string, warehouseId int, returnId uint64) (*delivery.ReturnModelResponse, error)
+	PostReturn(in *delivery.PostReturnModelRequest, userId string, warehouseId int, returnId uint64, actorId string) error
+}
+
+type ICustomerReturnUsecase struct {
+	customerReturnRepository repositories.CustomerReturnRepository
+	shipmentRepository       repositories.ShipmentRepository
+	productRepository        repositories.ProductRepository
+	reorderRuleRepository    repositories.ReorderRuleRepository
+	notifier                 notifier.Notifier
+	qrGenerator              qr.GeneratorQR
+	cfg                      config.Config
+	logger                   slog.Logger
+}
+
+func NewICustomerReturnUsecase(customerReturnRepository repositories.CustomerReturnRepository, shipmentRepository repositories.ShipmentRepository, productRepository repositories.ProductRepository, reorderRuleRepository repositories.ReorderRuleRepository, notifier notifier.Notifier, qrGenerator qr.GeneratorQR, cfg config.Config, logger slog.Logger) *ICustomerReturnUsecase {
+	return &ICustomerReturnUsecase{
+		customerReturnRepository: customerReturnRepository,
+		shipmentRepository:       shipmentRepository,
+		productRepository:        productRepository,
+		reorderRuleRepository:    reorderRuleRepository,
+		notifier:                 notifier,
+		qrGenerator:              qrGenerator,
+		cfg:                      cfg,
+		logger:                   logger,
+	}
+}
+
+// CreateReturn переводит количества из единиц строк отгрузки в доли базовой единицы и заводит черновик возврата
+func (cu *ICustomerReturnUsecase) CreateReturn(in *delivery.ReturnModelRequest, userId string, warehouseId int, actorId string) (*delivery.ReturnModelResponse, error) {
+	shipment, err := cu.shipmentRepository.FindShipmentData(userId, warehouseId, in.ShipmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	factors := make(map[uint64]float64, len(shipment.Lines))
+	for _, line := range shipment.Lines {
+		factors[line.Id] = line.UnitFactor
+	}
+
+	var lines []domain.CustomerReturnLine
+	for _, lineReq := range in.Lines {
+		switch lineReq.Condition {
+		case domain.ReturnConditionResell, domain.ReturnConditionRepair, domain.ReturnConditionScrap:
+		default:
+			return nil, custom_errors.ErrInvalidReturn
+		}
+
+		factor, ok := factors[lineReq.ShipmentLineId]
+		if !ok {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		quantity, err := toStockQuantity(lineReq.Quantity, factor)
+		if err != nil {
+			return nil, err
+		}
+		if quantity == 0 {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		lines = append(lines, domain.CustomerReturnLine{
+			ShipmentLineId: lineReq.ShipmentLineId,
+			Quantity:       quantity,
+			Condition:      lineReq.Condition,
+			ZoneId:         lineReq.ZoneId,
+		})
+	}
+
+	customerReturn := &domain.CustomerReturn{
+		WarehouseId: uint64(warehouseId),
+		ShipmentId:  in.ShipmentId,
+		Status:      domain.ReturnStatusDraft,
+		Comment:     in.Comment,
+		CreatedBy:   actorId,
+		Lines:       lines,
+	}
+
+	if err := cu.customerReturnRepository.InsertCustomerReturnData(customerReturn, userId); err != nil {
+		return nil, err
+	}
+
+	return cu.GetReturn(userId, warehouseId, customerReturn.Id)
+}
+
+func (cu *ICustomerReturnUsecase) GetAllReturns(userId string, warehouseId int) ([]delivery.ReturnModelResponse, error) {
+	returns, err := cu.customerReturnRepository.FindAllCustomerReturnData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	returnsRes := []delivery.ReturnModelResponse{}
+	for _, customerReturn := range *returns {
+		returnsRes = append(returnsRes, customerReturnToResponse(&customerReturn))
+	}
+
+	return returnsRes, nil
+}
+
+func (cu *ICustomerReturnUsecase) GetReturn(userId string, warehouseId int, returnId uint64) (*delivery.ReturnModelResponse, error) {
+	customerReturn, err := cu.customerReturnRepository.FindCustomerReturnData(userId, warehouseId, returnId)
+	if err != nil {
+		return nil, err
+	}
+
+	returnRes := customerReturnToResponse(customerReturn)
+
+	return &returnRes, nil
+}
+
+func (cu *ICustomerReturnUsecase) PostReturn(in *delivery.PostReturnModelRequest, userId string, warehouseId int, returnId uint64, actorId string) error {
+	serials := make(map[uint64][]string)
+	for _, line := range in.Lines {
+		if len(line.Serials) > 0 {
+			serials[line.LineId] = line.Serials
+		}
+	}
+
+	createdProducts, err := cu.customerReturnRepository.PostCustomerReturnData(userId, warehouseId, returnId, serials, actorId)
+	if err != nil {
+		return err
+	}
+
+	// Возвращенный товар учитывается на существующих строках, QR нужен только строкам, созданным проведением.
+	// Возврат к этому моменту уже проведен, поэтому ошибка выдачи QR только пишется в лог
+	for _, product := range *createdProducts {
+		qrPath, err := generateProductQR(cu.qrGenerator, cu.cfg, warehouseId, product.ZoneId, string(product.Uuid))
+		if err != nil {
+			cu.logger.Error(fmt.Sprintf("Return %d posted, but qr generation for product %s failed: %v", returnId, product.Uuid, err))
+			continue
+		}
+
+		if err := cu.productRepository.UpdateProductQrData(string(product.Uuid), qrPath); err != nil {
+			cu.logger.Error(fmt.Sprintf("Return %d posted, but qr generation for product %s failed: %v", returnId, product.Uuid, err))
+		}
+	}
+
+	if err := evaluateReorderRules(cu.reorderRuleRepository, cu.notifier, warehouseId, nil); err != nil {
+		cu.logger.Error(fmt.Sprintf("Return %d posted, but reorder evaluation failed: %v", returnId, err))
+	}
+
+	return nil
+}
+
+func customerReturnToResponse(customerReturn *domain.CustomerReturn) delivery.ReturnModelResponse {
+	linesRes := []delivery.ReturnLineModelResponse{}
+	for _, line := range customerReturn.Lines {
+		linesRes = append(linesRes, delivery.ReturnLineModelResponse{
+			Id:                line.Id,
+			ShipmentLineId:    line.ShipmentLineId,
+			ProductUuid:       line.ProductUuid,
+			SkuId:             line.SkuId,
+			Unit:              line.Unit,
+			Quantity:          fromStockQuantity(line.Quantity, line.UnitFactor),
+			Condition:         line.Condition,
+			ZoneId:            line.ZoneId,
+			ResultProductUuid: line.ResultProductUuid,
+			HoldId:            line.HoldId,
+		})
+	}
+
+	return delivery.ReturnModelResponse{
+		Id:          customerReturn.Id,
+		WarehouseId: customerReturn.WarehouseId,
+		ShipmentId:  customerReturn.ShipmentId,
+		Status:      customerReturn.Status,
+		Comment:     customerReturn.Comment,
+		CreatedBy:   customerReturn.CreatedBy,
+		CreatedAt:   customerReturn.CreatedAt,
+		PostedAt:    customerReturn.PostedAt,
+		Lines:       linesRes,
+	}
+}
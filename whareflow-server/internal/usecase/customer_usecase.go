@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"strings"
+)
+
+type CustomerUsecase interface {
+	CreateCustomer(in *delivery.CustomerModelRequest, userId string) (*delivery.CustomerModelResponse, error)
+	UpdateCustomer(in *delivery.CustomerModelRequest, userId string, customerId uint64) (*delivery.CustomerModelResponse, error)
+	GetAllCustomers(userId string) ([]delivery.CustomerModelResponse, error)
+	GetCustomer(userId string, customerId uint64) (*delivery.CustomerModelResponse, error)
+}
+
+type ICustomerUsecase struct {
+	customerRepository repositories.CustomerRepository
+}
+
+func NewICustomerUsecase(customerRepository repositories.CustomerRepository) *ICustomerUsecase {
+	return &ICustomerUsecase{
+		customerRepository: customerRepository,
+	}
+}
+
+func (cu *ICustomerUsecase) CreateCustomer(in *delivery.CustomerModelRequest, userId string) (*delivery.CustomerModelResponse, error) {
+	customer, err := buildCustomer(in)
+	if err != nil {
+		return nil, err
+	}
+	customer.UuidUser = userId
+
+	if err := cu.customerRepository.InsertCustomerData(customer); err != nil {
+		return nil, err
+	}
+
+	return cu.GetCustomer(userId, customer.Id)
+}
+
+func (cu *ICustomerUsecase) UpdateCustomer(in *delivery.CustomerModelRequest, userId string, customerId uint64) (*delivery.CustomerModelResponse, error) {
+	customer, err := buildCustomer(in)
+	if err != nil {
+		return nil, err
+	}
+	customer.Id = customerId
+
+	if err := cu.customerRepository.UpdateCustomerData(customer, userId); err != nil {
+		return nil, err
+	}
+
+	return cu.GetCustomer(userId, customerId)
+}
+
+func (cu *ICustomerUsecase) GetAllCustomers(userId string) ([]delivery.CustomerModelResponse, error) {
+	customers, err := cu.customerRepository.FindAllCustomerData(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	customersRes := []delivery.CustomerModelResponse{}
+	for _, customer := range *customers {
+		customersRes = append(customersRes, customerToResponse(&customer))
+	}
+
+	return customersRes, nil
+}
+
+func (cu *ICustomerUsecase) GetCustomer(userId string, customerId uint64) (*delivery.CustomerModelResponse, error) {
+	customer, err := cu.customerRepository.FindCustomerData(userId, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	customerRes := customerToResponse(customer)
+
+	return &customerRes, nil
+}
+
+// buildCustomer проверяет карточку покупателя. Address - адрес доставки, из обязательного только название
+func buildCustomer(in *delivery.CustomerModelRequest) (*domain.Customer, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, custom_errors.ErrInvalidCustomer
+	}
+
+	return &domain.Customer{
+		Name:        name,
+		ContactName: strings.TrimSpace(in.ContactName),
+		Email:       strings.TrimSpace(in.Email),
+		Phone:       strings.TrimSpace(in.Phone),
+		Address:     strings.TrimSpace(in.Address),
+	}, nil
+}
+
+func customerToResponse(customer *domain.Customer) delivery.CustomerModelResponse {
+	return delivery.CustomerModelResponse{
+		Id:          customer.Id,
+		Name:        customer.Name,
+		ContactName: customer.ContactName,
+		Email:       customer.Email,
+		Phone:       customer.Phone,
+		Address:     customer.Address,
+		CreatedAt:   customer.CreatedAt,
+	}
+}
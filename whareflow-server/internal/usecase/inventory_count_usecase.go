@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"log/slog"
+)
+
+type InventoryCountUsecase interface {
+	CreateInventoryCount(in *delivery.InventoryCountModelRequest, userId string, warehouseId int, actorId string) (*delivery.InventoryCountModelResponse, error)
+	GetAllInventoryCounts(userId string, warehouseId int) ([]delivery.InventoryCountModelResponse, error)
+	GetInventoryCount(userId string, warehouseId int, countId uint64) (*delivery.InventoryCountModelResponse, error)
+	SubmitCount(in *delivery.SubmitCountModelRequest, userId string, warehouseId int, countId uint64, actorId string) error
+	ApproveInventoryCount(userId string, warehouseId int, countId uint64, actorId string) (*delivery.VarianceReportResponse, error)
+	CancelInventoryCount(userId string, warehouseId int, countId uint64) error
+	GetVarianceReport(userId string, warehouseId int, countId uint64) (*delivery.VarianceReportResponse, error)
+}
+
+type IInventoryCountUsecase struct {
+	inventoryCountRepository repositories.InventoryCountRepository
+	skuRepository            repositories.SkuRepository
+	reorderRuleRepository    repositories.ReorderRuleRepository
+	notifier                 notifier.Notifier
+	logger                   slog.Logger
+}
+
+func NewIInventoryCountUsecase(inventoryCountRepository repositories.InventoryCountRepository, skuRepository repositories.SkuRepository, reorderRuleRepository repositories.ReorderRuleRepository, notifier notifier.Notifier, logger slog.Logger) *IInventoryCountUsecase {
+	return &IInventoryCountUsecase{
+		inventoryCountRepository: inventoryCountRepository,
+		skuRepository:            skuRepository,
+		reorderRuleRepository:    reorderRuleRepository,
+		notifier:                 notifier,
+		logger:                   logger,
+	}
+}
+
+func (iu *IInventoryCountUsecase) CreateInventoryCount(in *delivery.InventoryCountModelRequest, userId string, warehouseId int, actorId string) (*delivery.InventoryCountModelResponse, error) {
+	count := &domain.InventoryCount{
+		WarehouseId: uint64(warehouseId),
+		ZoneId:      in.ZoneId,
+		Status:      domain.InventoryCountStatusOpen,
+		Comment:     in.Comment,
+		CreatedBy:   actorId,
+	}
+
+	if err := iu.inventoryCountRepository.InsertInventoryCountData(count, userId); err != nil {
+		return nil, err
+	}
+
+	return iu.GetInventoryCount(userId, warehouseId, count.Id)
+}
+
+func (iu *IInventoryCountUsecase) GetAllInventoryCounts(userId string, warehouseId int) ([]delivery.InventoryCountModelResponse, error) {
+	counts, err := iu.inventoryCountRepository.FindAllInventoryCountData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	countsRes := []delivery.InventoryCountModelResponse{}
+	for _, count := range *counts {
+		countRes, err := iu.inventoryCountToResponse(&count, userId)
+		if err != nil {
+			return nil, err
+		}
+		countsRes = append(countsRes, countRes)
+	}
+
+	return countsRes, nil
+}
+
+func (iu *IInventoryCountUsecase) GetInventoryCount(userId string, warehouseId int, countId uint64) (*delivery.InventoryCountModelResponse, error) {
+	count, err := iu.inventoryCountRepository.FindInventoryCountData(userId, warehouseId, countId)
+	if err != nil {
+		return nil, err
+	}
+
+	countRes, err := iu.inventoryCountToResponse(count, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &countRes, nil
+}
+
+func (iu *IInventoryCountUsecase) SubmitCount(in *delivery.SubmitCountModelRequest, userId string, warehouseId int, countId uint64, actorId string) error {
+	productId := in.ProductUuid
+	if in.Qr != "" {
+		var err error
+		productId, err = parseProductQR(in.Qr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if productId == "" {
+		return custom_errors.ErrInvalidProductQR
+	}
+
+	skus, err := iu.skuRepository.FindProductSkusData(userId, []string{productId})
+	if err != nil {
+		return err
+	}
+
+	sku, err := productSku(skus, productId)
+	if err != nil {
+		return err
+	}
+
+	counted, err := quantityToStock(sku, in.CountedQuantity, in.Unit)
+	if err != nil {
+		return err
+	}
+
+	return iu.inventoryCountRepository.SubmitInventoryCountData(userId, warehouseId, countId, productId, counted, actorId)
+}
+
+// ApproveInventoryCount после корректировки остатков проверяет правила перезаказа склада.
+// Сессия к этому моменту уже утверждена, поэтому ошибка проверки только пишется в лог
+func (iu *IInventoryCountUsecase) ApproveInventoryCount(userId string, warehouseId int, countId uint64, actorId string) (*delivery.VarianceReportResponse, error) {
+	if err := iu.inventoryCountRepository.ApproveInventoryCountData(userId, warehouseId, countId, actorId); err != nil {
+		return nil, err
+	}
+
+	if err := evaluateReorderRules(iu.reorderRuleRepository, iu.notifier, warehouseId, nil); err != nil {
+		iu.logger.Error(fmt.Sprintf("Inventory count %d approved, but reorder evaluation failed: %v", countId, err))
+	}
+
+	return iu.GetVarianceReport(userId, warehouseId, countId)
+}
+
+func (iu *IInventoryCountUsecase) CancelInventoryCount(userId string, warehouseId int, countId uint64) error {
+	return iu.inventoryCountRepository.CancelInventoryCountData(userId, warehouseId, countId)
+}
+
+func (iu *IInventoryCountUsecase) GetVarianceReport(userId string, warehouseId int, countId uint64) (*delivery.VarianceReportResponse, error) {
+	count, err := iu.inventoryCountRepository.FindInventoryCountData(userId, warehouseId, countId)
+	if err != nil {
+		return nil, err
+	}
+
+	skus, err := iu.countSkus(count, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &delivery.VarianceReportResponse{
+		InventoryCountId: count.Id,
+		Status:           count.Status,
+		Lines:            []delivery.VarianceLineResponse{},
+	}
+
+	for _, line := range count.Lines {
+		if line.CountedQuantity == nil {
+			report.UncountedLines++
+			continue
+		}
+		report.CountedLines++
+
+		sku := skus[line.ProductUuid]
+		_, variance, err := stockDeltaToQuantity(sku, int64(*line.CountedQuantity)-int64(line.ExpectedQuantity), "")
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case variance > 0:
+			report.TotalSurplus += variance
+		case variance < 0:
+			report.TotalShortage -= variance
+		default:
+			continue
+		}
+
+		report.Lines = append(report.Lines, delivery.VarianceLineResponse{
+			ProductUuid:     line.ProductUuid,
+			Unit:            sku.Unit,
+			BookQuantity:    baseQuantity(sku, line.ExpectedQuantity),
+			CountedQuantity: baseQuantity(sku, *line.CountedQuantity),
+			Variance:        variance,
+		})
+	}
+
+	return report, nil
+}
+
+// countSkus возвращает позиции каталога строк сессии по uuid товара
+func (iu *IInventoryCountUsecase) countSkus(count *domain.InventoryCount, userId string) (map[string]*domain.Sku, error) {
+	productIds := make([]string, 0, len(count.Lines))
+	for _, line := range count.Lines {
+		productIds = append(productIds, line.ProductUuid)
+	}
+
+	skus, err := iu.skuRepository.FindProductSkusData(userId, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, productId := range productIds {
+		if _, err := productSku(skus, productId); err != nil {
+			return nil, err
+		}
+	}
+
+	return skus, nil
+}
+
+func (iu *IInventoryCountUsecase) inventoryCountToResponse(count *domain.InventoryCount, userId string) (delivery.InventoryCountModelResponse, error) {
+	skus, err := iu.countSkus(count, userId)
+	if err != nil {
+		return delivery.InventoryCountModelResponse{}, err
+	}
+
+	linesRes := []delivery.InventoryCountLineModelResponse{}
+	for _, line := range count.Lines {
+		sku := skus[line.ProductUuid]
+		lineRes := delivery.InventoryCountLineModelResponse{
+			Id:               line.Id,
+			ProductUuid:      line.ProductUuid,
+			Unit:             sku.Unit,
+			ExpectedQuantity: baseQuantity(sku, line.ExpectedQuantity),
+			CountedBy:        line.CountedBy,
+			CountedAt:        line.CountedAt,
+		}
+		if line.CountedQuantity != nil {
+			counted := baseQuantity(sku, *line.CountedQuantity)
+			lineRes.CountedQuantity = &counted
+		}
+		linesRes = append(linesRes, lineRes)
+	}
+
+	return delivery.InventoryCountModelResponse{
+		Id:          count.Id,
+		WarehouseId: count.WarehouseId,
+		ZoneId:      count.ZoneId,
+		Status:      count.Status,
+		Comment:     count.Comment,
+		CreatedBy:   count.CreatedBy,
+		ApprovedBy:  count.ApprovedBy,
+		CreatedAt:   count.CreatedAt,
+		ClosedAt:    count.ClosedAt,
+		Lines:       linesRes,
+	}, nil
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/config"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"github.com/Miroslovelife/whareflow/pkg/qr"
+	"log/slog"
+)
+
+type KitUsecase interface {
+	GetKit(userId string, kitSkuId uint64) (*delivery.KitModelResponse, error)
+	UpdateKit(in *delivery.KitModelRequest, userId string, kitSkuId uint64) (*delivery.KitModelResponse, error)
+	AssembleKit(in *delivery.AssembleKitModelRequest, userId string, warehouseId int, actorId string) (*delivery.KitOperationModelResponse, error)
+	DisassembleKit(in *delivery.DisassembleKitModelRequest, userId string, warehouseId int, actorId string) (*delivery.KitOperationModelResponse, error)
+	GetAllKitOperations(userId string, warehouseId int) ([]delivery.KitOperationModelResponse, error)
+	GetKitOperation(userId string, warehouseId int, operationId uint64) (*delivery.KitOperationModelResponse, error)
+}
+
+type IKitUsecase struct {
+	kitRepository         repositories.KitRepository
+	skuRepository         repositories.SkuRepository
+	productRepository     repositories.ProductRepository
+	reorderRuleRepository repositories.ReorderRuleRepository
+	notifier              notifier.Notifier
+	qrGenerator           qr.GeneratorQR
+	cfg                   config.Config
+	logger                slog.Logger
+}
+
+func NewIKitUsecase(kitRepository repositories.KitRepository, skuRepository repositories.SkuRepository, productRepository repositories.ProductRepository, reorderRuleRepository repositories.ReorderRuleRepository, notifier notifier.Notifier, qrGenerator qr.GeneratorQR, cfg config.Config, logger slog.Logger) *IKitUsecase {
+	return &IKitUsecase{
+		kitRepository:         kitRepository,
+		skuRepository:         skuRepository,
+		productRepository:     productRepository,
+		reorderRuleRepository: reorderRuleRepository,
+		notifier:              notifier,
+		qrGenerator:           qrGenerator,
+		cfg:                   cfg,
+		logger:                logger,
+	}
+}
+
+func (ku *IKitUsecase) GetKit(userId string, kitSkuId uint64) (*delivery.KitModelResponse, error) {
+	components, err := ku.kitRepository.FindKitComponentsData(userId, kitSkuId)
+	if err != nil {
+		return nil, err
+	}
+
+	componentsRes := []delivery.KitComponentModelResponse{}
+	for _, component := range *components {
+		componentRes := delivery.KitComponentModelResponse{
+			SkuId:    component.ComponentSkuId,
+			Quantity: float64(component.Quantity),
+		}
+		if component.ComponentSku != nil {
+			componentRes.Code = component.ComponentSku.Code
+			componentRes.Name = component.ComponentSku.Name
+			componentRes.Unit = component.ComponentSku.Unit
+			componentRes.Quantity = baseQuantity(component.ComponentSku, component.Quantity)
+		}
+		componentsRes = append(componentsRes, componentRes)
+	}
+
+	return &delivery.KitModelResponse{
+		KitSkuId:   kitSkuId,
+		Components: componentsRes,
+	}, nil
+}
+
+// UpdateKit переводит количества компонентов в доли их базовых единиц и заменяет спецификацию набора
+func (ku *IKitUsecase) UpdateKit(in *delivery.KitModelRequest, userId string, kitSkuId uint64) (*delivery.KitModelResponse, error) {
+	var components []domain.KitComponent
+	for _, componentReq := range in.Components {
+		sku, err := ku.skuRepository.FindSkuData(userId, componentReq.SkuId)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := quantityToStock(sku, componentReq.Quantity, componentReq.Unit)
+		if err != nil {
+			return nil, err
+		}
+		if quantity == 0 {
+			return nil, custom_errors.ErrInvalidKit
+		}
+
+		components = append(components, domain.KitComponent{
+			ComponentSkuId: sku.Id,
+			Quantity:       quantity,
+		})
+	}
+
+	if err := ku.kitRepository.ReplaceKitComponentsData(userId, kitSkuId, components); err != nil {
+		return nil, err
+	}
+
+	return ku.GetKit(userId, kitSkuId)
+}
+
+// AssembleKit после списания компонентов проверяет правила перезаказа по ним
+func (ku *IKitUsecase) AssembleKit(in *delivery.AssembleKitModelRequest, userId string, warehouseId int, actorId string) (*delivery.KitOperationModelResponse, error) {
+	kitSku, err := ku.skuRepository.FindSkuData(userId, in.KitSkuId)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, err := quantityToStock(kitSku, in.Quantity, in.Unit)
+	if err != nil {
+		return nil, err
+	}
+	if quantity == 0 {
+		return nil, custom_errors.ErrInvalidDocumentLine
+	}
+
+	operation := &domain.KitOperation{
+		WarehouseId: uint64(warehouseId),
+		KitSkuId:    in.KitSkuId,
+		Kind:        domain.KitOperationAssembly,
+		Quantity:    quantity,
+		ZoneId:      &in.ZoneId,
+		Comment:     in.Comment,
+		CreatedBy:   actorId,
+	}
+
+	createdProducts, err := ku.kitRepository.AssembleKitData(operation, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ku.afterKitOperation(operation, createdProducts)
+
+	return ku.GetKitOperation(userId, warehouseId, operation.Id)
+}
+
+// DisassembleKit после списания наборов проверяет правило перезаказа по позиции набора
+func (ku *IKitUsecase) DisassembleKit(in *delivery.DisassembleKitModelRequest, userId string, warehouseId int, actorId string) (*delivery.KitOperationModelResponse, error) {
+	kitProduct, err := ku.productRepository.FindProductData(userId, in.ProductUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, err := quantityToStock(kitProduct.Sku, in.Quantity, in.Unit)
+	if err != nil {
+		return nil, err
+	}
+	if quantity == 0 {
+		return nil, custom_errors.ErrInvalidDocumentLine
+	}
+
+	operation := &domain.KitOperation{
+		WarehouseId: uint64(warehouseId),
+		Kind:        domain.KitOperationDisassembly,
+		Quantity:    quantity,
+		ZoneId:      in.ZoneId,
+		Comment:     in.Comment,
+		CreatedBy:   actorId,
+	}
+
+	createdProducts, err := ku.kitRepository.DisassembleKitData(operation, in.ProductUuid, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	ku.afterKitOperation(operation, createdProducts)
+
+	return ku.GetKitOperation(userId, warehouseId, operation.Id)
+}
+
+func (ku *IKitUsecase) GetAllKitOperations(userId string, warehouseId int) ([]delivery.KitOperationModelResponse, error) {
+	operations, err := ku.kitRepository.FindAllKitOperationData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	operationsRes := []delivery.KitOperationModelResponse{}
+	for _, operation := range *operations {
+		operationRes, err := kitOperationToResponse(&operation)
+		if err != nil {
+			return nil, err
+		}
+		operationsRes = append(operationsRes, operationRes)
+	}
+
+	return operationsRes, nil
+}
+
+func (ku *IKitUsecase) GetKitOperation(userId string, warehouseId int, operationId uint64) (*delivery.KitOperationModelResponse, error) {
+	operation, err := ku.kitRepository.FindKitOperationData(userId, warehouseId, operationId)
+	if err != nil {
+		return nil, err
+	}
+
+	operationRes, err := kitOperationToResponse(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &operationRes, nil
+}
+
+// afterKitOperation выдает QR строкам товара, созданным операцией, и проверяет перезаказ по списанным позициям.
+// Операция к этому моменту уже проведена, поэтому ошибки этих шагов только пишутся в лог
+func (ku *IKitUsecase) afterKitOperation(operation *domain.KitOperation, createdProducts *[]domain.Product) {
+	warehouseId := int(operation.WarehouseId)
+
+	for _, product := range *createdProducts {
+		qrPath, err := generateProductQR(ku.qrGenerator, ku.cfg, warehouseId, product.ZoneId, string(product.Uuid))
+		if err != nil {
+			ku.logger.Error(fmt.Sprintf("Kit %s %d done, but qr generation for product %s failed: %v", operation.Kind, operation.Id, product.Uuid, err))
+			continue
+		}
+
+		if err := ku.productRepository.UpdateProductQrData(string(product.Uuid), qrPath); err != nil {
+			ku.logger.Error(fmt.Sprintf("Kit %s %d done, but qr generation for product %s failed: %v", operation.Kind, operation.Id, product.Uuid, err))
+		}
+	}
+
+	var skuIds []uint64
+	for _, line := range operation.Lines {
+		if line.Quantity < 0 {
+			skuIds = append(skuIds, line.SkuId)
+		}
+	}
+
+	if err := evaluateReorderRules(ku.reorderRuleRepository, ku.notifier, warehouseId, skuIds); err != nil {
+		ku.logger.Error(fmt.Sprintf("Kit %s %d done, but reorder evaluation failed: %v", operation.Kind, operation.Id, err))
+	}
+}
+
+func kitOperationToResponse(operation *domain.KitOperation) (delivery.KitOperationModelResponse, error) {
+	linesRes := []delivery.KitOperationLineModelResponse{}
+	for _, line := range operation.Lines {
+		if line.Sku == nil {
+			return delivery.KitOperationModelResponse{}, custom_errors.ErrSkuNotFound
+		}
+
+		unit, quantity, err := stockDeltaToQuantity(line.Sku, line.Quantity, "")
+		if err != nil {
+			return delivery.KitOperationModelResponse{}, err
+		}
+
+		linesRes = append(linesRes, delivery.KitOperationLineModelResponse{
+			ProductUuid: line.ProductUuid,
+			SkuId:       line.SkuId,
+			Unit:        unit,
+			Quantity:    quantity,
+			MovementId:  line.MovementId,
+		})
+	}
+
+	if operation.KitSku == nil {
+		return delivery.KitOperationModelResponse{}, custom_errors.ErrSkuNotFound
+	}
+
+	return delivery.KitOperationModelResponse{
+		Id:          operation.Id,
+		WarehouseId: operation.WarehouseId,
+		KitSkuId:    operation.KitSkuId,
+		Kind:        operation.Kind,
+		Unit:        operation.KitSku.Unit,
+		Quantity:    baseQuantity(operation.KitSku, operation.Quantity),
+		ZoneId:      operation.ZoneId,
+		Comment:     operation.Comment,
+		CreatedBy:   operation.CreatedBy,
+		CreatedAt:   operation.CreatedAt,
+		Lines:       linesRes,
+	}, nil
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/config"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/qr"
+	"strings"
+)
+
+type LocationUsecase interface {
+	CreateLocation(in *delivery.LocationModelRequest, userId string, warehouseId int, zoneId uint64) (*delivery.LocationModelResponse, error)
+	UpdateLocation(in *delivery.LocationModelRequest, userId string, warehouseId int, zoneId uint64, locationId uint64) error
+	GetLocationTree(userId string, warehouseId int, zoneId uint64) ([]delivery.LocationModelResponse, error)
+	DeleteLocation(userId string, warehouseId int, zoneId uint64, locationId uint64) error
+	GetLocationQr(userId string, warehouseId int, zoneId uint64, locationId uint64) (*delivery.LocationQrResponse, error)
+}
+
+type ILocationUsecase struct {
+	locationRepository repositories.LocationRepository
+	qrGenerator        qr.GeneratorQR
+	cfg                config.Config
+}
+
+func NewILocationUsecase(locationRepository repositories.LocationRepository, qrGenerator qr.GeneratorQR, cfg config.Config) *ILocationUsecase {
+	return &ILocationUsecase{
+		locationRepository: locationRepository,
+		qrGenerator:        qrGenerator,
+		cfg:                cfg,
+	}
+}
+
+// CreateLocation создает узел дерева. Для ячейки сразу выпускается QR-код, по которому ее сканируют при размещении
+func (lu *ILocationUsecase) CreateLocation(in *delivery.LocationModelRequest, userId string, warehouseId int, zoneId uint64) (*delivery.LocationModelResponse, error) {
+	code := strings.TrimSpace(in.Code)
+	if code == "" {
+		return nil, custom_errors.ErrInvalidLocation
+	}
+
+	location := &domain.Location{
+		ZoneId:   zoneId,
+		ParentId: in.ParentId,
+		Kind:     in.Kind,
+		Code:     code,
+		Capacity: in.Capacity,
+	}
+
+	if err := lu.locationRepository.InsertLocationData(location, userId, warehouseId); err != nil {
+		return nil, err
+	}
+
+	if location.Kind == domain.LocationKindBin {
+		qrPath, err := generateLocationQR(lu.qrGenerator, lu.cfg, warehouseId, zoneId, location.Id)
+		if err != nil {
+			return nil, fmt.Errorf("location %d created, but qr generation failed: %w", location.Id, err)
+		}
+
+		if err := lu.locationRepository.UpdateLocationQrData(location.Id, qrPath); err != nil {
+			return nil, fmt.Errorf("location %d created, but qr generation failed: %w", location.Id, err)
+		}
+		location.QrPath = qrPath
+	}
+
+	locationRes := locationToResponse(location, 0)
+
+	return &locationRes, nil
+}
+
+func (lu *ILocationUsecase) UpdateLocation(in *delivery.LocationModelRequest, userId string, warehouseId int, zoneId uint64, locationId uint64) error {
+	code := strings.TrimSpace(in.Code)
+	if code == "" {
+		return custom_errors.ErrInvalidLocation
+	}
+
+	location := &domain.Location{
+		Id:       locationId,
+		ZoneId:   zoneId,
+		Code:     code,
+		Capacity: in.Capacity,
+	}
+
+	return lu.locationRepository.UpdateLocationData(location, userId, warehouseId)
+}
+
+// GetLocationTree возвращает дерево адресов зоны. Заполненность узла складывается из остатков всех его ячеек
+func (lu *ILocationUsecase) GetLocationTree(userId string, warehouseId int, zoneId uint64) ([]delivery.LocationModelResponse, error) {
+	locations, err := lu.locationRepository.FindAllLocationData(userId, warehouseId, zoneId)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, err := lu.locationRepository.FindLocationStockData(zoneId)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint64][]domain.Location)
+	var roots []domain.Location
+	for _, location := range *locations {
+		if location.ParentId == nil {
+			roots = append(roots, location)
+			continue
+		}
+		children[*location.ParentId] = append(children[*location.ParentId], location)
+	}
+
+	var build func(location domain.Location) delivery.LocationModelResponse
+	build = func(location domain.Location) delivery.LocationModelResponse {
+		locationRes := locationToResponse(&location, stock[location.Id])
+		for _, child := range children[location.Id] {
+			childRes := build(child)
+			locationRes.Occupied += childRes.Occupied
+			locationRes.Children = append(locationRes.Children, childRes)
+		}
+
+		return locationRes
+	}
+
+	treeRes := []delivery.LocationModelResponse{}
+	for _, root := range roots {
+		treeRes = append(treeRes, build(root))
+	}
+
+	return treeRes, nil
+}
+
+func (lu *ILocationUsecase) DeleteLocation(userId string, warehouseId int, zoneId uint64, locationId uint64) error {
+	return lu.locationRepository.DeleteLocationData(userId, warehouseId, zoneId, locationId)
+}
+
+// GetLocationQr отдает QR-код ячейки картинкой для печати этикетки
+func (lu *ILocationUsecase) GetLocationQr(userId string, warehouseId int, zoneId uint64, locationId uint64) (*delivery.LocationQrResponse, error) {
+	location, err := lu.locationRepository.FindLocationData(userId, warehouseId, zoneId, locationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if location.Kind != domain.LocationKindBin {
+		return nil, custom_errors.ErrInvalidLocation
+	}
+
+	// Ячейки, созданные до сбоя генерации, получают QR-код при первом запросе
+	if location.QrPath == "" {
+		qrPath, err := generateLocationQR(lu.qrGenerator, lu.cfg, warehouseId, zoneId, location.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := lu.locationRepository.UpdateLocationQrData(location.Id, qrPath); err != nil {
+			return nil, err
+		}
+		location.QrPath = qrPath
+	}
+
+	qrImage, err := lu.qrGenerator.DecodeToBase64(location.QrPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery.LocationQrResponse{
+		LocationId: location.Id,
+		Code:       location.Code,
+		QrImage:    qrImage,
+	}, nil
+}
+
+func generateLocationQR(qrGenerator qr.GeneratorQR, cfg config.Config, warehouseId int, zoneId uint64, locationId uint64) (string, error) {
+	qrData := fmt.Sprintf("%s%d/%d/locations/%d", cfg.QR.UrlFrontend, warehouseId, zoneId, locationId)
+
+	pathToFle, err := qrGenerator.Generate(qrData, cfg.QR.PathToFile, fmt.Sprintf("location_%d.png", locationId))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("./%s", pathToFle), nil
+}
+
+func locationToResponse(location *domain.Location, occupied uint64) delivery.LocationModelResponse {
+	return delivery.LocationModelResponse{
+		Id:        location.Id,
+		ZoneId:    location.ZoneId,
+		ParentId:  location.ParentId,
+		Kind:      location.Kind,
+		Code:      location.Code,
+		Capacity:  location.Capacity,
+		Occupied:  occupied,
+		QrPath:    location.QrPath,
+		CreatedAt: location.CreatedAt,
+		Children:  []delivery.LocationModelResponse{},
+	}
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/label"
+	"math"
+)
+
+type ParcelUsecase interface {
+	CreateParcel(in *delivery.ParcelModelRequest, userId string, warehouseId int, shipmentId uint64, actorId string) (*delivery.ParcelModelResponse, error)
+	DeleteParcel(userId string, warehouseId int, shipmentId uint64, parcelId uint64) error
+	GetParcels(userId string, warehouseId int, shipmentId uint64) ([]delivery.ParcelModelResponse, error)
+	GetParcelLabel(userId string, warehouseId int, shipmentId uint64, parcelId uint64) ([]byte, error)
+}
+
+type IParcelUsecase struct {
+	parcelRepository    repositories.ParcelRepository
+	shipmentRepository  repositories.ShipmentRepository
+	warehouseRepository repositories.WareHouseRepository
+	labelRenderer       label.RendererLabel
+}
+
+func NewIParcelUsecase(parcelRepository repositories.ParcelRepository, shipmentRepository repositories.ShipmentRepository, warehouseRepository repositories.WareHouseRepository, labelRenderer label.RendererLabel) *IParcelUsecase {
+	return &IParcelUsecase{
+		parcelRepository:    parcelRepository,
+		shipmentRepository:  shipmentRepository,
+		warehouseRepository: warehouseRepository,
+		labelRenderer:       labelRenderer,
+	}
+}
+
+// CreateParcel переводит количества из единиц строк отгрузки в доли базовой единицы
+func (pu *IParcelUsecase) CreateParcel(in *delivery.ParcelModelRequest, userId string, warehouseId int, shipmentId uint64, actorId string) (*delivery.ParcelModelResponse, error) {
+	if !validDimension(in.LengthCm) || !validDimension(in.WidthCm) || !validDimension(in.HeightCm) {
+		return nil, custom_errors.ErrInvalidParcel
+	}
+
+	parcel := &domain.Parcel{
+		ShipmentId: shipmentId,
+		LengthCm:   in.LengthCm,
+		WidthCm:    in.WidthCm,
+		HeightCm:   in.HeightCm,
+		CreatedBy:  actorId,
+	}
+
+	if in.WeightKg != nil {
+		if !validDimension(*in.WeightKg) || *in.WeightKg == 0 {
+			return nil, custom_errors.ErrInvalidParcel
+		}
+		parcel.WeightKg = *in.WeightKg
+	}
+
+	shipment, err := pu.shipmentRepository.FindShipmentData(userId, warehouseId, shipmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	linesById := make(map[uint64]domain.ShipmentLine, len(shipment.Lines))
+	for _, line := range shipment.Lines {
+		linesById[line.Id] = line
+	}
+
+	for _, lineReq := range in.Lines {
+		line, ok := linesById[lineReq.LineId]
+		if !ok {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		quantity, err := toStockQuantity(lineReq.Quantity, line.UnitFactor)
+		if err != nil {
+			return nil, err
+		}
+
+		parcel.Lines = append(parcel.Lines, domain.ParcelLine{
+			ShipmentLineId: line.Id,
+			Quantity:       quantity,
+		})
+	}
+
+	if err := pu.parcelRepository.InsertParcelData(parcel, userId, warehouseId); err != nil {
+		return nil, err
+	}
+
+	parcelRes := parcelToResponse(parcel, linesById)
+
+	return &parcelRes, nil
+}
+
+func (pu *IParcelUsecase) DeleteParcel(userId string, warehouseId int, shipmentId uint64, parcelId uint64) error {
+	return pu.parcelRepository.DeleteParcelData(userId, warehouseId, shipmentId, parcelId)
+}
+
+func (pu *IParcelUsecase) GetParcels(userId string, warehouseId int, shipmentId uint64) ([]delivery.ParcelModelResponse, error) {
+	parcels, err := pu.parcelRepository.FindAllParcelData(userId, warehouseId, shipmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment, err := pu.shipmentRepository.FindShipmentData(userId, warehouseId, shipmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	linesById := make(map[uint64]domain.ShipmentLine, len(shipment.Lines))
+	for _, line := range shipment.Lines {
+		linesById[line.Id] = line
+	}
+
+	parcelsRes := []delivery.ParcelModelResponse{}
+	for _, parcel := range *parcels {
+		parcelsRes = append(parcelsRes, parcelToResponse(&parcel, linesById))
+	}
+
+	return parcelsRes, nil
+}
+
+// GetParcelLabel печатает этикетку места в ZPL. Штрихкод - номер заказа покупателя, а для отгрузки без заказа - номер отгрузки
+func (pu *IParcelUsecase) GetParcelLabel(userId string, warehouseId int, shipmentId uint64, parcelId uint64) ([]byte, error) {
+	parcels, err := pu.parcelRepository.FindAllParcelData(userId, warehouseId, shipmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	var parcel *domain.Parcel
+	for i := range *parcels {
+		if (*parcels)[i].Id == parcelId {
+			parcel = &(*parcels)[i]
+			break
+		}
+	}
+	if parcel == nil {
+		return nil, custom_errors.ErrParcelNotFound
+	}
+
+	warehouse, err := pu.warehouseRepository.FindWareHouseData(userId, uint(warehouseId))
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := pu.parcelRepository.FindShipmentOrderData(shipmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	shippingLabel := label.ShippingLabel{
+		SenderName:    warehouse.Name,
+		SenderAddress: warehouse.Address,
+		Reference:     fmt.Sprintf("Отгрузка №%d", shipmentId),
+		Barcode:       fmt.Sprintf("SH%d", shipmentId),
+		ParcelNumber:  parcel.Number,
+		ParcelCount:   len(*parcels),
+		WeightKg:      parcel.WeightKg,
+		LengthCm:      parcel.LengthCm,
+		WidthCm:       parcel.WidthCm,
+		HeightCm:      parcel.HeightCm,
+	}
+
+	if order != nil {
+		shippingLabel.Reference = fmt.Sprintf("Заказ №%d, отгрузка №%d", order.Id, shipmentId)
+		shippingLabel.Barcode = fmt.Sprintf("SO%d", order.Id)
+
+		if order.Customer != nil {
+			shippingLabel.RecipientName = order.Customer.Name
+			if order.Customer.ContactName != "" {
+				shippingLabel.RecipientName += ", " + order.Customer.ContactName
+			}
+			shippingLabel.RecipientAddress = order.Customer.Address
+			shippingLabel.RecipientPhone = order.Customer.Phone
+		}
+	}
+
+	data, err := pu.labelRenderer.RenderZPL(shippingLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render parcel label: %w", err)
+	}
+
+	return data, nil
+}
+
+func validDimension(value float64) bool {
+	return value >= 0 && !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+func parcelToResponse(parcel *domain.Parcel, linesById map[uint64]domain.ShipmentLine) delivery.ParcelModelResponse {
+	linesRes := []delivery.ParcelLineModelResponse{}
+	for _, parcelLine := range parcel.Lines {
+		line := linesById[parcelLine.ShipmentLineId]
+		linesRes = append(linesRes, delivery.ParcelLineModelResponse{
+			LineId:      parcelLine.ShipmentLineId,
+			ProductUuid: line.ProductUuid,
+			Unit:        line.Unit,
+			Quantity:    fromStockQuantity(parcelLine.Quantity, line.UnitFactor),
+		})
+	}
+
+	return delivery.ParcelModelResponse{
+		Id:         parcel.Id,
+		ShipmentId: parcel.ShipmentId,
+		Number:     parcel.Number,
+		WeightKg:   parcel.WeightKg,
+		LengthCm:   parcel.LengthCm,
+		WidthCm:    parcel.WidthCm,
+		HeightCm:   parcel.HeightCm,
+		CreatedBy:  parcel.CreatedBy,
+		CreatedAt:  parcel.CreatedAt,
+		Lines:      linesRes,
+	}
+}
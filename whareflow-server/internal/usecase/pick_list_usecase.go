@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"math"
+	"sort"
+	"strings"
+)
+
+type PickListUsecase interface {
+	GetWalkPath(userId string, warehouseId int) ([]delivery.WalkPathStepModelResponse, error)
+	UpdateWalkPath(in *delivery.WalkPathModelRequest, userId string, warehouseId int) ([]delivery.WalkPathStepModelResponse, error)
+	CreatePickList(in *delivery.PickListModelRequest, userId string, warehouseId int, actorId string) (*delivery.PickListModelResponse, error)
+	GetAllPickLists(userId string, warehouseId int, status string) ([]delivery.PickListModelResponse, error)
+	GetPickList(userId string, warehouseId int, listId uint64) (*delivery.PickListModelResponse, error)
+}
+
+type IPickListUsecase struct {
+	pickListRepository repositories.PickListRepository
+}
+
+func NewIPickListUsecase(pickListRepository repositories.PickListRepository) *IPickListUsecase {
+	return &IPickListUsecase{
+		pickListRepository: pickListRepository,
+	}
+}
+
+func (pu *IPickListUsecase) GetWalkPath(userId string, warehouseId int) ([]delivery.WalkPathStepModelResponse, error) {
+	steps, err := pu.pickListRepository.FindWalkPathData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	stepsRes := []delivery.WalkPathStepModelResponse{}
+	for _, step := range *steps {
+		stepsRes = append(stepsRes, delivery.WalkPathStepModelResponse{
+			Sequence:   step.Sequence,
+			ZoneId:     step.ZoneId,
+			LocationId: step.LocationId,
+		})
+	}
+
+	return stepsRes, nil
+}
+
+func (pu *IPickListUsecase) UpdateWalkPath(in *delivery.WalkPathModelRequest, userId string, warehouseId int) ([]delivery.WalkPathStepModelResponse, error) {
+	steps := make([]domain.WalkPathStep, 0, len(in.Steps))
+	for _, stepReq := range in.Steps {
+		steps = append(steps, domain.WalkPathStep{
+			ZoneId:     stepReq.ZoneId,
+			LocationId: stepReq.LocationId,
+		})
+	}
+
+	if err := pu.pickListRepository.ReplaceWalkPathData(userId, warehouseId, steps); err != nil {
+		return nil, err
+	}
+
+	return pu.GetWalkPath(userId, warehouseId)
+}
+
+func (pu *IPickListUsecase) CreatePickList(in *delivery.PickListModelRequest, userId string, warehouseId int, actorId string) (*delivery.PickListModelResponse, error) {
+	list := &domain.PickList{
+		WarehouseId: uint64(warehouseId),
+		Status:      domain.PickListStatusOpen,
+		CreatedBy:   actorId,
+	}
+
+	if err := pu.pickListRepository.InsertPickListData(list, userId, in.OrderIds, in.MaxLines); err != nil {
+		return nil, err
+	}
+
+	return pu.GetPickList(userId, warehouseId, list.Id)
+}
+
+func (pu *IPickListUsecase) GetAllPickLists(userId string, warehouseId int, status string) ([]delivery.PickListModelResponse, error) {
+	lists, err := pu.pickListRepository.FindAllPickListData(userId, warehouseId, status)
+	if err != nil {
+		return nil, err
+	}
+
+	listsRes := []delivery.PickListModelResponse{}
+	for _, list := range *lists {
+		listsRes = append(listsRes, pickListToResponse(&list))
+	}
+
+	return listsRes, nil
+}
+
+// GetPickList раскладывает задания листа по остановкам и упорядочивает их по текущему маршруту обхода склада
+func (pu *IPickListUsecase) GetPickList(userId string, warehouseId int, listId uint64) (*delivery.PickListModelResponse, error) {
+	list, err := pu.pickListRepository.FindPickListData(userId, warehouseId, listId)
+	if err != nil {
+		return nil, err
+	}
+
+	route, err := loadWalkRoute(pu.pickListRepository, userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	listRes := pickListToResponse(list)
+	listRes.Stops = pickStops(list.Tasks, route)
+
+	return &listRes, nil
+}
+
+// walkRoute - маршрут обхода склада вместе с зонами и узлами адресов, по которым ищется шаг для места хранения
+type walkRoute struct {
+	zones         map[uint64]domain.Zone
+	locations     map[uint64]domain.Location
+	zoneSteps     map[uint64]int
+	locationSteps map[uint64]int
+}
+
+func loadWalkRoute(pickListRepository repositories.PickListRepository, userId string, warehouseId int) (*walkRoute, error) {
+	steps, err := pickListRepository.FindWalkPathData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := pickListRepository.FindWarehouseZonesData(warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	var zoneIds []uint64
+	for _, zone := range *zones {
+		zoneIds = append(zoneIds, uint64(zone.Id))
+	}
+
+	locations, err := pickListRepository.FindZoneLocationsData(zoneIds)
+	if err != nil {
+		return nil, err
+	}
+
+	route := &walkRoute{
+		zones:         make(map[uint64]domain.Zone, len(*zones)),
+		locations:     make(map[uint64]domain.Location, len(*locations)),
+		zoneSteps:     make(map[uint64]int),
+		locationSteps: make(map[uint64]int),
+	}
+
+	for _, zone := range *zones {
+		route.zones[uint64(zone.Id)] = zone
+	}
+	for _, location := range *locations {
+		route.locations[location.Id] = location
+	}
+	for _, step := range *steps {
+		if step.LocationId != nil {
+			route.locationSteps[*step.LocationId] = step.Sequence
+		} else {
+			route.zoneSteps[step.ZoneId] = step.Sequence
+		}
+	}
+
+	return route, nil
+}
+
+// place возвращает номер шага маршрута для места и путь ячейки из кодов узлов адреса. Номер берется для самой ячейки,
+// а если его нет - для ближайшего родителя ячейки или для всей зоны. Место вне маршрута получает math.MaxInt
+func (r *walkRoute) place(zoneId uint64, locationId *uint64) (int, string) {
+	rank := math.MaxInt
+
+	var codes []string
+	for current := locationId; current != nil; {
+		location, found := r.locations[*current]
+		if !found {
+			break
+		}
+		codes = append([]string{location.Code}, codes...)
+
+		if sequence, routed := r.locationSteps[location.Id]; routed && rank == math.MaxInt {
+			rank = sequence
+		}
+
+		current = location.ParentId
+	}
+
+	if sequence, routed := r.zoneSteps[zoneId]; routed && rank == math.MaxInt {
+		rank = sequence
+	}
+
+	return rank, strings.Join(codes, "-")
+}
+
+// pickStops группирует задания по зонам и ячейкам и упорядочивает остановки по маршруту обхода.
+// Места вне маршрута проходятся последними, по зоне и адресу
+func pickStops(tasks []domain.PickTask, route *walkRoute) []delivery.PickStopModelResponse {
+	type stopKey struct {
+		zoneId     uint64
+		locationId uint64
+	}
+	type stop struct {
+		rank int
+		res  delivery.PickStopModelResponse
+	}
+
+	stops := make(map[stopKey]*stop)
+	for _, task := range tasks {
+		key := stopKey{zoneId: task.ZoneId}
+		if task.LocationId != nil {
+			key.locationId = *task.LocationId
+		}
+
+		current, ok := stops[key]
+		if !ok {
+			rank, path := route.place(task.ZoneId, task.LocationId)
+			current = &stop{
+				rank: rank,
+				res: delivery.PickStopModelResponse{
+					ZoneId:       task.ZoneId,
+					ZoneName:     route.zones[task.ZoneId].Name,
+					LocationId:   task.LocationId,
+					LocationPath: path,
+				},
+			}
+			stops[key] = current
+		}
+
+		current.res.Tasks = append(current.res.Tasks, pickTaskToResponse(&task))
+	}
+
+	ordered := make([]*stop, 0, len(stops))
+	for _, current := range stops {
+		sort.Slice(current.res.Tasks, func(i, j int) bool {
+			return current.res.Tasks[i].Id < current.res.Tasks[j].Id
+		})
+		ordered = append(ordered, current)
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].rank != ordered[j].rank {
+			return ordered[i].rank < ordered[j].rank
+		}
+		if ordered[i].res.ZoneId != ordered[j].res.ZoneId {
+			return ordered[i].res.ZoneId < ordered[j].res.ZoneId
+		}
+		return naturalLess(ordered[i].res.LocationPath, ordered[j].res.LocationPath)
+	})
+
+	stopsRes := make([]delivery.PickStopModelResponse, 0, len(ordered))
+	for i, current := range ordered {
+		current.res.Sequence = i + 1
+		stopsRes = append(stopsRes, current.res)
+	}
+
+	return stopsRes
+}
+
+// naturalLess сравнивает пути ячеек так, что числа в кодах сравниваются по значению: A-2 идет раньше A-10
+func naturalLess(a string, b string) bool {
+	for a != "" && b != "" {
+		aDigits, bDigits := leadingDigits(a), leadingDigits(b)
+		if aDigits != "" && bDigits != "" {
+			aNumber, bNumber := strings.TrimLeft(aDigits, "0"), strings.TrimLeft(bDigits, "0")
+			if len(aNumber) != len(bNumber) {
+				return len(aNumber) < len(bNumber)
+			}
+			if aNumber != bNumber {
+				return aNumber < bNumber
+			}
+
+			a, b = a[len(aDigits):], b[len(bDigits):]
+			continue
+		}
+
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) < len(b)
+}
+
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	return s[:i]
+}
+
+func pickListToResponse(list *domain.PickList) delivery.PickListModelResponse {
+	openTasks := 0
+	for _, task := range list.Tasks {
+		if task.Status == domain.PickTaskStatusOpen {
+			openTasks++
+		}
+	}
+
+	return delivery.PickListModelResponse{
+		Id:          list.Id,
+		WarehouseId: list.WarehouseId,
+		Status:      list.Status,
+		TaskCount:   len(list.Tasks),
+		OpenTasks:   openTasks,
+		CreatedBy:   list.CreatedBy,
+		CreatedAt:   list.CreatedAt,
+		DoneAt:      list.DoneAt,
+	}
+}
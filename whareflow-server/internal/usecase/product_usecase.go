@@ -5,66 +5,117 @@ import (
 	"github.com/Miroslovelife/whareflow/internal/config"
 	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
 	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
 	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
 	"github.com/Miroslovelife/whareflow/pkg/qr"
+	"log/slog"
+	"strings"
+	"time"
 )
 
 type ProductUsecase interface {
-	CreateProduct(in *delivery.ProductModelRequest, userId string, warehouseId int, zoneId uint64) error
-	FindProduct(userId, productId string) (*delivery.ProductModelResponse, error)
-	FindAllProductFromZone(userId string, zoneId int) (*[]delivery.ProductModelResponse, error)
-	FindAllProductFromWarehouse(userId string, warehouseId int) (*[]delivery.ProductModelResponse, error)
-	UpdateProduct(in *delivery.ProductModelRequest, warehouseId int, productId, userId string) error
+	CreateProduct(in *delivery.ProductModelRequest, userId string, warehouseId int, zoneId uint64, actorId string) error
+	FindProduct(userId, productId, unit string) (*delivery.ProductModelResponse, error)
+	FindAllProductFromZone(userId string, zoneId int, unit string) (*[]delivery.ProductModelResponse, error)
+	FindAllProductFromWarehouse(userId string, warehouseId int, unit string) (*[]delivery.ProductModelResponse, error)
+	UpdateProduct(in *delivery.ProductModelRequest, warehouseId int, productId, userId, actorId string) error
+	FindProductMovements(userId, productId string) (*delivery.StockMovementListResponse, error)
+	MoveProduct(in *delivery.MoveProductModelRequest, warehouseId int, productId, userId, actorId string) (*delivery.ProductModelResponse, error)
+	SuggestFefo(userId string, warehouseId int, skuId uint64, quantity float64, unit string) (*delivery.FefoSuggestionResponse, error)
+	FindExpiringProducts(userId string, warehouseId int, days int) (*delivery.ExpiringReportResponse, error)
 	//DeleteProduct(in *delivery.ProductModelRequest, userId string, warehouseId int) error
 }
 
 type IProductUsecase struct {
-	productRepository repositories.ProductRepository
-	qrGenerator       qr.GeneratorQR
-	cfg               config.Config
+	productRepository       repositories.ProductRepository
+	skuRepository           repositories.SkuRepository
+	stockMovementRepository repositories.StockMovementRepository
+	reservationRepository   repositories.ReservationRepository
+	stockHoldRepository     repositories.StockHoldRepository
+	serialNumberRepository  repositories.SerialNumberRepository
+	reorderRuleRepository   repositories.ReorderRuleRepository
+	notifier                notifier.Notifier
+	qrGenerator             qr.GeneratorQR
+	cfg                     config.Config
+	logger                  slog.Logger
 }
 
-func NewIProductUsecase(productRepository repositories.ProductRepository, qrGenerator qr.GeneratorQR, cfg config.Config) *IProductUsecase {
+func NewIProductUsecase(productRepository repositories.ProductRepository, skuRepository repositories.SkuRepository, stockMovementRepository repositories.StockMovementRepository, reservationRepository repositories.ReservationRepository, stockHoldRepository repositories.StockHoldRepository, serialNumberRepository repositories.SerialNumberRepository, reorderRuleRepository repositories.ReorderRuleRepository, notifier notifier.Notifier, qrGenerator qr.GeneratorQR, cfg config.Config, logger slog.Logger) *IProductUsecase {
 	return &IProductUsecase{
-		productRepository: productRepository,
-		qrGenerator:       qrGenerator,
-		cfg:               cfg,
+		productRepository:       productRepository,
+		skuRepository:           skuRepository,
+		stockMovementRepository: stockMovementRepository,
+		reservationRepository:   reservationRepository,
+		stockHoldRepository:     stockHoldRepository,
+		serialNumberRepository:  serialNumberRepository,
+		reorderRuleRepository:   reorderRuleRepository,
+		notifier:                notifier,
+		qrGenerator:             qrGenerator,
+		cfg:                     cfg,
+		logger:                  logger,
 	}
 }
 
-func (pu *IProductUsecase) CreateProduct(in *delivery.ProductModelRequest, userId string, warehouseId int, zoneId uint64) error {
+func (pu *IProductUsecase) CreateProduct(in *delivery.ProductModelRequest, userId string, warehouseId int, zoneId uint64, actorId string) error {
+	if err := checkLotDates(in.ProductionDate, in.ExpiryDate); err != nil {
+		return err
+	}
+
+	sku, err := pu.skuRepository.FindSkuData(userId, in.SkuId)
+	if err != nil {
+		return err
+	}
+
+	count, err := quantityToStock(sku, in.Count, in.Unit)
+	if err != nil {
+		return err
+	}
+
 	product := &domain.Product{
-		Title:       in.Title,
-		Count:       in.Count,
-		QrPath:      "",
-		Description: in.Description,
-		ZoneId:      zoneId,
+		SkuId:          in.SkuId,
+		Count:          count,
+		QrPath:         "",
+		ZoneId:         zoneId,
+		LocationId:     in.LocationId,
+		LotNumber:      in.LotNumber,
+		ProductionDate: in.ProductionDate,
+		ExpiryDate:     in.ExpiryDate,
 	}
 
-	createdProduct, err := pu.productRepository.InsertProductData(product, userId, warehouseId)
+	createdProduct, err := pu.productRepository.InsertProductData(product, userId, warehouseId, actorId, in.Serials)
 	if err != nil {
 		return err
 	}
 
-	qrData := fmt.Sprintf("%s%d/%d/products/%s", pu.cfg.QR.UrlFrontend, warehouseId, zoneId, createdProduct.Uuid)
+	if createdProduct.Sku.SerialTracked {
+		if err := generateSerialQRs(pu.qrGenerator, pu.cfg, pu.serialNumberRepository, warehouseId); err != nil {
+			return err
+		}
+	}
 
-	pathToFle, err := pu.qrGenerator.Generate(qrData, pu.cfg.QR.PathToFile, fmt.Sprintf("%s.png", string(createdProduct.Uuid)))
+	qrPath, err := generateProductQR(pu.qrGenerator, pu.cfg, warehouseId, zoneId, string(createdProduct.Uuid))
 	if err != nil {
 		return err
 	}
 
-	createdProduct.QrPath = fmt.Sprintf("./%s", pathToFle)
+	createdProduct.QrPath = qrPath
 	fmt.Println(createdProduct)
 
-	errUpdate := pu.productRepository.UpdateProductData(createdProduct, userId, warehouseId)
+	errUpdate := pu.productRepository.UpdateProductData(createdProduct, userId, warehouseId, actorId)
 	if errUpdate != nil {
-		return err
+		return errUpdate
+	}
+
+	// Приход снимает отметку оповещения, если остаток снова достиг точки перезаказа
+	if err := evaluateReorderRules(pu.reorderRuleRepository, pu.notifier, warehouseId, []uint64{in.SkuId}); err != nil {
+		pu.logger.Error(fmt.Sprintf("Product %s created, but reorder evaluation failed: %v", createdProduct.Uuid, err))
 	}
 
 	return nil
 }
 
-func (pu *IProductUsecase) FindProduct(userId, productId string) (*delivery.ProductModelResponse, error) {
+func (pu *IProductUsecase) FindProduct(userId, productId, unit string) (*delivery.ProductModelResponse, error) {
 	product, err := pu.productRepository.FindProductData(userId, productId)
 	if err != nil {
 		return nil, err
@@ -72,84 +123,398 @@ func (pu *IProductUsecase) FindProduct(userId, productId string) (*delivery.Prod
 
 	fmt.Println(product)
 
-	productResponse := delivery.ProductModelResponse{
-		Uuid:        string(product.Uuid),
-		Title:       product.Title,
-		Count:       product.Count,
-		QrImage:     product.QrPath,
-		Description: product.Description,
-		ZoneId:      product.ZoneId,
+	reserved, held, err := pu.findOccupiedQuantity([]string{string(product.Uuid)})
+	if err != nil {
+		return nil, err
+	}
+
+	productResponse, err := productToResponse(product, reserved[string(product.Uuid)], held[string(product.Uuid)], unit)
+	if err != nil {
+		return nil, err
 	}
 
 	return &productResponse, nil
 
 }
 
-func (pu *IProductUsecase) FindAllProductFromZone(userId string, zoneId int) (*[]delivery.ProductModelResponse, error) {
+func (pu *IProductUsecase) FindAllProductFromZone(userId string, zoneId int, unit string) (*[]delivery.ProductModelResponse, error) {
 	products, err := pu.productRepository.FindAllProductFromZoneData(userId, zoneId)
 	if err != nil {
 		return nil, err
 	}
 
-	var productsRepo []delivery.ProductModelResponse
 	fmt.Println(products, "wafaf")
-	for _, product := range *products {
-		productRepo := delivery.ProductModelResponse{
-			Uuid:        string(product.Uuid),
-			Title:       product.Title,
-			Description: product.Description,
-			QrImage:     product.QrPath,
-			ZoneId:      product.ZoneId,
+
+	return pu.productsToResponse(products, unit)
+}
+
+func (pu *IProductUsecase) FindAllProductFromWarehouse(userId string, warehouseId int, unit string) (*[]delivery.ProductModelResponse, error) {
+	products, err := pu.productRepository.FindAllProductFromWarehouseData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pu.productsToResponse(products, unit)
+}
+
+func (pu *IProductUsecase) UpdateProduct(in *delivery.ProductModelRequest, warehouseId int, productId, userId, actorId string) error {
+	if err := checkLotDates(in.ProductionDate, in.ExpiryDate); err != nil {
+		return err
+	}
+
+	product, err := pu.productRepository.FindProductData(userId, productId)
+	if err != nil {
+		return err
+	}
+
+	count, err := quantityToStock(product.Sku, in.Count, in.Unit)
+	if err != nil {
+		return err
+	}
+
+	product = &domain.Product{
+		Uuid:           product.Uuid,
+		SkuId:          product.SkuId,
+		Count:          count,
+		QrPath:         product.QrPath,
+		ZoneId:         product.ZoneId,
+		LotNumber:      in.LotNumber,
+		ProductionDate: in.ProductionDate,
+		ExpiryDate:     in.ExpiryDate,
+	}
+
+	errUpdate := pu.productRepository.UpdateProductData(product, userId, warehouseId, actorId)
+	if errUpdate != nil {
+		return errUpdate
+	}
+
+	// Остаток к этому моменту уже сохранен, поэтому ошибка проверки перезаказа только пишется в лог
+	if err := evaluateReorderRules(pu.reorderRuleRepository, pu.notifier, warehouseId, []uint64{product.SkuId}); err != nil {
+		pu.logger.Error(fmt.Sprintf("Product %s updated, but reorder evaluation failed: %v", productId, err))
+	}
+
+	return nil
+
+}
+
+func (pu *IProductUsecase) FindProductMovements(userId, productId string) (*delivery.StockMovementListResponse, error) {
+	product, err := pu.productRepository.FindProductData(userId, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := pu.stockMovementRepository.FindAllStockMovementData(userId, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	var balance int64
+	movementsRes := []delivery.StockMovementResponse{}
+	for _, movement := range *movements {
+		balance += movement.Quantity
+
+		_, quantity, err := stockDeltaToQuantity(product.Sku, movement.Quantity, "")
+		if err != nil {
+			return nil, err
 		}
 
-		productsRepo = append(productsRepo, productRepo)
+		movementsRes = append(movementsRes, delivery.StockMovementResponse{
+			Id:           movement.Id,
+			Quantity:     quantity,
+			Reason:       movement.Reason,
+			ActorUuid:    movement.ActorUuid,
+			SourceZoneId: movement.SourceZoneId,
+			TargetZoneId: movement.TargetZoneId,
+			CreatedAt:    movement.CreatedAt,
+		})
 	}
 
-	return &productsRepo, nil
+	_, ledgerBalance, err := stockDeltaToQuantity(product.Sku, balance, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery.StockMovementListResponse{
+		ProductUuid:   string(product.Uuid),
+		Unit:          product.Sku.Unit,
+		Count:         baseQuantity(product.Sku, product.Count),
+		LedgerBalance: ledgerBalance,
+		Reconciled:    balance == int64(product.Count),
+		Movements:     movementsRes,
+	}, nil
 }
 
-func (pu *IProductUsecase) FindAllProductFromWarehouse(userId string, warehouseId int) (*[]delivery.ProductModelResponse, error) {
-	products, err := pu.productRepository.FindAllProductFromWarehouseData(userId, warehouseId)
+// MoveProduct переносит товар в другую зону. Count = 0 означает перенос всего остатка.
+func (pu *IProductUsecase) MoveProduct(in *delivery.MoveProductModelRequest, warehouseId int, productId, userId, actorId string) (*delivery.ProductModelResponse, error) {
+	product, err := pu.productRepository.FindProductData(userId, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := quantityToStock(product.Sku, in.Count, in.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err = pu.productRepository.MoveProductData(productId, userId, warehouseId, in.ZoneId, in.LocationId, count, actorId, in.Serials)
+	if err != nil {
+		return nil, err
+	}
+
+	// В QR-коде зашита зона товара, поэтому после переноса он перевыпускается.
+	// Перенос к этому моменту уже проведен, поэтому ошибка выдачи QR только пишется в лог
+	qrPath, err := generateProductQR(pu.qrGenerator, pu.cfg, warehouseId, product.ZoneId, string(product.Uuid))
+	if err != nil {
+		pu.logger.Error(fmt.Sprintf("Product %s moved, but qr generation failed: %v", product.Uuid, err))
+	} else if err := pu.productRepository.UpdateProductQrData(string(product.Uuid), qrPath); err != nil {
+		pu.logger.Error(fmt.Sprintf("Product %s moved, but qr generation failed: %v", product.Uuid, err))
+	}
+
+	if err := evaluateReorderRules(pu.reorderRuleRepository, pu.notifier, warehouseId, []uint64{product.SkuId}); err != nil {
+		pu.logger.Error(fmt.Sprintf("Product %s moved, but reorder evaluation failed: %v", product.Uuid, err))
+	}
+
+	product, err = pu.productRepository.FindProductData(userId, string(product.Uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, held, err := pu.findOccupiedQuantity([]string{string(product.Uuid)})
+	if err != nil {
+		return nil, err
+	}
+
+	productResponse, err := productToResponse(product, reserved[string(product.Uuid)], held[string(product.Uuid)], "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &productResponse, nil
+}
+
+func (pu *IProductUsecase) productsToResponse(products *[]domain.Product, unit string) (*[]delivery.ProductModelResponse, error) {
+	productIds := make([]string, 0, len(*products))
+	for _, product := range *products {
+		productIds = append(productIds, string(product.Uuid))
+	}
+
+	reserved, held, err := pu.findOccupiedQuantity(productIds)
 	if err != nil {
 		return nil, err
 	}
 
 	var productsRepo []delivery.ProductModelResponse
 	for _, product := range *products {
-		productRepo := delivery.ProductModelResponse{
-			Uuid:        string(product.Uuid),
-			Title:       product.Title,
-			Description: product.Description,
-			QrImage:     product.QrPath,
-			ZoneId:      product.ZoneId,
+		productResponse, err := productToResponse(&product, reserved[string(product.Uuid)], held[string(product.Uuid)], unit)
+		if err != nil {
+			return nil, err
 		}
-
-		productsRepo = append(productsRepo, productRepo)
+		productsRepo = append(productsRepo, productResponse)
 	}
 
 	return &productsRepo, nil
 }
 
-func (pu *IProductUsecase) UpdateProduct(in *delivery.ProductModelRequest, warehouseId int, productId, userId string) error {
-	product, err := pu.productRepository.FindProductData(userId, productId)
+// findOccupiedQuantity возвращает по товарам действующие резервы и заблокированный остаток
+func (pu *IProductUsecase) findOccupiedQuantity(productIds []string) (map[string]uint64, map[string]uint64, error) {
+	reserved, err := pu.reservationRepository.FindReservedQuantityData(productIds)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	product = &domain.Product{
-		Uuid:        product.Uuid,
-		Title:       in.Title,
-		Count:       in.Count,
-		QrPath:      product.QrPath,
-		Description: in.Description,
-		ZoneId:      product.ZoneId,
+	held, err := pu.stockHoldRepository.FindHeldQuantityData(productIds)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	errUpdate := pu.productRepository.UpdateProductData(product, userId, warehouseId)
-	if errUpdate != nil {
-		return err
+	return reserved, held, nil
+}
+
+// productToResponse считает доступный остаток как остаток за вычетом действующих резервов и блокировок.
+// Quantity пересчитывается в единицу unit, единица, которой у позиции нет, - ошибка
+func productToResponse(product *domain.Product, reserved, held uint64, unit string) (delivery.ProductModelResponse, error) {
+	if product.Sku == nil {
+		return delivery.ProductModelResponse{}, custom_errors.ErrSkuNotFound
+	}
+
+	var available uint64
+	if product.Count > reserved+held {
+		available = product.Count - reserved - held
+	}
+
+	quantityUnit, quantity, err := stockToQuantity(product.Sku, product.Count, unit)
+	if err != nil {
+		return delivery.ProductModelResponse{}, err
+	}
+
+	_, availableQuantity, err := stockToQuantity(product.Sku, available, unit)
+	if err != nil {
+		return delivery.ProductModelResponse{}, err
+	}
+
+	return delivery.ProductModelResponse{
+		Uuid:              string(product.Uuid),
+		SkuId:             product.SkuId,
+		SkuCode:           product.Sku.Code,
+		Title:             product.Sku.Name,
+		Unit:              product.Sku.Unit,
+		Count:             baseQuantity(product.Sku, product.Count),
+		Reserved:          baseQuantity(product.Sku, reserved),
+		Held:              baseQuantity(product.Sku, held),
+		Available:         baseQuantity(product.Sku, available),
+		QuantityUnit:      quantityUnit,
+		Quantity:          quantity,
+		AvailableQuantity: availableQuantity,
+		QrImage:           product.QrPath,
+		Description:       product.Sku.Description,
+		ZoneId:            product.ZoneId,
+		LocationId:        product.LocationId,
+		LotNumber:         product.LotNumber,
+		ProductionDate:    product.ProductionDate,
+		ExpiryDate:        product.ExpiryDate,
+		SerialTracked:     product.Sku.SerialTracked,
+	}, nil
+}
+
+// SuggestFefo подбирает партии позиции каталога skuId под выдачу quantity в единице unit по правилу FEFO.
+// Зарезервированный и заблокированный остаток партий не предлагается
+func (pu *IProductUsecase) SuggestFefo(userId string, warehouseId int, skuId uint64, quantity float64, unit string) (*delivery.FefoSuggestionResponse, error) {
+	sku, err := pu.skuRepository.FindSkuData(userId, skuId)
+	if err != nil {
+		return nil, err
+	}
+
+	requested, err := quantityToStock(sku, quantity, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := pu.productRepository.FindFefoCandidatesData(userId, warehouseId, skuId)
+	if err != nil {
+		return nil, err
+	}
+
+	productIds := make([]string, 0, len(*products))
+	for _, product := range *products {
+		productIds = append(productIds, string(product.Uuid))
+	}
+
+	reserved, held, err := pu.findOccupiedQuantity(productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	quantityUnit, factor, err := skuUnitFactor(sku, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := &delivery.FefoSuggestionResponse{
+		SkuId:     skuId,
+		Unit:      quantityUnit,
+		Requested: fromStockQuantity(requested, factor),
+		Lines:     []delivery.FefoLineResponse{},
+	}
+
+	var suggested uint64
+	for _, product := range *products {
+		if suggested >= requested {
+			break
+		}
+
+		occupied := reserved[string(product.Uuid)] + held[string(product.Uuid)]
+		if product.Count <= occupied {
+			continue
+		}
+		available := product.Count - occupied
+
+		take := min(available, requested-suggested)
+		suggested += take
+		suggestion.Lines = append(suggestion.Lines, delivery.FefoLineResponse{
+			ProductUuid: string(product.Uuid),
+			ZoneId:      product.ZoneId,
+			LotNumber:   product.LotNumber,
+			ExpiryDate:  product.ExpiryDate,
+			Available:   fromStockQuantity(available, factor),
+			Take:        fromStockQuantity(take, factor),
+		})
+	}
+
+	suggestion.Suggested = fromStockQuantity(suggested, factor)
+	suggestion.Shortage = fromStockQuantity(requested-suggested, factor)
+
+	return suggestion, nil
+}
+
+// FindExpiringProducts возвращает партии, срок годности которых истекает в ближайшие days дней, и уже истекшие
+func (pu *IProductUsecase) FindExpiringProducts(userId string, warehouseId int, days int) (*delivery.ExpiringReportResponse, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	products, err := pu.productRepository.FindExpiringProductData(userId, warehouseId, today.AddDate(0, 0, days))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &delivery.ExpiringReportResponse{
+		Days:     days,
+		Products: []delivery.ExpiringProductResponse{},
+	}
+
+	for _, product := range *products {
+		expiry := *product.ExpiryDate
+		expiryDay := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, time.UTC)
+		daysLeft := int(expiryDay.Sub(today).Hours() / 24)
+
+		report.Products = append(report.Products, delivery.ExpiringProductResponse{
+			Uuid:       string(product.Uuid),
+			SkuId:      product.SkuId,
+			SkuCode:    product.Sku.Code,
+			Title:      product.Sku.Name,
+			ZoneId:     product.ZoneId,
+			LotNumber:  product.LotNumber,
+			ExpiryDate: expiry,
+			Unit:       product.Sku.Unit,
+			Count:      baseQuantity(product.Sku, product.Count),
+			DaysLeft:   daysLeft,
+			Expired:    daysLeft < 0,
+		})
+	}
+
+	return report, nil
+}
+
+func checkLotDates(productionDate, expiryDate *time.Time) error {
+	if productionDate != nil && expiryDate != nil && productionDate.After(*expiryDate) {
+		return custom_errors.ErrInvalidLotDates
 	}
 
 	return nil
+}
+
+// generateProductQR создает QR-код со ссылкой на страницу товара во фронтенде и возвращает путь к файлу
+func generateProductQR(qrGenerator qr.GeneratorQR, cfg config.Config, warehouseId int, zoneId uint64, productId string) (string, error) {
+	qrData := fmt.Sprintf("%s%d/%d/products/%s", cfg.QR.UrlFrontend, warehouseId, zoneId, productId)
+
+	pathToFle, err := qrGenerator.Generate(qrData, cfg.QR.PathToFile, fmt.Sprintf("%s.png", productId))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("./%s", pathToFle), nil
+}
+
+// parseProductQR достает uuid товара из содержимого QR-кода, созданного generateProductQR
+func parseProductQR(qrData string) (string, error) {
+	i := strings.LastIndex(qrData, "/products/")
+	if i == -1 {
+		return "", custom_errors.ErrInvalidProductQR
+	}
+
+	productId := strings.Trim(qrData[i+len("/products/"):], "/ ")
+	if productId == "" || strings.Contains(productId, "/") {
+		return "", custom_errors.ErrInvalidProductQR
+	}
 
+	return productId, nil
 }
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"time"
+)
+
+type PurchaseOrderUsecase interface {
+	CreatePurchaseOrder(in *delivery.PurchaseOrderModelRequest, userId string, warehouseId int, actorId string) (*delivery.PurchaseOrderModelResponse, error)
+	UpdatePurchaseOrder(in *delivery.PurchaseOrderModelRequest, userId string, warehouseId int, orderId uint64) error
+	ClosePurchaseOrder(userId string, warehouseId int, orderId uint64) error
+	GetAllPurchaseOrders(userId string, warehouseId int) ([]delivery.PurchaseOrderModelResponse, error)
+	GetOpenPurchaseOrders(userId string, warehouseId int) ([]delivery.PurchaseOrderModelResponse, error)
+	GetPurchaseOrder(userId string, warehouseId int, orderId uint64) (*delivery.PurchaseOrderModelResponse, error)
+	CreatePurchaseOrderReceipt(in *delivery.PurchaseOrderReceiptModelRequest, userId string, warehouseId int, orderId uint64, actorId string) (*delivery.ReceiptModelResponse, error)
+}
+
+type IPurchaseOrderUsecase struct {
+	purchaseOrderRepository repositories.PurchaseOrderRepository
+	receiptRepository       repositories.ReceiptRepository
+	skuRepository           repositories.SkuRepository
+}
+
+func NewIPurchaseOrderUsecase(purchaseOrderRepository repositories.PurchaseOrderRepository, receiptRepository repositories.ReceiptRepository, skuRepository repositories.SkuRepository) *IPurchaseOrderUsecase {
+	return &IPurchaseOrderUsecase{
+		purchaseOrderRepository: purchaseOrderRepository,
+		receiptRepository:       receiptRepository,
+		skuRepository:           skuRepository,
+	}
+}
+
+func (pu *IPurchaseOrderUsecase) CreatePurchaseOrder(in *delivery.PurchaseOrderModelRequest, userId string, warehouseId int, actorId string) (*delivery.PurchaseOrderModelResponse, error) {
+	lines, err := pu.buildPurchaseOrderLines(in.Lines, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &domain.PurchaseOrder{
+		WarehouseId:  uint64(warehouseId),
+		SupplierId:   in.SupplierId,
+		Status:       domain.PurchaseOrderStatusOpen,
+		ExpectedDate: in.ExpectedDate,
+		Comment:      in.Comment,
+		CreatedBy:    actorId,
+		Lines:        lines,
+	}
+
+	if err := pu.purchaseOrderRepository.InsertPurchaseOrderData(order, userId); err != nil {
+		return nil, err
+	}
+
+	return pu.GetPurchaseOrder(userId, warehouseId, order.Id)
+}
+
+func (pu *IPurchaseOrderUsecase) UpdatePurchaseOrder(in *delivery.PurchaseOrderModelRequest, userId string, warehouseId int, orderId uint64) error {
+	lines, err := pu.buildPurchaseOrderLines(in.Lines, userId)
+	if err != nil {
+		return err
+	}
+
+	order := &domain.PurchaseOrder{
+		Id:           orderId,
+		WarehouseId:  uint64(warehouseId),
+		SupplierId:   in.SupplierId,
+		ExpectedDate: in.ExpectedDate,
+		Comment:      in.Comment,
+		Lines:        lines,
+	}
+
+	return pu.purchaseOrderRepository.UpdatePurchaseOrderData(order, userId)
+}
+
+func (pu *IPurchaseOrderUsecase) ClosePurchaseOrder(userId string, warehouseId int, orderId uint64) error {
+	return pu.purchaseOrderRepository.ClosePurchaseOrderData(userId, warehouseId, orderId)
+}
+
+func (pu *IPurchaseOrderUsecase) GetAllPurchaseOrders(userId string, warehouseId int) ([]delivery.PurchaseOrderModelResponse, error) {
+	return pu.findPurchaseOrders(userId, warehouseId, nil)
+}
+
+// GetOpenPurchaseOrders - отчет по заказам склада, по которым еще ожидается поставка
+func (pu *IPurchaseOrderUsecase) GetOpenPurchaseOrders(userId string, warehouseId int) ([]delivery.PurchaseOrderModelResponse, error) {
+	return pu.findPurchaseOrders(userId, warehouseId, []string{
+		domain.PurchaseOrderStatusOpen,
+		domain.PurchaseOrderStatusPartiallyReceived,
+	})
+}
+
+func (pu *IPurchaseOrderUsecase) GetPurchaseOrder(userId string, warehouseId int, orderId uint64) (*delivery.PurchaseOrderModelResponse, error) {
+	order, err := pu.purchaseOrderRepository.FindPurchaseOrderData(userId, warehouseId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	orderRes := purchaseOrderToResponse(order, time.Now())
+
+	return &orderRes, nil
+}
+
+// CreatePurchaseOrderReceipt заводит черновик поступления на весь непоставленный остаток заказа.
+// Фактически принятое количество указывается дальше в самом поступлении, как и для обычной приемки
+func (pu *IPurchaseOrderUsecase) CreatePurchaseOrderReceipt(in *delivery.PurchaseOrderReceiptModelRequest, userId string, warehouseId int, orderId uint64, actorId string) (*delivery.ReceiptModelResponse, error) {
+	if in.ZoneId == 0 {
+		return nil, custom_errors.ErrInvalidDocumentLine
+	}
+
+	order, err := pu.purchaseOrderRepository.FindPurchaseOrderData(userId, warehouseId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []domain.ReceiptLine
+	for _, orderLine := range order.Lines {
+		if orderLine.ReceivedQuantity >= orderLine.Quantity {
+			continue
+		}
+
+		sku, err := pu.skuRepository.FindSkuData(userId, orderLine.SkuId)
+		if err != nil {
+			return nil, err
+		}
+
+		orderLineId := orderLine.Id
+		lines = append(lines, domain.ReceiptLine{
+			ZoneId:              in.ZoneId,
+			SkuId:               orderLine.SkuId,
+			Title:               sku.Name,
+			Description:         sku.Description,
+			Quantity:            orderLine.Quantity - orderLine.ReceivedQuantity,
+			Unit:                orderLine.Unit,
+			UnitFactor:          orderLine.UnitFactor,
+			PurchaseOrderLineId: &orderLineId,
+		})
+	}
+
+	if len(lines) == 0 {
+		return nil, custom_errors.ErrInvalidPurchaseOrder
+	}
+
+	receipt := &domain.Receipt{
+		WarehouseId:     uint64(warehouseId),
+		PurchaseOrderId: &order.Id,
+		Status:          domain.ReceiptStatusDraft,
+		Comment:         in.Comment,
+		CreatedBy:       actorId,
+		Lines:           lines,
+	}
+
+	if err := pu.receiptRepository.InsertReceiptData(receipt, userId); err != nil {
+		return nil, err
+	}
+
+	created, err := pu.receiptRepository.FindReceiptData(userId, warehouseId, receipt.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptRes := receiptToResponse(created)
+
+	return &receiptRes, nil
+}
+
+func (pu *IPurchaseOrderUsecase) findPurchaseOrders(userId string, warehouseId int, statuses []string) ([]delivery.PurchaseOrderModelResponse, error) {
+	orders, err := pu.purchaseOrderRepository.FindAllPurchaseOrderData(userId, warehouseId, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ordersRes := []delivery.PurchaseOrderModelResponse{}
+	for _, order := range *orders {
+		ordersRes = append(ordersRes, purchaseOrderToResponse(&order, now))
+	}
+
+	return ordersRes, nil
+}
+
+// buildPurchaseOrderLines переводит заказанные количества в доли базовой единицы. Позиция встречается в заказе один раз
+func (pu *IPurchaseOrderUsecase) buildPurchaseOrderLines(in []delivery.PurchaseOrderLineModelRequest, userId string) ([]domain.PurchaseOrderLine, error) {
+	if len(in) == 0 {
+		return nil, custom_errors.ErrInvalidPurchaseOrder
+	}
+
+	var lines []domain.PurchaseOrderLine
+	seen := make(map[uint64]struct{}, len(in))
+
+	for _, lineReq := range in {
+		if _, ok := seen[lineReq.SkuId]; ok {
+			return nil, custom_errors.ErrInvalidPurchaseOrder
+		}
+		seen[lineReq.SkuId] = struct{}{}
+
+		sku, err := pu.skuRepository.FindSkuData(userId, lineReq.SkuId)
+		if err != nil {
+			return nil, err
+		}
+
+		line := domain.PurchaseOrderLine{
+			SkuId:        sku.Id,
+			Title:        sku.Name,
+			ExpectedDate: lineReq.ExpectedDate,
+		}
+
+		line.Unit, line.UnitFactor, err = skuUnitFactor(sku, lineReq.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		line.Quantity, err = toStockQuantity(lineReq.Quantity, line.UnitFactor)
+		if err != nil {
+			return nil, err
+		}
+		if line.Quantity == 0 {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func purchaseOrderToResponse(order *domain.PurchaseOrder, now time.Time) delivery.PurchaseOrderModelResponse {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	waiting := order.Status == domain.PurchaseOrderStatusOpen || order.Status == domain.PurchaseOrderStatusPartiallyReceived
+
+	overdue := false
+	linesRes := []delivery.PurchaseOrderLineModelResponse{}
+	for _, line := range order.Lines {
+		lineRes := delivery.PurchaseOrderLineModelResponse{
+			Id:               line.Id,
+			SkuId:            line.SkuId,
+			Title:            line.Title,
+			Unit:             line.Unit,
+			Quantity:         fromStockQuantity(line.Quantity, line.UnitFactor),
+			ReceivedQuantity: fromStockQuantity(line.ReceivedQuantity, line.UnitFactor),
+			ExpectedDate:     line.ExpectedDate,
+		}
+
+		if line.ReceivedQuantity > line.Quantity {
+			lineRes.OverQuantity = fromStockQuantity(line.ReceivedQuantity-line.Quantity, line.UnitFactor)
+		} else if remaining := line.Quantity - line.ReceivedQuantity; remaining > 0 {
+			if order.Status == domain.PurchaseOrderStatusClosed {
+				lineRes.UnderQuantity = fromStockQuantity(remaining, line.UnitFactor)
+			} else {
+				lineRes.OutstandingQuantity = fromStockQuantity(remaining, line.UnitFactor)
+			}
+
+			expected := line.ExpectedDate
+			if expected == nil {
+				expected = order.ExpectedDate
+			}
+			if waiting && expected != nil && expected.Before(today) {
+				overdue = true
+			}
+		}
+
+		linesRes = append(linesRes, lineRes)
+	}
+
+	orderRes := delivery.PurchaseOrderModelResponse{
+		Id:           order.Id,
+		WarehouseId:  order.WarehouseId,
+		SupplierId:   order.SupplierId,
+		Status:       order.Status,
+		ExpectedDate: order.ExpectedDate,
+		Overdue:      overdue,
+		Comment:      order.Comment,
+		CreatedBy:    order.CreatedBy,
+		CreatedAt:    order.CreatedAt,
+		ClosedAt:     order.ClosedAt,
+		Lines:        linesRes,
+	}
+	if order.Supplier != nil {
+		orderRes.SupplierName = order.Supplier.Name
+	}
+
+	return orderRes
+}
@@ -0,0 +1,551 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Веса оценок места размещения, в сумме дают 1
+const (
+	putawayCapacityWeight      = 0.3
+	putawayCompatibilityWeight = 0.2
+	putawayConsolidationWeight = 0.3
+	putawayDistanceWeight      = 0.2
+)
+
+// putawayCandidatesLimit - сколько лучших мест предлагается по строке поступления
+const putawayCandidatesLimit = 5
+
+type PutawayUsecase interface {
+	GetPutawaySuggestions(userId string, warehouseId int, receiptId uint64) ([]delivery.PutawayLineModelResponse, error)
+	ConfirmPutaway(in *delivery.PutawayModelRequest, userId string, warehouseId int, receiptId uint64, lineId uint64, actorId string) (*delivery.PutawayModelResponse, error)
+	GetPutaways(userId string, warehouseId int, receiptId uint64) ([]delivery.PutawayModelResponse, error)
+}
+
+type IPutawayUsecase struct {
+	putawayRepository  repositories.PutawayRepository
+	pickListRepository repositories.PickListRepository
+	zoneRepository     repositories.ZoneRepository
+}
+
+func NewIPutawayUsecase(putawayRepository repositories.PutawayRepository, pickListRepository repositories.PickListRepository, zoneRepository repositories.ZoneRepository) *IPutawayUsecase {
+	return &IPutawayUsecase{
+		putawayRepository:  putawayRepository,
+		pickListRepository: pickListRepository,
+		zoneRepository:     zoneRepository,
+	}
+}
+
+// GetPutawaySuggestions предлагает места для каждой еще не размещенной строки поступления.
+// Места, куда строка не поместится или где ее нельзя хранить, не предлагаются
+func (pu *IPutawayUsecase) GetPutawaySuggestions(userId string, warehouseId int, receiptId uint64) ([]delivery.PutawayLineModelResponse, error) {
+	lines, err := pu.putawayRepository.FindPutawayLinesData(userId, warehouseId, receiptId)
+	if err != nil {
+		return nil, err
+	}
+
+	planner, err := pu.loadPutawayPlanner(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	linesRes := []delivery.PutawayLineModelResponse{}
+	for _, line := range *lines {
+		if line.Putaway != nil {
+			continue
+		}
+
+		candidates := planner.candidates(&line)
+		if len(candidates) > putawayCandidatesLimit {
+			candidates = candidates[:putawayCandidatesLimit]
+		}
+
+		lineRes := delivery.PutawayLineModelResponse{
+			LineId:      line.Line.Id,
+			ProductUuid: line.Line.ProductUuid,
+			SkuId:       line.Sku.Id,
+			SkuCode:     line.Sku.Code,
+			Title:       line.Line.Title,
+			Quantity:    fromStockQuantity(line.Line.ReceivedQuantity, line.Line.UnitFactor),
+			Unit:        line.Line.Unit,
+			ZoneId:      line.Line.ZoneId,
+			Candidates:  []delivery.PutawayCandidateModelResponse{},
+		}
+		if line.Product != nil {
+			lineRes.ZoneId = line.Product.ZoneId
+			lineRes.LocationId = line.Product.LocationId
+		}
+
+		for _, candidate := range candidates {
+			lineRes.Candidates = append(lineRes.Candidates, planner.candidateToResponse(&candidate, &line.Line))
+		}
+
+		linesRes = append(linesRes, lineRes)
+	}
+
+	return linesRes, nil
+}
+
+// ConfirmPutaway размещает строку проведенного поступления. Место сравнивается с лучшим предложением на момент
+// размещения: если товар кладут в другое место или предложить было нечего, размещение записывается как
+// отступление от подбора и без причины не принимается
+func (pu *IPutawayUsecase) ConfirmPutaway(in *delivery.PutawayModelRequest, userId string, warehouseId int, receiptId uint64, lineId uint64, actorId string) (*delivery.PutawayModelResponse, error) {
+	lines, err := pu.putawayRepository.FindPutawayLinesData(userId, warehouseId, receiptId)
+	if err != nil {
+		return nil, err
+	}
+
+	var line *domain.PutawayLine
+	for i := range *lines {
+		if (*lines)[i].Line.Id == lineId {
+			line = &(*lines)[i]
+			break
+		}
+	}
+	if line == nil {
+		return nil, custom_errors.ErrInvalidDocumentLine
+	}
+	if line.Putaway != nil {
+		return nil, custom_errors.ErrPutawayDone
+	}
+
+	planner, err := pu.loadPutawayPlanner(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	putaway := &domain.Putaway{
+		ReceiptId:     receiptId,
+		ReceiptLineId: lineId,
+		ZoneId:        in.ZoneId,
+		LocationId:    in.LocationId,
+		Override:      true,
+		Reason:        strings.TrimSpace(in.Reason),
+		CreatedBy:     actorId,
+	}
+
+	if candidates := planner.candidates(line); len(candidates) > 0 {
+		best := candidates[0]
+		putaway.SuggestedZoneId = &best.zoneId
+		putaway.SuggestedLocationId = best.locationId
+		putaway.Override = best.zoneId != in.ZoneId || !sameLocation(best.locationId, in.LocationId)
+	}
+
+	if putaway.Override && putaway.Reason == "" {
+		return nil, custom_errors.ErrPutawayReasonRequired
+	}
+
+	if err := pu.putawayRepository.InsertPutawayData(putaway, userId, warehouseId); err != nil {
+		return nil, err
+	}
+
+	putawayRes := putawayToResponse(putaway, &line.Line)
+
+	return &putawayRes, nil
+}
+
+func (pu *IPutawayUsecase) GetPutaways(userId string, warehouseId int, receiptId uint64) ([]delivery.PutawayModelResponse, error) {
+	lines, err := pu.putawayRepository.FindPutawayLinesData(userId, warehouseId, receiptId)
+	if err != nil {
+		return nil, err
+	}
+
+	putawaysRes := []delivery.PutawayModelResponse{}
+	for _, line := range *lines {
+		if line.Putaway != nil {
+			putawaysRes = append(putawaysRes, putawayToResponse(line.Putaway, &line.Line))
+		}
+	}
+
+	return putawaysRes, nil
+}
+
+// putawayPlanner - снимок склада, по которому оцениваются места размещения: маршрут обхода с зонами и узлами адресов,
+// загрузка зон и остатки позиций по зонам и ячейкам. occupied - остаток во всем поддереве узла адреса
+type putawayPlanner struct {
+	route         *walkRoute
+	firstStep     int
+	lastStep      int
+	zoneLoads     map[int]domain.ZoneLoad
+	zoneCounts    map[uint64]uint64
+	zoneSkuCounts map[uint64]map[uint64]uint64
+	binSkuCounts  map[uint64]map[uint64]uint64
+	occupied      map[uint64]uint64
+	bins          map[uint64][]domain.Location
+}
+
+// putawayCandidate - оцененное место размещения. sameSku - остаток той же позиции в ячейке или, для места без ячейки, в зоне
+type putawayCandidate struct {
+	zoneId        uint64
+	locationId    *uint64
+	score         float64
+	capacity      float64
+	compatibility float64
+	consolidation float64
+	nearness      float64
+	sameSku       uint64
+	distance      *int
+}
+
+func (pu *IPutawayUsecase) loadPutawayPlanner(userId string, warehouseId int) (*putawayPlanner, error) {
+	route, err := loadWalkRoute(pu.pickListRepository, userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, err := pu.putawayRepository.FindPutawayStockData(warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	zoneIds := make([]int, 0, len(route.zones))
+	for _, zone := range route.zones {
+		zoneIds = append(zoneIds, zone.Id)
+	}
+
+	zoneLoads, err := pu.zoneRepository.FindZoneLoadData(zoneIds)
+	if err != nil {
+		return nil, err
+	}
+
+	planner := &putawayPlanner{
+		route:         route,
+		firstStep:     math.MaxInt,
+		zoneLoads:     zoneLoads,
+		zoneCounts:    make(map[uint64]uint64),
+		zoneSkuCounts: make(map[uint64]map[uint64]uint64),
+		binSkuCounts:  make(map[uint64]map[uint64]uint64),
+		occupied:      make(map[uint64]uint64),
+		bins:          make(map[uint64][]domain.Location),
+	}
+
+	for _, steps := range []map[uint64]int{route.zoneSteps, route.locationSteps} {
+		for _, sequence := range steps {
+			planner.firstStep = min(planner.firstStep, sequence)
+			planner.lastStep = max(planner.lastStep, sequence)
+		}
+	}
+
+	for _, location := range route.locations {
+		if location.Kind == domain.LocationKindBin {
+			planner.bins[location.ZoneId] = append(planner.bins[location.ZoneId], location)
+		}
+	}
+	for zoneId := range planner.bins {
+		bins := planner.bins[zoneId]
+		sort.Slice(bins, func(i, j int) bool {
+			return bins[i].Id < bins[j].Id
+		})
+	}
+
+	for _, row := range *stock {
+		planner.zoneCounts[row.ZoneId] += row.Count
+		if planner.zoneSkuCounts[row.ZoneId] == nil {
+			planner.zoneSkuCounts[row.ZoneId] = make(map[uint64]uint64)
+		}
+		planner.zoneSkuCounts[row.ZoneId][row.SkuId] += row.Count
+
+		if row.LocationId == nil {
+			continue
+		}
+
+		if planner.binSkuCounts[*row.LocationId] == nil {
+			planner.binSkuCounts[*row.LocationId] = make(map[uint64]uint64)
+		}
+		planner.binSkuCounts[*row.LocationId][row.SkuId] += row.Count
+
+		for current := row.LocationId; current != nil; {
+			location, found := route.locations[*current]
+			if !found {
+				break
+			}
+			planner.occupied[location.Id] += row.Count
+			current = location.ParentId
+		}
+	}
+
+	return planner, nil
+}
+
+// candidates оценивает места для строки и упорядочивает их по убыванию оценки. В зоне с ячейками предлагаются ячейки,
+// зона без ячеек предлагается целиком. Место, где товар уже лежит, не предлагается
+func (p *putawayPlanner) candidates(line *domain.PutawayLine) []putawayCandidate {
+	quantity := line.Line.ReceivedQuantity
+
+	originZoneId := line.Line.ZoneId
+	var originLocationId *uint64
+	if line.Product != nil {
+		originZoneId = line.Product.ZoneId
+		originLocationId = line.Product.LocationId
+	}
+	originStep, _ := p.route.place(originZoneId, originLocationId)
+
+	var candidates []putawayCandidate
+	for zoneId, zone := range p.route.zones {
+		compatibility := storageCompatibility(&zone, &line.Sku)
+		if compatibility == 0 {
+			continue
+		}
+
+		// Проведенный товар уже входит в загрузку своей зоны, перенос внутри зоны ее не меняет
+		incoming := quantity
+		if line.Product != nil && zoneId == originZoneId {
+			incoming = 0
+		}
+		zoneShare, fits := p.zoneFreeShare(&zone, &line.Sku, incoming)
+		if !fits {
+			continue
+		}
+
+		sameSkuInZone := p.zoneSkuCounts[zoneId][line.Sku.Id]
+
+		bins := p.bins[zoneId]
+		if len(bins) == 0 {
+			if zoneId == originZoneId && originLocationId == nil {
+				continue
+			}
+
+			candidate := putawayCandidate{
+				zoneId:        zoneId,
+				capacity:      zoneShare,
+				compatibility: compatibility,
+				sameSku:       sameSkuInZone,
+			}
+			if sameSkuInZone > 0 {
+				candidate.consolidation = 1
+			}
+			p.rate(&candidate, originStep)
+			candidates = append(candidates, candidate)
+			continue
+		}
+
+		for _, bin := range bins {
+			if originLocationId != nil && *originLocationId == bin.Id {
+				continue
+			}
+
+			binShare, fits := p.binFreeShare(bin.Id, quantity)
+			if !fits {
+				continue
+			}
+
+			binId := bin.Id
+			candidate := putawayCandidate{
+				zoneId:        zoneId,
+				locationId:    &binId,
+				capacity:      min(zoneShare, binShare),
+				compatibility: compatibility,
+				sameSku:       p.binSkuCounts[bin.Id][line.Sku.Id],
+			}
+			// Та же позиция в ячейке - полная консолидация, в другой ячейке той же зоны - половина
+			if candidate.sameSku > 0 {
+				candidate.consolidation = 1
+			} else if sameSkuInZone > 0 {
+				candidate.consolidation = 0.5
+			}
+			p.rate(&candidate, originStep)
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		if candidates[i].zoneId != candidates[j].zoneId {
+			return candidates[i].zoneId < candidates[j].zoneId
+		}
+		return locationOrder(candidates[i].locationId) < locationOrder(candidates[j].locationId)
+	})
+
+	return candidates
+}
+
+// rate считает близость места к месту приемки по маршруту обхода и итоговую оценку. Если товар лежит вне маршрута,
+// расстояние считается от начала маршрута. Место вне маршрута получает нулевую близость
+func (p *putawayPlanner) rate(candidate *putawayCandidate, originStep int) {
+	step, _ := p.route.place(candidate.zoneId, candidate.locationId)
+	if step != math.MaxInt {
+		distance := step - p.firstStep
+		if originStep != math.MaxInt {
+			distance = step - originStep
+			if distance < 0 {
+				distance = -distance
+			}
+		}
+		candidate.distance = &distance
+		candidate.nearness = 1 - float64(distance)/float64(p.lastStep-p.firstStep+1)
+	}
+
+	candidate.score = roundScore(putawayCapacityWeight*candidate.capacity +
+		putawayCompatibilityWeight*candidate.compatibility +
+		putawayConsolidationWeight*candidate.consolidation +
+		putawayDistanceWeight*candidate.nearness)
+}
+
+// zoneFreeShare возвращает долю предела зоны, которая останется свободной после прихода incoming долей базовой единицы,
+// по самому тесному из пределов: вместимости, объему, весу и паллетоместам. false - позиция в зону не поместится
+func (p *putawayPlanner) zoneFreeShare(zone *domain.Zone, sku *domain.Sku, incoming uint64) (float64, bool) {
+	share, fits := limitShare(float64(p.zoneCounts[uint64(zone.Id)]+incoming), float64(max(zone.Capacity, 0)))
+	if !fits {
+		return 0, false
+	}
+
+	load := p.zoneLoads[zone.Id]
+	added := skuLoad(sku, incoming)
+
+	// Пары из занятого после прихода и предела зоны
+	var limits [][2]float64
+	if zone.MaxVolume != nil {
+		limits = append(limits, [2]float64{load.Volume + added.Volume, *zone.MaxVolume})
+	}
+	if zone.MaxWeight != nil {
+		limits = append(limits, [2]float64{load.Weight + added.Weight, *zone.MaxWeight})
+	}
+	if zone.MaxPallets != nil {
+		limits = append(limits, [2]float64{float64(load.Pallets + added.Pallets), float64(*zone.MaxPallets)})
+	}
+
+	for _, limit := range limits {
+		limitFree, fits := limitShare(limit[0], limit[1])
+		if !fits {
+			return 0, false
+		}
+		share = min(share, limitFree)
+	}
+
+	return share, true
+}
+
+// binFreeShare проверяет вместимость ячейки и всех ее родителей так же, как при приходе товара, и возвращает
+// свободную долю самого заполненного узла с ограничением. Без ограничений по пути ячейка считается свободной
+func (p *putawayPlanner) binFreeShare(locationId uint64, quantity uint64) (float64, bool) {
+	share := 1.0
+	for current := &locationId; current != nil; {
+		location, found := p.route.locations[*current]
+		if !found {
+			break
+		}
+
+		if location.Capacity != nil {
+			locationFree, fits := limitShare(float64(p.occupied[location.Id]+quantity), float64(*location.Capacity))
+			if !fits {
+				return 0, false
+			}
+			share = min(share, locationFree)
+		}
+
+		current = location.ParentId
+	}
+
+	return share, true
+}
+
+func (p *putawayPlanner) candidateToResponse(candidate *putawayCandidate, line *domain.ReceiptLine) delivery.PutawayCandidateModelResponse {
+	_, path := p.route.place(candidate.zoneId, candidate.locationId)
+
+	return delivery.PutawayCandidateModelResponse{
+		ZoneId:             candidate.zoneId,
+		ZoneName:           p.route.zones[candidate.zoneId].Name,
+		LocationId:         candidate.locationId,
+		LocationPath:       path,
+		Score:              candidate.score,
+		CapacityScore:      roundScore(candidate.capacity),
+		CompatibilityScore: roundScore(candidate.compatibility),
+		ConsolidationScore: roundScore(candidate.consolidation),
+		DistanceScore:      roundScore(candidate.nearness),
+		SameSkuQuantity:    fromStockQuantity(candidate.sameSku, line.UnitFactor),
+		Distance:           candidate.distance,
+	}
+}
+
+// storageCompatibility оценивает, насколько зона подходит позиции. Зоны, несовместимые по правилам хранения
+// domain.Zone.CanStore, получают 0, как и карантин: в него товар не размещают, а переводят блокировкой.
+// Обычный товар в зоне для опасных грузов хранить можно, но он занимает место, которого мало
+func storageCompatibility(zone *domain.Zone, sku *domain.Sku) float64 {
+	if zone.ZoneType == domain.ZoneTypeQuarantine || !zone.CanStore(sku) {
+		return 0
+	}
+
+	if sku.HazardClass == "" && zone.ZoneType == domain.ZoneTypeHazardous {
+		return 0.3
+	}
+
+	return 1
+}
+
+// skuLoad - загрузка, которую дают quantity долей базовой единицы позиции. Паллеты считаются отдельно
+// от уже лежащего в зоне остатка, поэтому оценка по паллетоместам получается с запасом
+func skuLoad(sku *domain.Sku, quantity uint64) domain.ZoneLoad {
+	scale := math.Pow10(int(sku.Decimals))
+	units := float64(quantity) / scale
+
+	load := domain.ZoneLoad{
+		Volume: units * sku.LengthCm * sku.WidthCm * sku.HeightCm / 1e6,
+		Weight: units * sku.WeightKg,
+	}
+
+	if sku.UnitsPerPallet > 0 {
+		perPallet := sku.UnitsPerPallet * uint64(scale)
+		load.Pallets = (quantity + perPallet - 1) / perPallet
+	}
+
+	return load
+}
+
+// limitShare возвращает долю предела limit, которая останется свободной при занятом used. false - предел превышен
+func limitShare(used float64, limit float64) (float64, bool) {
+	if used > limit {
+		return 0, false
+	}
+	if limit == 0 {
+		return 0, true
+	}
+
+	return 1 - used/limit, true
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
+
+func sameLocation(a *uint64, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return *a == *b
+}
+
+// locationOrder упорядочивает место без ячейки раньше ячеек
+func locationOrder(locationId *uint64) uint64 {
+	if locationId == nil {
+		return 0
+	}
+
+	return *locationId
+}
+
+func putawayToResponse(putaway *domain.Putaway, line *domain.ReceiptLine) delivery.PutawayModelResponse {
+	return delivery.PutawayModelResponse{
+		Id:                  putaway.Id,
+		ReceiptId:           putaway.ReceiptId,
+		LineId:              putaway.ReceiptLineId,
+		ProductUuid:         putaway.ProductUuid,
+		Quantity:            fromStockQuantity(putaway.Quantity, line.UnitFactor),
+		Unit:                line.Unit,
+		ZoneId:              putaway.ZoneId,
+		LocationId:          putaway.LocationId,
+		SuggestedZoneId:     putaway.SuggestedZoneId,
+		SuggestedLocationId: putaway.SuggestedLocationId,
+		Override:            putaway.Override,
+		Reason:              putaway.Reason,
+		CreatedBy:           putaway.CreatedBy,
+		CreatedAt:           putaway.CreatedAt,
+	}
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	"math"
+	"testing"
+)
+
+func TestStorageCompatibility(t *testing.T) {
+	ambient := &domain.Sku{TemperatureClass: domain.TemperatureClassAmbient}
+	hazardous := &domain.Sku{TemperatureClass: domain.TemperatureClassAmbient, HazardClass: "3"}
+
+	tests := []struct {
+		name string
+		zone domain.Zone
+		sku  *domain.Sku
+		want float64
+	}{
+		{name: "general zone", zone: domain.Zone{ZoneType: domain.ZoneTypeGeneral, TemperatureClass: domain.TemperatureClassAmbient}, sku: ambient, want: 1},
+		{name: "other temperature", zone: domain.Zone{ZoneType: domain.ZoneTypeGeneral, TemperatureClass: domain.TemperatureClassChilled}, sku: ambient, want: 0},
+		{name: "quarantine is not a putaway target", zone: domain.Zone{ZoneType: domain.ZoneTypeQuarantine, TemperatureClass: domain.TemperatureClassAmbient}, sku: ambient, want: 0},
+		{name: "plain goods in hazardous zone", zone: domain.Zone{ZoneType: domain.ZoneTypeHazardous, TemperatureClass: domain.TemperatureClassAmbient}, sku: ambient, want: 0.3},
+		{name: "hazardous goods in hazardous zone", zone: domain.Zone{ZoneType: domain.ZoneTypeHazardous, TemperatureClass: domain.TemperatureClassAmbient}, sku: hazardous, want: 1},
+		{name: "hazardous goods in general zone", zone: domain.Zone{ZoneType: domain.ZoneTypeGeneral, TemperatureClass: domain.TemperatureClassAmbient}, sku: hazardous, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := storageCompatibility(&tt.zone, tt.sku); got != tt.want {
+				t.Errorf("storageCompatibility() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitShare(t *testing.T) {
+	tests := []struct {
+		name     string
+		used     float64
+		limit    float64
+		want     float64
+		wantFits bool
+	}{
+		{name: "empty", used: 0, limit: 100, want: 1, wantFits: true},
+		{name: "partly used", used: 25, limit: 100, want: 0.75, wantFits: true},
+		{name: "exactly full", used: 100, limit: 100, want: 0, wantFits: true},
+		{name: "over the limit", used: 101, limit: 100, want: 0, wantFits: false},
+		{name: "zero limit stays empty", used: 0, limit: 0, want: 0, wantFits: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fits := limitShare(tt.used, tt.limit)
+			if got != tt.want || fits != tt.wantFits {
+				t.Errorf("limitShare() = (%v, %v), want (%v, %v)", got, fits, tt.want, tt.wantFits)
+			}
+		})
+	}
+}
+
+func TestSkuLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		sku      domain.Sku
+		quantity uint64
+		want     domain.ZoneLoad
+	}{
+		{
+			name:     "pieces with size and weight",
+			sku:      domain.Sku{LengthCm: 100, WidthCm: 50, HeightCm: 20, WeightKg: 2},
+			quantity: 10,
+			want:     domain.ZoneLoad{Volume: 1, Weight: 20},
+		},
+		{
+			name:     "pallets are rounded up",
+			sku:      domain.Sku{UnitsPerPallet: 40},
+			quantity: 41,
+			want:     domain.ZoneLoad{Pallets: 2},
+		},
+		{
+			name:     "fractional base unit",
+			sku:      domain.Sku{Decimals: 3, WeightKg: 1, UnitsPerPallet: 500},
+			quantity: 1500,
+			want:     domain.ZoneLoad{Weight: 1.5, Pallets: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := skuLoad(&tt.sku, tt.quantity)
+			if math.Abs(got.Volume-tt.want.Volume) > 1e-9 || math.Abs(got.Weight-tt.want.Weight) > 1e-9 || got.Pallets != tt.want.Pallets {
+				t.Errorf("skuLoad() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testPutawayPlanner - склад с маршрутом: зона приемки 1, зона хранения 2 с ячейками 21 и 22 (в 22 помещается 5),
+// зона опасных грузов 5. Карантин 3 и холодильная зона 4 в маршрут не входят. В ячейке 21 уже лежит позиция 7
+func testPutawayPlanner() *putawayPlanner {
+	smallBin := uint64(5)
+
+	zones := []domain.Zone{
+		{Id: 1, Capacity: 100, ZoneType: domain.ZoneTypeGeneral, TemperatureClass: domain.TemperatureClassAmbient},
+		{Id: 2, Capacity: 100, ZoneType: domain.ZoneTypeGeneral, TemperatureClass: domain.TemperatureClassAmbient},
+		{Id: 3, Capacity: 100, ZoneType: domain.ZoneTypeQuarantine, TemperatureClass: domain.TemperatureClassAmbient},
+		{Id: 4, Capacity: 100, ZoneType: domain.ZoneTypeGeneral, TemperatureClass: domain.TemperatureClassChilled},
+		{Id: 5, Capacity: 50, ZoneType: domain.ZoneTypeHazardous, TemperatureClass: domain.TemperatureClassAmbient},
+	}
+	bins := []domain.Location{
+		{Id: 21, ZoneId: 2, Kind: domain.LocationKindBin, Code: "A1"},
+		{Id: 22, ZoneId: 2, Kind: domain.LocationKindBin, Code: "A2", Capacity: &smallBin},
+	}
+
+	route := &walkRoute{
+		zones:         make(map[uint64]domain.Zone),
+		locations:     make(map[uint64]domain.Location),
+		zoneSteps:     map[uint64]int{1: 1, 2: 2, 5: 3},
+		locationSteps: map[uint64]int{},
+	}
+	for _, zone := range zones {
+		route.zones[uint64(zone.Id)] = zone
+	}
+	for _, bin := range bins {
+		route.locations[bin.Id] = bin
+	}
+
+	return &putawayPlanner{
+		route:         route,
+		firstStep:     1,
+		lastStep:      3,
+		zoneLoads:     map[int]domain.ZoneLoad{},
+		zoneCounts:    map[uint64]uint64{2: 10},
+		zoneSkuCounts: map[uint64]map[uint64]uint64{2: {7: 10}},
+		binSkuCounts:  map[uint64]map[uint64]uint64{21: {7: 10}},
+		occupied:      map[uint64]uint64{21: 10},
+		bins:          map[uint64][]domain.Location{2: bins},
+	}
+}
+
+func TestPutawayCandidates(t *testing.T) {
+	type place struct {
+		zoneId     uint64
+		locationId uint64
+		score      float64
+	}
+
+	tests := []struct {
+		name     string
+		sku      domain.Sku
+		quantity uint64
+		product  *domain.Product
+		want     []place
+	}{
+		{
+			name:     "consolidation wins, small bin and hazardous zone follow",
+			sku:      domain.Sku{Id: 7, TemperatureClass: domain.TemperatureClassAmbient},
+			quantity: 4,
+			product:  &domain.Product{ZoneId: 1},
+			want: []place{
+				{zoneId: 2, locationId: 21, score: 0.891},
+				{zoneId: 2, locationId: 22, score: 0.543},
+				{zoneId: 5, score: 0.403},
+			},
+		},
+		{
+			name:     "bin that is too small is skipped",
+			sku:      domain.Sku{Id: 7, TemperatureClass: domain.TemperatureClassAmbient},
+			quantity: 6,
+			product:  &domain.Product{ZoneId: 1},
+			want: []place{
+				{zoneId: 2, locationId: 21, score: 0.885},
+				{zoneId: 5, score: 0.391},
+			},
+		},
+		{
+			name:     "hazardous goods go only to hazardous zone",
+			sku:      domain.Sku{Id: 8, TemperatureClass: domain.TemperatureClassAmbient, HazardClass: "3"},
+			quantity: 4,
+			product:  &domain.Product{ZoneId: 1},
+			want: []place{
+				{zoneId: 5, score: 0.543},
+			},
+		},
+		{
+			name:     "current bin is not suggested",
+			sku:      domain.Sku{Id: 7, TemperatureClass: domain.TemperatureClassAmbient},
+			quantity: 4,
+			product:  &domain.Product{ZoneId: 2, LocationId: func() *uint64 { id := uint64(21); return &id }()},
+			want: []place{
+				{zoneId: 1, score: 0.621},
+				{zoneId: 2, locationId: 22, score: 0.61},
+				{zoneId: 5, score: 0.469},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := &domain.PutawayLine{
+				Line:    domain.ReceiptLine{ZoneId: 1, ReceivedQuantity: tt.quantity},
+				Sku:     tt.sku,
+				Product: tt.product,
+			}
+
+			candidates := testPutawayPlanner().candidates(line)
+			if len(candidates) != len(tt.want) {
+				t.Fatalf("candidates() returned %d places, want %d: %+v", len(candidates), len(tt.want), candidates)
+			}
+
+			for i, want := range tt.want {
+				got := candidates[i]
+				if got.zoneId != want.zoneId || locationOrder(got.locationId) != want.locationId || got.score != want.score {
+					t.Errorf("candidate %d = zone %d, location %d, score %v; want zone %d, location %d, score %v",
+						i, got.zoneId, locationOrder(got.locationId), got.score, want.zoneId, want.locationId, want.score)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/config"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"github.com/Miroslovelife/whareflow/pkg/qr"
+	"log/slog"
+)
+
+type ReceiptUsecase interface {
+	CreateReceipt(in *delivery.ReceiptModelRequest, userId string, warehouseId int, actorId string) (*delivery.ReceiptModelResponse, error)
+	UpdateReceipt(in *delivery.ReceiptModelRequest, userId string, warehouseId int, receiptId uint64) error
+	GetAllReceipts(userId string, warehouseId int) ([]delivery.ReceiptModelResponse, error)
+	GetReceipt(userId string, warehouseId int, receiptId uint64) (*delivery.ReceiptModelResponse, error)
+	ReceiveReceipt(in *delivery.ReceiveReceiptModelRequest, userId string, warehouseId int, receiptId uint64) error
+	PostReceipt(userId string, warehouseId int, receiptId uint64, actorId string) error
+}
+
+type IReceiptUsecase struct {
+	receiptRepository      repositories.ReceiptRepository
+	productRepository      repositories.ProductRepository
+	serialNumberRepository repositories.SerialNumberRepository
+	skuRepository          repositories.SkuRepository
+	reorderRuleRepository  repositories.ReorderRuleRepository
+	notifier               notifier.Notifier
+	qrGenerator            qr.GeneratorQR
+	cfg                    config.Config
+	logger                 slog.Logger
+}
+
+func NewIReceiptUsecase(receiptRepository repositories.ReceiptRepository, productRepository repositories.ProductRepository, serialNumberRepository repositories.SerialNumberRepository, skuRepository repositories.SkuRepository, reorderRuleRepository repositories.ReorderRuleRepository, notifier notifier.Notifier, qrGenerator qr.GeneratorQR, cfg config.Config, logger slog.Logger) *IReceiptUsecase {
+	return &IReceiptUsecase{
+		receiptRepository:      receiptRepository,
+		productRepository:      productRepository,
+		serialNumberRepository: serialNumberRepository,
+		skuRepository:          skuRepository,
+		reorderRuleRepository:  reorderRuleRepository,
+		notifier:               notifier,
+		qrGenerator:            qrGenerator,
+		cfg:                    cfg,
+		logger:                 logger,
+	}
+}
+
+func (ru *IReceiptUsecase) CreateReceipt(in *delivery.ReceiptModelRequest, userId string, warehouseId int, actorId string) (*delivery.ReceiptModelResponse, error) {
+	lines, err := ru.buildReceiptLines(in.Lines, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &domain.Receipt{
+		WarehouseId:     uint64(warehouseId),
+		PurchaseOrderId: in.PurchaseOrderId,
+		Status:          domain.ReceiptStatusDraft,
+		Comment:         in.Comment,
+		CreatedBy:       actorId,
+		Lines:           lines,
+	}
+
+	if err := ru.receiptRepository.InsertReceiptData(receipt, userId); err != nil {
+		return nil, err
+	}
+
+	return ru.GetReceipt(userId, warehouseId, receipt.Id)
+}
+
+func (ru *IReceiptUsecase) UpdateReceipt(in *delivery.ReceiptModelRequest, userId string, warehouseId int, receiptId uint64) error {
+	lines, err := ru.buildReceiptLines(in.Lines, userId)
+	if err != nil {
+		return err
+	}
+
+	receipt := &domain.Receipt{
+		Id:          receiptId,
+		WarehouseId: uint64(warehouseId),
+		Comment:     in.Comment,
+		Lines:       lines,
+	}
+
+	return ru.receiptRepository.UpdateReceiptLinesData(receipt, userId)
+}
+
+func (ru *IReceiptUsecase) GetAllReceipts(userId string, warehouseId int) ([]delivery.ReceiptModelResponse, error) {
+	receipts, err := ru.receiptRepository.FindAllReceiptData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptsRes := []delivery.ReceiptModelResponse{}
+	for _, receipt := range *receipts {
+		receiptsRes = append(receiptsRes, receiptToResponse(&receipt))
+	}
+
+	return receiptsRes, nil
+}
+
+func (ru *IReceiptUsecase) GetReceipt(userId string, warehouseId int, receiptId uint64) (*delivery.ReceiptModelResponse, error) {
+	receipt, err := ru.receiptRepository.FindReceiptData(userId, warehouseId, receiptId)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptRes := receiptToResponse(receipt)
+
+	return &receiptRes, nil
+}
+
+// ReceiveReceipt переводит принятые количества из единиц строк в доли базовой единицы
+func (ru *IReceiptUsecase) ReceiveReceipt(in *delivery.ReceiveReceiptModelRequest, userId string, warehouseId int, receiptId uint64) error {
+	receipt, err := ru.receiptRepository.FindReceiptData(userId, warehouseId, receiptId)
+	if err != nil {
+		return err
+	}
+
+	factors := make(map[uint64]float64, len(receipt.Lines))
+	for _, line := range receipt.Lines {
+		factors[line.Id] = line.UnitFactor
+	}
+
+	received := make(map[uint64]uint64, len(in.Lines))
+	serials := make(map[uint64][]string)
+	for _, line := range in.Lines {
+		factor, ok := factors[line.LineId]
+		if !ok {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+
+		quantity, err := toStockQuantity(line.ReceivedQuantity, factor)
+		if err != nil {
+			return err
+		}
+
+		received[line.LineId] = quantity
+		if len(line.Serials) > 0 {
+			serials[line.LineId] = line.Serials
+		}
+	}
+
+	return ru.receiptRepository.ReceiveReceiptData(userId, warehouseId, receiptId, received, serials)
+}
+
+func (ru *IReceiptUsecase) PostReceipt(userId string, warehouseId int, receiptId uint64, actorId string) error {
+	createdProducts, err := ru.receiptRepository.PostReceiptData(userId, warehouseId, receiptId, actorId)
+	if err != nil {
+		return err
+	}
+
+	// QR-коды генерируются только для товаров, созданных поступлением.
+	// Поступление к этому моменту уже проведено, поэтому ошибка выдачи QR только пишется в лог
+	for _, product := range *createdProducts {
+		qrPath, err := generateProductQR(ru.qrGenerator, ru.cfg, warehouseId, product.ZoneId, string(product.Uuid))
+		if err != nil {
+			ru.logger.Error(fmt.Sprintf("Receipt %d posted, but qr generation for product %s failed: %v", receiptId, product.Uuid, err))
+			continue
+		}
+
+		if err := ru.productRepository.UpdateProductQrData(string(product.Uuid), qrPath); err != nil {
+			ru.logger.Error(fmt.Sprintf("Receipt %d posted, but qr generation for product %s failed: %v", receiptId, product.Uuid, err))
+		}
+	}
+
+	if err := generateSerialQRs(ru.qrGenerator, ru.cfg, ru.serialNumberRepository, warehouseId); err != nil {
+		ru.logger.Error(fmt.Sprintf("Receipt %d posted, but serial qr generation failed: %v", receiptId, err))
+	}
+
+	// Приход снимает отметки оповещения с правил, остаток которых снова достиг точки перезаказа
+	if err := evaluateReorderRules(ru.reorderRuleRepository, ru.notifier, warehouseId, nil); err != nil {
+		ru.logger.Error(fmt.Sprintf("Receipt %d posted, but reorder evaluation failed: %v", receiptId, err))
+	}
+
+	return nil
+}
+
+func (ru *IReceiptUsecase) buildReceiptLines(in []delivery.ReceiptLineModelRequest, userId string) ([]domain.ReceiptLine, error) {
+	var lines []domain.ReceiptLine
+
+	for _, lineReq := range in {
+		if err := checkLotDates(lineReq.ProductionDate, lineReq.ExpiryDate); err != nil {
+			return nil, err
+		}
+
+		line := domain.ReceiptLine{
+			ZoneId:              lineReq.ZoneId,
+			SkuId:               lineReq.SkuId,
+			LotNumber:           lineReq.LotNumber,
+			ProductionDate:      lineReq.ProductionDate,
+			ExpiryDate:          lineReq.ExpiryDate,
+			PurchaseOrderLineId: lineReq.PurchaseOrderLineId,
+		}
+
+		if lineReq.ProductUuid != "" {
+			// Существующий товар принимается в свою зону
+			product, err := ru.productRepository.FindProductData(userId, lineReq.ProductUuid)
+			if err != nil {
+				return nil, custom_errors.ErrProductNotFound
+			}
+			if line.ZoneId != 0 && line.ZoneId != product.ZoneId {
+				return nil, custom_errors.ErrInvalidDocumentLine
+			}
+
+			if line.SkuId != 0 && line.SkuId != product.SkuId {
+				return nil, custom_errors.ErrInvalidDocumentLine
+			}
+
+			line.ZoneId = product.ZoneId
+			line.SkuId = product.SkuId
+
+			if line.LotNumber == "" || line.LotNumber == product.LotNumber {
+				productUuid := string(product.Uuid)
+				line.ProductUuid = &productUuid
+				line.LotNumber = product.LotNumber
+				line.ProductionDate = product.ProductionDate
+				line.ExpiryDate = product.ExpiryDate
+			}
+		} else if lineReq.SkuId == 0 || lineReq.ZoneId == 0 {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		// Название и описание сохраняются в строке документа на момент его создания
+		sku, err := ru.skuRepository.FindSkuData(userId, line.SkuId)
+		if err != nil {
+			return nil, err
+		}
+		line.Title = sku.Name
+		line.Description = sku.Description
+
+		line.Unit, line.UnitFactor, err = skuUnitFactor(sku, lineReq.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		line.Quantity, err = toStockQuantity(lineReq.Quantity, line.UnitFactor)
+		if err != nil {
+			return nil, err
+		}
+		if line.Quantity == 0 {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func receiptToResponse(receipt *domain.Receipt) delivery.ReceiptModelResponse {
+	linesRes := []delivery.ReceiptLineModelResponse{}
+	for _, line := range receipt.Lines {
+		lineRes := delivery.ReceiptLineModelResponse{
+			Id:                  line.Id,
+			SkuId:               line.SkuId,
+			Title:               line.Title,
+			Description:         line.Description,
+			ZoneId:              line.ZoneId,
+			Unit:                line.Unit,
+			Quantity:            fromStockQuantity(line.Quantity, line.UnitFactor),
+			ReceivedQuantity:    fromStockQuantity(line.ReceivedQuantity, line.UnitFactor),
+			LotNumber:           line.LotNumber,
+			ProductionDate:      line.ProductionDate,
+			ExpiryDate:          line.ExpiryDate,
+			PurchaseOrderLineId: line.PurchaseOrderLineId,
+		}
+		if line.ProductUuid != nil {
+			lineRes.ProductUuid = *line.ProductUuid
+		}
+
+		linesRes = append(linesRes, lineRes)
+	}
+
+	return delivery.ReceiptModelResponse{
+		Id:              receipt.Id,
+		WarehouseId:     receipt.WarehouseId,
+		PurchaseOrderId: receipt.PurchaseOrderId,
+		Status:          receipt.Status,
+		Comment:         receipt.Comment,
+		CreatedBy:       receipt.CreatedBy,
+		CreatedAt:       receipt.CreatedAt,
+		ReceivedAt:      receipt.ReceivedAt,
+		PostedAt:        receipt.PostedAt,
+		Lines:           linesRes,
+	}
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"log/slog"
+)
+
+type ReorderRuleUsecase interface {
+	SetReorderRule(in *delivery.ReorderRuleModelRequest, userId string, warehouseId int) (*delivery.ReorderRuleModelResponse, error)
+	GetAllReorderRules(userId string, warehouseId int) ([]delivery.ReorderRuleModelResponse, error)
+	DeleteReorderRule(userId string, warehouseId int, ruleId uint64) error
+	GetLowStockReport(userId string, warehouseId int) (*delivery.LowStockReportResponse, error)
+}
+
+type IReorderRuleUsecase struct {
+	reorderRuleRepository repositories.ReorderRuleRepository
+	skuRepository         repositories.SkuRepository
+	notifier              notifier.Notifier
+	logger                slog.Logger
+}
+
+func NewIReorderRuleUsecase(reorderRuleRepository repositories.ReorderRuleRepository, skuRepository repositories.SkuRepository, notifier notifier.Notifier, logger slog.Logger) *IReorderRuleUsecase {
+	return &IReorderRuleUsecase{
+		reorderRuleRepository: reorderRuleRepository,
+		skuRepository:         skuRepository,
+		notifier:              notifier,
+		logger:                logger,
+	}
+}
+
+// SetReorderRule сразу сверяет новое правило с остатком, чтобы не ждать следующего движения по позиции.
+// Правило к этому моменту уже сохранено, поэтому ошибка проверки только пишется в лог
+func (ru *IReorderRuleUsecase) SetReorderRule(in *delivery.ReorderRuleModelRequest, userId string, warehouseId int) (*delivery.ReorderRuleModelResponse, error) {
+	if in.SkuId == 0 || in.MaxLevel < in.MinLevel {
+		return nil, custom_errors.ErrInvalidReorderRule
+	}
+
+	sku, err := ru.skuRepository.FindSkuData(userId, in.SkuId)
+	if err != nil {
+		return nil, err
+	}
+
+	minLevel, err := quantityToStock(sku, in.MinLevel, in.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLevel, err := quantityToStock(sku, in.MaxLevel, in.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &domain.ReorderRule{
+		WarehouseId: uint64(warehouseId),
+		SkuId:       in.SkuId,
+		MinLevel:    minLevel,
+		MaxLevel:    maxLevel,
+	}
+
+	if err := ru.reorderRuleRepository.UpsertReorderRuleData(rule, userId); err != nil {
+		return nil, err
+	}
+
+	if err := evaluateReorderRules(ru.reorderRuleRepository, ru.notifier, warehouseId, []uint64{in.SkuId}); err != nil {
+		ru.logger.Error(fmt.Sprintf("Reorder rule for sku %d saved, but evaluation failed: %v", in.SkuId, err))
+	}
+
+	rules, err := ru.GetAllReorderRules(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ruleRes := range rules {
+		if ruleRes.SkuId == in.SkuId {
+			return &ruleRes, nil
+		}
+	}
+
+	return nil, custom_errors.ErrReorderRuleNotFound
+}
+
+func (ru *IReorderRuleUsecase) GetAllReorderRules(userId string, warehouseId int) ([]delivery.ReorderRuleModelResponse, error) {
+	rules, err := ru.reorderRuleRepository.FindAllReorderRuleData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	skuIds := make([]uint64, 0, len(*rules))
+	for _, rule := range *rules {
+		skuIds = append(skuIds, rule.SkuId)
+	}
+
+	skus, err := ru.skuRepository.FindSkusData(userId, skuIds)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesRes := []delivery.ReorderRuleModelResponse{}
+	for _, rule := range *rules {
+		sku, ok := skus[rule.SkuId]
+		if !ok {
+			return nil, custom_errors.ErrSkuNotFound
+		}
+
+		rulesRes = append(rulesRes, delivery.ReorderRuleModelResponse{
+			Id:          rule.Id,
+			WarehouseId: rule.WarehouseId,
+			SkuId:       rule.SkuId,
+			Unit:        sku.Unit,
+			MinLevel:    baseQuantity(sku, rule.MinLevel),
+			MaxLevel:    baseQuantity(sku, rule.MaxLevel),
+			AlertedAt:   rule.AlertedAt,
+			CreatedAt:   rule.CreatedAt,
+		})
+	}
+
+	return rulesRes, nil
+}
+
+func (ru *IReorderRuleUsecase) DeleteReorderRule(userId string, warehouseId int, ruleId uint64) error {
+	return ru.reorderRuleRepository.DeleteReorderRuleData(userId, warehouseId, ruleId)
+}
+
+func (ru *IReorderRuleUsecase) GetLowStockReport(userId string, warehouseId int) (*delivery.LowStockReportResponse, error) {
+	levels, err := ru.reorderRuleRepository.FindLowStockData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsRes := []delivery.LowStockItemResponse{}
+	for _, level := range *levels {
+		var reorderQuantity uint64
+		if level.MaxLevel > level.OnHand {
+			reorderQuantity = level.MaxLevel - level.OnHand
+		}
+
+		sku := stockLevelSku(&level)
+		itemsRes = append(itemsRes, delivery.LowStockItemResponse{
+			SkuId:           level.SkuId,
+			SkuCode:         level.SkuCode,
+			Title:           level.SkuName,
+			Unit:            level.Unit,
+			OnHand:          baseQuantity(sku, level.OnHand),
+			MinLevel:        baseQuantity(sku, level.MinLevel),
+			MaxLevel:        baseQuantity(sku, level.MaxLevel),
+			ReorderQuantity: baseQuantity(sku, reorderQuantity),
+		})
+	}
+
+	return &delivery.LowStockReportResponse{
+		WarehouseId: uint64(warehouseId),
+		Items:       itemsRes,
+	}, nil
+}
+
+// evaluateReorderRules - общая проверка уровней запаса после изменения остатков, ее вызывает каждый сценарий,
+// который проводит движения по журналу. Оповещение уходит по каждой позиции, остаток которой опустился ниже
+// точки перезаказа, если проверка первой отметила правило. Отметка снимается, если отправить не удалось,
+// поэтому неотправленное оповещение повторится при следующей проверке
+func evaluateReorderRules(reorderRuleRepository repositories.ReorderRuleRepository, alertNotifier notifier.Notifier, warehouseId int, skuIds []uint64) error {
+	crossed, err := reorderRuleRepository.EvaluateReorderRulesData(warehouseId, skuIds)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, level := range *crossed {
+		claimed, err := reorderRuleRepository.ClaimReorderAlertData(level.RuleId)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		sku := stockLevelSku(&level)
+		err = alertNotifier.Notify(notifier.Notification{
+			Subject: "low_stock",
+			Message: fmt.Sprintf("Stock of %s (%s) dropped below reorder point", level.SkuName, level.SkuCode),
+			Fields: map[string]interface{}{
+				"warehouse_id": level.WarehouseId,
+				"sku_id":       level.SkuId,
+				"unit":         level.Unit,
+				"on_hand":      baseQuantity(sku, level.OnHand),
+				"min_level":    baseQuantity(sku, level.MinLevel),
+				"max_level":    baseQuantity(sku, level.MaxLevel),
+			},
+		})
+		if err != nil {
+			errs = append(errs, err)
+			if err := reorderRuleRepository.ReleaseReorderAlertData(level.RuleId); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// stockLevelSku - базовая единица позиции из уровня запаса, по которой пересчитываются его количества
+func stockLevelSku(level *domain.StockLevel) *domain.Sku {
+	return &domain.Sku{
+		Id:       level.SkuId,
+		Code:     level.SkuCode,
+		Name:     level.SkuName,
+		Unit:     level.Unit,
+		Decimals: level.Decimals,
+	}
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ReservationSweeper периодически снимает истекшие резервы
+type ReservationSweeper struct {
+	reservationUsecase ReservationUsecase
+	interval           time.Duration
+	logger             slog.Logger
+}
+
+func NewReservationSweeper(reservationUsecase ReservationUsecase, interval time.Duration, logger slog.Logger) *ReservationSweeper {
+	return &ReservationSweeper{
+		reservationUsecase: reservationUsecase,
+		interval:           interval,
+		logger:             logger,
+	}
+}
+
+// Run блокируется до отмены ctx, поэтому запускается в отдельной горутине
+func (rs *ReservationSweeper) Run(ctx context.Context) {
+	if rs.interval <= 0 {
+		rs.logger.Warn("Reservation sweeper is disabled: sweep interval is not positive")
+		return
+	}
+
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := rs.reservationUsecase.ReleaseExpiredReservations()
+			if err != nil {
+				rs.logger.Error(fmt.Sprintf("Can't release expired reservations: %v", err))
+				continue
+			}
+			if released > 0 {
+				rs.logger.Info(fmt.Sprintf("Released %d expired reservations", released))
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"time"
+)
+
+type ReservationUsecase interface {
+	CreateReservation(in *delivery.ReservationModelRequest, userId string, warehouseId int, actorId string) (*delivery.ReservationModelResponse, error)
+	GetAllReservations(userId string, warehouseId int, productId string) ([]delivery.ReservationModelResponse, error)
+	ReleaseReservation(userId string, warehouseId int, reservationId uint64) error
+	ReleaseExpiredReservations() (int64, error)
+}
+
+type IReservationUsecase struct {
+	reservationRepository repositories.ReservationRepository
+	skuRepository         repositories.SkuRepository
+}
+
+func NewIReservationUsecase(reservationRepository repositories.ReservationRepository, skuRepository repositories.SkuRepository) *IReservationUsecase {
+	return &IReservationUsecase{
+		reservationRepository: reservationRepository,
+		skuRepository:         skuRepository,
+	}
+}
+
+func (ru *IReservationUsecase) CreateReservation(in *delivery.ReservationModelRequest, userId string, warehouseId int, actorId string) (*delivery.ReservationModelResponse, error) {
+	if in.ProductUuid == "" || in.Quantity <= 0 || in.OwnerRef == "" {
+		return nil, custom_errors.ErrInvalidReservation
+	}
+
+	var expiresAt time.Time
+	switch {
+	case in.ExpiresAt != nil:
+		expiresAt = *in.ExpiresAt
+	case in.TtlMinutes > 0:
+		expiresAt = time.Now().Add(time.Duration(in.TtlMinutes) * time.Minute)
+	default:
+		return nil, custom_errors.ErrInvalidReservation
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return nil, custom_errors.ErrInvalidReservation
+	}
+
+	skus, err := ru.skuRepository.FindProductSkusData(userId, []string{in.ProductUuid})
+	if err != nil {
+		return nil, err
+	}
+
+	sku, err := productSku(skus, in.ProductUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, err := quantityToStock(sku, in.Quantity, in.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &domain.Reservation{
+		ProductUuid: in.ProductUuid,
+		Quantity:    quantity,
+		OwnerRef:    in.OwnerRef,
+		Status:      domain.ReservationStatusActive,
+		ExpiresAt:   &expiresAt,
+		CreatedBy:   actorId,
+	}
+
+	if err := ru.reservationRepository.InsertReservationData(reservation, userId, warehouseId); err != nil {
+		return nil, err
+	}
+
+	reservationRes := reservationToResponse(reservation, sku)
+
+	return &reservationRes, nil
+}
+
+func (ru *IReservationUsecase) GetAllReservations(userId string, warehouseId int, productId string) ([]delivery.ReservationModelResponse, error) {
+	reservations, err := ru.reservationRepository.FindAllReservationData(userId, warehouseId, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	productIds := make([]string, 0, len(*reservations))
+	for _, reservation := range *reservations {
+		productIds = append(productIds, reservation.ProductUuid)
+	}
+
+	skus, err := ru.skuRepository.FindProductSkusData(userId, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	reservationsRes := []delivery.ReservationModelResponse{}
+	for _, reservation := range *reservations {
+		sku, err := productSku(skus, reservation.ProductUuid)
+		if err != nil {
+			return nil, err
+		}
+		reservationsRes = append(reservationsRes, reservationToResponse(&reservation, sku))
+	}
+
+	return reservationsRes, nil
+}
+
+func (ru *IReservationUsecase) ReleaseReservation(userId string, warehouseId int, reservationId uint64) error {
+	return ru.reservationRepository.ReleaseReservationData(userId, warehouseId, reservationId)
+}
+
+func (ru *IReservationUsecase) ReleaseExpiredReservations() (int64, error) {
+	return ru.reservationRepository.ReleaseExpiredReservationsData()
+}
+
+func reservationToResponse(reservation *domain.Reservation, sku *domain.Sku) delivery.ReservationModelResponse {
+	return delivery.ReservationModelResponse{
+		Id:          reservation.Id,
+		ProductUuid: reservation.ProductUuid,
+		Unit:        sku.Unit,
+		Quantity:    baseQuantity(sku, reservation.Quantity),
+		OwnerRef:    reservation.OwnerRef,
+		Status:      reservation.Status,
+		ExpiresAt:   reservation.ExpiresAt,
+		CreatedBy:   reservation.CreatedBy,
+		CreatedAt:   reservation.CreatedAt,
+		ReleasedAt:  reservation.ReleasedAt,
+	}
+}
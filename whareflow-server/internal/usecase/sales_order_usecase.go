@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"log/slog"
+	"time"
+)
+
+// salesOrderReservationTtl - срок резерва строк заказа, если в запросе он не задан
+const salesOrderReservationTtl = 30 * 24 * time.Hour
+
+type SalesOrderUsecase interface {
+	CreateSalesOrder(in *delivery.SalesOrderModelRequest, userId string, warehouseId int, actorId string) (*delivery.SalesOrderModelResponse, error)
+	ConfirmSalesOrder(userId string, warehouseId int, orderId uint64, actorId string) error
+	CancelSalesOrder(userId string, warehouseId int, orderId uint64) error
+	GetAllSalesOrders(userId string, warehouseId int) ([]delivery.SalesOrderModelResponse, error)
+	GetSalesOrder(userId string, warehouseId int, orderId uint64) (*delivery.SalesOrderModelResponse, error)
+	GetPickTasks(userId string, warehouseId int, orderId uint64) ([]delivery.PickTaskModelResponse, error)
+	CompletePickTask(in *delivery.CompletePickTaskModelRequest, userId string, warehouseId int, taskId uint64, actorId string) error
+}
+
+type ISalesOrderUsecase struct {
+	salesOrderRepository repositories.SalesOrderRepository
+	productRepository    repositories.ProductRepository
+	notifier             notifier.Notifier
+	logger               slog.Logger
+}
+
+func NewISalesOrderUsecase(salesOrderRepository repositories.SalesOrderRepository, productRepository repositories.ProductRepository, notifier notifier.Notifier, logger slog.Logger) *ISalesOrderUsecase {
+	return &ISalesOrderUsecase{
+		salesOrderRepository: salesOrderRepository,
+		productRepository:    productRepository,
+		notifier:             notifier,
+		logger:               logger,
+	}
+}
+
+func (su *ISalesOrderUsecase) CreateSalesOrder(in *delivery.SalesOrderModelRequest, userId string, warehouseId int, actorId string) (*delivery.SalesOrderModelResponse, error) {
+	lines, err := su.buildSalesOrderLines(in.Lines, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	reservedUntil := time.Now().Add(salesOrderReservationTtl)
+	if in.ReservedUntil != nil {
+		if !in.ReservedUntil.After(time.Now()) {
+			return nil, custom_errors.ErrInvalidSalesOrder
+		}
+		reservedUntil = *in.ReservedUntil
+	}
+
+	order := &domain.SalesOrder{
+		WarehouseId:   uint64(warehouseId),
+		CustomerId:    in.CustomerId,
+		Status:        domain.SalesOrderStatusDraft,
+		ReservedUntil: reservedUntil,
+		Comment:       in.Comment,
+		CreatedBy:     actorId,
+		Lines:         lines,
+	}
+
+	if err := su.salesOrderRepository.InsertSalesOrderData(order, userId); err != nil {
+		return nil, err
+	}
+
+	return su.GetSalesOrder(userId, warehouseId, order.Id)
+}
+
+// ConfirmSalesOrder после подтверждения оповещает работников склада с правом product_manage о новых заданиях на сборку.
+// Заказ к этому моменту уже подтвержден, поэтому ошибки оповещения только пишутся в лог
+func (su *ISalesOrderUsecase) ConfirmSalesOrder(userId string, warehouseId int, orderId uint64, actorId string) error {
+	if err := su.salesOrderRepository.ConfirmSalesOrderData(userId, warehouseId, orderId, actorId); err != nil {
+		return err
+	}
+
+	pickers, err := su.salesOrderRepository.FindPickersData(warehouseId)
+	if err != nil {
+		su.logger.Error(fmt.Sprintf("Sales order %d confirmed, but pickers lookup failed: %v", orderId, err))
+		return nil
+	}
+
+	for _, picker := range *pickers {
+		err := su.notifier.Notify(notifier.Notification{
+			Subject: "pick_tasks",
+			Message: fmt.Sprintf("Sales order %d is confirmed and waiting to be picked", orderId),
+			Fields: map[string]interface{}{
+				"warehouse_id": warehouseId,
+				"order_id":     orderId,
+				"user_id":      string(picker.Uuid),
+				"username":     picker.Username,
+			},
+		})
+		if err != nil {
+			su.logger.Error(fmt.Sprintf("Sales order %d confirmed, but notification of picker %s failed: %v", orderId, picker.Uuid, err))
+		}
+	}
+
+	return nil
+}
+
+func (su *ISalesOrderUsecase) CancelSalesOrder(userId string, warehouseId int, orderId uint64) error {
+	return su.salesOrderRepository.CancelSalesOrderData(userId, warehouseId, orderId)
+}
+
+func (su *ISalesOrderUsecase) GetAllSalesOrders(userId string, warehouseId int) ([]delivery.SalesOrderModelResponse, error) {
+	orders, err := su.salesOrderRepository.FindAllSalesOrderData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	ordersRes := []delivery.SalesOrderModelResponse{}
+	for _, order := range *orders {
+		ordersRes = append(ordersRes, salesOrderToResponse(&order))
+	}
+
+	return ordersRes, nil
+}
+
+func (su *ISalesOrderUsecase) GetSalesOrder(userId string, warehouseId int, orderId uint64) (*delivery.SalesOrderModelResponse, error) {
+	order, err := su.salesOrderRepository.FindSalesOrderData(userId, warehouseId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	orderRes := salesOrderToResponse(order)
+
+	return &orderRes, nil
+}
+
+// GetPickTasks возвращает задания заказа orderId, а при orderId = 0 - очередь невыполненных заданий склада
+func (su *ISalesOrderUsecase) GetPickTasks(userId string, warehouseId int, orderId uint64) ([]delivery.PickTaskModelResponse, error) {
+	tasks, err := su.salesOrderRepository.FindAllPickTaskData(userId, warehouseId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	tasksRes := []delivery.PickTaskModelResponse{}
+	for _, task := range *tasks {
+		tasksRes = append(tasksRes, pickTaskToResponse(&task))
+	}
+
+	return tasksRes, nil
+}
+
+// CompletePickTask переводит собранное количество из единицы задания в доли базовой единицы
+func (su *ISalesOrderUsecase) CompletePickTask(in *delivery.CompletePickTaskModelRequest, userId string, warehouseId int, taskId uint64, actorId string) error {
+	task, err := su.salesOrderRepository.FindPickTaskData(userId, warehouseId, taskId)
+	if err != nil {
+		return err
+	}
+
+	picked := task.Quantity
+	if in.PickedQuantity != nil {
+		picked, err = toStockQuantity(*in.PickedQuantity, task.UnitFactor)
+		if err != nil {
+			return err
+		}
+	}
+
+	return su.salesOrderRepository.CompletePickTaskData(userId, warehouseId, taskId, picked, actorId)
+}
+
+func (su *ISalesOrderUsecase) buildSalesOrderLines(in []delivery.SalesOrderLineModelRequest, userId string) ([]domain.SalesOrderLine, error) {
+	if len(in) == 0 {
+		return nil, custom_errors.ErrInvalidSalesOrder
+	}
+
+	var lines []domain.SalesOrderLine
+	seen := make(map[string]struct{}, len(in))
+
+	for _, lineReq := range in {
+		if lineReq.ProductUuid == "" {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		// Строка заказа становится строкой отгрузки, а там один товар - одна строка
+		if _, ok := seen[lineReq.ProductUuid]; ok {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+		seen[lineReq.ProductUuid] = struct{}{}
+
+		product, err := su.productRepository.FindProductData(userId, lineReq.ProductUuid)
+		if err != nil || product.Sku == nil {
+			return nil, custom_errors.ErrProductNotFound
+		}
+
+		unit, factor, err := skuUnitFactor(product.Sku, lineReq.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := toStockQuantity(lineReq.Quantity, factor)
+		if err != nil {
+			return nil, err
+		}
+		if quantity == 0 {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		lines = append(lines, domain.SalesOrderLine{
+			ProductUuid: lineReq.ProductUuid,
+			Quantity:    quantity,
+			Unit:        unit,
+			UnitFactor:  factor,
+		})
+	}
+
+	return lines, nil
+}
+
+func salesOrderToResponse(order *domain.SalesOrder) delivery.SalesOrderModelResponse {
+	linesRes := []delivery.SalesOrderLineModelResponse{}
+	for _, line := range order.Lines {
+		linesRes = append(linesRes, delivery.SalesOrderLineModelResponse{
+			Id:            line.Id,
+			ProductUuid:   line.ProductUuid,
+			Unit:          line.Unit,
+			Quantity:      fromStockQuantity(line.Quantity, line.UnitFactor),
+			ReservationId: line.ReservationId,
+		})
+	}
+
+	orderRes := delivery.SalesOrderModelResponse{
+		Id:            order.Id,
+		WarehouseId:   order.WarehouseId,
+		CustomerId:    order.CustomerId,
+		Status:        order.Status,
+		ShipmentId:    order.ShipmentId,
+		ReservedUntil: order.ReservedUntil,
+		Comment:       order.Comment,
+		CreatedBy:     order.CreatedBy,
+		CreatedAt:     order.CreatedAt,
+		ConfirmedAt:   order.ConfirmedAt,
+		ShippedAt:     order.ShippedAt,
+		CancelledAt:   order.CancelledAt,
+		Lines:         linesRes,
+	}
+	if order.Customer != nil {
+		orderRes.CustomerName = order.Customer.Name
+	}
+
+	return orderRes
+}
+
+func pickTaskToResponse(task *domain.PickTask) delivery.PickTaskModelResponse {
+	return delivery.PickTaskModelResponse{
+		Id:             task.Id,
+		OrderId:        task.OrderId,
+		WaveId:         task.WaveId,
+		ShipmentLineId: task.ShipmentLineId,
+		ProductUuid:    task.ProductUuid,
+		ZoneId:         task.ZoneId,
+		LocationId:     task.LocationId,
+		Unit:           task.Unit,
+		Quantity:       fromStockQuantity(task.Quantity, task.UnitFactor),
+		PickedQuantity: fromStockQuantity(task.PickedQuantity, task.UnitFactor),
+		Status:         task.Status,
+		PickedBy:       task.PickedBy,
+		CreatedAt:      task.CreatedAt,
+		DoneAt:         task.DoneAt,
+	}
+}
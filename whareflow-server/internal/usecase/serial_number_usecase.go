@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/config"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/qr"
+	"net/url"
+)
+
+type SerialNumberUsecase interface {
+	FindSerialHistory(userId string, warehouseId int, serial string) (*delivery.SerialHistoryResponse, error)
+	FindAllSerialFromProduct(userId, productId string) ([]delivery.SerialNumberResponse, error)
+}
+
+type ISerialNumberUsecase struct {
+	serialNumberRepository repositories.SerialNumberRepository
+	productRepository      repositories.ProductRepository
+}
+
+func NewISerialNumberUsecase(serialNumberRepository repositories.SerialNumberRepository, productRepository repositories.ProductRepository) *ISerialNumberUsecase {
+	return &ISerialNumberUsecase{
+		serialNumberRepository: serialNumberRepository,
+		productRepository:      productRepository,
+	}
+}
+
+// FindSerialHistory собирает историю номера по всем его записям на складе, текущее состояние берется из последней
+func (su *ISerialNumberUsecase) FindSerialHistory(userId string, warehouseId int, serial string) (*delivery.SerialHistoryResponse, error) {
+	serials, err := su.serialNumberRepository.FindSerialData(userId, warehouseId, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(*serials))
+	for _, serialNumber := range *serials {
+		ids = append(ids, serialNumber.Id)
+	}
+
+	movements, err := su.serialNumberRepository.FindSerialMovementsData(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	current := (*serials)[len(*serials)-1]
+	history := &delivery.SerialHistoryResponse{
+		Serial:      current.Serial,
+		Status:      current.Status,
+		ProductUuid: current.ProductUuid,
+		QrImage:     current.QrPath,
+		Movements:   []delivery.SerialMovementResponse{},
+	}
+
+	if current.ProductUuid != nil && current.Status == domain.SerialStatusInStock {
+		product, err := su.productRepository.FindProductData(userId, *current.ProductUuid)
+		if err != nil {
+			return nil, err
+		}
+		history.ZoneId = &product.ZoneId
+	}
+
+	for _, movement := range *movements {
+		history.Movements = append(history.Movements, delivery.SerialMovementResponse{
+			ProductUuid:  movement.ProductUuid,
+			Reason:       movement.Reason,
+			ActorUuid:    movement.ActorUuid,
+			SourceZoneId: movement.SourceZoneId,
+			TargetZoneId: movement.TargetZoneId,
+			CreatedAt:    movement.CreatedAt,
+		})
+	}
+
+	return history, nil
+}
+
+func (su *ISerialNumberUsecase) FindAllSerialFromProduct(userId, productId string) ([]delivery.SerialNumberResponse, error) {
+	serials, err := su.serialNumberRepository.FindAllSerialFromProductData(userId, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	serialsRes := []delivery.SerialNumberResponse{}
+	for _, serial := range *serials {
+		serialsRes = append(serialsRes, delivery.SerialNumberResponse{
+			Id:          serial.Id,
+			Serial:      serial.Serial,
+			Status:      serial.Status,
+			ProductUuid: serial.ProductUuid,
+			QrImage:     serial.QrPath,
+			CreatedAt:   serial.CreatedAt,
+		})
+	}
+
+	return serialsRes, nil
+}
+
+// generateSerialQRs выпускает QR-коды номерам склада, у которых их еще нет.
+// QR ведет на историю номера и не зависит от зоны, поэтому при перемещениях не перевыпускается
+func generateSerialQRs(qrGenerator qr.GeneratorQR, cfg config.Config, serialNumberRepository repositories.SerialNumberRepository, warehouseId int) error {
+	serials, err := serialNumberRepository.FindAllSerialWithoutQrData(warehouseId)
+	if err != nil {
+		return err
+	}
+
+	for _, serial := range *serials {
+		qrData := fmt.Sprintf("%s%d/serials/%s", cfg.QR.UrlFrontend, warehouseId, url.PathEscape(serial.Serial))
+
+		pathToFile, err := qrGenerator.Generate(qrData, cfg.QR.PathToFile, fmt.Sprintf("serial_%d.png", serial.Id))
+		if err != nil {
+			return err
+		}
+
+		if err := serialNumberRepository.UpdateSerialQrData(serial.Id, fmt.Sprintf("./%s", pathToFile)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
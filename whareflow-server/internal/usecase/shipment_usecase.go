@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"log/slog"
+)
+
+type ShipmentUsecase interface {
+	CreateShipment(in *delivery.ShipmentModelRequest, userId string, warehouseId int, actorId string) (*delivery.ShipmentModelResponse, error)
+	UpdateShipment(in *delivery.ShipmentModelRequest, userId string, warehouseId int, shipmentId uint64) error
+	GetAllShipments(userId string, warehouseId int) ([]delivery.ShipmentModelResponse, error)
+	GetShipment(userId string, warehouseId int, shipmentId uint64) (*delivery.ShipmentModelResponse, error)
+	PackShipment(in *delivery.PackShipmentModelRequest, userId string, warehouseId int, shipmentId uint64) error
+	ShipShipment(userId string, warehouseId int, shipmentId uint64, actorId string) error
+}
+
+type IShipmentUsecase struct {
+	shipmentRepository    repositories.ShipmentRepository
+	productRepository     repositories.ProductRepository
+	reorderRuleRepository repositories.ReorderRuleRepository
+	notifier              notifier.Notifier
+	logger                slog.Logger
+}
+
+func NewIShipmentUsecase(shipmentRepository repositories.ShipmentRepository, productRepository repositories.ProductRepository, reorderRuleRepository repositories.ReorderRuleRepository, notifier notifier.Notifier, logger slog.Logger) *IShipmentUsecase {
+	return &IShipmentUsecase{
+		shipmentRepository:    shipmentRepository,
+		productRepository:     productRepository,
+		reorderRuleRepository: reorderRuleRepository,
+		notifier:              notifier,
+		logger:                logger,
+	}
+}
+
+func (su *IShipmentUsecase) CreateShipment(in *delivery.ShipmentModelRequest, userId string, warehouseId int, actorId string) (*delivery.ShipmentModelResponse, error) {
+	lines, err := su.buildShipmentLines(in.Lines, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	shipment := &domain.Shipment{
+		WarehouseId: uint64(warehouseId),
+		Status:      domain.ShipmentStatusPicking,
+		Comment:     in.Comment,
+		CreatedBy:   actorId,
+		Lines:       lines,
+	}
+
+	if err := su.shipmentRepository.InsertShipmentData(shipment, userId); err != nil {
+		return nil, err
+	}
+
+	return su.GetShipment(userId, warehouseId, shipment.Id)
+}
+
+func (su *IShipmentUsecase) UpdateShipment(in *delivery.ShipmentModelRequest, userId string, warehouseId int, shipmentId uint64) error {
+	lines, err := su.buildShipmentLines(in.Lines, userId)
+	if err != nil {
+		return err
+	}
+
+	shipment := &domain.Shipment{
+		Id:          shipmentId,
+		WarehouseId: uint64(warehouseId),
+		Comment:     in.Comment,
+		Lines:       lines,
+	}
+
+	return su.shipmentRepository.UpdateShipmentLinesData(shipment, userId)
+}
+
+func (su *IShipmentUsecase) GetAllShipments(userId string, warehouseId int) ([]delivery.ShipmentModelResponse, error) {
+	shipments, err := su.shipmentRepository.FindAllShipmentData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	shipmentsRes := []delivery.ShipmentModelResponse{}
+	for _, shipment := range *shipments {
+		shipmentsRes = append(shipmentsRes, shipmentToResponse(&shipment))
+	}
+
+	return shipmentsRes, nil
+}
+
+func (su *IShipmentUsecase) GetShipment(userId string, warehouseId int, shipmentId uint64) (*delivery.ShipmentModelResponse, error) {
+	shipment, err := su.shipmentRepository.FindShipmentData(userId, warehouseId, shipmentId)
+	if err != nil {
+		return nil, err
+	}
+
+	shipmentRes := shipmentToResponse(shipment)
+
+	return &shipmentRes, nil
+}
+
+// PackShipment переводит собранные количества из единиц строк в доли базовой единицы
+func (su *IShipmentUsecase) PackShipment(in *delivery.PackShipmentModelRequest, userId string, warehouseId int, shipmentId uint64) error {
+	shipment, err := su.shipmentRepository.FindShipmentData(userId, warehouseId, shipmentId)
+	if err != nil {
+		return err
+	}
+
+	factors := make(map[uint64]float64, len(shipment.Lines))
+	for _, line := range shipment.Lines {
+		factors[line.Id] = line.UnitFactor
+	}
+
+	picked := make(map[uint64]uint64, len(in.Lines))
+	serials := make(map[uint64][]string)
+	for _, line := range in.Lines {
+		factor, ok := factors[line.LineId]
+		if !ok {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+
+		quantity, err := toStockQuantity(line.PickedQuantity, factor)
+		if err != nil {
+			return err
+		}
+
+		picked[line.LineId] = quantity
+		if len(line.Serials) > 0 {
+			serials[line.LineId] = line.Serials
+		}
+	}
+
+	return su.shipmentRepository.PackShipmentData(userId, warehouseId, shipmentId, picked, serials)
+}
+
+// ShipShipment после списания проверяет все правила перезаказа склада: отгрузка может задеть любую позицию.
+// Отгрузка к этому моменту уже проведена, поэтому ошибка проверки только пишется в лог
+func (su *IShipmentUsecase) ShipShipment(userId string, warehouseId int, shipmentId uint64, actorId string) error {
+	if err := su.shipmentRepository.ShipShipmentData(userId, warehouseId, shipmentId, actorId); err != nil {
+		return err
+	}
+
+	if err := evaluateReorderRules(su.reorderRuleRepository, su.notifier, warehouseId, nil); err != nil {
+		su.logger.Error(fmt.Sprintf("Shipment %d shipped, but reorder evaluation failed: %v", shipmentId, err))
+	}
+
+	return nil
+}
+
+func (su *IShipmentUsecase) buildShipmentLines(in []delivery.ShipmentLineModelRequest, userId string) ([]domain.ShipmentLine, error) {
+	var lines []domain.ShipmentLine
+	seen := make(map[string]struct{}, len(in))
+
+	for _, lineReq := range in {
+		if lineReq.ProductUuid == "" {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		// Один товар - одна строка, иначе собранное количество нельзя однозначно проверить
+		if _, ok := seen[lineReq.ProductUuid]; ok {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+		seen[lineReq.ProductUuid] = struct{}{}
+
+		product, err := su.productRepository.FindProductData(userId, lineReq.ProductUuid)
+		if err != nil || product.Sku == nil {
+			return nil, custom_errors.ErrProductNotFound
+		}
+
+		unit, factor, err := skuUnitFactor(product.Sku, lineReq.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := toStockQuantity(lineReq.Quantity, factor)
+		if err != nil {
+			return nil, err
+		}
+		if quantity == 0 {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		lines = append(lines, domain.ShipmentLine{
+			ProductUuid: lineReq.ProductUuid,
+			SkuId:       product.SkuId,
+			Quantity:    quantity,
+			Unit:        unit,
+			UnitFactor:  factor,
+		})
+	}
+
+	return lines, nil
+}
+
+func shipmentToResponse(shipment *domain.Shipment) delivery.ShipmentModelResponse {
+	linesRes := []delivery.ShipmentLineModelResponse{}
+	for _, line := range shipment.Lines {
+		linesRes = append(linesRes, delivery.ShipmentLineModelResponse{
+			Id:             line.Id,
+			ProductUuid:    line.ProductUuid,
+			Unit:           line.Unit,
+			Quantity:       fromStockQuantity(line.Quantity, line.UnitFactor),
+			PickedQuantity: fromStockQuantity(line.PickedQuantity, line.UnitFactor),
+		})
+	}
+
+	return delivery.ShipmentModelResponse{
+		Id:          shipment.Id,
+		WarehouseId: shipment.WarehouseId,
+		Status:      shipment.Status,
+		Comment:     shipment.Comment,
+		CreatedBy:   shipment.CreatedBy,
+		CreatedAt:   shipment.CreatedAt,
+		PackedAt:    shipment.PackedAt,
+		ShippedAt:   shipment.ShippedAt,
+		Lines:       linesRes,
+	}
+}
@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// hazardClassPattern - класс опасности ООН с необязательным подклассом: 3, 2.1, 6.2
+var hazardClassPattern = regexp.MustCompile(`^[1-9](\.[1-6])?$`)
+
+// maxSkuDecimals совпадает с ограничением skus.decimals в базе
+const maxSkuDecimals = 6
+
+type SkuUsecase interface {
+	CreateSku(in *delivery.SkuModelRequest, userId string) (*delivery.SkuModelResponse, error)
+	UpdateSku(in *delivery.SkuModelRequest, userId string, skuId uint64) (*delivery.SkuModelResponse, error)
+	GetAllSkus(userId string) ([]delivery.SkuModelResponse, error)
+	GetSku(userId string, skuId uint64) (*delivery.SkuModelResponse, error)
+}
+
+type ISkuUsecase struct {
+	skuRepository repositories.SkuRepository
+}
+
+func NewISkuUsecase(skuRepository repositories.SkuRepository) *ISkuUsecase {
+	return &ISkuUsecase{
+		skuRepository: skuRepository,
+	}
+}
+
+func (su *ISkuUsecase) CreateSku(in *delivery.SkuModelRequest, userId string) (*delivery.SkuModelResponse, error) {
+	sku, err := buildSku(in)
+	if err != nil {
+		return nil, err
+	}
+	sku.UuidUser = userId
+
+	if err := su.skuRepository.InsertSkuData(sku); err != nil {
+		return nil, err
+	}
+
+	return su.GetSku(userId, sku.Id)
+}
+
+func (su *ISkuUsecase) UpdateSku(in *delivery.SkuModelRequest, userId string, skuId uint64) (*delivery.SkuModelResponse, error) {
+	sku, err := buildSku(in)
+	if err != nil {
+		return nil, err
+	}
+	sku.Id = skuId
+
+	if err := su.skuRepository.UpdateSkuData(sku, userId); err != nil {
+		return nil, err
+	}
+
+	return su.GetSku(userId, skuId)
+}
+
+func (su *ISkuUsecase) GetAllSkus(userId string) ([]delivery.SkuModelResponse, error) {
+	skus, err := su.skuRepository.FindAllSkuData(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	skusRes := []delivery.SkuModelResponse{}
+	for _, sku := range *skus {
+		skusRes = append(skusRes, skuToResponse(&sku))
+	}
+
+	return skusRes, nil
+}
+
+func (su *ISkuUsecase) GetSku(userId string, skuId uint64) (*delivery.SkuModelResponse, error) {
+	sku, err := su.skuRepository.FindSkuData(userId, skuId)
+	if err != nil {
+		return nil, err
+	}
+
+	skuRes := skuToResponse(sku)
+
+	return &skuRes, nil
+}
+
+// buildSku проверяет обязательные поля карточки. Пустой штрихкод хранится как NULL, чтобы не мешать уникальности.
+// Серийный товар учитывается только целыми базовыми единицами
+func buildSku(in *delivery.SkuModelRequest) (*domain.Sku, error) {
+	code := strings.TrimSpace(in.Code)
+	name := strings.TrimSpace(in.Name)
+	if code == "" || name == "" {
+		return nil, custom_errors.ErrInvalidSku
+	}
+
+	unit := strings.TrimSpace(in.Unit)
+	if unit == "" {
+		unit = "pcs"
+	}
+
+	if in.Decimals > maxSkuDecimals || (in.SerialTracked && in.Decimals > 0) {
+		return nil, custom_errors.ErrInvalidSku
+	}
+
+	if in.LengthCm < 0 || in.WidthCm < 0 || in.HeightCm < 0 || in.WeightKg < 0 {
+		return nil, custom_errors.ErrInvalidSku
+	}
+
+	temperatureClass := strings.TrimSpace(in.TemperatureClass)
+	if temperatureClass == "" {
+		temperatureClass = domain.TemperatureClassAmbient
+	}
+	if !validTemperatureClass(temperatureClass) {
+		return nil, custom_errors.ErrInvalidSku
+	}
+
+	hazardClass := strings.TrimSpace(in.HazardClass)
+	if hazardClass != "" && !hazardClassPattern.MatchString(hazardClass) {
+		return nil, custom_errors.ErrInvalidSku
+	}
+
+	units := []domain.SkuUnit{}
+	seen := map[string]struct{}{unit: {}}
+	for _, unitReq := range in.Units {
+		unitCode := strings.TrimSpace(unitReq.Code)
+		if unitCode == "" || unitReq.Factor <= 0 || math.IsInf(unitReq.Factor, 0) {
+			return nil, custom_errors.ErrInvalidSku
+		}
+		if _, ok := seen[unitCode]; ok {
+			return nil, custom_errors.ErrInvalidSku
+		}
+		seen[unitCode] = struct{}{}
+
+		units = append(units, domain.SkuUnit{
+			Code:   unitCode,
+			Name:   unitReq.Name,
+			Factor: unitReq.Factor,
+		})
+	}
+
+	sku := &domain.Sku{
+		Code:             code,
+		Name:             name,
+		Description:      in.Description,
+		Unit:             unit,
+		Attributes:       in.Attributes,
+		SerialTracked:    in.SerialTracked,
+		Decimals:         in.Decimals,
+		Units:            units,
+		LengthCm:         in.LengthCm,
+		WidthCm:          in.WidthCm,
+		HeightCm:         in.HeightCm,
+		WeightKg:         in.WeightKg,
+		UnitsPerPallet:   in.UnitsPerPallet,
+		TemperatureClass: temperatureClass,
+		HazardClass:      hazardClass,
+	}
+
+	if barcode := strings.TrimSpace(in.Barcode); barcode != "" {
+		sku.Barcode = &barcode
+	}
+
+	return sku, nil
+}
+
+func skuToResponse(sku *domain.Sku) delivery.SkuModelResponse {
+	attributes := sku.Attributes
+	if attributes == nil {
+		attributes = domain.SkuAttributes{}
+	}
+
+	units := []delivery.SkuUnitModel{}
+	for _, unit := range sku.Units {
+		units = append(units, delivery.SkuUnitModel{
+			Code:   unit.Code,
+			Name:   unit.Name,
+			Factor: unit.Factor,
+		})
+	}
+
+	return delivery.SkuModelResponse{
+		Id:               sku.Id,
+		Code:             sku.Code,
+		Name:             sku.Name,
+		Description:      sku.Description,
+		Unit:             sku.Unit,
+		Barcode:          sku.Barcode,
+		Attributes:       attributes,
+		SerialTracked:    sku.SerialTracked,
+		Decimals:         sku.Decimals,
+		Units:            units,
+		LengthCm:         sku.LengthCm,
+		WidthCm:          sku.WidthCm,
+		HeightCm:         sku.HeightCm,
+		WeightKg:         sku.WeightKg,
+		UnitsPerPallet:   sku.UnitsPerPallet,
+		TemperatureClass: sku.TemperatureClass,
+		HazardClass:      sku.HazardClass,
+		CreatedAt:        sku.CreatedAt,
+	}
+}
+
+// skuUnitFactor возвращает код единицы и число хранимых долей базовой единицы в одной единице unit.
+// Пустая единица означает базовую
+func skuUnitFactor(sku *domain.Sku, unit string) (string, float64, error) {
+	scale := math.Pow10(int(sku.Decimals))
+	if unit == "" || unit == sku.Unit {
+		return sku.Unit, scale, nil
+	}
+
+	for _, skuUnit := range sku.Units {
+		if skuUnit.Code == unit {
+			return skuUnit.Code, skuUnit.Factor * scale, nil
+		}
+	}
+
+	return "", 0, custom_errors.ErrUnitNotFound
+}
+
+// toStockQuantity переводит количество в единице с коэффициентом factor в хранимые доли базовой единицы.
+// Количество, которое не выражается целым числом долей, не принимается, чтобы остаток не округлялся молча
+func toStockQuantity(quantity float64, factor float64) (uint64, error) {
+	if quantity < 0 || math.IsNaN(quantity) || math.IsInf(quantity, 0) {
+		return 0, custom_errors.ErrInvalidDocumentLine
+	}
+
+	value := quantity * factor
+	rounded := math.Round(value)
+	if math.Abs(value-rounded) > 1e-6 {
+		return 0, custom_errors.ErrFractionalQuantity
+	}
+
+	return uint64(rounded), nil
+}
+
+// fromStockQuantity переводит хранимое количество обратно в единицу с коэффициентом factor
+func fromStockQuantity(count uint64, factor float64) float64 {
+	if factor <= 0 {
+		return float64(count)
+	}
+
+	return math.Round(float64(count)/factor*1e6) / 1e6
+}
+
+// quantityToStock переводит количество в единице unit позиции sku в хранимые доли базовой единицы.
+// Неизвестная единица - ошибка, а не молчаливый пересчет в базовую
+func quantityToStock(sku *domain.Sku, quantity float64, unit string) (uint64, error) {
+	_, factor, err := skuUnitFactor(sku, unit)
+	if err != nil {
+		return 0, err
+	}
+
+	return toStockQuantity(quantity, factor)
+}
+
+// stockToQuantity переводит хранимое количество в единицу unit позиции sku и возвращает код этой единицы
+func stockToQuantity(sku *domain.Sku, count uint64, unit string) (string, float64, error) {
+	quantityUnit, factor, err := skuUnitFactor(sku, unit)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return quantityUnit, fromStockQuantity(count, factor), nil
+}
+
+// stockDeltaToQuantity - stockToQuantity для изменения остатка со знаком
+func stockDeltaToQuantity(sku *domain.Sku, delta int64, unit string) (string, float64, error) {
+	quantityUnit, quantity, err := stockToQuantity(sku, uint64(max(delta, -delta)), unit)
+	if err != nil {
+		return "", 0, err
+	}
+	if delta < 0 {
+		quantity = -quantity
+	}
+
+	return quantityUnit, quantity, nil
+}
+
+// baseQuantity переводит хранимое количество в базовую единицу позиции, для нее пересчет не может не сойтись
+func baseQuantity(sku *domain.Sku, count uint64) float64 {
+	_, quantity, _ := stockToQuantity(sku, count, "")
+
+	return quantity
+}
+
+// productSku достает позицию каталога строки товара из результата FindProductSkusData
+func productSku(skus map[string]*domain.Sku, productId string) (*domain.Sku, error) {
+	sku, ok := skus[productId]
+	if !ok {
+		return nil, custom_errors.ErrProductNotFound
+	}
+
+	return sku, nil
+}
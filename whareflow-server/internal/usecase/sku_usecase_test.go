@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"math"
+	"testing"
+)
+
+// weighedSku - позиция в килограммах с точностью до грамма, коробкой по 12.5 кг и паллетой по 500 кг
+func weighedSku() *domain.Sku {
+	return &domain.Sku{
+		Unit:     "kg",
+		Decimals: 3,
+		Units: []domain.SkuUnit{
+			{Code: "box", Factor: 12.5},
+			{Code: "pallet", Factor: 500},
+		},
+	}
+}
+
+func TestSkuUnitFactor(t *testing.T) {
+	tests := []struct {
+		name     string
+		sku      *domain.Sku
+		unit     string
+		wantUnit string
+		want     float64
+		wantErr  error
+	}{
+		{name: "empty unit is base", sku: weighedSku(), unit: "", wantUnit: "kg", want: 1000},
+		{name: "base unit by code", sku: weighedSku(), unit: "kg", wantUnit: "kg", want: 1000},
+		{name: "extra unit", sku: weighedSku(), unit: "box", wantUnit: "box", want: 12500},
+		{name: "whole pieces", sku: &domain.Sku{Unit: "pcs"}, unit: "", wantUnit: "pcs", want: 1},
+		{name: "unknown unit", sku: weighedSku(), unit: "crate", wantErr: custom_errors.ErrUnitNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit, factor, err := skuUnitFactor(tt.sku, tt.unit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("skuUnitFactor() error = %v, want %v", err, tt.wantErr)
+			}
+			if unit != tt.wantUnit || factor != tt.want {
+				t.Errorf("skuUnitFactor() = (%q, %v), want (%q, %v)", unit, factor, tt.wantUnit, tt.want)
+			}
+		})
+	}
+}
+
+func TestToStockQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		factor   float64
+		want     uint64
+		wantErr  error
+	}{
+		{name: "whole base units", quantity: 7, factor: 1, want: 7},
+		{name: "grams of kilograms", quantity: 1.25, factor: 1000, want: 1250},
+		{name: "float noise is rounded", quantity: 0.1 + 0.2, factor: 10, want: 3},
+		{name: "boxes of fractional factor", quantity: 3, factor: 12500, want: 37500},
+		{name: "zero", quantity: 0, factor: 1000, want: 0},
+		{name: "finer than precision", quantity: 1.2345, factor: 1000, wantErr: custom_errors.ErrFractionalQuantity},
+		{name: "half piece", quantity: 0.5, factor: 1, wantErr: custom_errors.ErrFractionalQuantity},
+		{name: "negative", quantity: -1, factor: 1, wantErr: custom_errors.ErrInvalidDocumentLine},
+		{name: "not a number", quantity: math.NaN(), factor: 1, wantErr: custom_errors.ErrInvalidDocumentLine},
+		{name: "infinity", quantity: math.Inf(1), factor: 1, wantErr: custom_errors.ErrInvalidDocumentLine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toStockQuantity(tt.quantity, tt.factor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("toStockQuantity() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("toStockQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromStockQuantity(t *testing.T) {
+	tests := []struct {
+		name   string
+		count  uint64
+		factor float64
+		want   float64
+	}{
+		{name: "whole base units", count: 7, factor: 1, want: 7},
+		{name: "grams to kilograms", count: 1250, factor: 1000, want: 1.25},
+		{name: "partial box", count: 6250, factor: 12500, want: 0.5},
+		{name: "repeating fraction is rounded", count: 1, factor: 3, want: 0.333333},
+		{name: "missing factor keeps count", count: 5, factor: 0, want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fromStockQuantity(tt.count, tt.factor); got != tt.want {
+				t.Errorf("fromStockQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuantityRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		unit     string
+		want     uint64
+		wantErr  error
+	}{
+		{name: "base unit", quantity: 2.5, unit: "", want: 2500},
+		{name: "boxes", quantity: 2, unit: "box", want: 25000},
+		{name: "pallet", quantity: 0.5, unit: "pallet", want: 250000},
+		{name: "unknown unit is an error", quantity: 1, unit: "crate", wantErr: custom_errors.ErrUnitNotFound},
+		{name: "finer than a gram", quantity: 0.0001, unit: "kg", wantErr: custom_errors.ErrFractionalQuantity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sku := weighedSku()
+
+			count, err := quantityToStock(sku, tt.quantity, tt.unit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("quantityToStock() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if count != tt.want {
+				t.Fatalf("quantityToStock() = %v, want %v", count, tt.want)
+			}
+
+			_, quantity, err := stockToQuantity(sku, count, tt.unit)
+			if err != nil {
+				t.Fatalf("stockToQuantity() error = %v", err)
+			}
+			if quantity != tt.quantity {
+				t.Errorf("stockToQuantity() = %v, want %v", quantity, tt.quantity)
+			}
+		})
+	}
+}
+
+func TestStockDeltaToQuantity(t *testing.T) {
+	tests := []struct {
+		name  string
+		delta int64
+		unit  string
+		want  float64
+	}{
+		{name: "incoming", delta: 2500, unit: "", want: 2.5},
+		{name: "outgoing", delta: -2500, unit: "", want: -2.5},
+		{name: "outgoing boxes", delta: -25000, unit: "box", want: -2},
+		{name: "zero", delta: 0, unit: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := stockDeltaToQuantity(weighedSku(), tt.delta, tt.unit)
+			if err != nil {
+				t.Fatalf("stockDeltaToQuantity() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("stockDeltaToQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
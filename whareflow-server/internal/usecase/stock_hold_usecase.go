@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+)
+
+type StockHoldUsecase interface {
+	CreateStockHold(in *delivery.StockHoldModelRequest, userId string, warehouseId int, actorId string) (*delivery.StockHoldModelResponse, error)
+	ChangeStockHoldStatus(in *delivery.StockHoldStatusModelRequest, userId string, warehouseId int, holdId uint64, actorId string) (*delivery.StockHoldModelResponse, error)
+	ReleaseStockHold(in *delivery.ReleaseStockHoldModelRequest, userId string, warehouseId int, holdId uint64, actorId string) (*delivery.StockHoldModelResponse, error)
+	GetAllStockHolds(userId string, warehouseId int, productId string) ([]delivery.StockHoldModelResponse, error)
+	GetStockStatusHistory(userId string, warehouseId int, productId string) ([]delivery.StockStatusChangeModelResponse, error)
+}
+
+type IStockHoldUsecase struct {
+	stockHoldRepository repositories.StockHoldRepository
+	skuRepository       repositories.SkuRepository
+}
+
+func NewIStockHoldUsecase(stockHoldRepository repositories.StockHoldRepository, skuRepository repositories.SkuRepository) *IStockHoldUsecase {
+	return &IStockHoldUsecase{
+		stockHoldRepository: stockHoldRepository,
+		skuRepository:       skuRepository,
+	}
+}
+
+func (hu *IStockHoldUsecase) CreateStockHold(in *delivery.StockHoldModelRequest, userId string, warehouseId int, actorId string) (*delivery.StockHoldModelResponse, error) {
+	if in.ProductUuid == "" || in.Quantity <= 0 || !holdStatus(in.Status) {
+		return nil, custom_errors.ErrInvalidStockHold
+	}
+
+	sku, err := hu.productSku(userId, in.ProductUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, err := quantityToStock(sku, in.Quantity, in.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	hold := &domain.StockHold{
+		ProductUuid: in.ProductUuid,
+		Quantity:    quantity,
+		Status:      in.Status,
+		Reason:      in.Reason,
+		CreatedBy:   actorId,
+	}
+
+	if err := hu.stockHoldRepository.InsertStockHoldData(hold, userId, warehouseId); err != nil {
+		return nil, err
+	}
+
+	holdRes := stockHoldToResponse(hold, sku)
+
+	return &holdRes, nil
+}
+
+func (hu *IStockHoldUsecase) ChangeStockHoldStatus(in *delivery.StockHoldStatusModelRequest, userId string, warehouseId int, holdId uint64, actorId string) (*delivery.StockHoldModelResponse, error) {
+	if !holdStatus(in.Status) {
+		return nil, custom_errors.ErrInvalidStockHold
+	}
+
+	hold, err := hu.stockHoldRepository.ChangeStockHoldStatusData(userId, warehouseId, holdId, in.Status, in.Reason, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	sku, err := hu.productSku(userId, hold.ProductUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	holdRes := stockHoldToResponse(hold, sku)
+
+	return &holdRes, nil
+}
+
+func (hu *IStockHoldUsecase) ReleaseStockHold(in *delivery.ReleaseStockHoldModelRequest, userId string, warehouseId int, holdId uint64, actorId string) (*delivery.StockHoldModelResponse, error) {
+	hold, err := hu.stockHoldRepository.FindStockHoldData(userId, warehouseId, holdId)
+	if err != nil {
+		return nil, err
+	}
+
+	sku, err := hu.productSku(userId, hold.ProductUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, err := quantityToStock(sku, in.Quantity, in.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err = hu.stockHoldRepository.ReleaseStockHoldData(userId, warehouseId, holdId, quantity, in.Reason, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	holdRes := stockHoldToResponse(hold, sku)
+
+	return &holdRes, nil
+}
+
+func (hu *IStockHoldUsecase) GetAllStockHolds(userId string, warehouseId int, productId string) ([]delivery.StockHoldModelResponse, error) {
+	holds, err := hu.stockHoldRepository.FindAllStockHoldData(userId, warehouseId, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	productIds := make([]string, 0, len(*holds))
+	for _, hold := range *holds {
+		productIds = append(productIds, hold.ProductUuid)
+	}
+
+	skus, err := hu.skuRepository.FindProductSkusData(userId, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	holdsRes := []delivery.StockHoldModelResponse{}
+	for _, hold := range *holds {
+		sku, err := productSku(skus, hold.ProductUuid)
+		if err != nil {
+			return nil, err
+		}
+		holdsRes = append(holdsRes, stockHoldToResponse(&hold, sku))
+	}
+
+	return holdsRes, nil
+}
+
+func (hu *IStockHoldUsecase) GetStockStatusHistory(userId string, warehouseId int, productId string) ([]delivery.StockStatusChangeModelResponse, error) {
+	changes, err := hu.stockHoldRepository.FindStockStatusHistoryData(userId, warehouseId, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	productIds := make([]string, 0, len(*changes))
+	for _, change := range *changes {
+		productIds = append(productIds, change.ProductUuid)
+	}
+
+	skus, err := hu.skuRepository.FindProductSkusData(userId, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	changesRes := []delivery.StockStatusChangeModelResponse{}
+	for _, change := range *changes {
+		sku, err := productSku(skus, change.ProductUuid)
+		if err != nil {
+			return nil, err
+		}
+
+		changesRes = append(changesRes, delivery.StockStatusChangeModelResponse{
+			Id:          change.Id,
+			HoldId:      change.HoldId,
+			ProductUuid: change.ProductUuid,
+			FromStatus:  change.FromStatus,
+			ToStatus:    change.ToStatus,
+			Unit:        sku.Unit,
+			Quantity:    baseQuantity(sku, change.Quantity),
+			Reason:      change.Reason,
+			ActorUuid:   change.ActorUuid,
+			CreatedAt:   change.CreatedAt,
+		})
+	}
+
+	return changesRes, nil
+}
+
+// productSku возвращает позицию каталога строки товара, по которой пересчитываются количества блокировки
+func (hu *IStockHoldUsecase) productSku(userId, productId string) (*domain.Sku, error) {
+	skus, err := hu.skuRepository.FindProductSkusData(userId, []string{productId})
+	if err != nil {
+		return nil, err
+	}
+
+	return productSku(skus, productId)
+}
+
+// holdStatus сообщает, является ли статус статусом блокировки. available и released задаются только снятием блокировки
+func holdStatus(status string) bool {
+	switch status {
+	case domain.StockStatusQuarantine, domain.StockStatusDamaged, domain.StockStatusBlocked:
+		return true
+	}
+
+	return false
+}
+
+func stockHoldToResponse(hold *domain.StockHold, sku *domain.Sku) delivery.StockHoldModelResponse {
+	return delivery.StockHoldModelResponse{
+		Id:          hold.Id,
+		ProductUuid: hold.ProductUuid,
+		Unit:        sku.Unit,
+		Quantity:    baseQuantity(sku, hold.Quantity),
+		Status:      hold.Status,
+		Reason:      hold.Reason,
+		CreatedBy:   hold.CreatedBy,
+		CreatedAt:   hold.CreatedAt,
+		ReleasedBy:  hold.ReleasedBy,
+		ReleasedAt:  hold.ReleasedAt,
+	}
+}
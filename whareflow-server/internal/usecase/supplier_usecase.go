@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"strings"
+)
+
+type SupplierUsecase interface {
+	CreateSupplier(in *delivery.SupplierModelRequest, userId string) (*delivery.SupplierModelResponse, error)
+	UpdateSupplier(in *delivery.SupplierModelRequest, userId string, supplierId uint64) (*delivery.SupplierModelResponse, error)
+	GetAllSuppliers(userId string) ([]delivery.SupplierModelResponse, error)
+	GetSupplier(userId string, supplierId uint64) (*delivery.SupplierModelResponse, error)
+}
+
+type ISupplierUsecase struct {
+	supplierRepository repositories.SupplierRepository
+}
+
+func NewISupplierUsecase(supplierRepository repositories.SupplierRepository) *ISupplierUsecase {
+	return &ISupplierUsecase{
+		supplierRepository: supplierRepository,
+	}
+}
+
+func (su *ISupplierUsecase) CreateSupplier(in *delivery.SupplierModelRequest, userId string) (*delivery.SupplierModelResponse, error) {
+	supplier, err := buildSupplier(in)
+	if err != nil {
+		return nil, err
+	}
+	supplier.UuidUser = userId
+
+	if err := su.supplierRepository.InsertSupplierData(supplier); err != nil {
+		return nil, err
+	}
+
+	return su.GetSupplier(userId, supplier.Id)
+}
+
+func (su *ISupplierUsecase) UpdateSupplier(in *delivery.SupplierModelRequest, userId string, supplierId uint64) (*delivery.SupplierModelResponse, error) {
+	supplier, err := buildSupplier(in)
+	if err != nil {
+		return nil, err
+	}
+	supplier.Id = supplierId
+
+	if err := su.supplierRepository.UpdateSupplierData(supplier, userId); err != nil {
+		return nil, err
+	}
+
+	return su.GetSupplier(userId, supplierId)
+}
+
+func (su *ISupplierUsecase) GetAllSuppliers(userId string) ([]delivery.SupplierModelResponse, error) {
+	suppliers, err := su.supplierRepository.FindAllSupplierData(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	suppliersRes := []delivery.SupplierModelResponse{}
+	for _, supplier := range *suppliers {
+		suppliersRes = append(suppliersRes, supplierToResponse(&supplier))
+	}
+
+	return suppliersRes, nil
+}
+
+func (su *ISupplierUsecase) GetSupplier(userId string, supplierId uint64) (*delivery.SupplierModelResponse, error) {
+	supplier, err := su.supplierRepository.FindSupplierData(userId, supplierId)
+	if err != nil {
+		return nil, err
+	}
+
+	supplierRes := supplierToResponse(supplier)
+
+	return &supplierRes, nil
+}
+
+// buildSupplier проверяет карточку поставщика, обязательно только название
+func buildSupplier(in *delivery.SupplierModelRequest) (*domain.Supplier, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, custom_errors.ErrInvalidSupplier
+	}
+
+	return &domain.Supplier{
+		Name:        name,
+		TaxId:       strings.TrimSpace(in.TaxId),
+		ContactName: strings.TrimSpace(in.ContactName),
+		Email:       strings.TrimSpace(in.Email),
+		Phone:       strings.TrimSpace(in.Phone),
+		Address:     strings.TrimSpace(in.Address),
+	}, nil
+}
+
+func supplierToResponse(supplier *domain.Supplier) delivery.SupplierModelResponse {
+	return delivery.SupplierModelResponse{
+		Id:          supplier.Id,
+		Name:        supplier.Name,
+		TaxId:       supplier.TaxId,
+		ContactName: supplier.ContactName,
+		Email:       supplier.Email,
+		Phone:       supplier.Phone,
+		Address:     supplier.Address,
+		CreatedAt:   supplier.CreatedAt,
+	}
+}
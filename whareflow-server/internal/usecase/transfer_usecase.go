@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/Miroslovelife/whareflow/internal/config"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"github.com/Miroslovelife/whareflow/pkg/qr"
+	"log/slog"
+)
+
+type TransferUsecase interface {
+	CreateTransfer(in *delivery.TransferModelRequest, userId string, warehouseId int, actorId string) (*delivery.TransferModelResponse, error)
+	UpdateTransfer(in *delivery.TransferModelRequest, userId string, warehouseId int, transferId uint64) error
+	GetAllTransfers(userId string, warehouseId int) ([]delivery.TransferModelResponse, error)
+	GetTransfer(userId string, warehouseId int, transferId uint64) (*delivery.TransferModelResponse, error)
+	ShipTransfer(userId string, warehouseId int, transferId uint64, actorId string) error
+	ReceiveTransfer(in *delivery.ReceiveTransferModelRequest, userId string, warehouseId int, transferId uint64, actorId string) error
+	GetTransferDiscrepancy(userId string, warehouseId int, transferId uint64) (*delivery.TransferDiscrepancyResponse, error)
+}
+
+type ITransferUsecase struct {
+	transferRepository    repositories.TransferRepository
+	productRepository     repositories.ProductRepository
+	skuRepository         repositories.SkuRepository
+	reorderRuleRepository repositories.ReorderRuleRepository
+	notifier              notifier.Notifier
+	qrGenerator           qr.GeneratorQR
+	cfg                   config.Config
+	logger                slog.Logger
+}
+
+func NewITransferUsecase(transferRepository repositories.TransferRepository, productRepository repositories.ProductRepository, skuRepository repositories.SkuRepository, reorderRuleRepository repositories.ReorderRuleRepository, notifier notifier.Notifier, qrGenerator qr.GeneratorQR, cfg config.Config, logger slog.Logger) *ITransferUsecase {
+	return &ITransferUsecase{
+		transferRepository:    transferRepository,
+		productRepository:     productRepository,
+		skuRepository:         skuRepository,
+		reorderRuleRepository: reorderRuleRepository,
+		notifier:              notifier,
+		qrGenerator:           qrGenerator,
+		cfg:                   cfg,
+		logger:                logger,
+	}
+}
+
+func (tu *ITransferUsecase) CreateTransfer(in *delivery.TransferModelRequest, userId string, warehouseId int, actorId string) (*delivery.TransferModelResponse, error) {
+	lines, err := tu.buildTransferLines(in.Lines, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := &domain.Transfer{
+		SourceWarehouseId: uint64(warehouseId),
+		TargetWarehouseId: in.TargetWarehouseId,
+		Status:            domain.TransferStatusDraft,
+		Comment:           in.Comment,
+		CreatedBy:         actorId,
+		Lines:             lines,
+	}
+
+	if err := tu.transferRepository.InsertTransferData(transfer, userId); err != nil {
+		return nil, err
+	}
+
+	return tu.GetTransfer(userId, warehouseId, transfer.Id)
+}
+
+// UpdateTransfer заменяет строки черновика. Склад-получатель после создания не меняется
+func (tu *ITransferUsecase) UpdateTransfer(in *delivery.TransferModelRequest, userId string, warehouseId int, transferId uint64) error {
+	lines, err := tu.buildTransferLines(in.Lines, userId)
+	if err != nil {
+		return err
+	}
+
+	transfer := &domain.Transfer{
+		Id:                transferId,
+		SourceWarehouseId: uint64(warehouseId),
+		Comment:           in.Comment,
+		Lines:             lines,
+	}
+
+	return tu.transferRepository.UpdateTransferLinesData(transfer, userId)
+}
+
+func (tu *ITransferUsecase) GetAllTransfers(userId string, warehouseId int) ([]delivery.TransferModelResponse, error) {
+	transfers, err := tu.transferRepository.FindAllTransferData(userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	transfersRes := []delivery.TransferModelResponse{}
+	for _, transfer := range *transfers {
+		transferRes, err := tu.transferToResponse(&transfer, userId)
+		if err != nil {
+			return nil, err
+		}
+		transfersRes = append(transfersRes, transferRes)
+	}
+
+	return transfersRes, nil
+}
+
+func (tu *ITransferUsecase) GetTransfer(userId string, warehouseId int, transferId uint64) (*delivery.TransferModelResponse, error) {
+	transfer, err := tu.transferRepository.FindTransferData(userId, warehouseId, transferId)
+	if err != nil {
+		return nil, err
+	}
+
+	transferRes, err := tu.transferToResponse(transfer, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transferRes, nil
+}
+
+// ShipTransfer после списания со склада-отправителя проверяет его правила перезаказа.
+// Перемещение к этому моменту уже отгружено, поэтому ошибка проверки только пишется в лог
+func (tu *ITransferUsecase) ShipTransfer(userId string, warehouseId int, transferId uint64, actorId string) error {
+	if err := tu.transferRepository.ShipTransferData(userId, warehouseId, transferId, actorId); err != nil {
+		return err
+	}
+
+	if err := evaluateReorderRules(tu.reorderRuleRepository, tu.notifier, warehouseId, nil); err != nil {
+		tu.logger.Error(fmt.Sprintf("Transfer %d shipped, but reorder evaluation failed: %v", transferId, err))
+	}
+
+	return nil
+}
+
+// ReceiveTransfer переводит принятые количества в доли базовых единиц позиций строк перемещения
+func (tu *ITransferUsecase) ReceiveTransfer(in *delivery.ReceiveTransferModelRequest, userId string, warehouseId int, transferId uint64, actorId string) error {
+	transfer, err := tu.transferRepository.FindTransferData(userId, warehouseId, transferId)
+	if err != nil {
+		return err
+	}
+
+	lineSkus, err := tu.transferSkus(transfer, userId)
+	if err != nil {
+		return err
+	}
+
+	received := make(map[uint64]domain.TransferLine, len(in.Lines))
+	for _, line := range in.Lines {
+		sku, ok := lineSkus[line.LineId]
+		if !ok {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+
+		quantity, err := quantityToStock(sku, line.ReceivedQuantity, line.Unit)
+		if err != nil {
+			return err
+		}
+
+		zoneId := line.ZoneId
+		received[line.LineId] = domain.TransferLine{
+			Id:               line.LineId,
+			ReceivedQuantity: quantity,
+			TargetZoneId:     &zoneId,
+		}
+	}
+
+	createdProducts, err := tu.transferRepository.ReceiveTransferData(userId, warehouseId, transferId, received, in.ZoneId, actorId)
+	if err != nil {
+		return err
+	}
+
+	// Перемещение к этому моменту уже принято, поэтому ошибка выдачи QR только пишется в лог
+	for _, product := range *createdProducts {
+		qrPath, err := generateProductQR(tu.qrGenerator, tu.cfg, warehouseId, product.ZoneId, string(product.Uuid))
+		if err != nil {
+			tu.logger.Error(fmt.Sprintf("Transfer %d received, but qr generation for product %s failed: %v", transferId, product.Uuid, err))
+			continue
+		}
+
+		if err := tu.productRepository.UpdateProductQrData(string(product.Uuid), qrPath); err != nil {
+			tu.logger.Error(fmt.Sprintf("Transfer %d received, but qr generation for product %s failed: %v", transferId, product.Uuid, err))
+		}
+	}
+
+	if err := evaluateReorderRules(tu.reorderRuleRepository, tu.notifier, warehouseId, nil); err != nil {
+		tu.logger.Error(fmt.Sprintf("Transfer %d received, but reorder evaluation failed: %v", transferId, err))
+	}
+
+	return nil
+}
+
+// GetTransferDiscrepancy сравнивает отгруженное и принятое количество. Строки без расхождений в отчет не попадают
+func (tu *ITransferUsecase) GetTransferDiscrepancy(userId string, warehouseId int, transferId uint64) (*delivery.TransferDiscrepancyResponse, error) {
+	transfer, err := tu.transferRepository.FindTransferData(userId, warehouseId, transferId)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Status != domain.TransferStatusReceived {
+		return nil, custom_errors.ErrInvalidDocumentStatus
+	}
+
+	skus, err := tu.transferSkus(transfer, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &delivery.TransferDiscrepancyResponse{
+		TransferId: transfer.Id,
+		Status:     transfer.Status,
+		Lines:      []delivery.TransferDiscrepancyLineResponse{},
+	}
+
+	for _, line := range transfer.Lines {
+		sku := skus[line.Id]
+		shipped := baseQuantity(sku, line.Quantity)
+		received := baseQuantity(sku, line.ReceivedQuantity)
+		report.TotalShipped += shipped
+		report.TotalReceived += received
+
+		if line.ReceivedQuantity == line.Quantity {
+			continue
+		}
+
+		_, difference, err := stockDeltaToQuantity(sku, int64(line.ReceivedQuantity)-int64(line.Quantity), "")
+		if err != nil {
+			return nil, err
+		}
+
+		report.Lines = append(report.Lines, delivery.TransferDiscrepancyLineResponse{
+			LineId:      line.Id,
+			ProductUuid: line.ProductUuid,
+			Title:       line.Title,
+			Unit:        sku.Unit,
+			Shipped:     shipped,
+			Received:    received,
+			Difference:  difference,
+		})
+	}
+
+	report.HasDiscrepancy = len(report.Lines) > 0
+
+	return report, nil
+}
+
+// buildTransferLines переводит количества строк в доли базовых единиц позиций перемещаемых товаров
+func (tu *ITransferUsecase) buildTransferLines(in []delivery.TransferLineModelRequest, userId string) ([]domain.TransferLine, error) {
+	productIds := make([]string, 0, len(in))
+	for _, lineReq := range in {
+		productIds = append(productIds, lineReq.ProductUuid)
+	}
+
+	skus, err := tu.skuRepository.FindProductSkusData(userId, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []domain.TransferLine
+	seen := make(map[string]struct{}, len(in))
+
+	for _, lineReq := range in {
+		if lineReq.ProductUuid == "" || lineReq.Quantity <= 0 {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+
+		if _, ok := seen[lineReq.ProductUuid]; ok {
+			return nil, custom_errors.ErrInvalidDocumentLine
+		}
+		seen[lineReq.ProductUuid] = struct{}{}
+
+		sku, err := productSku(skus, lineReq.ProductUuid)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := quantityToStock(sku, lineReq.Quantity, lineReq.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		productUuid := lineReq.ProductUuid
+		lines = append(lines, domain.TransferLine{
+			ProductUuid: &productUuid,
+			SkuId:       &sku.Id,
+			Quantity:    quantity,
+		})
+	}
+
+	return lines, nil
+}
+
+// transferSkus возвращает позиции каталога по id строк перемещения. Позиция хранится в самой строке,
+// поэтому находится и после удаления исходной строки товара. Строка без позиции берет ее у товара
+func (tu *ITransferUsecase) transferSkus(transfer *domain.Transfer, userId string) (map[uint64]*domain.Sku, error) {
+	var skuIds []uint64
+	var productIds []string
+	for _, line := range transfer.Lines {
+		if line.SkuId != nil {
+			skuIds = append(skuIds, *line.SkuId)
+		} else if line.ProductUuid != nil {
+			productIds = append(productIds, *line.ProductUuid)
+		}
+	}
+
+	skus, err := tu.skuRepository.FindSkusData(userId, skuIds)
+	if err != nil {
+		return nil, err
+	}
+
+	productSkus, err := tu.skuRepository.FindProductSkusData(userId, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	lineSkus := make(map[uint64]*domain.Sku, len(transfer.Lines))
+	for _, line := range transfer.Lines {
+		var sku *domain.Sku
+		switch {
+		case line.SkuId != nil:
+			sku = skus[*line.SkuId]
+		case line.ProductUuid != nil:
+			sku = productSkus[*line.ProductUuid]
+		}
+		if sku == nil {
+			return nil, custom_errors.ErrSkuNotFound
+		}
+		lineSkus[line.Id] = sku
+	}
+
+	return lineSkus, nil
+}
+
+func (tu *ITransferUsecase) transferToResponse(transfer *domain.Transfer, userId string) (delivery.TransferModelResponse, error) {
+	skus, err := tu.transferSkus(transfer, userId)
+	if err != nil {
+		return delivery.TransferModelResponse{}, err
+	}
+
+	var inTransit float64
+	linesRes := []delivery.TransferLineModelResponse{}
+	for _, line := range transfer.Lines {
+		sku := skus[line.Id]
+		quantity := baseQuantity(sku, line.Quantity)
+		if transfer.Status == domain.TransferStatusInTransit {
+			inTransit += quantity
+		}
+
+		linesRes = append(linesRes, delivery.TransferLineModelResponse{
+			Id:                line.Id,
+			ProductUuid:       line.ProductUuid,
+			SkuId:             line.SkuId,
+			Title:             line.Title,
+			Description:       line.Description,
+			Unit:              sku.Unit,
+			Quantity:          quantity,
+			ReceivedQuantity:  baseQuantity(sku, line.ReceivedQuantity),
+			TargetZoneId:      line.TargetZoneId,
+			TargetProductUuid: line.TargetProductUuid,
+			LotNumber:         line.LotNumber,
+			ProductionDate:    line.ProductionDate,
+			ExpiryDate:        line.ExpiryDate,
+		})
+	}
+
+	return delivery.TransferModelResponse{
+		Id:                transfer.Id,
+		SourceWarehouseId: transfer.SourceWarehouseId,
+		TargetWarehouseId: transfer.TargetWarehouseId,
+		Status:            transfer.Status,
+		Comment:           transfer.Comment,
+		CreatedBy:         transfer.CreatedBy,
+		ShippedBy:         transfer.ShippedBy,
+		ReceivedBy:        transfer.ReceivedBy,
+		CreatedAt:         transfer.CreatedAt,
+		ShippedAt:         transfer.ShippedAt,
+		ReceivedAt:        transfer.ReceivedAt,
+		InTransit:         inTransit,
+		Lines:             linesRes,
+	}, nil
+}
@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"fmt"
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"github.com/Miroslovelife/whareflow/pkg/notifier"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// taskStatsDefaultPeriod - период статистики, если начало не задано
+const taskStatsDefaultPeriod = 7 * 24 * time.Hour
+
+type WarehouseTaskUsecase interface {
+	CreateWarehouseTask(in *delivery.WarehouseTaskModelRequest, userId string, warehouseId int, actorId string) (*delivery.WarehouseTaskModelResponse, error)
+	AssignWarehouseTask(in *delivery.AssignWarehouseTaskModelRequest, userId string, warehouseId int, taskId uint64) error
+	CancelWarehouseTask(userId string, warehouseId int, taskId uint64) error
+	ClaimWarehouseTask(userId string, warehouseId int, taskId uint64, actorId string) error
+	CompleteWarehouseTask(userId string, warehouseId int, taskId uint64, actorId string) error
+	AbandonWarehouseTask(in *delivery.AbandonWarehouseTaskModelRequest, userId string, warehouseId int, taskId uint64, actorId string) error
+	GetAllWarehouseTasks(userId string, warehouseId int, status string, taskType string, assigneeId string) ([]delivery.WarehouseTaskModelResponse, error)
+	GetWorkerQueue(userId string, warehouseId int, actorId string) ([]delivery.WarehouseTaskModelResponse, error)
+	GetWarehouseTask(userId string, warehouseId int, taskId uint64) (*delivery.WarehouseTaskModelResponse, error)
+	GetWorkerTask(userId string, warehouseId int, taskId uint64, actorId string) (*delivery.WarehouseTaskModelResponse, error)
+	GetTaskStats(userId string, warehouseId int, from *time.Time, to *time.Time) (*delivery.TaskStatsModelResponse, error)
+}
+
+type IWarehouseTaskUsecase struct {
+	warehouseTaskRepository repositories.WarehouseTaskRepository
+	notifier                notifier.Notifier
+	logger                  slog.Logger
+}
+
+func NewIWarehouseTaskUsecase(warehouseTaskRepository repositories.WarehouseTaskRepository, notifier notifier.Notifier, logger slog.Logger) *IWarehouseTaskUsecase {
+	return &IWarehouseTaskUsecase{
+		warehouseTaskRepository: warehouseTaskRepository,
+		notifier:                notifier,
+		logger:                  logger,
+	}
+}
+
+// CreateWarehouseTask оповещает назначенного исполнителя. Задание к этому моменту уже создано,
+// поэтому ошибка оповещения только пишется в лог
+func (tu *IWarehouseTaskUsecase) CreateWarehouseTask(in *delivery.WarehouseTaskModelRequest, userId string, warehouseId int, actorId string) (*delivery.WarehouseTaskModelResponse, error) {
+	title := strings.TrimSpace(in.Title)
+	if title == "" || !validWarehouseTaskType(in.Type) {
+		return nil, custom_errors.ErrInvalidWarehouseTask
+	}
+
+	task := &domain.WarehouseTask{
+		WarehouseId: uint64(warehouseId),
+		Type:        in.Type,
+		Status:      domain.WarehouseTaskStatusOpen,
+		Priority:    in.Priority,
+		Title:       title,
+		Description: in.Description,
+		Reference:   in.Reference,
+		ProductUuid: in.ProductUuid,
+		ZoneId:      in.ZoneId,
+		LocationId:  in.LocationId,
+		ReceiptId:   in.ReceiptId,
+		PickTaskId:  in.PickTaskId,
+		AssigneeId:  in.AssigneeId,
+		CreatedBy:   actorId,
+		DueAt:       in.DueAt,
+	}
+
+	if err := tu.warehouseTaskRepository.InsertWarehouseTaskData(task, userId); err != nil {
+		return nil, err
+	}
+
+	if task.AssigneeId != nil {
+		if err := tu.notifyAssignee(task, *task.AssigneeId); err != nil {
+			tu.logger.Error(fmt.Sprintf("Task %d created, but assignee notification failed: %v", task.Id, err))
+		}
+	}
+
+	taskRes := warehouseTaskToResponse(task)
+
+	return &taskRes, nil
+}
+
+// AssignWarehouseTask оповещает нового исполнителя. Назначение к этому моменту уже сохранено,
+// поэтому ошибки оповещения только пишутся в лог
+func (tu *IWarehouseTaskUsecase) AssignWarehouseTask(in *delivery.AssignWarehouseTaskModelRequest, userId string, warehouseId int, taskId uint64) error {
+	if err := tu.warehouseTaskRepository.AssignWarehouseTaskData(userId, warehouseId, taskId, in.AssigneeId); err != nil {
+		return err
+	}
+
+	if in.AssigneeId == nil {
+		return nil
+	}
+
+	task, err := tu.warehouseTaskRepository.FindWarehouseTaskData(userId, warehouseId, taskId)
+	if err != nil {
+		tu.logger.Error(fmt.Sprintf("Task %d assigned, but task lookup failed: %v", taskId, err))
+		return nil
+	}
+
+	if err := tu.notifyAssignee(task, *in.AssigneeId); err != nil {
+		tu.logger.Error(fmt.Sprintf("Task %d assigned, but assignee notification failed: %v", taskId, err))
+	}
+
+	return nil
+}
+
+func (tu *IWarehouseTaskUsecase) CancelWarehouseTask(userId string, warehouseId int, taskId uint64) error {
+	return tu.warehouseTaskRepository.CancelWarehouseTaskData(userId, warehouseId, taskId)
+}
+
+func (tu *IWarehouseTaskUsecase) ClaimWarehouseTask(userId string, warehouseId int, taskId uint64, actorId string) error {
+	return tu.warehouseTaskRepository.ClaimWarehouseTaskData(userId, warehouseId, taskId, actorId)
+}
+
+func (tu *IWarehouseTaskUsecase) CompleteWarehouseTask(userId string, warehouseId int, taskId uint64, actorId string) error {
+	return tu.warehouseTaskRepository.CompleteWarehouseTaskData(userId, warehouseId, taskId, actorId)
+}
+
+func (tu *IWarehouseTaskUsecase) AbandonWarehouseTask(in *delivery.AbandonWarehouseTaskModelRequest, userId string, warehouseId int, taskId uint64, actorId string) error {
+	return tu.warehouseTaskRepository.AbandonWarehouseTaskData(userId, warehouseId, taskId, actorId, strings.TrimSpace(in.Reason))
+}
+
+func (tu *IWarehouseTaskUsecase) GetAllWarehouseTasks(userId string, warehouseId int, status string, taskType string, assigneeId string) ([]delivery.WarehouseTaskModelResponse, error) {
+	tasks, err := tu.warehouseTaskRepository.FindAllWarehouseTaskData(userId, warehouseId, status, taskType, assigneeId)
+	if err != nil {
+		return nil, err
+	}
+
+	return warehouseTasksToResponse(*tasks), nil
+}
+
+func (tu *IWarehouseTaskUsecase) GetWorkerQueue(userId string, warehouseId int, actorId string) ([]delivery.WarehouseTaskModelResponse, error) {
+	tasks, err := tu.warehouseTaskRepository.FindWorkerQueueData(userId, warehouseId, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	return warehouseTasksToResponse(*tasks), nil
+}
+
+func (tu *IWarehouseTaskUsecase) GetWarehouseTask(userId string, warehouseId int, taskId uint64) (*delivery.WarehouseTaskModelResponse, error) {
+	task, err := tu.warehouseTaskRepository.FindWarehouseTaskData(userId, warehouseId, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	taskRes := warehouseTaskToResponse(task)
+
+	return &taskRes, nil
+}
+
+func (tu *IWarehouseTaskUsecase) GetWorkerTask(userId string, warehouseId int, taskId uint64, actorId string) (*delivery.WarehouseTaskModelResponse, error) {
+	task, err := tu.warehouseTaskRepository.FindWorkerTaskData(userId, warehouseId, taskId, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	taskRes := warehouseTaskToResponse(task)
+
+	return &taskRes, nil
+}
+
+// GetTaskStats считает выработку работников за [from, to). Без to период заканчивается текущим моментом,
+// без from - начинается за неделю до конца периода
+func (tu *IWarehouseTaskUsecase) GetTaskStats(userId string, warehouseId int, from *time.Time, to *time.Time) (*delivery.TaskStatsModelResponse, error) {
+	periodTo := time.Now()
+	if to != nil {
+		periodTo = *to
+	}
+
+	periodFrom := periodTo.Add(-taskStatsDefaultPeriod)
+	if from != nil {
+		periodFrom = *from
+	}
+
+	if !periodFrom.Before(periodTo) {
+		return nil, custom_errors.ErrInvalidStatsPeriod
+	}
+
+	stats, err := tu.warehouseTaskRepository.FindTaskStatsData(userId, warehouseId, periodFrom, periodTo)
+	if err != nil {
+		return nil, err
+	}
+
+	type typeTotals struct {
+		completed int64
+		seconds   int64
+	}
+	type workerTotals struct {
+		res    delivery.WorkerStatModelResponse
+		byType map[string]*typeTotals
+	}
+
+	workers := make(map[string]*workerTotals)
+	for _, stat := range *stats {
+		worker, ok := workers[stat.ActorId]
+		if !ok {
+			worker = &workerTotals{
+				res: delivery.WorkerStatModelResponse{
+					UserId:    stat.ActorId,
+					Username:  stat.Username,
+					FirstName: stat.FirstName,
+					LastName:  stat.LastName,
+				},
+				byType: make(map[string]*typeTotals),
+			}
+			workers[stat.ActorId] = worker
+		}
+
+		switch stat.Action {
+		case domain.WarehouseTaskActionClaim:
+			worker.res.Claimed += stat.Count
+		case domain.WarehouseTaskActionAbandon:
+			worker.res.Abandoned += stat.Count
+		case domain.WarehouseTaskActionComplete:
+			worker.res.Completed += stat.Count
+			worker.res.WorkMinutes += float64(stat.DurationSeconds) / 60
+
+			totals, ok := worker.byType[stat.TaskType]
+			if !ok {
+				totals = &typeTotals{}
+				worker.byType[stat.TaskType] = totals
+			}
+			totals.completed += stat.Count
+			totals.seconds += stat.DurationSeconds
+		}
+	}
+
+	workersRes := make([]delivery.WorkerStatModelResponse, 0, len(workers))
+	for _, worker := range workers {
+		worker.res.ByType = []delivery.TaskTypeStatModelResponse{}
+		for taskType, totals := range worker.byType {
+			worker.res.ByType = append(worker.res.ByType, delivery.TaskTypeStatModelResponse{
+				Type:       taskType,
+				Completed:  totals.completed,
+				AvgMinutes: averageMinutes(totals.seconds, totals.completed),
+			})
+		}
+		sort.Slice(worker.res.ByType, func(i, j int) bool {
+			return worker.res.ByType[i].Type < worker.res.ByType[j].Type
+		})
+
+		worker.res.AvgMinutes = averageMinutes(int64(worker.res.WorkMinutes*60), worker.res.Completed)
+		worker.res.WorkMinutes = math.Round(worker.res.WorkMinutes*100) / 100
+		workersRes = append(workersRes, worker.res)
+	}
+
+	// Сначала самые результативные работники
+	sort.Slice(workersRes, func(i, j int) bool {
+		if workersRes[i].Completed != workersRes[j].Completed {
+			return workersRes[i].Completed > workersRes[j].Completed
+		}
+		return workersRes[i].UserId < workersRes[j].UserId
+	})
+
+	return &delivery.TaskStatsModelResponse{
+		From:    periodFrom,
+		To:      periodTo,
+		Workers: workersRes,
+	}, nil
+}
+
+func (tu *IWarehouseTaskUsecase) notifyAssignee(task *domain.WarehouseTask, assigneeId string) error {
+	worker, err := tu.warehouseTaskRepository.FindTaskWorkerData(int(task.WarehouseId), assigneeId)
+	if err != nil {
+		return err
+	}
+
+	return tu.notifier.Notify(notifier.Notification{
+		Subject: "warehouse_task",
+		Message: fmt.Sprintf("Task %d (%s) is assigned to you: %s", task.Id, task.Type, task.Title),
+		Fields: map[string]interface{}{
+			"warehouse_id": task.WarehouseId,
+			"task_id":      task.Id,
+			"user_id":      assigneeId,
+			"username":     worker.Username,
+		},
+	})
+}
+
+func validWarehouseTaskType(taskType string) bool {
+	switch taskType {
+	case domain.WarehouseTaskTypeReceive,
+		domain.WarehouseTaskTypePutaway,
+		domain.WarehouseTaskTypePick,
+		domain.WarehouseTaskTypeCount,
+		domain.WarehouseTaskTypeMove:
+		return true
+	}
+	return false
+}
+
+func averageMinutes(seconds int64, count int64) float64 {
+	if count == 0 {
+		return 0
+	}
+
+	return math.Round(float64(seconds)/float64(count)/60*100) / 100
+}
+
+func warehouseTasksToResponse(tasks []domain.WarehouseTask) []delivery.WarehouseTaskModelResponse {
+	tasksRes := []delivery.WarehouseTaskModelResponse{}
+	for _, task := range tasks {
+		tasksRes = append(tasksRes, warehouseTaskToResponse(&task))
+	}
+
+	return tasksRes
+}
+
+func warehouseTaskToResponse(task *domain.WarehouseTask) delivery.WarehouseTaskModelResponse {
+	return delivery.WarehouseTaskModelResponse{
+		Id:          task.Id,
+		WarehouseId: task.WarehouseId,
+		Type:        task.Type,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		Title:       task.Title,
+		Description: task.Description,
+		Reference:   task.Reference,
+		ProductUuid: task.ProductUuid,
+		ZoneId:      task.ZoneId,
+		LocationId:  task.LocationId,
+		ReceiptId:   task.ReceiptId,
+		PickTaskId:  task.PickTaskId,
+		AssigneeId:  task.AssigneeId,
+		ClaimedBy:   task.ClaimedBy,
+		CreatedBy:   task.CreatedBy,
+		CreatedAt:   task.CreatedAt,
+		DueAt:       task.DueAt,
+		ClaimedAt:   task.ClaimedAt,
+		CompletedAt: task.CompletedAt,
+		CancelledAt: task.CancelledAt,
+	}
+}
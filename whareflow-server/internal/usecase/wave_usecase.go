@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
+	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
+	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"sort"
+)
+
+type WaveUsecase interface {
+	CreateWave(in *delivery.WaveModelRequest, userId string, warehouseId int, actorId string) (*delivery.WaveModelResponse, error)
+	PickWave(in *delivery.WavePickModelRequest, userId string, warehouseId int, waveId uint64) error
+	SortWave(in *delivery.WaveSortModelRequest, userId string, warehouseId int, waveId uint64, actorId string) ([]delivery.WaveSortPutModelResponse, error)
+	CompleteWave(userId string, warehouseId int, waveId uint64, actorId string) error
+	GetAllWaves(userId string, warehouseId int, status string) ([]delivery.WaveModelResponse, error)
+	GetWave(userId string, warehouseId int, waveId uint64) (*delivery.WaveModelResponse, error)
+}
+
+type IWaveUsecase struct {
+	waveRepository     repositories.WaveRepository
+	pickListRepository repositories.PickListRepository
+}
+
+func NewIWaveUsecase(waveRepository repositories.WaveRepository, pickListRepository repositories.PickListRepository) *IWaveUsecase {
+	return &IWaveUsecase{
+		waveRepository:     waveRepository,
+		pickListRepository: pickListRepository,
+	}
+}
+
+func (wu *IWaveUsecase) CreateWave(in *delivery.WaveModelRequest, userId string, warehouseId int, actorId string) (*delivery.WaveModelResponse, error) {
+	if in.MaxOrders < 0 {
+		return nil, custom_errors.ErrInvalidWave
+	}
+
+	wave := &domain.Wave{
+		WarehouseId: uint64(warehouseId),
+		Status:      domain.WaveStatusPicking,
+		CreatedBy:   actorId,
+	}
+
+	if err := wu.waveRepository.InsertWaveData(wave, userId, in.OrderIds, in.MaxOrders); err != nil {
+		return nil, err
+	}
+
+	return wu.GetWave(userId, warehouseId, wave.Id)
+}
+
+// PickWave переводит собранное количество строк из единицы строки волны в доли базовой единицы
+func (wu *IWaveUsecase) PickWave(in *delivery.WavePickModelRequest, userId string, warehouseId int, waveId uint64) error {
+	wave, err := wu.waveRepository.FindWaveData(userId, warehouseId, waveId)
+	if err != nil {
+		return err
+	}
+
+	factors := make(map[uint64]float64, len(wave.Lines))
+	for _, line := range wave.Lines {
+		factors[line.Id] = line.UnitFactor
+	}
+
+	picked := make(map[uint64]uint64, len(in.Lines))
+	for _, lineReq := range in.Lines {
+		factor, ok := factors[lineReq.LineId]
+		if !ok {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+
+		if _, duplicate := picked[lineReq.LineId]; duplicate {
+			return custom_errors.ErrInvalidDocumentLine
+		}
+
+		quantity, err := toStockQuantity(lineReq.PickedQuantity, factor)
+		if err != nil {
+			return err
+		}
+		picked[lineReq.LineId] = quantity
+	}
+
+	return wu.waveRepository.PickWaveData(userId, warehouseId, waveId, picked)
+}
+
+func (wu *IWaveUsecase) SortWave(in *delivery.WaveSortModelRequest, userId string, warehouseId int, waveId uint64, actorId string) ([]delivery.WaveSortPutModelResponse, error) {
+	wave, err := wu.waveRepository.FindWaveData(userId, warehouseId, waveId)
+	if err != nil {
+		return nil, err
+	}
+
+	var line *domain.WaveLine
+	for i := range wave.Lines {
+		if wave.Lines[i].ProductUuid == in.ProductUuid {
+			line = &wave.Lines[i]
+			break
+		}
+	}
+	if line == nil {
+		return nil, custom_errors.ErrNothingToSort
+	}
+
+	quantity := 1.0
+	if in.Quantity != nil {
+		quantity = *in.Quantity
+	}
+
+	stockQuantity, err := toStockQuantity(quantity, line.UnitFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	puts, err := wu.waveRepository.SortWaveData(userId, warehouseId, waveId, in.ProductUuid, stockQuantity, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	putsRes := []delivery.WaveSortPutModelResponse{}
+	for _, put := range *puts {
+		putsRes = append(putsRes, delivery.WaveSortPutModelResponse{
+			Slot:     put.Slot,
+			OrderId:  put.OrderId,
+			TaskId:   put.TaskId,
+			Unit:     put.Unit,
+			Quantity: fromStockQuantity(put.Quantity, put.UnitFactor),
+		})
+	}
+
+	return putsRes, nil
+}
+
+func (wu *IWaveUsecase) CompleteWave(userId string, warehouseId int, waveId uint64, actorId string) error {
+	return wu.waveRepository.CompleteWaveData(userId, warehouseId, waveId, actorId)
+}
+
+func (wu *IWaveUsecase) GetAllWaves(userId string, warehouseId int, status string) ([]delivery.WaveModelResponse, error) {
+	waves, err := wu.waveRepository.FindAllWaveData(userId, warehouseId, status)
+	if err != nil {
+		return nil, err
+	}
+
+	wavesRes := []delivery.WaveModelResponse{}
+	for _, wave := range *waves {
+		wavesRes = append(wavesRes, waveToResponse(&wave))
+	}
+
+	return wavesRes, nil
+}
+
+// GetWave упорядочивает строки волны по текущему маршруту обхода склада, как остановки листа сборки
+func (wu *IWaveUsecase) GetWave(userId string, warehouseId int, waveId uint64) (*delivery.WaveModelResponse, error) {
+	wave, err := wu.waveRepository.FindWaveData(userId, warehouseId, waveId)
+	if err != nil {
+		return nil, err
+	}
+
+	route, err := loadWalkRoute(wu.pickListRepository, userId, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+
+	type rankedLine struct {
+		rank int
+		res  delivery.WaveLineModelResponse
+	}
+
+	ranked := make([]rankedLine, 0, len(wave.Lines))
+	for _, line := range wave.Lines {
+		rank, path := route.place(line.ZoneId, line.LocationId)
+		ranked = append(ranked, rankedLine{
+			rank: rank,
+			res: delivery.WaveLineModelResponse{
+				Id:             line.Id,
+				ProductUuid:    line.ProductUuid,
+				ZoneId:         line.ZoneId,
+				ZoneName:       route.zones[line.ZoneId].Name,
+				LocationId:     line.LocationId,
+				LocationPath:   path,
+				Unit:           line.Unit,
+				Quantity:       fromStockQuantity(line.Quantity, line.UnitFactor),
+				PickedQuantity: fromStockQuantity(line.PickedQuantity, line.UnitFactor),
+				SortedQuantity: fromStockQuantity(line.SortedQuantity, line.UnitFactor),
+			},
+		})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].rank != ranked[j].rank {
+			return ranked[i].rank < ranked[j].rank
+		}
+		if ranked[i].res.ZoneId != ranked[j].res.ZoneId {
+			return ranked[i].res.ZoneId < ranked[j].res.ZoneId
+		}
+		if ranked[i].res.LocationPath != ranked[j].res.LocationPath {
+			return ranked[i].res.LocationPath < ranked[j].res.LocationPath
+		}
+		return ranked[i].res.ProductUuid < ranked[j].res.ProductUuid
+	})
+
+	waveRes := waveToResponse(wave)
+	waveRes.Lines = make([]delivery.WaveLineModelResponse, 0, len(ranked))
+	for i, line := range ranked {
+		line.res.Sequence = i + 1
+		waveRes.Lines = append(waveRes.Lines, line.res)
+	}
+
+	return &waveRes, nil
+}
+
+func waveToResponse(wave *domain.Wave) delivery.WaveModelResponse {
+	ordersRes := []delivery.WaveOrderModelResponse{}
+	for _, waveOrder := range wave.Orders {
+		ordersRes = append(ordersRes, delivery.WaveOrderModelResponse{
+			OrderId: waveOrder.OrderId,
+			Slot:    waveOrder.Slot,
+		})
+	}
+
+	return delivery.WaveModelResponse{
+		Id:          wave.Id,
+		WarehouseId: wave.WarehouseId,
+		Status:      wave.Status,
+		CreatedBy:   wave.CreatedBy,
+		CreatedAt:   wave.CreatedAt,
+		PickedAt:    wave.PickedAt,
+		DoneAt:      wave.DoneAt,
+		Orders:      ordersRes,
+	}
+}
@@ -3,7 +3,9 @@ package usecase
 import (
 	delivery "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/model"
 	"github.com/Miroslovelife/whareflow/internal/domain"
+	custom_errors "github.com/Miroslovelife/whareflow/internal/errors"
 	"github.com/Miroslovelife/whareflow/internal/repositories"
+	"math"
 )
 
 type ZoneUsecase interface {
@@ -25,10 +27,19 @@ func NewIZoneUsecase(zoneRepo repositories.ZoneRepository) *IZoneUsecase {
 }
 
 func (zu *IZoneUsecase) CreateZone(in delivery.ZoneModelRequest, userId string, warehouseId int) error {
+	if err := checkZoneRequest(&in); err != nil {
+		return err
+	}
+
 	zone := &domain.Zone{
-		Name:        in.Name,
-		Capacity:    in.Capacity,
-		WarehouseId: warehouseId,
+		Name:             in.Name,
+		Capacity:         in.Capacity,
+		WarehouseId:      warehouseId,
+		MaxVolume:        in.MaxVolume,
+		MaxWeight:        in.MaxWeight,
+		MaxPallets:       in.MaxPallets,
+		ZoneType:         in.ZoneType,
+		TemperatureClass: in.TemperatureClass,
 	}
 
 	if err := zu.zoneRepository.InsertZoneData(zone, userId); err != nil {
@@ -39,11 +50,20 @@ func (zu *IZoneUsecase) CreateZone(in delivery.ZoneModelRequest, userId string,
 }
 
 func (zu *IZoneUsecase) UpdateZone(in delivery.ZoneModelRequest, userId string, zoneId int, warehouseId int) error {
+	if err := checkZoneRequest(&in); err != nil {
+		return err
+	}
+
 	zone := &domain.Zone{
-		Id:          zoneId,
-		Name:        in.Name,
-		Capacity:    in.Capacity,
-		WarehouseId: warehouseId,
+		Id:               zoneId,
+		Name:             in.Name,
+		Capacity:         in.Capacity,
+		WarehouseId:      warehouseId,
+		MaxVolume:        in.MaxVolume,
+		MaxWeight:        in.MaxWeight,
+		MaxPallets:       in.MaxPallets,
+		ZoneType:         in.ZoneType,
+		TemperatureClass: in.TemperatureClass,
 	}
 
 	if err := zu.zoneRepository.UpdateZoneData(zone, userId); err != nil {
@@ -59,16 +79,20 @@ func (zu *IZoneUsecase) GetAllZone(userId string, warehouseId int) ([]delivery.Z
 		return nil, err
 	}
 
+	zoneIds := make([]int, 0, len(*zonesRepo))
+	for _, zonesRepoValue := range *zonesRepo {
+		zoneIds = append(zoneIds, zonesRepoValue.Id)
+	}
+
+	loads, err := zu.zoneRepository.FindZoneLoadData(zoneIds)
+	if err != nil {
+		return nil, err
+	}
+
 	var zones []delivery.ZoneModelResponse
 
 	for _, zonesRepoValue := range *zonesRepo {
-		zone := delivery.ZoneModelResponse{
-			Id:       zonesRepoValue.Id,
-			Name:     zonesRepoValue.Name,
-			Capacity: zonesRepoValue.Capacity,
-		}
-
-		zones = append(zones, zone)
+		zones = append(zones, zoneToResponse(zonesRepoValue, loads[zonesRepoValue.Id]))
 	}
 
 	return zones, nil
@@ -80,12 +104,13 @@ func (zu *IZoneUsecase) GetZone(userId string, warehouseId, zoneId int) (*delive
 		return nil, err
 	}
 
-	zone := delivery.ZoneModelResponse{
-		Id:       zonesRepo.Id,
-		Name:     zonesRepo.Name,
-		Capacity: zonesRepo.Capacity,
+	loads, err := zu.zoneRepository.FindZoneLoadData([]int{zonesRepo.Id})
+	if err != nil {
+		return nil, err
 	}
 
+	zone := zoneToResponse(*zonesRepo, loads[zonesRepo.Id])
+
 	return &zone, nil
 }
 
@@ -97,3 +122,75 @@ func (zu *IZoneUsecase) DeleteZone(userId string, warehouseId, zoneId int) error
 
 	return nil
 }
+
+// checkZoneRequest проверяет пределы и атрибуты зоны, подставляя значения по умолчанию для пустых типа и режима
+func checkZoneRequest(in *delivery.ZoneModelRequest) error {
+	if (in.MaxVolume != nil && *in.MaxVolume < 0) || (in.MaxWeight != nil && *in.MaxWeight < 0) {
+		return custom_errors.ErrInvalidZoneLimits
+	}
+
+	if in.ZoneType == "" {
+		in.ZoneType = domain.ZoneTypeGeneral
+	}
+	if in.TemperatureClass == "" {
+		in.TemperatureClass = domain.TemperatureClassAmbient
+	}
+
+	switch in.ZoneType {
+	case domain.ZoneTypeGeneral, domain.ZoneTypeHazardous, domain.ZoneTypeQuarantine:
+	default:
+		return custom_errors.ErrInvalidZoneType
+	}
+	if !validTemperatureClass(in.TemperatureClass) {
+		return custom_errors.ErrInvalidZoneType
+	}
+
+	return nil
+}
+
+func validTemperatureClass(class string) bool {
+	switch class {
+	case domain.TemperatureClassAmbient, domain.TemperatureClassChilled, domain.TemperatureClassFrozen:
+		return true
+	}
+
+	return false
+}
+
+func zoneToResponse(zone domain.Zone, load domain.ZoneLoad) delivery.ZoneModelResponse {
+	response := delivery.ZoneModelResponse{
+		Id:               zone.Id,
+		Name:             zone.Name,
+		Capacity:         zone.Capacity,
+		ZoneType:         zone.ZoneType,
+		TemperatureClass: zone.TemperatureClass,
+		MaxVolume:        zone.MaxVolume,
+		MaxWeight:        zone.MaxWeight,
+		MaxPallets:       zone.MaxPallets,
+		UsedVolume:       load.Volume,
+		UsedWeight:       load.Weight,
+		UsedPallets:      load.Pallets,
+	}
+
+	if zone.MaxVolume != nil {
+		response.VolumeUtilization = utilizationPercent(load.Volume, *zone.MaxVolume)
+	}
+	if zone.MaxWeight != nil {
+		response.WeightUtilization = utilizationPercent(load.Weight, *zone.MaxWeight)
+	}
+	if zone.MaxPallets != nil {
+		response.PalletUtilization = utilizationPercent(float64(load.Pallets), float64(*zone.MaxPallets))
+	}
+
+	return response
+}
+
+// utilizationPercent округляет загрузку до сотых процента, нулевой предел при любой загрузке считается заполненным
+func utilizationPercent(used, limit float64) *float64 {
+	percent := 100.0
+	if limit > 0 {
+		percent = math.Round(used/limit*10000) / 100
+	}
+
+	return &percent
+}
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS stock_movements_append_only ON public.stock_movements;
+DROP FUNCTION IF EXISTS public.stock_movements_forbid_change();
+DROP TABLE IF EXISTS public.stock_movements;
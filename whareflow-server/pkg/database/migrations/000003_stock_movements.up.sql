@@ -0,0 +1,44 @@
+-- Журнал хранит идентификаторы товара и зон без внешних ключей: удаление товара, зоны, склада
+-- или пользователя не затрагивает записанные движения
+CREATE TABLE public.stock_movements (
+                                        id BIGSERIAL PRIMARY KEY,
+                                        product_uuid UUID NOT NULL,
+                                        quantity BIGINT NOT NULL CHECK (quantity <> 0),
+                                        reason VARCHAR(50) NOT NULL,
+                                        actor_uuid UUID NOT NULL,
+                                        source_zone_id BIGINT,
+                                        target_zone_id BIGINT,
+                                        created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX stock_movements_product_uuid_idx ON public.stock_movements (product_uuid, created_at);
+
+-- Журнал только дополняется: удалять и изменять уже записанные движения нельзя
+CREATE FUNCTION public.stock_movements_forbid_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        RAISE EXCEPTION 'stock_movements is append-only';
+    END IF;
+    IF NEW.product_uuid IS DISTINCT FROM OLD.product_uuid
+        OR NEW.quantity IS DISTINCT FROM OLD.quantity
+        OR NEW.reason IS DISTINCT FROM OLD.reason
+        OR NEW.actor_uuid IS DISTINCT FROM OLD.actor_uuid
+        OR NEW.created_at IS DISTINCT FROM OLD.created_at THEN
+        RAISE EXCEPTION 'stock_movements is append-only';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON public.stock_movements
+    FOR EACH ROW EXECUTE FUNCTION public.stock_movements_forbid_change();
+
+-- Начальные остатки уже существующих товаров переносим в журнал,
+-- чтобы сумма движений совпадала с products.count
+INSERT INTO public.stock_movements (product_uuid, quantity, reason, actor_uuid, target_zone_id)
+SELECT p.uuid, p.count, 'initial', w.uuid_user, p.zone_id
+FROM public.products p
+         JOIN public.zones z ON p.zone_id = z.id
+         JOIN public.ware_houses w ON z.ware_house_id = w.id
+WHERE p.count <> 0;
@@ -0,0 +1,4 @@
+DELETE FROM permissions
+WHERE name = 'receipt_manage';
+DROP TABLE IF EXISTS public.receipt_lines;
+DROP TABLE IF EXISTS public.receipts;
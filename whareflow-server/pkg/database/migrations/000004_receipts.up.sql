@@ -0,0 +1,27 @@
+CREATE TABLE public.receipts (
+                                 id BIGSERIAL PRIMARY KEY,
+                                 ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                 status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'received', 'posted')),
+                                 comment VARCHAR(500),
+                                 created_by UUID NOT NULL,
+                                 created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                 received_at TIMESTAMP,
+                                 posted_at TIMESTAMP
+);
+
+CREATE TABLE public.receipt_lines (
+                                      id BIGSERIAL PRIMARY KEY,
+                                      receipt_id BIGINT NOT NULL REFERENCES public.receipts(id) ON DELETE CASCADE,
+                                      zone_id BIGINT NOT NULL REFERENCES public.zones(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                      product_uuid UUID REFERENCES public.products(uuid) ON DELETE SET NULL,
+                                      title VARCHAR(200),
+                                      description VARCHAR(500),
+                                      quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                      received_quantity BIGINT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0)
+);
+
+CREATE INDEX receipts_ware_house_id_idx ON public.receipts (ware_house_id);
+CREATE INDEX receipt_lines_receipt_id_idx ON public.receipt_lines (receipt_id);
+
+INSERT INTO permissions (name)
+VALUES ('receipt_manage');
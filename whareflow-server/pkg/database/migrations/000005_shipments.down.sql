@@ -0,0 +1,6 @@
+DELETE FROM permissions
+WHERE name = 'shipment_manage';
+DROP TABLE IF EXISTS public.shipment_lines;
+DROP TABLE IF EXISTS public.shipments;
+ALTER TABLE public.products
+    DROP CONSTRAINT IF EXISTS products_count_non_negative;
@@ -0,0 +1,28 @@
+-- Последняя линия защиты от отрицательных остатков при конкурентных списаниях
+ALTER TABLE public.products
+    ADD CONSTRAINT products_count_non_negative CHECK (count >= 0);
+
+CREATE TABLE public.shipments (
+                                  id BIGSERIAL PRIMARY KEY,
+                                  ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                  status VARCHAR(20) NOT NULL DEFAULT 'picking' CHECK (status IN ('picking', 'packed', 'shipped')),
+                                  comment VARCHAR(500),
+                                  created_by UUID NOT NULL,
+                                  created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                  packed_at TIMESTAMP,
+                                  shipped_at TIMESTAMP
+);
+
+CREATE TABLE public.shipment_lines (
+                                       id BIGSERIAL PRIMARY KEY,
+                                       shipment_id BIGINT NOT NULL REFERENCES public.shipments(id) ON DELETE CASCADE,
+                                       product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE CASCADE,
+                                       quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                       picked_quantity BIGINT NOT NULL DEFAULT 0 CHECK (picked_quantity >= 0 AND picked_quantity <= quantity)
+);
+
+CREATE INDEX shipments_ware_house_id_idx ON public.shipments (ware_house_id);
+CREATE INDEX shipment_lines_shipment_id_idx ON public.shipment_lines (shipment_id);
+
+INSERT INTO permissions (name)
+VALUES ('shipment_manage');
@@ -0,0 +1,4 @@
+DELETE FROM permissions
+WHERE name = 'transfer_manage';
+DROP TABLE IF EXISTS public.transfer_lines;
+DROP TABLE IF EXISTS public.transfers;
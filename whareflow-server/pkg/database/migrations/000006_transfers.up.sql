@@ -0,0 +1,34 @@
+CREATE TABLE public.transfers (
+                                  id BIGSERIAL PRIMARY KEY,
+                                  source_ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                  target_ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                  status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_transit', 'received')),
+                                  comment VARCHAR(500),
+                                  created_by UUID NOT NULL,
+                                  shipped_by UUID,
+                                  received_by UUID,
+                                  created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                  shipped_at TIMESTAMP,
+                                  received_at TIMESTAMP,
+                                  CONSTRAINT transfers_different_warehouses CHECK (source_ware_house_id <> target_ware_house_id)
+);
+
+-- Пока перемещение в статусе in_transit, отгруженное количество строк и есть остаток "в пути"
+CREATE TABLE public.transfer_lines (
+                                       id BIGSERIAL PRIMARY KEY,
+                                       transfer_id BIGINT NOT NULL REFERENCES public.transfers(id) ON DELETE CASCADE,
+                                       product_uuid UUID REFERENCES public.products(uuid) ON DELETE SET NULL,
+                                       title VARCHAR(200),
+                                       description VARCHAR(500),
+                                       quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                       received_quantity BIGINT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+                                       target_zone_id BIGINT REFERENCES public.zones(id) ON DELETE SET NULL,
+                                       target_product_uuid UUID REFERENCES public.products(uuid) ON DELETE SET NULL
+);
+
+CREATE INDEX transfers_source_ware_house_id_idx ON public.transfers (source_ware_house_id);
+CREATE INDEX transfers_target_ware_house_id_idx ON public.transfers (target_ware_house_id);
+CREATE INDEX transfer_lines_transfer_id_idx ON public.transfer_lines (transfer_id);
+
+INSERT INTO permissions (name)
+VALUES ('transfer_manage');
@@ -0,0 +1,3 @@
+DELETE FROM permissions
+WHERE name = 'reservation_manage';
+DROP TABLE IF EXISTS public.reservations;
@@ -0,0 +1,18 @@
+CREATE TABLE public.reservations (
+                                     id BIGSERIAL PRIMARY KEY,
+                                     product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE CASCADE ON UPDATE CASCADE,
+                                     quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                     owner_ref VARCHAR(100) NOT NULL,
+                                     status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'released', 'expired')),
+                                     expires_at TIMESTAMP NOT NULL,
+                                     created_by UUID NOT NULL,
+                                     created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                     released_at TIMESTAMP
+);
+
+-- Резерв считается по активным записям, поэтому индекс только по ним
+CREATE INDEX reservations_active_product_uuid_idx ON public.reservations (product_uuid) WHERE status = 'active';
+CREATE INDEX reservations_active_expires_at_idx ON public.reservations (expires_at) WHERE status = 'active';
+
+INSERT INTO permissions (name)
+VALUES ('reservation_manage');
@@ -0,0 +1,4 @@
+DELETE FROM permissions
+WHERE name = 'inventory_count';
+DROP TABLE IF EXISTS public.inventory_count_lines;
+DROP TABLE IF EXISTS public.inventory_counts;
@@ -0,0 +1,28 @@
+CREATE TABLE public.inventory_counts (
+                                         id BIGSERIAL PRIMARY KEY,
+                                         ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                         zone_id BIGINT REFERENCES public.zones(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                         status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'approved', 'cancelled')),
+                                         comment VARCHAR(500),
+                                         created_by UUID NOT NULL,
+                                         approved_by UUID,
+                                         created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                         closed_at TIMESTAMP
+);
+
+-- expected_quantity - учетный остаток на момент открытия, после сканирования - на момент сканирования
+CREATE TABLE public.inventory_count_lines (
+                                              id BIGSERIAL PRIMARY KEY,
+                                              inventory_count_id BIGINT NOT NULL REFERENCES public.inventory_counts(id) ON DELETE CASCADE,
+                                              product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE CASCADE,
+                                              expected_quantity BIGINT NOT NULL CHECK (expected_quantity >= 0),
+                                              counted_quantity BIGINT CHECK (counted_quantity >= 0),
+                                              counted_by UUID,
+                                              counted_at TIMESTAMP,
+                                              CONSTRAINT inventory_count_lines_unique_product UNIQUE (inventory_count_id, product_uuid)
+);
+
+CREATE INDEX inventory_counts_ware_house_id_idx ON public.inventory_counts (ware_house_id);
+
+INSERT INTO permissions (name)
+VALUES ('inventory_count');
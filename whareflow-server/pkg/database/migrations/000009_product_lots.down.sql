@@ -0,0 +1,17 @@
+ALTER TABLE public.transfer_lines
+    DROP COLUMN IF EXISTS lot_number,
+    DROP COLUMN IF EXISTS production_date,
+    DROP COLUMN IF EXISTS expiry_date;
+
+ALTER TABLE public.receipt_lines
+    DROP COLUMN IF EXISTS lot_number,
+    DROP COLUMN IF EXISTS production_date,
+    DROP COLUMN IF EXISTS expiry_date;
+
+DROP INDEX IF EXISTS products_expiry_date_idx;
+
+ALTER TABLE public.products
+    DROP CONSTRAINT IF EXISTS products_lot_dates,
+    DROP COLUMN IF EXISTS lot_number,
+    DROP COLUMN IF EXISTS production_date,
+    DROP COLUMN IF EXISTS expiry_date;
@@ -0,0 +1,18 @@
+-- Строка products теперь хранит остаток партии товара в зоне: товар + партия + зона
+ALTER TABLE public.products
+    ADD COLUMN lot_number VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN production_date DATE,
+    ADD COLUMN expiry_date DATE,
+    ADD CONSTRAINT products_lot_dates CHECK (production_date IS NULL OR expiry_date IS NULL OR production_date <= expiry_date);
+
+CREATE INDEX products_expiry_date_idx ON public.products (expiry_date) WHERE expiry_date IS NOT NULL;
+
+ALTER TABLE public.receipt_lines
+    ADD COLUMN lot_number VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN production_date DATE,
+    ADD COLUMN expiry_date DATE;
+
+ALTER TABLE public.transfer_lines
+    ADD COLUMN lot_number VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN production_date DATE,
+    ADD COLUMN expiry_date DATE;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS public.serial_movements;
+DROP TABLE IF EXISTS public.serial_numbers;
+
+ALTER TABLE public.receipt_lines
+    DROP COLUMN IF EXISTS serial_tracked;
+
+ALTER TABLE public.products
+    DROP COLUMN IF EXISTS serial_tracked;
@@ -0,0 +1,32 @@
+ALTER TABLE public.products
+    ADD COLUMN serial_tracked BOOLEAN NOT NULL DEFAULT false;
+
+-- Признак нужен для строк, создающих новый товар: у них еще нет строки products
+ALTER TABLE public.receipt_lines
+    ADD COLUMN serial_tracked BOOLEAN NOT NULL DEFAULT false;
+
+-- pending - номер принят по поступлению, но поступление еще не проведено
+CREATE TABLE public.serial_numbers (
+                                       id BIGSERIAL PRIMARY KEY,
+                                       serial VARCHAR(100) NOT NULL,
+                                       uuid_user UUID NOT NULL REFERENCES public.users(uuid) ON DELETE CASCADE ON UPDATE CASCADE,
+                                       ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                       product_uuid UUID REFERENCES public.products(uuid) ON DELETE SET NULL ON UPDATE CASCADE,
+                                       status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'in_stock', 'issued')),
+                                       receipt_line_id BIGINT REFERENCES public.receipt_lines(id) ON DELETE SET NULL,
+                                       shipment_line_id BIGINT REFERENCES public.shipment_lines(id) ON DELETE SET NULL,
+                                       qr TEXT NOT NULL DEFAULT '',
+                                       created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Номер уникален у владельца, а не во всей системе. Выданный номер может вернуться на склад,
+-- поэтому уникальность только среди не выданных
+CREATE UNIQUE INDEX serial_numbers_active_serial_idx ON public.serial_numbers (uuid_user, serial) WHERE status <> 'issued';
+CREATE INDEX serial_numbers_product_uuid_idx ON public.serial_numbers (product_uuid);
+CREATE INDEX serial_numbers_ware_house_id_serial_idx ON public.serial_numbers (ware_house_id, serial);
+
+CREATE TABLE public.serial_movements (
+                                         serial_number_id BIGINT NOT NULL REFERENCES public.serial_numbers(id) ON DELETE CASCADE,
+                                         stock_movement_id BIGINT NOT NULL REFERENCES public.stock_movements(id) ON DELETE CASCADE,
+                                         PRIMARY KEY (serial_number_id, stock_movement_id)
+);
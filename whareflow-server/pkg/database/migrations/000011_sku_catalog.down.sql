@@ -0,0 +1,32 @@
+ALTER TABLE public.receipt_lines
+    ADD COLUMN serial_tracked BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE public.receipt_lines
+SET serial_tracked = skus.serial_tracked
+FROM public.skus
+WHERE receipt_lines.sku_id = skus.id;
+
+ALTER TABLE public.products
+    ADD COLUMN title CHAR(200),
+    ADD COLUMN description CHAR(500),
+    ADD COLUMN serial_tracked BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE public.products
+SET title          = skus.name,
+    description    = skus.description,
+    serial_tracked = skus.serial_tracked
+FROM public.skus
+WHERE products.sku_id = skus.id;
+
+DROP INDEX IF EXISTS products_sku_id_idx;
+
+ALTER TABLE public.transfer_lines
+    DROP COLUMN IF EXISTS sku_id;
+
+ALTER TABLE public.receipt_lines
+    DROP COLUMN IF EXISTS sku_id;
+
+ALTER TABLE public.products
+    DROP COLUMN IF EXISTS sku_id;
+
+DROP TABLE IF EXISTS public.skus;
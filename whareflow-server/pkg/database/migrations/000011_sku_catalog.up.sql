@@ -0,0 +1,116 @@
+-- Каталог позиций владельца складов. Строка products остается складским остатком: позиция + партия + зона
+CREATE TABLE public.skus (
+                             id BIGSERIAL PRIMARY KEY,
+                             uuid_user UUID NOT NULL REFERENCES public.users(uuid) ON DELETE CASCADE ON UPDATE CASCADE,
+                             code VARCHAR(64) NOT NULL,
+                             name VARCHAR(200) NOT NULL,
+                             description VARCHAR(500) NOT NULL DEFAULT '',
+                             unit VARCHAR(20) NOT NULL DEFAULT 'pcs',
+                             barcode VARCHAR(64),
+                             attributes JSONB NOT NULL DEFAULT '{}',
+                             serial_tracked BOOLEAN NOT NULL DEFAULT false,
+                             created_at TIMESTAMP NOT NULL DEFAULT now(),
+                             CONSTRAINT skus_unique_code UNIQUE (uuid_user, code)
+);
+
+CREATE UNIQUE INDEX skus_unique_barcode_idx ON public.skus (uuid_user, barcode) WHERE barcode IS NOT NULL;
+
+ALTER TABLE public.products
+    ADD COLUMN sku_id BIGINT REFERENCES public.skus(id) ON DELETE RESTRICT;
+
+ALTER TABLE public.receipt_lines
+    ADD COLUMN sku_id BIGINT REFERENCES public.skus(id) ON DELETE RESTRICT;
+
+ALTER TABLE public.transfer_lines
+    ADD COLUMN sku_id BIGINT REFERENCES public.skus(id) ON DELETE SET NULL;
+
+-- Перенос данных: строки одного владельца с одинаковым названием становятся одной позицией каталога.
+-- Строки поступлений без товара тоже попадают в каталог, чтобы черновики можно было провести.
+-- Строки перемещений, чей исходный товар уже удален, сопоставляются по названию, чтобы отгруженное можно было принять
+WITH items AS (
+    SELECT ware_houses.uuid_user,
+           COALESCE(NULLIF(rtrim(products.title), ''), 'Без названия') AS name,
+           COALESCE(rtrim(products.description), '') AS description,
+           products.serial_tracked
+    FROM public.products
+             JOIN public.zones ON products.zone_id = zones.id
+             JOIN public.ware_houses ON zones.ware_house_id = ware_houses.id
+    UNION ALL
+    SELECT ware_houses.uuid_user,
+           COALESCE(NULLIF(rtrim(receipt_lines.title), ''), 'Без названия'),
+           COALESCE(receipt_lines.description, ''),
+           receipt_lines.serial_tracked
+    FROM public.receipt_lines
+             JOIN public.receipts ON receipt_lines.receipt_id = receipts.id
+             JOIN public.ware_houses ON receipts.ware_house_id = ware_houses.id
+    WHERE receipt_lines.product_uuid IS NULL
+    UNION ALL
+    SELECT ware_houses.uuid_user,
+           COALESCE(NULLIF(rtrim(transfer_lines.title), ''), 'Без названия'),
+           COALESCE(transfer_lines.description, ''),
+           false
+    FROM public.transfer_lines
+             JOIN public.transfers ON transfer_lines.transfer_id = transfers.id
+             JOIN public.ware_houses ON transfers.source_ware_house_id = ware_houses.id
+    WHERE transfer_lines.product_uuid IS NULL
+),
+     grouped AS (
+         SELECT uuid_user, name, max(description) AS description, bool_or(serial_tracked) AS serial_tracked
+         FROM items
+         GROUP BY uuid_user, name
+     )
+INSERT INTO public.skus (uuid_user, code, name, description, serial_tracked)
+SELECT uuid_user,
+       'SKU-' || lpad((row_number() OVER (PARTITION BY uuid_user ORDER BY name))::text, 6, '0'),
+       name,
+       description,
+       serial_tracked
+FROM grouped;
+
+UPDATE public.products
+SET sku_id = skus.id
+FROM public.zones, public.ware_houses, public.skus
+WHERE products.zone_id = zones.id
+  AND zones.ware_house_id = ware_houses.id
+  AND skus.uuid_user = ware_houses.uuid_user
+  AND skus.name = COALESCE(NULLIF(rtrim(products.title), ''), 'Без названия');
+
+UPDATE public.receipt_lines
+SET sku_id = products.sku_id
+FROM public.products
+WHERE receipt_lines.product_uuid = products.uuid;
+
+UPDATE public.receipt_lines
+SET sku_id = skus.id
+FROM public.receipts, public.ware_houses, public.skus
+WHERE receipt_lines.sku_id IS NULL
+  AND receipt_lines.receipt_id = receipts.id
+  AND receipts.ware_house_id = ware_houses.id
+  AND skus.uuid_user = ware_houses.uuid_user
+  AND skus.name = COALESCE(NULLIF(rtrim(receipt_lines.title), ''), 'Без названия');
+
+UPDATE public.transfer_lines
+SET sku_id = products.sku_id
+FROM public.products
+WHERE transfer_lines.product_uuid = products.uuid;
+
+UPDATE public.transfer_lines
+SET sku_id = skus.id
+FROM public.transfers, public.ware_houses, public.skus
+WHERE transfer_lines.sku_id IS NULL
+  AND transfer_lines.transfer_id = transfers.id
+  AND transfers.source_ware_house_id = ware_houses.id
+  AND skus.uuid_user = ware_houses.uuid_user
+  AND skus.name = COALESCE(NULLIF(rtrim(transfer_lines.title), ''), 'Без названия');
+
+ALTER TABLE public.products
+    ALTER COLUMN sku_id SET NOT NULL,
+    DROP COLUMN title,
+    DROP COLUMN description,
+    DROP COLUMN serial_tracked;
+
+ALTER TABLE public.receipt_lines
+    ALTER COLUMN sku_id SET NOT NULL,
+    DROP COLUMN serial_tracked;
+
+CREATE INDEX products_sku_id_idx ON public.products (sku_id);
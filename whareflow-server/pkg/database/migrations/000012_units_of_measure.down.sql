@@ -0,0 +1,12 @@
+ALTER TABLE public.shipment_lines
+    DROP COLUMN IF EXISTS unit_factor,
+    DROP COLUMN IF EXISTS unit;
+
+ALTER TABLE public.receipt_lines
+    DROP COLUMN IF EXISTS unit_factor,
+    DROP COLUMN IF EXISTS unit;
+
+DROP TABLE IF EXISTS public.sku_units;
+
+ALTER TABLE public.skus
+    DROP COLUMN IF EXISTS decimals;
@@ -0,0 +1,30 @@
+-- Остаток хранится в базовой единице позиции (skus.unit). decimals - число знаков после запятой базовой единицы:
+-- products.count хранит количество в 10^-decimals долях, для штучного товара decimals = 0
+ALTER TABLE public.skus
+    ADD COLUMN decimals SMALLINT NOT NULL DEFAULT 0 CHECK (decimals BETWEEN 0 AND 6);
+
+-- Дополнительные единицы позиции: factor - сколько базовых единиц в одной такой единице
+CREATE TABLE public.sku_units (
+                                  id BIGSERIAL PRIMARY KEY,
+                                  sku_id BIGINT NOT NULL REFERENCES public.skus(id) ON DELETE CASCADE,
+                                  code VARCHAR(20) NOT NULL,
+                                  name VARCHAR(100) NOT NULL DEFAULT '',
+                                  factor NUMERIC(18, 6) NOT NULL CHECK (factor > 0),
+                                  CONSTRAINT sku_units_unique_code UNIQUE (sku_id, code)
+);
+
+-- Строки документов хранят количество в долях базовой единицы, а единицу и коэффициент пересчета - на момент создания строки
+ALTER TABLE public.receipt_lines
+    ADD COLUMN unit VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN unit_factor NUMERIC(24, 6) NOT NULL DEFAULT 1;
+
+ALTER TABLE public.shipment_lines
+    ADD COLUMN unit VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN unit_factor NUMERIC(24, 6) NOT NULL DEFAULT 1;
+
+UPDATE public.receipt_lines SET unit = skus.unit FROM public.skus WHERE receipt_lines.sku_id = skus.id;
+
+UPDATE public.shipment_lines SET unit = skus.unit
+FROM public.products
+         JOIN public.skus ON products.sku_id = skus.id
+WHERE shipment_lines.product_uuid = products.uuid;
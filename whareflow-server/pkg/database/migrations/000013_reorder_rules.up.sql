@@ -0,0 +1,13 @@
+-- Уровни запаса позиции каталога на складе. Уровни задаются в долях базовой единицы, как и products.count.
+-- alerted_at выставляется при оповещении о падении остатка ниже min_level и сбрасывается, когда остаток восстановлен
+CREATE TABLE public.reorder_rules (
+                                      id BIGSERIAL PRIMARY KEY,
+                                      ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                      sku_id BIGINT NOT NULL REFERENCES public.skus(id) ON DELETE CASCADE,
+                                      min_level BIGINT NOT NULL CHECK (min_level >= 0),
+                                      max_level BIGINT NOT NULL,
+                                      alerted_at TIMESTAMP,
+                                      created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                      CONSTRAINT reorder_rules_unique_sku UNIQUE (ware_house_id, sku_id),
+                                      CONSTRAINT reorder_rules_levels CHECK (max_level >= min_level)
+);
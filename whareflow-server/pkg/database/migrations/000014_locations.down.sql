@@ -0,0 +1,4 @@
+ALTER TABLE public.products
+    DROP COLUMN IF EXISTS location_id;
+
+DROP TABLE IF EXISTS public.locations;
@@ -0,0 +1,21 @@
+-- Дерево адресов хранения внутри зоны: ряд / стеллаж / полка / ячейка. Товар размещается только в ячейке (bin).
+-- capacity NULL - вместимость узла не ограничена, иначе это предел суммарного остатка во всем поддереве узла
+CREATE TABLE public.locations (
+                                  id BIGSERIAL PRIMARY KEY,
+                                  zone_id BIGINT NOT NULL REFERENCES public.zones(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                  parent_id BIGINT REFERENCES public.locations(id) ON DELETE CASCADE,
+                                  kind VARCHAR(10) NOT NULL CHECK (kind IN ('aisle', 'rack', 'shelf', 'bin')),
+                                  code VARCHAR(50) NOT NULL,
+                                  capacity BIGINT CHECK (capacity >= 0),
+                                  qr VARCHAR(255) NOT NULL DEFAULT '',
+                                  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX locations_unique_code_idx ON public.locations (zone_id, COALESCE(parent_id, 0), code);
+CREATE INDEX locations_parent_id_idx ON public.locations (parent_id);
+
+-- Товар без ячейки лежит в зоне без уточнения адреса, как и раньше
+ALTER TABLE public.products
+    ADD COLUMN location_id BIGINT REFERENCES public.locations(id) ON DELETE SET NULL;
+
+CREATE INDEX products_location_id_idx ON public.products (location_id);
@@ -0,0 +1,11 @@
+ALTER TABLE public.skus
+    DROP COLUMN IF EXISTS units_per_pallet,
+    DROP COLUMN IF EXISTS weight_kg,
+    DROP COLUMN IF EXISTS height_cm,
+    DROP COLUMN IF EXISTS width_cm,
+    DROP COLUMN IF EXISTS length_cm;
+
+ALTER TABLE public.zones
+    DROP COLUMN IF EXISTS max_pallets,
+    DROP COLUMN IF EXISTS max_weight,
+    DROP COLUMN IF EXISTS max_volume;
@@ -0,0 +1,14 @@
+-- Пределы зоны по объему (м³), весу (кг) и паллетоместам. NULL - предел не задан
+ALTER TABLE public.zones
+    ADD COLUMN max_volume NUMERIC(14, 3) CHECK (max_volume >= 0),
+    ADD COLUMN max_weight NUMERIC(14, 3) CHECK (max_weight >= 0),
+    ADD COLUMN max_pallets BIGINT CHECK (max_pallets >= 0);
+
+-- Габариты (см) и вес (кг) одной базовой единицы позиции, units_per_pallet - базовых единиц на паллете.
+-- Нули означают, что характеристика не задана и в загрузку зоны не входит
+ALTER TABLE public.skus
+    ADD COLUMN length_cm NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (length_cm >= 0),
+    ADD COLUMN width_cm NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (width_cm >= 0),
+    ADD COLUMN height_cm NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (height_cm >= 0),
+    ADD COLUMN weight_kg NUMERIC(12, 4) NOT NULL DEFAULT 0 CHECK (weight_kg >= 0),
+    ADD COLUMN units_per_pallet BIGINT NOT NULL DEFAULT 0 CHECK (units_per_pallet >= 0);
@@ -0,0 +1,7 @@
+ALTER TABLE public.skus
+    DROP COLUMN IF EXISTS hazard_class,
+    DROP COLUMN IF EXISTS temperature_class;
+
+ALTER TABLE public.zones
+    DROP COLUMN IF EXISTS temperature_class,
+    DROP COLUMN IF EXISTS zone_type;
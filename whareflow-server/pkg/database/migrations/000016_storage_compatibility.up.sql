@@ -0,0 +1,10 @@
+-- Тип зоны и ее температурный режим. Опасные грузы принимают только зоны hazardous и quarantine,
+-- температурный класс позиции должен совпадать с режимом зоны
+ALTER TABLE public.zones
+    ADD COLUMN zone_type VARCHAR(20) NOT NULL DEFAULT 'general' CHECK (zone_type IN ('general', 'hazardous', 'quarantine')),
+    ADD COLUMN temperature_class VARCHAR(10) NOT NULL DEFAULT 'ambient' CHECK (temperature_class IN ('ambient', 'chilled', 'frozen'));
+
+-- Требования позиции к хранению. hazard_class - класс опасности по ДОПОГ/ООН ('3', '2.1'), пустая строка - не опасный груз
+ALTER TABLE public.skus
+    ADD COLUMN temperature_class VARCHAR(10) NOT NULL DEFAULT 'ambient' CHECK (temperature_class IN ('ambient', 'chilled', 'frozen')),
+    ADD COLUMN hazard_class VARCHAR(5) NOT NULL DEFAULT '';
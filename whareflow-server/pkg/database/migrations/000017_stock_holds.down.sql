@@ -0,0 +1,4 @@
+DELETE FROM permissions
+WHERE name IN ('hold_manage', 'hold_release');
+DROP TABLE IF EXISTS public.stock_status_changes;
+DROP TABLE IF EXISTS public.stock_holds;
@@ -0,0 +1,34 @@
+-- Блокировка части остатка товара без его списания. Пока блокировка не снята (released),
+-- ее количество не входит в доступный остаток и не предлагается к отбору
+CREATE TABLE public.stock_holds (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE CASCADE ON UPDATE CASCADE,
+                                    quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                    status VARCHAR(20) NOT NULL CHECK (status IN ('quarantine', 'damaged', 'blocked', 'released')),
+                                    reason VARCHAR(255) NOT NULL DEFAULT '',
+                                    created_by UUID NOT NULL,
+                                    created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                    released_by UUID,
+                                    released_at TIMESTAMP
+);
+
+CREATE INDEX stock_holds_active_product_uuid_idx ON public.stock_holds (product_uuid) WHERE status <> 'released';
+
+-- История смены статуса остатка. available - остаток без блокировки
+CREATE TABLE public.stock_status_changes (
+                                             id BIGSERIAL PRIMARY KEY,
+                                             hold_id BIGINT NOT NULL REFERENCES public.stock_holds(id) ON DELETE CASCADE,
+                                             product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE CASCADE ON UPDATE CASCADE,
+                                             from_status VARCHAR(20) NOT NULL,
+                                             to_status VARCHAR(20) NOT NULL,
+                                             quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                             reason VARCHAR(255) NOT NULL DEFAULT '',
+                                             actor_uuid UUID NOT NULL,
+                                             created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX stock_status_changes_product_uuid_idx ON public.stock_status_changes (product_uuid, created_at);
+
+INSERT INTO permissions (name)
+VALUES ('hold_manage'),
+       ('hold_release');
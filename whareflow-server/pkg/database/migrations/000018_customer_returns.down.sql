@@ -0,0 +1,6 @@
+DELETE FROM permissions
+WHERE name = 'return_manage';
+DROP TABLE IF EXISTS public.customer_return_lines;
+DROP TABLE IF EXISTS public.customer_returns;
+ALTER TABLE public.shipment_lines
+    DROP COLUMN IF EXISTS sku_id;
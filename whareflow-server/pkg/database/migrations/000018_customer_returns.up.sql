@@ -0,0 +1,46 @@
+-- Строка отгрузки запоминает отгруженную позицию, чтобы возврат не зависел от текущей строки товара
+ALTER TABLE public.shipment_lines
+    ADD COLUMN sku_id BIGINT REFERENCES public.skus(id) ON DELETE RESTRICT;
+
+UPDATE public.shipment_lines
+SET sku_id = products.sku_id
+FROM public.products
+WHERE shipment_lines.product_uuid = products.uuid;
+
+ALTER TABLE public.shipment_lines
+    ALTER COLUMN sku_id SET NOT NULL;
+
+-- Возврат от покупателя по проведенной отгрузке. Решение по строке (condition) определяет, куда попадет товар:
+-- resell - обратно на остаток исходной позиции, repair и scrap - в карантинную зону под блокировку
+CREATE TABLE public.customer_returns (
+                                         id BIGSERIAL PRIMARY KEY,
+                                         ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                         shipment_id BIGINT NOT NULL REFERENCES public.shipments(id) ON DELETE CASCADE,
+                                         status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'posted')),
+                                         comment VARCHAR(500),
+                                         created_by UUID NOT NULL,
+                                         created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                         posted_at TIMESTAMP
+);
+
+CREATE TABLE public.customer_return_lines (
+                                              id BIGSERIAL PRIMARY KEY,
+                                              return_id BIGINT NOT NULL REFERENCES public.customer_returns(id) ON DELETE CASCADE,
+                                              shipment_line_id BIGINT NOT NULL REFERENCES public.shipment_lines(id) ON DELETE CASCADE,
+                                              product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE RESTRICT,
+                                              sku_id BIGINT NOT NULL REFERENCES public.skus(id),
+                                              quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                              unit VARCHAR(20) NOT NULL,
+                                              unit_factor NUMERIC(24, 6) NOT NULL,
+                                              condition VARCHAR(10) NOT NULL CHECK (condition IN ('resell', 'repair', 'scrap')),
+                                              zone_id BIGINT REFERENCES public.zones(id) ON DELETE SET NULL,
+                                              result_product_uuid UUID REFERENCES public.products(uuid) ON DELETE SET NULL,
+                                              hold_id BIGINT REFERENCES public.stock_holds(id) ON DELETE SET NULL
+);
+
+CREATE INDEX customer_returns_ware_house_id_idx ON public.customer_returns (ware_house_id);
+CREATE INDEX customer_return_lines_return_id_idx ON public.customer_return_lines (return_id);
+CREATE INDEX customer_return_lines_shipment_line_id_idx ON public.customer_return_lines (shipment_line_id);
+
+INSERT INTO permissions (name)
+VALUES ('return_manage');
@@ -0,0 +1,5 @@
+DELETE FROM permissions
+WHERE name = 'kit_manage';
+DROP TABLE IF EXISTS public.kit_operation_lines;
+DROP TABLE IF EXISTS public.kit_operations;
+DROP TABLE IF EXISTS public.kit_components;
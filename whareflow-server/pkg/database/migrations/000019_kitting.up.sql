@@ -0,0 +1,39 @@
+-- Спецификация набора (BOM): quantity - доли базовой единицы компонента на одну единицу набора
+CREATE TABLE public.kit_components (
+                                       id BIGSERIAL PRIMARY KEY,
+                                       kit_sku_id BIGINT NOT NULL REFERENCES public.skus(id) ON DELETE CASCADE,
+                                       component_sku_id BIGINT NOT NULL REFERENCES public.skus(id) ON DELETE CASCADE,
+                                       quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                       UNIQUE (kit_sku_id, component_sku_id),
+                                       CHECK (kit_sku_id <> component_sku_id)
+);
+
+-- Сборка (assembly) списывает компоненты и приходует набор, разборка (disassembly) - наоборот
+CREATE TABLE public.kit_operations (
+                                       id BIGSERIAL PRIMARY KEY,
+                                       ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                       kit_sku_id BIGINT NOT NULL REFERENCES public.skus(id),
+                                       kind VARCHAR(20) NOT NULL CHECK (kind IN ('assembly', 'disassembly')),
+                                       quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                       zone_id BIGINT REFERENCES public.zones(id) ON DELETE SET NULL,
+                                       comment VARCHAR(500),
+                                       created_by UUID NOT NULL,
+                                       created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Строки операции: движение по одной строке товара. quantity со знаком, как в stock_movements
+CREATE TABLE public.kit_operation_lines (
+                                            id BIGSERIAL PRIMARY KEY,
+                                            operation_id BIGINT NOT NULL REFERENCES public.kit_operations(id) ON DELETE CASCADE,
+                                            product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE CASCADE ON UPDATE CASCADE,
+                                            sku_id BIGINT NOT NULL REFERENCES public.skus(id),
+                                            quantity BIGINT NOT NULL CHECK (quantity <> 0),
+                                            movement_id BIGINT NOT NULL REFERENCES public.stock_movements(id) ON DELETE CASCADE
+);
+
+CREATE INDEX kit_components_kit_sku_id_idx ON public.kit_components (kit_sku_id);
+CREATE INDEX kit_operations_ware_house_id_idx ON public.kit_operations (ware_house_id);
+CREATE INDEX kit_operation_lines_operation_id_idx ON public.kit_operation_lines (operation_id);
+
+INSERT INTO permissions (name)
+VALUES ('kit_manage');
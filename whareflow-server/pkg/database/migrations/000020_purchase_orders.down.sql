@@ -0,0 +1,12 @@
+DELETE FROM permissions
+WHERE name = 'purchase_manage';
+
+ALTER TABLE public.receipt_lines
+    DROP COLUMN IF EXISTS purchase_order_line_id;
+
+ALTER TABLE public.receipts
+    DROP COLUMN IF EXISTS purchase_order_id;
+
+DROP TABLE IF EXISTS public.purchase_order_lines;
+DROP TABLE IF EXISTS public.purchase_orders;
+DROP TABLE IF EXISTS public.suppliers;
@@ -0,0 +1,54 @@
+-- Справочник поставщиков владельца складов
+CREATE TABLE public.suppliers (
+                                  id BIGSERIAL PRIMARY KEY,
+                                  uuid_user UUID NOT NULL REFERENCES public.users(uuid) ON DELETE CASCADE ON UPDATE CASCADE,
+                                  name VARCHAR(200) NOT NULL,
+                                  tax_id VARCHAR(32) NOT NULL DEFAULT '',
+                                  contact_name VARCHAR(200) NOT NULL DEFAULT '',
+                                  email VARCHAR(200) NOT NULL DEFAULT '',
+                                  phone VARCHAR(50) NOT NULL DEFAULT '',
+                                  address VARCHAR(500) NOT NULL DEFAULT '',
+                                  created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                  CONSTRAINT suppliers_unique_name UNIQUE (uuid_user, name)
+);
+
+-- Заказ поставщику. Статус меняется проведением поступлений по заказу: open -> partially_received -> received.
+-- closed - заказ закрыт вручную, недопоставка по нему больше не ожидается
+CREATE TABLE public.purchase_orders (
+                                        id BIGSERIAL PRIMARY KEY,
+                                        ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                        supplier_id BIGINT NOT NULL REFERENCES public.suppliers(id) ON DELETE RESTRICT,
+                                        status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'partially_received', 'received', 'closed')),
+                                        expected_date DATE,
+                                        comment VARCHAR(500),
+                                        created_by UUID NOT NULL,
+                                        created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                        closed_at TIMESTAMP
+);
+
+-- Количества в долях базовой единицы позиции. received_quantity может превысить quantity при перепоставке
+CREATE TABLE public.purchase_order_lines (
+                                             id BIGSERIAL PRIMARY KEY,
+                                             order_id BIGINT NOT NULL REFERENCES public.purchase_orders(id) ON DELETE CASCADE,
+                                             sku_id BIGINT NOT NULL REFERENCES public.skus(id) ON DELETE RESTRICT,
+                                             title VARCHAR(200),
+                                             quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                             received_quantity BIGINT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+                                             unit VARCHAR(20) NOT NULL,
+                                             unit_factor NUMERIC(24, 6) NOT NULL,
+                                             expected_date DATE
+);
+
+CREATE INDEX purchase_orders_ware_house_id_idx ON public.purchase_orders (ware_house_id, status);
+CREATE INDEX purchase_order_lines_order_id_idx ON public.purchase_order_lines (order_id);
+
+ALTER TABLE public.receipts
+    ADD COLUMN purchase_order_id BIGINT REFERENCES public.purchase_orders(id) ON DELETE SET NULL;
+
+ALTER TABLE public.receipt_lines
+    ADD COLUMN purchase_order_line_id BIGINT REFERENCES public.purchase_order_lines(id) ON DELETE SET NULL;
+
+CREATE INDEX receipt_lines_purchase_order_line_id_idx ON public.receipt_lines (purchase_order_line_id);
+
+INSERT INTO permissions (name)
+VALUES ('purchase_manage');
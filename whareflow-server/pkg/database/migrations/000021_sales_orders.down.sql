@@ -0,0 +1,14 @@
+DELETE FROM permissions
+WHERE name = 'sales_manage';
+
+UPDATE public.reservations
+SET expires_at = now()
+WHERE expires_at IS NULL;
+
+ALTER TABLE public.reservations
+    ALTER COLUMN expires_at SET NOT NULL;
+
+DROP TABLE IF EXISTS public.pick_tasks;
+DROP TABLE IF EXISTS public.sales_order_lines;
+DROP TABLE IF EXISTS public.sales_orders;
+DROP TABLE IF EXISTS public.customers;
@@ -0,0 +1,74 @@
+-- Справочник покупателей владельца складов
+CREATE TABLE public.customers (
+                                  id BIGSERIAL PRIMARY KEY,
+                                  uuid_user UUID NOT NULL REFERENCES public.users(uuid) ON DELETE CASCADE ON UPDATE CASCADE,
+                                  name VARCHAR(200) NOT NULL,
+                                  contact_name VARCHAR(200) NOT NULL DEFAULT '',
+                                  email VARCHAR(200) NOT NULL DEFAULT '',
+                                  phone VARCHAR(50) NOT NULL DEFAULT '',
+                                  address VARCHAR(500) NOT NULL DEFAULT '',
+                                  created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                  CONSTRAINT customers_unique_name UNIQUE (uuid_user, name)
+);
+
+-- Заказ покупателя. draft -> confirmed при подтверждении, дальше статус следует за сборкой и отгрузкой:
+-- picking (собрана первая строка), packed, shipped. cancelled - заказ отменен до упаковки,
+-- expired - резерв черновика истек раньше подтверждения
+CREATE TABLE public.sales_orders (
+                                     id BIGSERIAL PRIMARY KEY,
+                                     ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                     customer_id BIGINT NOT NULL REFERENCES public.customers(id) ON DELETE RESTRICT,
+                                     status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'confirmed', 'picking', 'packed', 'shipped', 'cancelled', 'expired')),
+                                     shipment_id BIGINT REFERENCES public.shipments(id) ON DELETE SET NULL,
+                                     reserved_until TIMESTAMP NOT NULL,
+                                     comment VARCHAR(500),
+                                     created_by UUID NOT NULL,
+                                     created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                     confirmed_at TIMESTAMP,
+                                     shipped_at TIMESTAMP,
+                                     cancelled_at TIMESTAMP
+);
+
+-- Количество в долях базовой единицы позиции, строка держит резерв на товаре до отгрузки или отмены заказа
+CREATE TABLE public.sales_order_lines (
+                                          id BIGSERIAL PRIMARY KEY,
+                                          order_id BIGINT NOT NULL REFERENCES public.sales_orders(id) ON DELETE CASCADE,
+                                          product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE RESTRICT,
+                                          quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                          unit VARCHAR(20) NOT NULL,
+                                          unit_factor NUMERIC(24, 6) NOT NULL,
+                                          reservation_id BIGINT REFERENCES public.reservations(id) ON DELETE SET NULL
+);
+
+-- Задание на сборку одной строки отгрузки заказа. Задания не закреплены за работником:
+-- их выполняет любой работник с правом product_manage на склад
+CREATE TABLE public.pick_tasks (
+                                   id BIGSERIAL PRIMARY KEY,
+                                   ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                   order_id BIGINT NOT NULL REFERENCES public.sales_orders(id) ON DELETE CASCADE,
+                                   shipment_line_id BIGINT REFERENCES public.shipment_lines(id) ON DELETE SET NULL,
+                                   product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE CASCADE,
+                                   zone_id BIGINT NOT NULL REFERENCES public.zones(id) ON DELETE CASCADE,
+                                   location_id BIGINT REFERENCES public.locations(id) ON DELETE SET NULL,
+                                   quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                   picked_quantity BIGINT NOT NULL DEFAULT 0 CHECK (picked_quantity >= 0 AND picked_quantity <= quantity),
+                                   unit VARCHAR(20) NOT NULL,
+                                   unit_factor NUMERIC(24, 6) NOT NULL,
+                                   status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'done', 'cancelled')),
+                                   picked_by UUID,
+                                   created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                   done_at TIMESTAMP
+);
+
+CREATE INDEX sales_orders_ware_house_id_idx ON public.sales_orders (ware_house_id, status);
+CREATE INDEX sales_orders_shipment_id_idx ON public.sales_orders (shipment_id);
+CREATE INDEX sales_order_lines_order_id_idx ON public.sales_order_lines (order_id);
+CREATE INDEX pick_tasks_open_ware_house_id_idx ON public.pick_tasks (ware_house_id) WHERE status = 'open';
+CREATE INDEX pick_tasks_order_id_idx ON public.pick_tasks (order_id);
+
+-- Резерв подтвержденного заказа бессрочный: его снимает отгрузка или отмена заказа, а не фоновая очистка
+ALTER TABLE public.reservations
+    ALTER COLUMN expires_at DROP NOT NULL;
+
+INSERT INTO permissions (name)
+VALUES ('sales_manage');
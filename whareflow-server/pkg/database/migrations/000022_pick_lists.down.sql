@@ -0,0 +1,5 @@
+ALTER TABLE public.pick_tasks
+    DROP COLUMN IF EXISTS pick_list_id;
+
+DROP TABLE IF EXISTS public.pick_lists;
+DROP TABLE IF EXISTS public.walk_path_steps;
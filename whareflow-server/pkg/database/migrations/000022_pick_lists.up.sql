@@ -0,0 +1,28 @@
+-- Маршрут обхода склада: порядок, в котором сборщик проходит зоны и узлы адресов хранения.
+-- Шаг с location_id покрывает все поддерево узла, шаг без него - остаток зоны
+CREATE TABLE public.walk_path_steps (
+                                        id BIGSERIAL PRIMARY KEY,
+                                        ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                        sequence INT NOT NULL CHECK (sequence > 0),
+                                        zone_id BIGINT NOT NULL REFERENCES public.zones(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                        location_id BIGINT REFERENCES public.locations(id) ON DELETE CASCADE,
+                                        CONSTRAINT walk_path_steps_unique_sequence UNIQUE (ware_house_id, sequence)
+);
+
+CREATE UNIQUE INDEX walk_path_steps_unique_place_idx ON public.walk_path_steps (ware_house_id, zone_id, COALESCE(location_id, 0));
+
+-- Лист сборки объединяет невыполненные задания на сборку в один обход склада
+CREATE TABLE public.pick_lists (
+                                   id BIGSERIAL PRIMARY KEY,
+                                   ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                   status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'done')),
+                                   created_by UUID NOT NULL,
+                                   created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                   done_at TIMESTAMP
+);
+
+ALTER TABLE public.pick_tasks
+    ADD COLUMN pick_list_id BIGINT REFERENCES public.pick_lists(id) ON DELETE SET NULL;
+
+CREATE INDEX pick_lists_ware_house_id_idx ON public.pick_lists (ware_house_id, status);
+CREATE INDEX pick_tasks_pick_list_id_idx ON public.pick_tasks (pick_list_id);
@@ -0,0 +1,6 @@
+ALTER TABLE public.pick_tasks
+    DROP COLUMN IF EXISTS wave_id;
+
+DROP TABLE IF EXISTS public.wave_lines;
+DROP TABLE IF EXISTS public.wave_orders;
+DROP TABLE IF EXISTS public.waves;
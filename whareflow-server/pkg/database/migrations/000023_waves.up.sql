@@ -0,0 +1,41 @@
+-- Волна сборки: несколько подтвержденных заказов собираются за один обход склада,
+-- затем собранный товар раскладывается по ячейкам стены сортировки - по одной на заказ
+CREATE TABLE public.waves (
+                              id BIGSERIAL PRIMARY KEY,
+                              ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                              status VARCHAR(20) NOT NULL DEFAULT 'picking' CHECK (status IN ('picking', 'sorting', 'done')),
+                              created_by UUID NOT NULL,
+                              created_at TIMESTAMP NOT NULL DEFAULT now(),
+                              picked_at TIMESTAMP,
+                              done_at TIMESTAMP
+);
+
+CREATE TABLE public.wave_orders (
+                                    id BIGSERIAL PRIMARY KEY,
+                                    wave_id BIGINT NOT NULL REFERENCES public.waves(id) ON DELETE CASCADE,
+                                    order_id BIGINT NOT NULL REFERENCES public.sales_orders(id) ON DELETE CASCADE,
+                                    slot INT NOT NULL CHECK (slot > 0),
+                                    CONSTRAINT wave_orders_unique_order UNIQUE (wave_id, order_id),
+                                    CONSTRAINT wave_orders_unique_slot UNIQUE (wave_id, slot)
+);
+
+-- Строка волны - суммарное количество товара по всем заказам волны, в долях базовой единицы
+CREATE TABLE public.wave_lines (
+                                   id BIGSERIAL PRIMARY KEY,
+                                   wave_id BIGINT NOT NULL REFERENCES public.waves(id) ON DELETE CASCADE,
+                                   product_uuid UUID NOT NULL REFERENCES public.products(uuid) ON DELETE CASCADE,
+                                   zone_id BIGINT NOT NULL REFERENCES public.zones(id) ON DELETE CASCADE,
+                                   location_id BIGINT REFERENCES public.locations(id) ON DELETE SET NULL,
+                                   quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                   picked_quantity BIGINT NOT NULL DEFAULT 0 CHECK (picked_quantity >= 0),
+                                   sorted_quantity BIGINT NOT NULL DEFAULT 0 CHECK (sorted_quantity >= 0),
+                                   unit VARCHAR(20) NOT NULL,
+                                   unit_factor NUMERIC(24, 6) NOT NULL CHECK (unit_factor > 0),
+                                   CONSTRAINT wave_lines_unique_product UNIQUE (wave_id, product_uuid)
+);
+
+ALTER TABLE public.pick_tasks
+    ADD COLUMN wave_id BIGINT REFERENCES public.waves(id) ON DELETE SET NULL;
+
+CREATE INDEX waves_ware_house_id_idx ON public.waves (ware_house_id, status);
+CREATE INDEX pick_tasks_wave_id_idx ON public.pick_tasks (wave_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.parcel_lines;
+DROP TABLE IF EXISTS public.parcels;
@@ -0,0 +1,24 @@
+-- Грузовые места упакованной отгрузки. number - порядковый номер места, печатается на этикетке как "место N из M"
+CREATE TABLE public.parcels (
+                                id BIGSERIAL PRIMARY KEY,
+                                shipment_id BIGINT NOT NULL REFERENCES public.shipments(id) ON DELETE CASCADE,
+                                number INT NOT NULL CHECK (number > 0),
+                                weight_kg NUMERIC(12, 4) NOT NULL CHECK (weight_kg > 0),
+                                length_cm NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (length_cm >= 0),
+                                width_cm NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (width_cm >= 0),
+                                height_cm NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (height_cm >= 0),
+                                created_by UUID NOT NULL,
+                                created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                CONSTRAINT parcels_unique_number UNIQUE (shipment_id, number)
+);
+
+-- Содержимое места: количество строки отгрузки в долях базовой единицы
+CREATE TABLE public.parcel_lines (
+                                     id BIGSERIAL PRIMARY KEY,
+                                     parcel_id BIGINT NOT NULL REFERENCES public.parcels(id) ON DELETE CASCADE,
+                                     shipment_line_id BIGINT NOT NULL REFERENCES public.shipment_lines(id) ON DELETE CASCADE,
+                                     quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                     CONSTRAINT parcel_lines_unique_line UNIQUE (parcel_id, shipment_line_id)
+);
+
+CREATE INDEX parcel_lines_shipment_line_id_idx ON public.parcel_lines (shipment_line_id);
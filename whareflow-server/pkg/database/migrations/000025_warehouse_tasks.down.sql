@@ -0,0 +1,4 @@
+DELETE FROM permissions
+WHERE name IN ('task_work', 'task_manage');
+DROP TABLE IF EXISTS public.warehouse_task_events;
+DROP TABLE IF EXISTS public.warehouse_tasks;
@@ -0,0 +1,51 @@
+-- Складские задания работникам. Задание без assignee_id лежит в общей очереди склада,
+-- с assignee_id - видно и доступно из всех работников только назначенному.
+-- Приемка и размещение ссылаются на поступление receipt_id, сборка - на задание на сборку pick_task_id:
+-- выполнить такое задание можно только после обработки документа
+CREATE TABLE public.warehouse_tasks (
+                                        id BIGSERIAL PRIMARY KEY,
+                                        ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                        type VARCHAR(20) NOT NULL CHECK (type IN ('receive', 'putaway', 'pick', 'count', 'move')),
+                                        status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'claimed', 'done', 'cancelled')),
+                                        priority INT NOT NULL DEFAULT 0,
+                                        title VARCHAR(255) NOT NULL,
+                                        description TEXT NOT NULL DEFAULT '',
+                                        reference VARCHAR(100) NOT NULL DEFAULT '',
+                                        product_uuid UUID REFERENCES public.products(uuid) ON DELETE SET NULL,
+                                        zone_id BIGINT REFERENCES public.zones(id) ON DELETE SET NULL,
+                                        location_id BIGINT REFERENCES public.locations(id) ON DELETE SET NULL,
+                                        receipt_id BIGINT REFERENCES public.receipts(id) ON DELETE CASCADE,
+                                        pick_task_id BIGINT REFERENCES public.pick_tasks(id) ON DELETE CASCADE,
+                                        assignee_id UUID REFERENCES public.users(uuid) ON DELETE SET NULL,
+                                        claimed_by UUID REFERENCES public.users(uuid) ON DELETE SET NULL,
+                                        created_by UUID NOT NULL,
+                                        created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                        due_at TIMESTAMP,
+                                        claimed_at TIMESTAMP,
+                                        completed_at TIMESTAMP,
+                                        cancelled_at TIMESTAMP,
+                                        CONSTRAINT warehouse_tasks_document CHECK (
+                                            (type IN ('receive', 'putaway')) = (receipt_id IS NOT NULL)
+                                                AND (type = 'pick') = (pick_task_id IS NOT NULL))
+);
+
+-- Журнал переходов задания. duration_seconds - время от взятия задания в работу до выполнения или отказа,
+-- по журналу считается выработка работников
+CREATE TABLE public.warehouse_task_events (
+                                              id BIGSERIAL PRIMARY KEY,
+                                              task_id BIGINT NOT NULL REFERENCES public.warehouse_tasks(id) ON DELETE CASCADE,
+                                              ware_house_id BIGINT NOT NULL REFERENCES public.ware_houses(id) ON DELETE CASCADE ON UPDATE CASCADE,
+                                              task_type VARCHAR(20) NOT NULL,
+                                              action VARCHAR(20) NOT NULL CHECK (action IN ('claim', 'complete', 'abandon')),
+                                              actor_id UUID NOT NULL,
+                                              reason TEXT NOT NULL DEFAULT '',
+                                              duration_seconds BIGINT NOT NULL DEFAULT 0 CHECK (duration_seconds >= 0),
+                                              created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX warehouse_tasks_queue_idx ON public.warehouse_tasks (ware_house_id, status, assignee_id);
+CREATE INDEX warehouse_task_events_stats_idx ON public.warehouse_task_events (ware_house_id, created_at);
+
+INSERT INTO permissions (name)
+VALUES ('task_work'),
+       ('task_manage');
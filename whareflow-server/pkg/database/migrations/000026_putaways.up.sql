@@ -0,0 +1,21 @@
+-- Размещение принятого товара по строке поступления. suggested_* - лучшее место, которое предложил подбор,
+-- override = true, если товар положили в другое место, тогда причина обязательна
+CREATE TABLE public.putaways (
+                                 id BIGSERIAL PRIMARY KEY,
+                                 receipt_id BIGINT NOT NULL REFERENCES public.receipts(id) ON DELETE CASCADE,
+                                 receipt_line_id BIGINT NOT NULL REFERENCES public.receipt_lines(id) ON DELETE CASCADE,
+                                 product_uuid UUID REFERENCES public.products(uuid) ON DELETE SET NULL,
+                                 quantity BIGINT NOT NULL CHECK (quantity > 0),
+                                 zone_id BIGINT NOT NULL REFERENCES public.zones(id) ON DELETE CASCADE,
+                                 location_id BIGINT REFERENCES public.locations(id) ON DELETE SET NULL,
+                                 suggested_zone_id BIGINT REFERENCES public.zones(id) ON DELETE SET NULL,
+                                 suggested_location_id BIGINT REFERENCES public.locations(id) ON DELETE SET NULL,
+                                 override BOOLEAN NOT NULL DEFAULT false,
+                                 reason TEXT NOT NULL DEFAULT '',
+                                 created_by UUID NOT NULL,
+                                 created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                 CONSTRAINT putaways_unique_line UNIQUE (receipt_line_id),
+                                 CONSTRAINT putaways_override_reason CHECK (NOT override OR reason <> '')
+);
+
+CREATE INDEX putaways_receipt_id_idx ON public.putaways (receipt_id);
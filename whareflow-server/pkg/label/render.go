@@ -0,0 +1,119 @@
+package label
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// ShippingLabel - данные транспортной этикетки грузового места. Barcode печатается штрихкодом Code 128
+// и должен состоять из печатаемых символов ASCII
+type ShippingLabel struct {
+	SenderName       string
+	SenderAddress    string
+	RecipientName    string
+	RecipientAddress string
+	RecipientPhone   string
+	Reference        string
+	Barcode          string
+	ParcelNumber     int
+	ParcelCount      int
+	WeightKg         float64
+	LengthCm         float64
+	WidthCm          float64
+	HeightCm         float64
+}
+
+type RendererLabel interface {
+	RenderZPL(label ShippingLabel) ([]byte, error)
+}
+
+type Renderer struct {
+	logger slog.Logger
+}
+
+func NewRenderer(logger slog.Logger) *Renderer {
+	return &Renderer{
+		logger: logger,
+	}
+}
+
+var ErrInvalidBarcode = errors.New("barcode must contain printable ASCII characters only")
+
+// RenderZPL собирает этикетку 4x6 дюйма для термопринтеров Zebra с разрешением 203 dpi.
+// Текст передается в UTF-8 (^CI28), служебные символы ZPL в полях экранируются через ^FH
+func (r *Renderer) RenderZPL(label ShippingLabel) ([]byte, error) {
+	if label.Barcode == "" {
+		return nil, ErrInvalidBarcode
+	}
+	for _, ch := range label.Barcode {
+		if ch < 0x20 || ch > 0x7e {
+			r.logger.Error(fmt.Sprintf("error: invalid barcode %q", label.Barcode))
+			return nil, ErrInvalidBarcode
+		}
+	}
+
+	var b strings.Builder
+
+	b.WriteString("^XA\n^CI28\n^PW812\n^LL1218\n")
+
+	writeText(&b, 40, 40, 732, 28, 1, "Отправитель")
+	writeText(&b, 40, 80, 732, 36, 1, label.SenderName)
+	writeText(&b, 40, 125, 732, 28, 2, label.SenderAddress)
+	b.WriteString("^FO40,200^GB732,3,3^FS\n")
+
+	writeText(&b, 40, 230, 732, 28, 1, "Получатель")
+	writeText(&b, 40, 270, 732, 48, 2, label.RecipientName)
+	writeText(&b, 40, 380, 732, 34, 3, label.RecipientAddress)
+	writeText(&b, 40, 510, 732, 34, 1, label.RecipientPhone)
+	b.WriteString("^FO40,570^GB732,3,3^FS\n")
+
+	parcel := fmt.Sprintf("Место %d/%d", label.ParcelNumber, label.ParcelCount)
+	writeText(&b, 40, 600, 360, 40, 1, parcel)
+	writeText(&b, 420, 600, 352, 40, 1, formatNumber(label.WeightKg)+" кг")
+	if label.LengthCm > 0 || label.WidthCm > 0 || label.HeightCm > 0 {
+		dimensions := fmt.Sprintf("%sx%sx%s см", formatNumber(label.LengthCm), formatNumber(label.WidthCm), formatNumber(label.HeightCm))
+		writeText(&b, 40, 660, 732, 34, 1, dimensions)
+	}
+	writeText(&b, 40, 710, 732, 34, 1, label.Reference)
+	b.WriteString("^FO40,760^GB732,3,3^FS\n")
+
+	b.WriteString("^BY3,3,240\n")
+	b.WriteString("^FO60,800^BCN,240,Y,N,N^FH^FD" + escapeField(label.Barcode) + "^FS\n")
+	b.WriteString("^XZ\n")
+
+	return []byte(b.String()), nil
+}
+
+// writeText печатает текст шрифтом высоты size в блоке шириной width точек не длиннее lines строк
+func writeText(b *strings.Builder, x int, y int, width int, size int, lines int, text string) {
+	if text == "" {
+		return
+	}
+
+	fmt.Fprintf(b, "^FO%d,%d^A0N,%d,%d^FB%d,%d,0,L^FH^FD%s^FS\n", x, y, size, size, width, lines, escapeField(text))
+}
+
+// escapeField заменяет символы, которые ZPL разбирает как команды, на шестнадцатеричные коды ^FH.
+// Переводы строк в одной строке поля не имеют смысла и печатаются пробелом
+func escapeField(text string) string {
+	var b strings.Builder
+	for _, ch := range text {
+		switch ch {
+		case '^', '~', '_':
+			fmt.Fprintf(&b, "_%02X", ch)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteRune(ch)
+		}
+	}
+
+	return b.String()
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
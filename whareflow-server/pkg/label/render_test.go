@@ -0,0 +1,191 @@
+package label
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestEscapeField(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain text", text: "ООО Ромашка", want: "ООО Ромашка"},
+		{name: "caret", text: "A^B", want: "A_5EB"},
+		{name: "tilde", text: "~DG", want: "_7EDG"},
+		{name: "underscore is the ^FH prefix", text: "a_b", want: "a_5Fb"},
+		{name: "line breaks become spaces", text: "ул. Ленина\r\n1\t2", want: "ул. Ленина  1 2"},
+		{name: "empty", text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeField(tt.text); got != tt.want {
+				t.Errorf("escapeField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		want  string
+	}{
+		{name: "whole", value: 12, want: "12"},
+		{name: "fraction", value: 1.25, want: "1.25"},
+		{name: "zero", value: 0, want: "0"},
+		{name: "no exponent", value: 1500000, want: "1500000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatNumber(tt.value); got != tt.want {
+				t.Errorf("formatNumber() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "field block", text: "Получатель", want: "^FO40,230^A0N,28,28^FB732,2,0,L^FH^FDПолучатель^FS\n"},
+		{name: "escaped field", text: "A^B", want: "^FO40,230^A0N,28,28^FB732,2,0,L^FH^FDA_5EB^FS\n"},
+		{name: "empty text is skipped", text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeText(&b, 40, 230, 732, 28, 2, tt.text)
+			if got := b.String(); got != tt.want {
+				t.Errorf("writeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func testShippingLabel() ShippingLabel {
+	return ShippingLabel{
+		SenderName:       "Склад Север",
+		SenderAddress:    "Москва, ул. Складская, 1",
+		RecipientName:    "Иван Петров",
+		RecipientAddress: "Казань, ул. Баумана, 5",
+		RecipientPhone:   "+79001234567",
+		Reference:        "ORD-42",
+		Barcode:          "SHP000042",
+		ParcelNumber:     1,
+		ParcelCount:      2,
+		WeightKg:         3.5,
+	}
+}
+
+func TestRenderZPL(t *testing.T) {
+	renderer := NewRenderer(*slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name     string
+		label    func(label *ShippingLabel)
+		want     []string
+		wantNone []string
+		wantErr  error
+	}{
+		{
+			name:  "full label",
+			label: func(label *ShippingLabel) {},
+			want: []string{
+				"^XA\n^CI28\n^PW812\n^LL1218\n",
+				"^FDСклад Север^FS",
+				"^FDИван Петров^FS",
+				"^FDМесто 1/2^FS",
+				"^FD3.5 кг^FS",
+				"^FDORD-42^FS",
+				"^FO60,800^BCN,240,Y,N,N^FH^FDSHP000042^FS\n^XZ\n",
+			},
+			wantNone: []string{" см^FS"},
+		},
+		{
+			name: "dimensions are printed when set",
+			label: func(label *ShippingLabel) {
+				label.LengthCm, label.WidthCm, label.HeightCm = 40, 30, 20.5
+			},
+			want: []string{"^FO40,660^A0N,34,34^FB732,1,0,L^FH^FD40x30x20.5 см^FS"},
+		},
+		{
+			name: "empty fields are skipped",
+			label: func(label *ShippingLabel) {
+				label.RecipientPhone = ""
+			},
+			wantNone: []string{"^FO40,510"},
+		},
+		{
+			name: "control characters in text are escaped",
+			label: func(label *ShippingLabel) {
+				label.RecipientAddress = "Казань^XZ~JA_1"
+			},
+			want:     []string{"^FDКазань_5EXZ_7EJA_5F1^FS"},
+			wantNone: []string{"Казань^XZ"},
+		},
+		{
+			name: "control characters in barcode are escaped",
+			label: func(label *ShippingLabel) {
+				label.Barcode = "A^B"
+			},
+			want: []string{"^FH^FDA_5EB^FS"},
+		},
+		{
+			name:    "empty barcode",
+			label:   func(label *ShippingLabel) { label.Barcode = "" },
+			wantErr: ErrInvalidBarcode,
+		},
+		{
+			name:    "barcode with line break",
+			label:   func(label *ShippingLabel) { label.Barcode = "SHP\n42" },
+			wantErr: ErrInvalidBarcode,
+		},
+		{
+			name:    "barcode with cyrillic",
+			label:   func(label *ShippingLabel) { label.Barcode = "ОТП42" },
+			wantErr: ErrInvalidBarcode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label := testShippingLabel()
+			tt.label(&label)
+
+			zpl, err := renderer.RenderZPL(label)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RenderZPL() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if zpl != nil {
+					t.Errorf("RenderZPL() = %q, want nil", zpl)
+				}
+				return
+			}
+
+			got := string(zpl)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("RenderZPL() does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.wantNone {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("RenderZPL() contains %q:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package notifier
+
+import (
+	"log/slog"
+)
+
+// Notification - оповещение для пользователя. Fields - данные, по которым получатель может разобрать оповещение
+type Notification struct {
+	Subject string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Notifier доставляет оповещения. Реализации (почта, мессенджеры) подключаются вместо LogNotifier
+type Notifier interface {
+	Notify(notification Notification) error
+}
+
+// LogNotifier пишет оповещения в лог сервера и используется по умолчанию
+type LogNotifier struct {
+	logger slog.Logger
+}
+
+func NewLogNotifier(logger slog.Logger) *LogNotifier {
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+func (n *LogNotifier) Notify(notification Notification) error {
+	args := []any{"subject", notification.Subject}
+	for key, value := range notification.Fields {
+		args = append(args, key, value)
+	}
+
+	n.logger.Warn(notification.Message, args...)
+
+	return nil
+}
@@ -1,20 +1,31 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	_ "github.com/Miroslovelife/whareflow/docs"
 	"github.com/Miroslovelife/whareflow/internal/config"
 	"github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/handler"
 	custom_middleware "github.com/Miroslovelife/whareflow/internal/deliviry/http/v1/middleware"
 	"github.com/Miroslovelife/whareflow/internal/di/wire"
+	"github.com/Miroslovelife/whareflow/internal/usecase"
 	"github.com/Miroslovelife/whareflow/pkg/database"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/labstack/gommon/log"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+// shutdownTimeout - сколько сервер ждет завершения текущих запросов при остановке
+const shutdownTimeout = 10 * time.Second
+
 // @title WareFlow api
 // @version 1.0
 
@@ -33,14 +44,35 @@ type echoServer struct {
 }
 
 type DeliveryLayer struct {
-	userHandlers         *handler.IUserHttpHandler
-	warehouseHandlers    *handler.IWareHouseHandler
-	zoneHandlers         *handler.IZoneHandler
-	productHandlers      *handler.IProductHandler
-	roleHandler          *handler.IRoleHandler
-	authMiddleware       *custom_middleware.AuthHttpMiddleware
-	roleMiddleware       *custom_middleware.RoleHttpMiddleware
-	permissionMiddleware *custom_middleware.IWhPermissionMiddleware
+	userHandlers           *handler.IUserHttpHandler
+	warehouseHandlers      *handler.IWareHouseHandler
+	zoneHandlers           *handler.IZoneHandler
+	productHandlers        *handler.IProductHandler
+	roleHandler            *handler.IRoleHandler
+	receiptHandlers        *handler.IReceiptHandler
+	shipmentHandlers       *handler.IShipmentHandler
+	transferHandlers       *handler.ITransferHandler
+	reservationHandlers    *handler.IReservationHandler
+	inventoryCountHandlers *handler.IInventoryCountHandler
+	serialNumberHandlers   *handler.ISerialNumberHandler
+	skuHandlers            *handler.ISkuHandler
+	reorderRuleHandlers    *handler.IReorderRuleHandler
+	locationHandlers       *handler.ILocationHandler
+	stockHoldHandlers      *handler.IStockHoldHandler
+	customerReturnHandlers *handler.ICustomerReturnHandler
+	kitHandlers            *handler.IKitHandler
+	supplierHandlers       *handler.ISupplierHandler
+	purchaseOrderHandlers  *handler.IPurchaseOrderHandler
+	customerHandlers       *handler.ICustomerHandler
+	salesOrderHandlers     *handler.ISalesOrderHandler
+	pickListHandlers       *handler.IPickListHandler
+	waveHandlers           *handler.IWaveHandler
+	parcelHandlers         *handler.IParcelHandler
+	warehouseTaskHandlers  *handler.IWarehouseTaskHandler
+	putawayHandlers        *handler.IPutawayHandler
+	authMiddleware         *custom_middleware.AuthHttpMiddleware
+	roleMiddleware         *custom_middleware.RoleHttpMiddleware
+	permissionMiddleware   *custom_middleware.IWhPermissionMiddleware
 }
 
 func NewEchoServer(logger slog.Logger, db database.Database, cfg *config.Config) *echoServer {
@@ -74,7 +106,11 @@ func (s *echoServer) Start() {
 
 	s.app.GET("/swagger/*", echoSwagger.WrapHandler)
 
-	delivery := s.InitLayers()
+	// ctx отменяется по сигналу остановки, вместе с ним останавливаются фоновые задачи
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	delivery := s.InitLayers(ctx)
 
 	admin := v1.Group("/admin", delivery.roleMiddleware.IsAdmin)
 	owner := v1.Group("/owner", delivery.authMiddleware.Auth, delivery.roleMiddleware.IsOwner)
@@ -85,10 +121,23 @@ func (s *echoServer) Start() {
 	s.InitOwnerRoutes(owner, delivery)
 	s.InitEmployerRoutes(employer, delivery)
 
-	s.app.Logger.Fatal(s.app.Start(fmt.Sprintf("0.0.0.0:%d", 8089)))
+	go func() {
+		if err := s.app.Start(fmt.Sprintf("0.0.0.0:%d", 8089)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.app.Logger.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.app.Shutdown(shutdownCtx); err != nil {
+		s.app.Logger.Fatal(err)
+	}
 }
 
-func (s *echoServer) InitLayers() *DeliveryLayer {
+// InitLayers собирает слои приложения. Фоновые задачи работают, пока не отменен ctx
+func (s *echoServer) InitLayers(ctx context.Context) *DeliveryLayer {
 	repoLayer := wire.InitializeRepoProviderSet(s.db, s.logger)
 
 	serviceLayer := wire.InitializeServiceProviderSet(s.cfg.Auth.PasswordSalt, s.logger)
@@ -100,11 +149,40 @@ func (s *echoServer) InitLayers() *DeliveryLayer {
 		repoLayer.WareHouseRepo,
 		repoLayer.ZoneRepo,
 		repoLayer.ProductRepo,
+		repoLayer.StockMovementRepo,
 		serviceLayer.QR,
 		s.cfg,
 		repoLayer.PermissionRepo,
+		repoLayer.ReceiptRepo,
+		repoLayer.ShipmentRepo,
+		repoLayer.TransferRepo,
+		repoLayer.ReservationRepo,
+		repoLayer.InventoryCountRepo,
+		repoLayer.SerialNumberRepo,
+		repoLayer.SkuRepo,
+		repoLayer.ReorderRuleRepo,
+		serviceLayer.Notifier,
+		repoLayer.LocationRepo,
+		repoLayer.StockHoldRepo,
+		repoLayer.CustomerReturnRepo,
+		repoLayer.KitRepo,
+		repoLayer.SupplierRepo,
+		repoLayer.PurchaseOrderRepo,
+		repoLayer.CustomerRepo,
+		repoLayer.SalesOrderRepo,
+		repoLayer.PickListRepo,
+		repoLayer.WaveRepo,
+		repoLayer.ParcelRepo,
+		serviceLayer.Label,
+		repoLayer.WarehouseTaskRepo,
+		repoLayer.PutawayRepo,
+		s.logger,
 	)
 
+	// Истекшие резервы снимаются в фоне, пока работает сервер
+	reservationSweeper := usecase.NewReservationSweeper(usecaseLayer.ReservationUsecase, s.cfg.Reservation.SweepInterval, s.logger)
+	go reservationSweeper.Run(ctx)
+
 	handlerLayer := wire.InitializeHandlerProviderSet(
 		s.logger,
 		usecaseLayer.UserUsecase,
@@ -113,6 +191,27 @@ func (s *echoServer) InitLayers() *DeliveryLayer {
 		usecaseLayer.ProductUsecase,
 		s.cfg,
 		usecaseLayer.PermissionUsecase,
+		usecaseLayer.ReceiptUsecase,
+		usecaseLayer.ShipmentUsecase,
+		usecaseLayer.TransferUsecase,
+		usecaseLayer.ReservationUsecase,
+		usecaseLayer.InventoryCountUsecase,
+		usecaseLayer.SerialNumberUsecase,
+		usecaseLayer.SkuUsecase,
+		usecaseLayer.ReorderRuleUsecase,
+		usecaseLayer.LocationUsecase,
+		usecaseLayer.StockHoldUsecase,
+		usecaseLayer.CustomerReturnUsecase,
+		usecaseLayer.KitUsecase,
+		usecaseLayer.SupplierUsecase,
+		usecaseLayer.PurchaseOrderUsecase,
+		usecaseLayer.CustomerUsecase,
+		usecaseLayer.SalesOrderUsecase,
+		usecaseLayer.PickListUsecase,
+		usecaseLayer.WaveUsecase,
+		usecaseLayer.ParcelUsecase,
+		usecaseLayer.WarehouseTaskUsecase,
+		usecaseLayer.PutawayUsecase,
 	)
 
 	middlewareLayer := wire.InitializeMiddlewareProviderSet(
@@ -123,14 +222,35 @@ func (s *echoServer) InitLayers() *DeliveryLayer {
 	)
 
 	return &DeliveryLayer{
-		userHandlers:         handlerLayer.UserHandler,
-		warehouseHandlers:    handlerLayer.WareHouseHandler,
-		zoneHandlers:         handlerLayer.ZoneHandler,
-		productHandlers:      handlerLayer.ProductHandler,
-		roleHandler:          handlerLayer.RoleHandler,
-		authMiddleware:       middlewareLayer.AuthMiddleware,
-		roleMiddleware:       middlewareLayer.RoleMiddleware,
-		permissionMiddleware: middlewareLayer.WhMiddleware,
+		userHandlers:           handlerLayer.UserHandler,
+		warehouseHandlers:      handlerLayer.WareHouseHandler,
+		zoneHandlers:           handlerLayer.ZoneHandler,
+		productHandlers:        handlerLayer.ProductHandler,
+		roleHandler:            handlerLayer.RoleHandler,
+		receiptHandlers:        handlerLayer.ReceiptHandler,
+		shipmentHandlers:       handlerLayer.ShipmentHandler,
+		transferHandlers:       handlerLayer.TransferHandler,
+		reservationHandlers:    handlerLayer.ReservationHandler,
+		inventoryCountHandlers: handlerLayer.InventoryCountHandler,
+		serialNumberHandlers:   handlerLayer.SerialNumberHandler,
+		skuHandlers:            handlerLayer.SkuHandler,
+		reorderRuleHandlers:    handlerLayer.ReorderRuleHandler,
+		locationHandlers:       handlerLayer.LocationHandler,
+		stockHoldHandlers:      handlerLayer.StockHoldHandler,
+		customerReturnHandlers: handlerLayer.CustomerReturnHandler,
+		kitHandlers:            handlerLayer.KitHandler,
+		supplierHandlers:       handlerLayer.SupplierHandler,
+		purchaseOrderHandlers:  handlerLayer.PurchaseOrderHandler,
+		customerHandlers:       handlerLayer.CustomerHandler,
+		salesOrderHandlers:     handlerLayer.SalesOrderHandler,
+		pickListHandlers:       handlerLayer.PickListHandler,
+		waveHandlers:           handlerLayer.WaveHandler,
+		parcelHandlers:         handlerLayer.ParcelHandler,
+		warehouseTaskHandlers:  handlerLayer.WarehouseTaskHandler,
+		putawayHandlers:        handlerLayer.PutawayHandler,
+		authMiddleware:         middlewareLayer.AuthMiddleware,
+		roleMiddleware:         middlewareLayer.RoleMiddleware,
+		permissionMiddleware:   middlewareLayer.WhMiddleware,
 	}
 
 }
@@ -172,6 +292,27 @@ func (s *echoServer) InitAdminRoutes(group *echo.Group, delivery *DeliveryLayer)
 func (s *echoServer) InitOwnerRoutes(group *echo.Group, delivery *DeliveryLayer) {
 	group.GET("/product/:product_id", delivery.productHandlers.GetProduct)
 	group.GET("/permission", delivery.roleHandler.GetAllPermissionTypes) // Return all permission types
+
+	skuRouters := group.Group("/sku")
+	skuRouters.GET("", delivery.skuHandlers.GetAllSkus)
+	skuRouters.GET("/:sku_id", delivery.skuHandlers.GetSku)
+	skuRouters.POST("", delivery.skuHandlers.CreateSku)
+	skuRouters.PUT("/:sku_id", delivery.skuHandlers.UpdateSku)
+	skuRouters.GET("/:sku_id/kit", delivery.kitHandlers.GetKit)
+	skuRouters.PUT("/:sku_id/kit", delivery.kitHandlers.UpdateKit)
+
+	supplierRouters := group.Group("/supplier")
+	supplierRouters.GET("", delivery.supplierHandlers.GetAllSuppliers)
+	supplierRouters.GET("/:supplier_id", delivery.supplierHandlers.GetSupplier)
+	supplierRouters.POST("", delivery.supplierHandlers.CreateSupplier)
+	supplierRouters.PUT("/:supplier_id", delivery.supplierHandlers.UpdateSupplier)
+
+	customerRouters := group.Group("/customer")
+	customerRouters.GET("", delivery.customerHandlers.GetAllCustomers)
+	customerRouters.GET("/:customer_id", delivery.customerHandlers.GetCustomer)
+	customerRouters.POST("", delivery.customerHandlers.CreateCustomer)
+	customerRouters.PUT("/:customer_id", delivery.customerHandlers.UpdateCustomer)
+
 	warehouseRouters := group.Group("/warehouse")
 	warehouseRouters.GET("", delivery.warehouseHandlers.GetAllWarehouses)
 	warehouseRouters.GET("/:warehouse_id", delivery.warehouseHandlers.GetWarehouse)
@@ -186,6 +327,13 @@ func (s *echoServer) InitOwnerRoutes(group *echo.Group, delivery *DeliveryLayer)
 	zoneRouters.PUT("/:zone_id", delivery.zoneHandlers.UpdateZone)
 	zoneRouters.DELETE("/:zone_id", delivery.zoneHandlers.DeleteZone)
 
+	locationRouters := zoneRouters.Group("/:zone_id/location")
+	locationRouters.GET("", delivery.locationHandlers.GetLocationTree)
+	locationRouters.POST("", delivery.locationHandlers.CreateLocation)
+	locationRouters.PUT("/:location_id", delivery.locationHandlers.UpdateLocation)
+	locationRouters.DELETE("/:location_id", delivery.locationHandlers.DeleteLocation)
+	locationRouters.GET("/:location_id/qr", delivery.locationHandlers.GetLocationQr)
+
 	productZoneRouters := zoneRouters.Group("/:zone_id/product")
 	productZoneRouters.GET("/:product_id", delivery.productHandlers.GetProduct)
 	productZoneRouters.GET("", delivery.productHandlers.GetAllProductsFromZone)
@@ -194,6 +342,131 @@ func (s *echoServer) InitOwnerRoutes(group *echo.Group, delivery *DeliveryLayer)
 	productWarehouseRouters.GET("", delivery.productHandlers.GetAllProductsFromWarehouse)
 	productWarehouseRouters.PUT("/:product_id", delivery.productHandlers.UpdateProduct)
 	productWarehouseRouters.POST("", delivery.productHandlers.CreateProduct)
+	productWarehouseRouters.GET("/:product_id/movements", delivery.productHandlers.GetProductMovements)
+	productWarehouseRouters.POST("/:product_id/move", delivery.productHandlers.MoveProduct)
+	productWarehouseRouters.GET("/fefo", delivery.productHandlers.GetFefoSuggestion)
+	warehouseRouters.GET("/:warehouse_id/expiring", delivery.productHandlers.GetExpiringProducts)
+	productWarehouseRouters.GET("/:product_id/serials", delivery.serialNumberHandlers.GetProductSerials)
+	warehouseRouters.GET("/:warehouse_id/serial/:serial", delivery.serialNumberHandlers.GetSerialHistory)
+	warehouseRouters.GET("/:warehouse_id/low-stock", delivery.reorderRuleHandlers.GetLowStockReport)
+
+	reorderRuleRouters := warehouseRouters.Group("/:warehouse_id/reorder-rule")
+	reorderRuleRouters.GET("", delivery.reorderRuleHandlers.GetAllReorderRules)
+	reorderRuleRouters.PUT("", delivery.reorderRuleHandlers.SetReorderRule)
+	reorderRuleRouters.DELETE("/:rule_id", delivery.reorderRuleHandlers.DeleteReorderRule)
+
+	receiptRouters := warehouseRouters.Group("/:warehouse_id/receipt")
+	receiptRouters.GET("", delivery.receiptHandlers.GetAllReceipts)
+	receiptRouters.GET("/:receipt_id", delivery.receiptHandlers.GetReceipt)
+	receiptRouters.POST("", delivery.receiptHandlers.CreateReceipt)
+	receiptRouters.PUT("/:receipt_id", delivery.receiptHandlers.UpdateReceipt)
+	receiptRouters.POST("/:receipt_id/receive", delivery.receiptHandlers.ReceiveReceipt)
+	receiptRouters.POST("/:receipt_id/post", delivery.receiptHandlers.PostReceipt)
+	receiptRouters.GET("/:receipt_id/putaway", delivery.putawayHandlers.GetPutawaySuggestions)
+	receiptRouters.GET("/:receipt_id/putaway/history", delivery.putawayHandlers.GetPutaways)
+	receiptRouters.POST("/:receipt_id/putaway/:line_id", delivery.putawayHandlers.ConfirmPutaway)
+
+	purchaseOrderRouters := warehouseRouters.Group("/:warehouse_id/purchase_order")
+	purchaseOrderRouters.GET("", delivery.purchaseOrderHandlers.GetAllPurchaseOrders)
+	purchaseOrderRouters.GET("/open", delivery.purchaseOrderHandlers.GetOpenPurchaseOrders)
+	purchaseOrderRouters.GET("/:order_id", delivery.purchaseOrderHandlers.GetPurchaseOrder)
+	purchaseOrderRouters.POST("", delivery.purchaseOrderHandlers.CreatePurchaseOrder)
+	purchaseOrderRouters.PUT("/:order_id", delivery.purchaseOrderHandlers.UpdatePurchaseOrder)
+	purchaseOrderRouters.POST("/:order_id/close", delivery.purchaseOrderHandlers.ClosePurchaseOrder)
+	purchaseOrderRouters.POST("/:order_id/receipt", delivery.purchaseOrderHandlers.CreatePurchaseOrderReceipt)
+
+	salesOrderRouters := warehouseRouters.Group("/:warehouse_id/sales_order")
+	salesOrderRouters.GET("", delivery.salesOrderHandlers.GetAllSalesOrders)
+	salesOrderRouters.GET("/:order_id", delivery.salesOrderHandlers.GetSalesOrder)
+	salesOrderRouters.POST("", delivery.salesOrderHandlers.CreateSalesOrder)
+	salesOrderRouters.POST("/:order_id/confirm", delivery.salesOrderHandlers.ConfirmSalesOrder)
+	salesOrderRouters.POST("/:order_id/cancel", delivery.salesOrderHandlers.CancelSalesOrder)
+	salesOrderRouters.GET("/:order_id/pick_task", delivery.salesOrderHandlers.GetSalesOrderPickTasks)
+
+	pickTaskRouters := warehouseRouters.Group("/:warehouse_id/pick_task")
+	pickTaskRouters.GET("", delivery.salesOrderHandlers.GetPickTasks)
+	pickTaskRouters.POST("/:task_id/complete", delivery.salesOrderHandlers.CompletePickTask)
+
+	pickRouters := warehouseRouters.Group("/:warehouse_id/pick")
+	pickRouters.GET("/walk_path", delivery.pickListHandlers.GetWalkPath)
+	pickRouters.PUT("/walk_path", delivery.pickListHandlers.UpdateWalkPath)
+	pickRouters.GET("", delivery.pickListHandlers.GetAllPickLists)
+	pickRouters.GET("/:list_id", delivery.pickListHandlers.GetPickList)
+	pickRouters.POST("", delivery.pickListHandlers.CreatePickList)
+
+	waveRouters := warehouseRouters.Group("/:warehouse_id/wave")
+	waveRouters.GET("", delivery.waveHandlers.GetAllWaves)
+	waveRouters.GET("/:wave_id", delivery.waveHandlers.GetWave)
+	waveRouters.POST("", delivery.waveHandlers.CreateWave)
+	waveRouters.POST("/:wave_id/pick", delivery.waveHandlers.PickWave)
+	waveRouters.POST("/:wave_id/sort", delivery.waveHandlers.SortWave)
+	waveRouters.POST("/:wave_id/complete", delivery.waveHandlers.CompleteWave)
+
+	taskRouters := warehouseRouters.Group("/:warehouse_id/task")
+	taskRouters.GET("", delivery.warehouseTaskHandlers.GetAllWarehouseTasks)
+	taskRouters.GET("/queue", delivery.warehouseTaskHandlers.GetWorkerQueue)
+	taskRouters.GET("/stats", delivery.warehouseTaskHandlers.GetTaskStats)
+	taskRouters.GET("/:task_id", delivery.warehouseTaskHandlers.GetWarehouseTask)
+	taskRouters.POST("", delivery.warehouseTaskHandlers.CreateWarehouseTask)
+	taskRouters.PUT("/:task_id/assign", delivery.warehouseTaskHandlers.AssignWarehouseTask)
+	taskRouters.POST("/:task_id/cancel", delivery.warehouseTaskHandlers.CancelWarehouseTask)
+	taskRouters.POST("/:task_id/claim", delivery.warehouseTaskHandlers.ClaimWarehouseTask)
+	taskRouters.POST("/:task_id/complete", delivery.warehouseTaskHandlers.CompleteWarehouseTask)
+	taskRouters.POST("/:task_id/abandon", delivery.warehouseTaskHandlers.AbandonWarehouseTask)
+
+	shipmentRouters := warehouseRouters.Group("/:warehouse_id/shipment")
+	shipmentRouters.GET("", delivery.shipmentHandlers.GetAllShipments)
+	shipmentRouters.GET("/:shipment_id", delivery.shipmentHandlers.GetShipment)
+	shipmentRouters.POST("", delivery.shipmentHandlers.CreateShipment)
+	shipmentRouters.PUT("/:shipment_id", delivery.shipmentHandlers.UpdateShipment)
+	shipmentRouters.POST("/:shipment_id/pack", delivery.shipmentHandlers.PackShipment)
+	shipmentRouters.POST("/:shipment_id/ship", delivery.shipmentHandlers.ShipShipment)
+	shipmentRouters.GET("/:shipment_id/parcel", delivery.parcelHandlers.GetParcels)
+	shipmentRouters.POST("/:shipment_id/parcel", delivery.parcelHandlers.CreateParcel)
+	shipmentRouters.DELETE("/:shipment_id/parcel/:parcel_id", delivery.parcelHandlers.DeleteParcel)
+	shipmentRouters.GET("/:shipment_id/parcel/:parcel_id/label", delivery.parcelHandlers.GetParcelLabel)
+
+	transferRouters := warehouseRouters.Group("/:warehouse_id/transfer")
+	transferRouters.GET("", delivery.transferHandlers.GetAllTransfers)
+	transferRouters.GET("/:transfer_id", delivery.transferHandlers.GetTransfer)
+	transferRouters.POST("", delivery.transferHandlers.CreateTransfer)
+	transferRouters.PUT("/:transfer_id", delivery.transferHandlers.UpdateTransfer)
+	transferRouters.POST("/:transfer_id/ship", delivery.transferHandlers.ShipTransfer)
+	transferRouters.POST("/:transfer_id/receive", delivery.transferHandlers.ReceiveTransfer)
+	transferRouters.GET("/:transfer_id/discrepancy", delivery.transferHandlers.GetTransferDiscrepancy)
+
+	reservationRouters := warehouseRouters.Group("/:warehouse_id/reservation")
+	reservationRouters.GET("", delivery.reservationHandlers.GetAllReservations)
+	reservationRouters.POST("", delivery.reservationHandlers.CreateReservation)
+	reservationRouters.DELETE("/:reservation_id", delivery.reservationHandlers.ReleaseReservation)
+
+	holdRouters := warehouseRouters.Group("/:warehouse_id/hold")
+	holdRouters.GET("", delivery.stockHoldHandlers.GetAllStockHolds)
+	holdRouters.GET("/history", delivery.stockHoldHandlers.GetStockStatusHistory)
+	holdRouters.POST("", delivery.stockHoldHandlers.CreateStockHold)
+	holdRouters.PUT("/:hold_id", delivery.stockHoldHandlers.ChangeStockHoldStatus)
+	holdRouters.POST("/:hold_id/release", delivery.stockHoldHandlers.ReleaseStockHold)
+
+	returnRouters := warehouseRouters.Group("/:warehouse_id/return")
+	returnRouters.GET("", delivery.customerReturnHandlers.GetAllReturns)
+	returnRouters.GET("/:return_id", delivery.customerReturnHandlers.GetReturn)
+	returnRouters.POST("", delivery.customerReturnHandlers.CreateReturn)
+	returnRouters.POST("/:return_id/post", delivery.customerReturnHandlers.PostReturn)
+
+	kitRouters := warehouseRouters.Group("/:warehouse_id/kit")
+	kitRouters.GET("", delivery.kitHandlers.GetAllKitOperations)
+	kitRouters.GET("/:operation_id", delivery.kitHandlers.GetKitOperation)
+	kitRouters.POST("/assemble", delivery.kitHandlers.AssembleKit)
+	kitRouters.POST("/disassemble", delivery.kitHandlers.DisassembleKit)
+
+	inventoryRouters := warehouseRouters.Group("/:warehouse_id/inventory")
+	inventoryRouters.GET("", delivery.inventoryCountHandlers.GetAllInventoryCounts)
+	inventoryRouters.GET("/:count_id", delivery.inventoryCountHandlers.GetInventoryCount)
+	inventoryRouters.POST("", delivery.inventoryCountHandlers.CreateInventoryCount)
+	inventoryRouters.POST("/:count_id/scan", delivery.inventoryCountHandlers.SubmitCount)
+	inventoryRouters.POST("/:count_id/approve", delivery.inventoryCountHandlers.ApproveInventoryCount)
+	inventoryRouters.POST("/:count_id/cancel", delivery.inventoryCountHandlers.CancelInventoryCount)
+	inventoryRouters.GET("/:count_id/variance", delivery.inventoryCountHandlers.GetVarianceReport)
 
 	employerWarehouseRoutes := warehouseRouters.Group("")
 	employerWarehouseRoutes.GET("/:warehouse_id/employer", delivery.warehouseHandlers.GetEmployers)
@@ -215,7 +488,8 @@ func (s *echoServer) InitEmployerRoutes(group *echo.Group, delivery *DeliveryLay
 
 	warehouseRoutersManage := warehouseRouters.Group("/global/:warehouse_id/:action", delivery.permissionMiddleware.SetGroup("warehouse"),
 		delivery.permissionMiddleware.HasPermissionOnWarehouse)
-	warehouseRoutersManage.GET("", delivery.warehouseHandlers.GetWarehouse) // Получение информации о складе
+	warehouseRoutersManage.GET("", delivery.warehouseHandlers.GetWarehouse)            // Получение информации о складе
+	warehouseRoutersManage.PUT("/walk_path", delivery.pickListHandlers.UpdateWalkPath) // Изменение маршрута обхода склада
 
 	// Роли (работники)
 	roleRoutes := warehouseRouters.Group("/role/:warehouse_id/:action",
@@ -234,6 +508,16 @@ func (s *echoServer) InitEmployerRoutes(group *echo.Group, delivery *DeliveryLay
 	zoneRouters.PUT("/:zone_id", delivery.zoneHandlers.UpdateZone)    // Обновление зоны
 	zoneRouters.DELETE("/:zone_id", delivery.zoneHandlers.DeleteZone) // Удаление зоны
 
+	// Адреса хранения внутри зоны (права на зоны)
+	locationRouters := warehouseRouters.Group("/:warehouse_id/zone/:action/:zone_id/location",
+		delivery.permissionMiddleware.SetGroup("zone"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	locationRouters.GET("", delivery.locationHandlers.GetLocationTree)                // Дерево адресов зоны
+	locationRouters.POST("", delivery.locationHandlers.CreateLocation)                // Создание узла
+	locationRouters.PUT("/:location_id", delivery.locationHandlers.UpdateLocation)    // Изменение узла
+	locationRouters.DELETE("/:location_id", delivery.locationHandlers.DeleteLocation) // Удаление узла
+	locationRouters.GET("/:location_id/qr", delivery.locationHandlers.GetLocationQr)  // QR-код ячейки для печати
+
 	// Продукты в зоне
 	productZoneRouters := warehouseRouters.Group("/:warehouse_id/zone/:zone_id/product/:action",
 		delivery.permissionMiddleware.SetGroup("product"),
@@ -244,8 +528,203 @@ func (s *echoServer) InitEmployerRoutes(group *echo.Group, delivery *DeliveryLay
 	productWarehouseRouters := warehouseRouters.Group("/:warehouse_id/product/:action",
 		delivery.permissionMiddleware.SetGroup("product"),
 		delivery.permissionMiddleware.HasPermissionOnWarehouse)
-	productWarehouseRouters.GET("", delivery.productHandlers.GetAllProductsFromWarehouse) // Получение всех продуктов на складе
-	productWarehouseRouters.PUT("/:product_id", delivery.productHandlers.UpdateProduct)   // Обновление продукта на складе
+	productWarehouseRouters.GET("", delivery.productHandlers.GetAllProductsFromWarehouse)               // Получение всех продуктов на складе
+	productWarehouseRouters.PUT("/:product_id", delivery.productHandlers.UpdateProduct)                 // Обновление продукта на складе
+	productWarehouseRouters.GET("/:product_id/movements", delivery.productHandlers.GetProductMovements) // История движений продукта
+	productWarehouseRouters.POST("/:product_id/move", delivery.productHandlers.MoveProduct)             // Перемещение продукта между зонами
+	productWarehouseRouters.GET("/fefo", delivery.productHandlers.GetFefoSuggestion)                    // Подбор партий под выдачу по FEFO
+	productWarehouseRouters.GET("/expiring", delivery.productHandlers.GetExpiringProducts)              // Отчет по истекающим срокам годности
 	// Создание нового продукта
 
+	// Каталог владельца только для чтения (права на продукты), изменяет его сам владелец
+	skuRouters := warehouseRouters.Group("/:warehouse_id/product/:action/sku",
+		delivery.permissionMiddleware.SetGroup("product"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	skuRouters.GET("", delivery.skuHandlers.GetAllSkus)         // Получение каталога
+	skuRouters.GET("/:sku_id", delivery.skuHandlers.GetSku)     // Получение позиции каталога
+	skuRouters.GET("/:sku_id/kit", delivery.kitHandlers.GetKit) // Получение спецификации набора
+
+	// Серийные номера (права на продукты)
+	serialRouters := warehouseRouters.Group("/:warehouse_id/product/:action",
+		delivery.permissionMiddleware.SetGroup("product"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	serialRouters.GET("/:product_id/serials", delivery.serialNumberHandlers.GetProductSerials) // Серийные номера на остатке продукта
+	serialRouters.GET("/serial/:serial", delivery.serialNumberHandlers.GetSerialHistory)       // История серийного номера
+
+	// Уровни запаса и отчет по низким остаткам (права на продукты)
+	reorderRuleRouters := warehouseRouters.Group("/:warehouse_id/product/:action",
+		delivery.permissionMiddleware.SetGroup("product"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	reorderRuleRouters.GET("/low-stock", delivery.reorderRuleHandlers.GetLowStockReport)                // Отчет по низким остаткам
+	reorderRuleRouters.GET("/reorder-rule", delivery.reorderRuleHandlers.GetAllReorderRules)            // Получение уровней запаса
+	reorderRuleRouters.PUT("/reorder-rule", delivery.reorderRuleHandlers.SetReorderRule)                // Установка уровней запаса
+	reorderRuleRouters.DELETE("/reorder-rule/:rule_id", delivery.reorderRuleHandlers.DeleteReorderRule) // Удаление уровней запаса
+
+	// Задания на сборку заказов (права на продукты)
+	pickTaskRouters := warehouseRouters.Group("/:warehouse_id/pick_task/:action",
+		delivery.permissionMiddleware.SetGroup("pick_task"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	pickTaskRouters.GET("", delivery.salesOrderHandlers.GetPickTasks)                        // Очередь заданий на сборку
+	pickTaskRouters.POST("/:task_id/complete", delivery.salesOrderHandlers.CompletePickTask) // Выполнение задания
+
+	// Листы сборки по маршруту обхода склада (права на продукты)
+	pickRouters := warehouseRouters.Group("/:warehouse_id/pick/:action",
+		delivery.permissionMiddleware.SetGroup("pick"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	pickRouters.GET("/walk_path", delivery.pickListHandlers.GetWalkPath) // Маршрут обхода склада
+	pickRouters.GET("", delivery.pickListHandlers.GetAllPickLists)       // Получение листов сборки
+	pickRouters.GET("/:list_id", delivery.pickListHandlers.GetPickList)  // Лист сборки по остановкам маршрута
+	pickRouters.POST("", delivery.pickListHandlers.CreatePickList)       // Формирование листа сборки
+
+	// Волны сборки и раскладка на стене сортировки (права на продукты)
+	waveRouters := warehouseRouters.Group("/:warehouse_id/wave/:action",
+		delivery.permissionMiddleware.SetGroup("wave"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	waveRouters.GET("", delivery.waveHandlers.GetAllWaves)                     // Получение волн сборки
+	waveRouters.GET("/:wave_id", delivery.waveHandlers.GetWave)                // Волна по маршруту обхода
+	waveRouters.POST("", delivery.waveHandlers.CreateWave)                     // Планирование волны
+	waveRouters.POST("/:wave_id/pick", delivery.waveHandlers.PickWave)         // Завершение обхода волны
+	waveRouters.POST("/:wave_id/sort", delivery.waveHandlers.SortWave)         // Раскладка товара по ячейкам заказов
+	waveRouters.POST("/:wave_id/complete", delivery.waveHandlers.CompleteWave) // Завершение раскладки
+
+	// Очередь заданий работника
+	taskRouters := warehouseRouters.Group("/:warehouse_id/task/:action",
+		delivery.permissionMiddleware.SetGroup("task"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	taskRouters.GET("/queue", delivery.warehouseTaskHandlers.GetWorkerQueue)                     // Очередь заданий работника
+	taskRouters.GET("/:task_id", delivery.warehouseTaskHandlers.GetWorkerTask)                   // Получение задания
+	taskRouters.POST("/:task_id/claim", delivery.warehouseTaskHandlers.ClaimWarehouseTask)       // Взятие задания в работу
+	taskRouters.POST("/:task_id/complete", delivery.warehouseTaskHandlers.CompleteWarehouseTask) // Выполнение задания
+	taskRouters.POST("/:task_id/abandon", delivery.warehouseTaskHandlers.AbandonWarehouseTask)   // Отказ от задания
+
+	// Постановка заданий и выработка работников требуют отдельного права task_manage
+	taskManageRouters := warehouseRouters.Group("/:warehouse_id/task/:action",
+		delivery.permissionMiddleware.SetGroup("task_manage"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	taskManageRouters.GET("", delivery.warehouseTaskHandlers.GetAllWarehouseTasks)                 // Получение заданий склада
+	taskManageRouters.GET("/stats", delivery.warehouseTaskHandlers.GetTaskStats)                   // Выработка работников
+	taskManageRouters.POST("", delivery.warehouseTaskHandlers.CreateWarehouseTask)                 // Создание задания
+	taskManageRouters.PUT("/:task_id/assign", delivery.warehouseTaskHandlers.AssignWarehouseTask)  // Назначение задания
+	taskManageRouters.POST("/:task_id/cancel", delivery.warehouseTaskHandlers.CancelWarehouseTask) // Отмена задания
+
+	// Поступления на склад
+	receiptRouters := warehouseRouters.Group("/:warehouse_id/receipt/:action",
+		delivery.permissionMiddleware.SetGroup("receipt"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	receiptRouters.GET("", delivery.receiptHandlers.GetAllReceipts)                               // Получение всех поступлений склада
+	receiptRouters.GET("/:receipt_id", delivery.receiptHandlers.GetReceipt)                       // Получение поступления
+	receiptRouters.POST("", delivery.receiptHandlers.CreateReceipt)                               // Создание черновика поступления
+	receiptRouters.PUT("/:receipt_id", delivery.receiptHandlers.UpdateReceipt)                    // Изменение строк черновика
+	receiptRouters.POST("/:receipt_id/receive", delivery.receiptHandlers.ReceiveReceipt)          // Приемка товара
+	receiptRouters.POST("/:receipt_id/post", delivery.receiptHandlers.PostReceipt)                // Проведение поступления
+	receiptRouters.GET("/:receipt_id/putaway", delivery.putawayHandlers.GetPutawaySuggestions)    // Подбор мест размещения
+	receiptRouters.GET("/:receipt_id/putaway/history", delivery.putawayHandlers.GetPutaways)      // Размещения по поступлению
+	receiptRouters.POST("/:receipt_id/putaway/:line_id", delivery.putawayHandlers.ConfirmPutaway) // Размещение строки
+
+	// Заказы поставщикам, справочник поставщиков только для чтения - его ведет владелец
+	purchaseOrderRouters := warehouseRouters.Group("/:warehouse_id/purchase_order/:action",
+		delivery.permissionMiddleware.SetGroup("purchase_order"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	purchaseOrderRouters.GET("/supplier", delivery.supplierHandlers.GetAllSuppliers)                           // Справочник поставщиков
+	purchaseOrderRouters.GET("/supplier/:supplier_id", delivery.supplierHandlers.GetSupplier)                  // Получение поставщика
+	purchaseOrderRouters.GET("", delivery.purchaseOrderHandlers.GetAllPurchaseOrders)                          // Получение заказов склада
+	purchaseOrderRouters.GET("/open", delivery.purchaseOrderHandlers.GetOpenPurchaseOrders)                    // Отчет по открытым заказам
+	purchaseOrderRouters.GET("/:order_id", delivery.purchaseOrderHandlers.GetPurchaseOrder)                    // Получение заказа
+	purchaseOrderRouters.POST("", delivery.purchaseOrderHandlers.CreatePurchaseOrder)                          // Создание заказа
+	purchaseOrderRouters.PUT("/:order_id", delivery.purchaseOrderHandlers.UpdatePurchaseOrder)                 // Изменение заказа
+	purchaseOrderRouters.POST("/:order_id/close", delivery.purchaseOrderHandlers.ClosePurchaseOrder)           // Закрытие заказа
+	purchaseOrderRouters.POST("/:order_id/receipt", delivery.purchaseOrderHandlers.CreatePurchaseOrderReceipt) // Поступление по заказу
+
+	// Заказы покупателей, справочник покупателей только для чтения - его ведет владелец
+	salesOrderRouters := warehouseRouters.Group("/:warehouse_id/sales_order/:action",
+		delivery.permissionMiddleware.SetGroup("sales_order"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	salesOrderRouters.GET("/customer", delivery.customerHandlers.GetAllCustomers)                     // Справочник покупателей
+	salesOrderRouters.GET("/customer/:customer_id", delivery.customerHandlers.GetCustomer)            // Получение покупателя
+	salesOrderRouters.GET("", delivery.salesOrderHandlers.GetAllSalesOrders)                          // Получение заказов склада
+	salesOrderRouters.GET("/:order_id", delivery.salesOrderHandlers.GetSalesOrder)                    // Получение заказа
+	salesOrderRouters.POST("", delivery.salesOrderHandlers.CreateSalesOrder)                          // Создание заказа с резервом
+	salesOrderRouters.POST("/:order_id/confirm", delivery.salesOrderHandlers.ConfirmSalesOrder)       // Подтверждение заказа и выдача заданий на сборку
+	salesOrderRouters.POST("/:order_id/cancel", delivery.salesOrderHandlers.CancelSalesOrder)         // Отмена заказа
+	salesOrderRouters.GET("/:order_id/pick_task", delivery.salesOrderHandlers.GetSalesOrderPickTasks) // Задания на сборку заказа
+
+	// Отгрузки со склада
+	shipmentRouters := warehouseRouters.Group("/:warehouse_id/shipment/:action",
+		delivery.permissionMiddleware.SetGroup("shipment"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	shipmentRouters.GET("", delivery.shipmentHandlers.GetAllShipments)                                   // Получение всех отгрузок склада
+	shipmentRouters.GET("/:shipment_id", delivery.shipmentHandlers.GetShipment)                          // Получение отгрузки
+	shipmentRouters.POST("", delivery.shipmentHandlers.CreateShipment)                                   // Создание отгрузки
+	shipmentRouters.PUT("/:shipment_id", delivery.shipmentHandlers.UpdateShipment)                       // Изменение строк отгрузки
+	shipmentRouters.POST("/:shipment_id/pack", delivery.shipmentHandlers.PackShipment)                   // Упаковка собранного товара
+	shipmentRouters.POST("/:shipment_id/ship", delivery.shipmentHandlers.ShipShipment)                   // Списание и отгрузка
+	shipmentRouters.GET("/:shipment_id/parcel", delivery.parcelHandlers.GetParcels)                      // Грузовые места отгрузки
+	shipmentRouters.POST("/:shipment_id/parcel", delivery.parcelHandlers.CreateParcel)                   // Добавление грузового места
+	shipmentRouters.DELETE("/:shipment_id/parcel/:parcel_id", delivery.parcelHandlers.DeleteParcel)      // Удаление грузового места
+	shipmentRouters.GET("/:shipment_id/parcel/:parcel_id/label", delivery.parcelHandlers.GetParcelLabel) // Этикетка места для термопринтера
+
+	// Перемещения между складами. warehouse_id - склад, от имени которого выполняется операция:
+	// отправка проверяет права на складе-отправителе, приемка - на складе-получателе
+	transferRouters := warehouseRouters.Group("/:warehouse_id/transfer/:action",
+		delivery.permissionMiddleware.SetGroup("transfer"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	transferRouters.GET("", delivery.transferHandlers.GetAllTransfers)                                 // Входящие и исходящие перемещения склада
+	transferRouters.GET("/:transfer_id", delivery.transferHandlers.GetTransfer)                        // Получение перемещения
+	transferRouters.POST("", delivery.transferHandlers.CreateTransfer)                                 // Создание черновика перемещения
+	transferRouters.PUT("/:transfer_id", delivery.transferHandlers.UpdateTransfer)                     // Изменение строк черновика
+	transferRouters.POST("/:transfer_id/ship", delivery.transferHandlers.ShipTransfer)                 // Отправка товара в путь
+	transferRouters.POST("/:transfer_id/receive", delivery.transferHandlers.ReceiveTransfer)           // Приемка на складе-получателе
+	transferRouters.GET("/:transfer_id/discrepancy", delivery.transferHandlers.GetTransferDiscrepancy) // Отчет о расхождениях
+
+	// Резервы товара
+	reservationRouters := warehouseRouters.Group("/:warehouse_id/reservation/:action",
+		delivery.permissionMiddleware.SetGroup("reservation"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	reservationRouters.GET("", delivery.reservationHandlers.GetAllReservations)                    // Действующие резервы склада
+	reservationRouters.POST("", delivery.reservationHandlers.CreateReservation)                    // Резервирование товара
+	reservationRouters.DELETE("/:reservation_id", delivery.reservationHandlers.ReleaseReservation) // Снятие резерва
+
+	// Блокировки остатка: карантин, брак, блокировка
+	holdRouters := warehouseRouters.Group("/:warehouse_id/hold/:action",
+		delivery.permissionMiddleware.SetGroup("hold"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	holdRouters.GET("", delivery.stockHoldHandlers.GetAllStockHolds)               // Действующие блокировки склада
+	holdRouters.GET("/history", delivery.stockHoldHandlers.GetStockStatusHistory)  // История статусов остатка
+	holdRouters.POST("", delivery.stockHoldHandlers.CreateStockHold)               // Блокировка остатка
+	holdRouters.PUT("/:hold_id", delivery.stockHoldHandlers.ChangeStockHoldStatus) // Смена статуса блокировки
+
+	// Снятие блокировки требует отдельного права hold_release
+	holdReleaseRouters := warehouseRouters.Group("/:warehouse_id/hold/:action",
+		delivery.permissionMiddleware.SetGroup("hold_release"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	holdReleaseRouters.POST("/:hold_id/release", delivery.stockHoldHandlers.ReleaseStockHold) // Снятие блокировки
+
+	// Возвраты покупателей по отгрузкам
+	returnRouters := warehouseRouters.Group("/:warehouse_id/return/:action",
+		delivery.permissionMiddleware.SetGroup("return"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	returnRouters.GET("", delivery.customerReturnHandlers.GetAllReturns)               // Получение возвратов склада
+	returnRouters.GET("/:return_id", delivery.customerReturnHandlers.GetReturn)        // Получение возврата
+	returnRouters.POST("", delivery.customerReturnHandlers.CreateReturn)               // Создание черновика возврата
+	returnRouters.POST("/:return_id/post", delivery.customerReturnHandlers.PostReturn) // Проведение возврата
+
+	// Сборка и разборка наборов
+	kitRouters := warehouseRouters.Group("/:warehouse_id/kit/:action",
+		delivery.permissionMiddleware.SetGroup("kit"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	kitRouters.GET("", delivery.kitHandlers.GetAllKitOperations)           // Получение операций с наборами
+	kitRouters.GET("/:operation_id", delivery.kitHandlers.GetKitOperation) // Получение операции
+	kitRouters.POST("/assemble", delivery.kitHandlers.AssembleKit)         // Сборка наборов
+	kitRouters.POST("/disassemble", delivery.kitHandlers.DisassembleKit)   // Разборка наборов
+
+	// Пересчет товара. Утверждение и отмена доступны только владельцу склада
+	inventoryRouters := warehouseRouters.Group("/:warehouse_id/inventory/:action",
+		delivery.permissionMiddleware.SetGroup("inventory"),
+		delivery.permissionMiddleware.HasPermissionOnWarehouse)
+	inventoryRouters.GET("", delivery.inventoryCountHandlers.GetAllInventoryCounts)                // Сессии пересчета склада
+	inventoryRouters.GET("/:count_id", delivery.inventoryCountHandlers.GetInventoryCount)          // Получение сессии пересчета
+	inventoryRouters.POST("", delivery.inventoryCountHandlers.CreateInventoryCount)                // Открытие пересчета
+	inventoryRouters.POST("/:count_id/scan", delivery.inventoryCountHandlers.SubmitCount)          // Отправка количества по QR-коду
+	inventoryRouters.GET("/:count_id/variance", delivery.inventoryCountHandlers.GetVarianceReport) // Отчет о расхождениях
+
 }